@@ -7,14 +7,15 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-// ENUM(ethereum,spark,avalon_finance,polygon)
+// ENUM(ethereum,spark,avalon_finance,polygon,radiant)
 //
 // AaveProtocolDeployment matches the numerous deployments of Aave.
 // The naming convention here is:
@@ -57,6 +58,268 @@ const aaveV3ABI = `
          "type": "uint16"
        }
      ]
+   },
+   {
+     "name": "borrow",
+     "type": "function",
+     "inputs": [
+       {
+         "type": "address"
+       },
+       {
+         "type": "uint256"
+       },
+       {
+         "type": "uint256"
+       },
+       {
+         "type": "uint16"
+       },
+       {
+         "type": "address"
+       }
+     ]
+   },
+   {
+     "name": "repayWithATokens",
+     "type": "function",
+     "inputs": [
+       {
+         "type": "address"
+       },
+       {
+         "type": "uint256"
+       },
+       {
+         "type": "uint256"
+       }
+     ],
+     "outputs": [
+       {
+         "type": "uint256"
+       }
+     ]
+   },
+   {
+     "name": "getUserAccountData",
+     "type": "function",
+     "stateMutability": "view",
+     "inputs": [
+       {
+         "name": "user",
+         "type": "address"
+       }
+     ],
+     "outputs": [
+       {
+         "name": "totalCollateralBase",
+         "type": "uint256"
+       },
+       {
+         "name": "totalDebtBase",
+         "type": "uint256"
+       },
+       {
+         "name": "availableBorrowsBase",
+         "type": "uint256"
+       },
+       {
+         "name": "currentLiquidationThreshold",
+         "type": "uint256"
+       },
+       {
+         "name": "ltv",
+         "type": "uint256"
+       },
+       {
+         "name": "healthFactor",
+         "type": "uint256"
+       }
+     ]
+   },
+   {
+     "name": "setUserUseReserveAsCollateral",
+     "type": "function",
+     "inputs": [
+       {
+         "name": "asset",
+         "type": "address"
+       },
+       {
+         "name": "useAsCollateral",
+         "type": "bool"
+       }
+     ]
+   },
+   {
+     "name": "flashLoanSimple",
+     "type": "function",
+     "inputs": [
+       {
+         "name": "receiverAddress",
+         "type": "address"
+       },
+       {
+         "name": "asset",
+         "type": "address"
+       },
+       {
+         "name": "amount",
+         "type": "uint256"
+       },
+       {
+         "name": "params",
+         "type": "bytes"
+       },
+       {
+         "name": "referralCode",
+         "type": "uint16"
+       }
+     ]
+   },
+   {
+     "name": "setUserEMode",
+     "type": "function",
+     "inputs": [
+       {
+         "name": "categoryId",
+         "type": "uint8"
+       }
+     ]
+   },
+   {
+     "name": "getUserEMode",
+     "type": "function",
+     "stateMutability": "view",
+     "inputs": [
+       {
+         "name": "user",
+         "type": "address"
+       }
+     ],
+     "outputs": [
+       {
+         "type": "uint256"
+       }
+     ]
+   },
+   {
+     "name": "getEModeCategoryData",
+     "type": "function",
+     "stateMutability": "view",
+     "inputs": [
+       {
+         "name": "id",
+         "type": "uint8"
+       }
+     ],
+     "outputs": [
+       {
+         "type": "tuple",
+         "components": [
+           { "name": "ltv", "type": "uint16" },
+           { "name": "liquidationThreshold", "type": "uint16" },
+           { "name": "liquidationBonus", "type": "uint16" },
+           { "name": "priceSource", "type": "address" },
+           { "name": "label", "type": "string" }
+         ]
+       }
+     ]
+   },
+   {
+     "name": "getReservesList",
+     "type": "function",
+     "stateMutability": "view",
+     "inputs": [],
+     "outputs": [
+       {
+         "type": "address[]"
+       }
+     ]
+   },
+   {
+     "name": "getReserveData",
+     "type": "function",
+     "stateMutability": "view",
+     "inputs": [
+       {
+         "name": "asset",
+         "type": "address"
+       }
+     ],
+     "outputs": [
+       {
+         "name": "configuration",
+         "type": "uint256"
+       },
+       {
+         "name": "liquidityIndex",
+         "type": "uint128"
+       },
+       {
+         "name": "currentLiquidityRate",
+         "type": "uint128"
+       },
+       {
+         "name": "variableBorrowIndex",
+         "type": "uint128"
+       },
+       {
+         "name": "currentVariableBorrowRate",
+         "type": "uint128"
+       },
+       {
+         "name": "currentStableBorrowRate",
+         "type": "uint128"
+       },
+       {
+         "name": "lastUpdateTimestamp",
+         "type": "uint40"
+       },
+       {
+         "name": "id",
+         "type": "uint16"
+       },
+       {
+         "name": "aTokenAddress",
+         "type": "address"
+       },
+       {
+         "name": "stableDebtTokenAddress",
+         "type": "address"
+       },
+       {
+         "name": "variableDebtTokenAddress",
+         "type": "address"
+       },
+       {
+         "name": "interestRateStrategyAddress",
+         "type": "address"
+       },
+       {
+         "name": "accruedToTreasury",
+         "type": "uint128"
+       },
+       {
+         "name": "unbacked",
+         "type": "uint128"
+       },
+       {
+         "name": "isolationModeTotalDebt",
+         "type": "uint128"
+       }
+     ]
+   },
+   {
+     "name": "ADDRESSES_PROVIDER",
+     "type": "function",
+     "stateMutability": "view",
+     "inputs": [],
+     "outputs": [
+       {
+         "type": "address"
+       }
+     ]
    }
  ]
 	`
@@ -91,6 +354,96 @@ const aaveDataProviderABI = `
     ],
     "stateMutability": "view",
     "type": "function"
+  },
+  {
+    "inputs": [
+      {
+        "internalType": "address",
+        "name": "asset",
+        "type": "address"
+      }
+    ],
+    "name": "getReserveConfigurationData",
+    "outputs": [
+      { "internalType": "uint256", "name": "decimals", "type": "uint256" },
+      { "internalType": "uint256", "name": "ltv", "type": "uint256" },
+      { "internalType": "uint256", "name": "liquidationThreshold", "type": "uint256" },
+      { "internalType": "uint256", "name": "liquidationBonus", "type": "uint256" },
+      { "internalType": "uint256", "name": "reserveFactor", "type": "uint256" },
+      { "internalType": "bool", "name": "usageAsCollateralEnabled", "type": "bool" },
+      { "internalType": "bool", "name": "borrowingEnabled", "type": "bool" },
+      { "internalType": "bool", "name": "stableBorrowRateEnabled", "type": "bool" },
+      { "internalType": "bool", "name": "isActive", "type": "bool" },
+      { "internalType": "bool", "name": "isFrozen", "type": "bool" }
+    ],
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      {
+        "internalType": "address",
+        "name": "asset",
+        "type": "address"
+      }
+    ],
+    "name": "getReserveCaps",
+    "outputs": [
+      { "internalType": "uint256", "name": "borrowCap", "type": "uint256" },
+      { "internalType": "uint256", "name": "supplyCap", "type": "uint256" }
+    ],
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      {
+        "internalType": "address",
+        "name": "asset",
+        "type": "address"
+      }
+    ],
+    "name": "getATokenTotalSupply",
+    "outputs": [
+      { "internalType": "uint256", "name": "", "type": "uint256" }
+    ],
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      {
+        "internalType": "address",
+        "name": "asset",
+        "type": "address"
+      }
+    ],
+    "name": "getTotalDebt",
+    "outputs": [
+      { "internalType": "uint256", "name": "", "type": "uint256" }
+    ],
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "address", "name": "asset", "type": "address" },
+      { "internalType": "address", "name": "user", "type": "address" }
+    ],
+    "name": "getUserReserveData",
+    "outputs": [
+      { "internalType": "uint256", "name": "currentATokenBalance", "type": "uint256" },
+      { "internalType": "uint256", "name": "currentStableDebt", "type": "uint256" },
+      { "internalType": "uint256", "name": "currentVariableDebt", "type": "uint256" },
+      { "internalType": "uint256", "name": "principalStableDebt", "type": "uint256" },
+      { "internalType": "uint256", "name": "scaledVariableDebt", "type": "uint256" },
+      { "internalType": "uint256", "name": "stableBorrowRate", "type": "uint256" },
+      { "internalType": "uint256", "name": "liquidityRate", "type": "uint256" },
+      { "internalType": "uint40", "name": "stableRateLastUpdated", "type": "uint40" },
+      { "internalType": "bool", "name": "usageAsCollateralEnabled", "type": "bool" }
+    ],
+    "stateMutability": "view",
+    "type": "function"
   }
 ]`
 
@@ -100,8 +453,60 @@ var (
 	ethSparklendProviderContract      = common.HexToAddress("0xFc21d6d146E6086B8359705C8b28512a983db0cb")
 	bnbAaveDataProviderContract       = common.HexToAddress("0x41585C50524fb8c3899B43D7D797d9486AAc94DB")
 	avalonFinanceDataProviderContract = common.HexToAddress("0x672b19DdA450120C505214D149Ee7F7B6DEd8C39")
+	radiantBnbDataProviderContract    = common.HexToAddress("0x2f9D57E97C3DFED8676e605BC504a48E0c5917E9")
+	gnosisAaveDataProviderContract    = common.HexToAddress("0x501B4c19dd9C2e06E94dA7b6D5Ed4ddA013EC741")
+	avalancheAaveDataProviderContract = common.HexToAddress("0x69FA688f1Dc47d4B5d8029D5a35FB7a548310654")
 )
 
+// aaveVariableInterestRateMode selects the variable rate debt when borrowing and repaying.
+// Stable rate borrowing is deprecated on every V3 deployment
+const aaveVariableInterestRateMode = 2
+
+// defaultATokenCacheTTL is how long a resolved aToken address is reused before
+// it is fetched again from the data provider. Reserve to aToken mappings rarely change
+const defaultATokenCacheTTL = 10 * time.Minute
+
+type aTokenCacheEntry struct {
+	address   common.Address
+	expiresAt time.Time
+}
+
+// AaveOption configures optional behaviour of an AaveOperation.
+// NetworkOption is an AaveOption too
+type AaveOption interface {
+	applyAave(*AaveOperation)
+}
+
+type aaveOption func(*AaveOperation)
+
+func (o aaveOption) applyAave(a *AaveOperation) { o(a) }
+
+func (o NetworkOption) applyAave(a *AaveOperation) { o(&a.network) }
+
+// WithATokenCacheTTL sets how long resolved aToken addresses are cached.
+// A ttl less than or equal to zero disables the cache entirely
+func WithATokenCacheTTL(ttl time.Duration) AaveOption {
+	return aaveOption(func(a *AaveOperation) {
+		a.aTokenCacheTTL = ttl
+	})
+}
+
+// WithAaveReferralCode sets the referral code used when supplying
+// if the caller does not provide one in ExtraData
+func WithAaveReferralCode(code uint16) AaveOption {
+	return aaveOption(func(a *AaveOperation) {
+		a.referralCode = code
+	})
+}
+
+// WithDynamicAssets makes GetSupportedAssets and IsSupportedAsset read the
+// reserves of the pool, refreshed every defaultReservesCacheTTL, instead of the static list
+func WithDynamicAssets(enabled bool) AaveOption {
+	return aaveOption(func(a *AaveOperation) {
+		a.dynamicAssets = enabled
+	})
+}
+
 // AaveOperation implements the Protocol interface for Aave
 type AaveOperation struct {
 	parsedABI       abi.ABI
@@ -110,32 +515,75 @@ type AaveOperation struct {
 	chainID         *big.Int
 	version         string
 	fork            AaveProtocolDeployment
+	referralCode    uint16
 	erc20ABI        abi.ABI
+	oracleABI       abi.ABI
+	gatewayABI      abi.ABI
+
+	client   EthClient
+	observer Observer
+	metadata AssetMetadata
 
-	client *ethclient.Client
+	// verified on first use, see networkCheck
+	network networkCheck
+
+	aTokenCacheMu  sync.RWMutex
+	aTokenCache    map[common.Address]aTokenCacheEntry
+	aTokenCacheTTL time.Duration
+
+	reservesMu sync.RWMutex
+	// reserves pulled from the pool, nil until SyncSupportedAssets succeeds
+	reserves         []common.Address
+	reservesSyncedAt time.Time
+	dynamicAssets    bool
+
+	// disabled through the registry, see SetProtocolEnabled
+	deprecation
 }
 
+var _ Protocol = (*AaveOperation)(nil)
+
 func isAaveChainSupported(chainID *big.Int, fork AaveProtocolDeployment) error {
 
-	if !IsBnb(chainID) && !IsEth(chainID) && !IsPolygon(chainID) {
-		return errors.New("only Ethereum, BNB, and Polygon chains are supported")
+	if !IsBnb(chainID) && !IsEth(chainID) && !IsPolygon(chainID) && !IsGnosis(chainID) && !IsAvalanche(chainID) {
+		return errors.New("only Ethereum, BNB, Polygon, Gnosis and Avalanche chains are supported")
 	}
 
 	if IsBnb(chainID) && fork == AaveProtocolDeploymentSpark {
 		return errors.New("spark finance is not supported on Bnb chain. Only Ethereum")
 	}
 
+	if fork == AaveProtocolDeploymentRadiant && !IsBnb(chainID) {
+		return errors.New("radiant is only supported on Bnb chain at the moment")
+	}
+
 	if IsPolygon(chainID) && fork != AaveProtocolDeploymentPolygon {
 		return errors.New("only the official aave deployment on Polygon is supported at the moment")
 	}
 
+	if IsGnosis(chainID) && fork != AaveProtocolDeploymentEthereum {
+		return errors.New("only the official aave deployment on Gnosis is supported at the moment")
+	}
+
+	if IsAvalanche(chainID) && fork != AaveProtocolDeploymentEthereum {
+		return errors.New("only the official aave deployment on Avalanche is supported at the moment")
+	}
+
 	return nil
 }
 
+// NewAaveOperationOffline creates an AaveOperation that packs calldata without a
+// node, see NewOfflineClient. Calls reading the chain fail with ErrOffline
+func NewAaveOperationOffline(chainID *big.Int, fork AaveProtocolDeployment,
+	opts ...AaveOption) (*AaveOperation, error) {
+	return NewAaveOperation(NewOfflineClient(chainID), chainID, fork, opts...)
+}
+
 func NewAaveOperation(
-	client *ethclient.Client,
+	client EthClient,
 	chainID *big.Int,
 	fork AaveProtocolDeployment,
+	opts ...AaveOption,
 ) (*AaveOperation, error) {
 
 	if err := isAaveChainSupported(chainID, fork); err != nil {
@@ -146,27 +594,22 @@ func NewAaveOperation(
 		return nil, errors.New("invalid Aave fork")
 	}
 
-	networkID, err := client.NetworkID(context.Background())
+	dataProviderABI, err := abi.JSON(strings.NewReader(aaveDataProviderABI))
 	if err != nil {
-		return nil, fmt.Errorf("client.NetworkID: could not fetch network id... %w", err)
-	}
-
-	if networkID.Cmp(chainID) != 0 {
-		return nil, fmt.Errorf("network id of client(%d) does not match chainID provided (%d)",
-			networkID.Int64(), chainID.Int64())
+		return nil, err
 	}
 
-	parsedABI, err := abi.JSON(strings.NewReader(aaveV3ABI))
+	erc20ABI, err := abi.JSON(strings.NewReader(erc20BalanceOfABI))
 	if err != nil {
 		return nil, err
 	}
 
-	dataProviderABI, err := abi.JSON(strings.NewReader(aaveDataProviderABI))
+	oracleABI, err := abi.JSON(strings.NewReader(aaveOracleABI))
 	if err != nil {
 		return nil, err
 	}
 
-	erc20ABI, err := abi.JSON(strings.NewReader(erc20BalanceOfABI))
+	gatewayABI, err := abi.JSON(strings.NewReader(aaveWrappedTokenGatewayABI))
 	if err != nil {
 		return nil, err
 	}
@@ -179,51 +622,122 @@ func NewAaveOperation(
 		if chainID.Cmp(BscChainID) == 0 {
 			contract = AaveBnbV3ContractAddress
 		}
+		if IsGnosis(chainID) {
+			contract = AaveGnosisV3ContractAddress
+		}
+		if IsAvalanche(chainID) {
+			contract = AaveAvalancheV3ContractAddress
+		}
 	case AaveProtocolDeploymentAvalonFinance:
 		contract = AvalonFinanceContractAddress
 	case AaveProtocolDeploymentSpark:
 		contract = SparkLendContractAddress
 	case AaveProtocolDeploymentPolygon:
-		contract = polygonAaveDataProviderContract
+		contract = AavePolygonV3ContractAddress
+	case AaveProtocolDeploymentRadiant:
+		contract = RadiantBnbContractAddress
 	}
 
 	var version string = "3"
-	if fork == AaveProtocolDeploymentAvalonFinance {
+	if fork == AaveProtocolDeploymentAvalonFinance || fork == AaveProtocolDeploymentRadiant {
 		version = "2"
 	}
 
-	return &AaveOperation{
+	parsedABI, err := abi.JSON(strings.NewReader(poolABI(version)))
+	if err != nil {
+		return nil, err
+	}
+
+	a := &AaveOperation{
 		dataProviderABI: dataProviderABI,
 		parsedABI:       parsedABI,
 		erc20ABI:        erc20ABI,
+		oracleABI:       oracleABI,
+		gatewayABI:      gatewayABI,
 		contract:        contract,
 		chainID:         chainID,
 		version:         version,
 		client:          client,
 		fork:            fork,
-	}, nil
+		aTokenCache:     make(map[common.Address]aTokenCacheEntry),
+		aTokenCacheTTL:  defaultATokenCacheTTL,
+	}
+
+	for _, opt := range opts {
+		opt.applyAave(a)
+	}
+
+	return a, nil
 }
 
 // GenerateCalldata creates the necessary blockchain transaction data
 func (a *AaveOperation) GenerateCalldata(ctx context.Context, chainID *big.Int,
 	action ContractAction, params TransactionParams) (string, error) {
+	if err := a.deprecation.check(a.GetUniqueKey()); err != nil {
+		return "", err
+	}
+
+	if err := params.checkAmountRange(); err != nil {
+		return "", err
+	}
+
+	return observeCalldata(a.observer, a.GetName(), action, func() (string, error) {
+		return a.generateCalldata(ctx, chainID, action, params)
+	})
+}
+
+func (a *AaveOperation) generateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
 
 	if err := isAaveChainSupported(a.chainID, a.fork); err != nil {
 		return "", err
 	}
 
+	if err := a.network.ensure(ctx, a.client, a.chainID); err != nil {
+		return "", err
+	}
+
+	if !a.IsSupportedAction(action) {
+		return "", ErrUnsupportedAction
+	}
+
+	if action == LoanSetEMode {
+		return a.generateEModeCalldata(params)
+	}
+
+	if action == LoanSetCollateral {
+		return a.generateCollateralCalldata(params)
+	}
+
+	if params.Amount == nil {
+		return "", ErrAmountRequired
+	}
+
+	if action == LoanFlashLoan {
+		return a.generateFlashLoanCalldata(params)
+	}
+
+	if action == LoanDelegateCredit {
+		return a.generateDelegationCalldata(params)
+	}
+
+	if IsNativeToken(params.Asset) {
+		return a.generateGatewayCalldata(action, params)
+	}
+
 	var calldata []byte
 	var err error
 
 	switch action {
 	case LoanSupply:
 
-		referalCode, ok := params.ExtraData["referral_code"].(uint16)
-		if !ok {
-			return "", errors.New("referal code is not a uint16")
+		var referalCode uint16
+		referalCode, err = a.supplyReferralCode(params)
+		if err != nil {
+			return "", err
 		}
 
-		calldata, err = a.parsedABI.Pack("supply",
+		calldata, err = a.parsedABI.Pack(a.poolMethod("supply"),
 			params.Asset, params.Amount, params.GetBeneficiaryOwner(), referalCode)
 		if err != nil {
 			return "", err
@@ -237,20 +751,143 @@ func (a *AaveOperation) GenerateCalldata(ctx context.Context, chainID *big.Int,
 			return "", err
 		}
 
+	case LoanBorrow:
+
+		var referalCode uint16
+		referalCode, err = a.supplyReferralCode(params)
+		if err != nil {
+			return "", err
+		}
+
+		// the debt is taken by the recipient when set, who must have
+		// delegated credit to the sender with LoanDelegateCredit
+		calldata, err = a.parsedABI.Pack("borrow", params.Asset, params.Amount,
+			big.NewInt(aaveVariableInterestRateMode), referalCode, params.GetBeneficiaryOwner())
+		if err != nil {
+			return "", err
+		}
+
+	case LoanRepay:
+
+		if !useATokens(params) {
+			return "", errors.New("repay is only supported from aTokens. set use_atokens")
+		}
+
+		calldata, err = a.parsedABI.Pack("repayWithATokens",
+			params.Asset, params.Amount, big.NewInt(aaveVariableInterestRateMode))
+		if err != nil {
+			return "", err
+		}
+
 	default:
-		return "", errors.New("operation not supported")
+		return "", ErrUnsupportedAction
 	}
 
 	return HexPrefix + hex.EncodeToString(calldata), nil
 }
 
+// supplyReferralCode returns the referral code of the supply or borrow. The code set in
+// ExtraData takes precedence over the one configured on the operation, which is
+// 0 by default since referral codes are optional on Aave
+func (a *AaveOperation) supplyReferralCode(params TransactionParams) (uint16, error) {
+	v, ok := params.ExtraData["referral_code"]
+	if !ok {
+		return a.referralCode, nil
+	}
+
+	code, err := toUint16(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid referral_code: %w", err)
+	}
+
+	return code, nil
+}
+
+// useATokens reports whether the caller asked to repay from supplied aTokens
+func useATokens(params TransactionParams) bool {
+	v, ok := params.ExtraData["use_atokens"].(bool)
+	return ok && v
+}
+
+// ClearATokenCache drops every cached aToken address so the next lookup
+// goes back to the data provider
+func (l *AaveOperation) ClearATokenCache() {
+	l.aTokenCacheMu.Lock()
+	defer l.aTokenCacheMu.Unlock()
+
+	l.aTokenCache = make(map[common.Address]aTokenCacheEntry)
+}
+
+// getAToken resolves the aToken of the reserve asset, serving it from the cache
+// when a fresh entry exists
 func (l *AaveOperation) getAToken(ctx context.Context, asset common.Address) (common.Address, error) {
+	if l.aTokenCacheTTL <= 0 {
+		aToken, _, _, err := l.getReserveTokens(ctx, asset)
+		return aToken, err
+	}
 
-	calldata, err := l.dataProviderABI.Pack("getReserveTokensAddresses", asset)
+	l.aTokenCacheMu.RLock()
+	entry, ok := l.aTokenCache[asset]
+	l.aTokenCacheMu.RUnlock()
+
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.address, nil
+	}
+
+	addr, _, _, err := l.getReserveTokens(ctx, asset)
 	if err != nil {
 		return common.Address{}, err
 	}
 
+	l.aTokenCacheMu.Lock()
+	l.aTokenCache[asset] = aTokenCacheEntry{
+		address:   addr,
+		expiresAt: time.Now().Add(l.aTokenCacheTTL),
+	}
+	l.aTokenCacheMu.Unlock()
+
+	return addr, nil
+}
+
+// getReserveTokens resolves the aToken, stable debt and variable debt tokens
+// of the reserve asset from the data provider
+func (l *AaveOperation) getReserveTokens(ctx context.Context,
+	asset common.Address) (aToken, stableDebt, variableDebt common.Address, err error) {
+
+	calldata, err := l.dataProviderABI.Pack("getReserveTokensAddresses", asset)
+	if err != nil {
+		return common.Address{}, common.Address{}, common.Address{}, err
+	}
+
+	toContract, err := l.dataProvider()
+	if err != nil {
+		return common.Address{}, common.Address{}, common.Address{}, err
+	}
+
+	result, err := l.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &toContract,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return common.Address{}, common.Address{}, common.Address{}, err
+	}
+
+	err = l.dataProviderABI.UnpackIntoInterface(&[]interface{}{&aToken, &stableDebt, &variableDebt},
+		"getReserveTokensAddresses", result)
+	if err != nil {
+		return common.Address{}, common.Address{}, common.Address{}, err
+	}
+
+	if aToken.Hex() == zeroAddress {
+		return common.Address{}, common.Address{}, common.Address{}, ErrAssetNotSupported
+	}
+
+	return aToken, stableDebt, variableDebt, nil
+}
+
+// dataProvider returns the pool data provider of the deployment
+func (l *AaveOperation) dataProvider() (common.Address, error) {
+
 	var toContract common.Address
 	switch {
 	case IsEth(l.chainID):
@@ -261,76 +898,161 @@ func (l *AaveOperation) getAToken(ctx context.Context, asset common.Address) (co
 
 	case IsBnb(l.chainID):
 		if l.fork == AaveProtocolDeploymentSpark {
-			return common.HexToAddress(""), errors.New("BSC: spark finance is not supported on Aave")
+			return common.Address{}, errors.New("BSC: spark finance is not supported on Aave")
 		}
 
 		toContract = bnbAaveDataProviderContract
 		if l.fork == AaveProtocolDeploymentAvalonFinance {
 			toContract = avalonFinanceDataProviderContract
 		}
+		if l.fork == AaveProtocolDeploymentRadiant {
+			toContract = radiantBnbDataProviderContract
+		}
 	case IsPolygon(l.chainID):
 		toContract = polygonAaveDataProviderContract
+	case IsGnosis(l.chainID):
+		toContract = gnosisAaveDataProviderContract
+	case IsAvalanche(l.chainID):
+		toContract = avalancheAaveDataProviderContract
 	default:
-		return common.HexToAddress(""), errors.New("unsupported chain")
+		return common.Address{}, errors.New("unsupported chain")
 	}
 
-	result, err := l.client.CallContract(ctx, ethereum.CallMsg{
-		To:   &toContract,
-		Data: calldata,
-	}, nil)
+	return toContract, nil
+}
+
+// EstimateGas estimates the gas needed to execute the generated calldata
+func (l *AaveOperation) EstimateGas(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (uint64, error) {
+
+	tx, err := l.BuildTransaction(ctx, chainID, action, params)
 	if err != nil {
-		return common.Address{}, err
+		return 0, err
 	}
 
-	addr := common.Address{}
-	// dummy value we just need to unpack successfully
-	value := common.Address{}
-	err = l.dataProviderABI.UnpackIntoInterface(&[]interface{}{&addr, &value, &value}, "getReserveTokensAddresses", result)
+	return estimateGas(ctx, l.client, params.Sender, tx)
+}
+
+// BuildTransaction generates the calldata for the action together with
+// the contract to call and the value to send
+func (l *AaveOperation) BuildTransaction(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (*Transaction, error) {
+
+	calldata, err := l.GenerateCalldata(ctx, chainID, action, params)
 	if err != nil {
-		return common.Address{}, err
+		return nil, err
 	}
 
-	if addr.Hex() == zeroAddress {
-		return common.Address{}, errors.New("asset not supported")
+	to := l.contractFor(action, params)
+
+	// the delegation is approved on the debt token rather than the pool
+	if action == LoanDelegateCredit {
+		to, err = l.variableDebtToken(ctx, params.Asset)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return addr, nil
+	return &Transaction{
+		To:    to,
+		Data:  calldata,
+		Value: l.CallValue(action, params),
+	}, nil
 }
 
 // Validate checks if the provided parameters are valid for the specified action
 func (l *AaveOperation) Validate(ctx context.Context,
 	chainID *big.Int, action ContractAction, params TransactionParams) error {
 
+	if err := l.deprecation.check(l.GetUniqueKey()); err != nil {
+		return err
+	}
+
 	if err := isAaveChainSupported(l.chainID, l.fork); err != nil {
 		return err
 	}
 
+	if err := l.network.ensure(ctx, l.client, l.chainID); err != nil {
+		return err
+	}
+
+	if !l.IsSupportedAction(action) {
+		return ErrUnsupportedAction
+	}
+
+	// the category applies to the whole account rather than an asset
+	if action == LoanSetEMode {
+		return l.validateEMode(ctx, params)
+	}
+
 	if !l.IsSupportedAsset(ctx, l.chainID, params.Asset) {
-		return fmt.Errorf("asset not supported %s", params.Asset)
+		return fmt.Errorf("%w %s", ErrAssetNotSupported, params.Asset)
 	}
 
-	if action != LoanSupply && action != LoanWithdraw {
-		return errors.New("unsupported action")
+	if action == LoanSetCollateral {
+		return l.validateCollateral(ctx, params)
 	}
 
-	if params.Amount.Cmp(big.NewInt(0)) <= 0 {
-		return errors.New("amount must be greater than zero")
+	// repaying the loan is enforced on chain within the transaction
+	if action == LoanFlashLoan {
+		return l.validateFlashLoan(params)
 	}
 
-	if action == LoanSupply {
-		return nil
+	if action == LoanDelegateCredit {
+		return l.validateDelegation(params)
 	}
 
-	_, balance, err := l.GetBalance(ctx, l.chainID, params.Sender, params.Asset)
-	if err != nil {
+	if action != LoanSupply && action != LoanWithdraw && action != LoanBorrow && action != LoanRepay {
+		return ErrUnsupportedAction
+	}
+
+	if action == LoanRepay && !useATokens(params) {
+		return errors.New("repay is only supported from aTokens. set use_atokens")
+	}
+
+	if err := params.ValidateAmount(); err != nil {
 		return err
 	}
 
-	if balance.Cmp(params.Amount) == -1 {
-		return errors.New("balance not enough")
+	if err := checkAmountPrecision(ctx, l.client, l.metadata, l.chainID, params, params.Asset); err != nil {
+		return err
 	}
 
-	return nil
+	if action == LoanSupply {
+		// the gateway wraps the native token sent along
+		if IsNativeToken(params.Asset) {
+			return nil
+		}
+
+		if err := l.checkSupplyCap(ctx, params.Asset, params.Amount); err != nil {
+			return err
+		}
+
+		return checkAllowance(ctx, l.client, params, params.Asset, l.contract, params.Amount)
+	}
+
+	// the borrowed amount is only bounded by the collateral of the debtor
+	if action == LoanBorrow {
+		return l.checkHealthFactor(ctx, action, params)
+	}
+
+	if !params.lenientValidation(ValidationStrict) {
+		_, balance, err := l.GetBalance(ctx, l.chainID, params.Sender, params.Asset)
+		if err != nil {
+			return err
+		}
+
+		if balance.Cmp(params.Amount) == -1 {
+			return ErrInsufficientBalance
+		}
+	}
+
+	// burning aTokens to repay lowers the debt as much as the collateral
+	if action == LoanRepay {
+		return nil
+	}
+
+	return l.checkHealthFactor(ctx, action, params)
 }
 
 // GetBalance retrieves the balance for a specified account and asset
@@ -349,7 +1071,7 @@ func (l *AaveOperation) GetBalance(ctx context.Context,
 		return address, nil, err
 	}
 
-	aToken, err := l.getAToken(ctx, asset)
+	aToken, err := l.getAToken(ctx, l.reserveAsset(asset))
 	if err != nil {
 		return address, nil, err
 	}
@@ -374,25 +1096,10 @@ func (l *AaveOperation) GetSupportedAssets(ctx context.Context, chainID *big.Int
 		return []common.Address{}, err
 	}
 
-	var protocol string
+	assets := l.supportedAssets(ctx)
 
-	switch l.fork {
-	case AaveProtocolDeploymentEthereum:
-		protocol = AaveV3
-	case AaveProtocolDeploymentAvalonFinance:
-		protocol = AvalonFinance
-	case AaveProtocolDeploymentSpark:
-		protocol = SparkLend
-	case AaveProtocolDeploymentPolygon:
-		protocol = AaveV3
-	default:
-		protocol = AaveV3
-	}
-
-	assets := make([]common.Address, 0, len(tokenSupportedMap[l.chainID.Int64()][protocol]))
-
-	for _, v := range tokenSupportedMap[l.chainID.Int64()][protocol] {
-		assets = append(assets, common.HexToAddress(v))
+	if _, ok := l.wrappedTokenGateway(); ok {
+		assets = append(assets, common.HexToAddress(nativeDenomAddress))
 	}
 
 	return assets, nil
@@ -404,40 +1111,12 @@ func (l *AaveOperation) IsSupportedAsset(ctx context.Context, chainID *big.Int,
 		return false
 	}
 
-	protocols, ok := tokenSupportedMap[l.chainID.Int64()]
-	if !ok {
-		return false
-	}
-
-	var protocol string
-
-	switch l.fork {
-	case AaveProtocolDeploymentEthereum:
-		protocol = AaveV3
-	case AaveProtocolDeploymentAvalonFinance:
-		protocol = AvalonFinance
-	case AaveProtocolDeploymentSpark:
-		protocol = SparkLend
-	default:
-		protocol = AaveV3
+	if IsNativeToken(asset) {
+		_, ok := l.wrappedTokenGateway()
+		return ok
 	}
 
-	addrs, ok := protocols[protocol]
-	if !ok {
-		return false
-	}
-
-	if len(addrs) == 0 {
-		return false
-	}
-
-	for _, addr := range addrs {
-		if strings.EqualFold(strings.ToLower(asset.Hex()), strings.ToLower(addr)) {
-			return true
-		}
-	}
-
-	return false
+	return containsAddress(l.supportedAssets(ctx), asset)
 }
 
 // GetProtocolConfig returns the protocol config for a specific chain
@@ -456,9 +1135,36 @@ func (l *AaveOperation) GetABI(chainID *big.Int) abi.ABI { return l.parsedABI }
 // GetType returns the protocol type
 func (l *AaveOperation) GetType() ProtocolType { return TypeLoan }
 
-// GetContractAddress returns the contract address for a specific chain
+// GetContractAddress returns the pool address for a specific chain.
+// Native token actions target the wrapped token gateway instead, use
+// BuildTransaction to get the right contract for a given action
 func (l *AaveOperation) GetContractAddress(chainID *big.Int) common.Address { return l.contract }
 
+// GetContractAddressForAction returns the contract the calldata of action is sent
+// to for ERC20 assets, the pool. Native token supplies and withdrawals go through
+// the wrapped token gateway instead, see GetGatewayAddress. Credit delegations
+// are approved on the debt token of the asset, which only BuildTransaction resolves
+func (l *AaveOperation) GetContractAddressForAction(chainID *big.Int,
+	action ContractAction) (common.Address, error) {
+
+	if !l.IsSupportedAction(action) {
+		return common.Address{}, ErrUnsupportedAction
+	}
+
+	if action == LoanDelegateCredit {
+		return common.Address{}, errors.New("the debt token receiving the delegation depends on the asset, use BuildTransaction")
+	}
+
+	return l.contract, nil
+}
+
+// GetGatewayAddress returns the wrapped token gateway native token supplies and
+// withdrawals are sent to. It reports false on deployments without a gateway
+func (l *AaveOperation) GetGatewayAddress(chainID *big.Int) (common.Address, bool) {
+	gw, ok := l.wrappedTokenGateway()
+	return gw.gateway, ok
+}
+
 // Name returns the human readable name for the protocol
 func (l *AaveOperation) GetName() string {
 
@@ -467,6 +1173,8 @@ func (l *AaveOperation) GetName() string {
 		return SparkLend
 	case AaveProtocolDeploymentAvalonFinance:
 		return AvalonFinance
+	case AaveProtocolDeploymentRadiant:
+		return Radiant
 
 	default:
 		return AaveV3
@@ -475,3 +1183,53 @@ func (l *AaveOperation) GetName() string {
 
 // GetVersion returns the version of the protocol
 func (l *AaveOperation) GetVersion() string { return l.version }
+
+// GetUniqueKey returns the key identifying the protocol across chains
+func (l *AaveOperation) GetUniqueKey() string { return protocolKey(l.GetName(), l.chainID) }
+
+// CallValue returns the native amount to send along with the calldata.
+// ERC20 reserves are pulled with an allowance so nothing is sent, native
+// supplies go through the payable depositETH of the gateway
+func (l *AaveOperation) CallValue(action ContractAction, params TransactionParams) *big.Int {
+	if action == LoanSupply && IsNativeToken(params.Asset) {
+		return params.nativeCallValue()
+	}
+
+	return big.NewInt(0)
+}
+
+// Capabilities describes the actions, chains and extra data supported by the protocol.
+// V2 pools can neither borrow, repay with aTokens nor use eMode
+func (l *AaveOperation) Capabilities() ProtocolCapabilities {
+	actions := []ContractAction{LoanSupply, LoanWithdraw, LoanBorrow, LoanRepay, LoanSetEMode, LoanSetCollateral,
+		LoanFlashLoan, LoanDelegateCredit}
+	requires := []string{"use_atokens", aaveExtraDataEModeCategory, aaveExtraDataUseAsCollateral,
+		aaveExtraDataFlashLoanReceiver, aaveExtraDataDelegatee}
+
+	if l.isV2() {
+		actions = []ContractAction{LoanSupply, LoanWithdraw, LoanSetCollateral, LoanDelegateCredit}
+		requires = []string{aaveExtraDataUseAsCollateral, aaveExtraDataDelegatee}
+	}
+
+	return ProtocolCapabilities{
+		SupportedActions:  actions,
+		SupportedChains:   []*big.Int{l.chainID},
+		RequiresExtraData: requires,
+	}
+}
+
+// IsSupportedAction reports whether calldata can be generated for action
+func (l *AaveOperation) IsSupportedAction(action ContractAction) bool {
+	return l.Capabilities().Supports(action)
+}
+
+// SetObserver implements Observable
+func (a *AaveOperation) SetObserver(observer Observer) {
+	a.observer = observer
+	a.client = observeClient(a.client, a.GetName(), observer)
+}
+
+// SetAssetMetadata implements AssetMetadataUser
+func (a *AaveOperation) SetAssetMetadata(metadata AssetMetadata) {
+	a.metadata = metadata
+}
@@ -0,0 +1,274 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const aaveOracleABI = `
+[
+  {
+    "inputs": [],
+    "name": "getPriceOracle",
+    "outputs": [
+      {
+        "internalType": "address",
+        "name": "",
+        "type": "address"
+      }
+    ],
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      {
+        "internalType": "address",
+        "name": "asset",
+        "type": "address"
+      }
+    ],
+    "name": "getAssetPrice",
+    "outputs": [
+      {
+        "internalType": "uint256",
+        "name": "",
+        "type": "uint256"
+      }
+    ],
+    "stateMutability": "view",
+    "type": "function"
+  }
+]`
+
+// ErrHealthFactorTooLow is returned when an action would leave the account
+// with a health factor below 1 and open it up to liquidation
+var ErrHealthFactorTooLow = errors.New("action would drop the health factor below 1")
+
+var (
+	// aaveHealthFactorOne is a health factor of 1 expressed with 18 decimals
+	aaveHealthFactorOne = big.NewInt(1e18)
+	aavePercentageScale = big.NewInt(1e4)
+)
+
+// AaveAccountData is the position of an account across every reserve of the pool.
+// Base amounts are denominated in the base currency of the pool oracle,
+// LTV and liquidation threshold are in basis points and
+// the health factor has 18 decimals
+type AaveAccountData struct {
+	TotalCollateralBase         *big.Int
+	TotalDebtBase               *big.Int
+	AvailableBorrowsBase        *big.Int
+	CurrentLiquidationThreshold *big.Int
+	LTV                         *big.Int
+	HealthFactor                *big.Int
+}
+
+// aaveReserveConfiguration is the subset of getReserveConfigurationData we rely on
+type aaveReserveConfiguration struct {
+	Decimals             *big.Int
+	LTV                  *big.Int
+	LiquidationThreshold *big.Int
+}
+
+// GetAccountData fetches the account data of account from the pool
+func (l *AaveOperation) GetAccountData(ctx context.Context, account common.Address) (AaveAccountData, error) {
+
+	var data AaveAccountData
+
+	if err := isAaveChainSupported(l.chainID, l.fork); err != nil {
+		return data, err
+	}
+
+	calldata, err := l.parsedABI.Pack("getUserAccountData", account)
+	if err != nil {
+		return data, err
+	}
+
+	result, err := l.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &l.contract,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return data, err
+	}
+
+	values, err := l.parsedABI.Unpack("getUserAccountData", result)
+	if err != nil {
+		return data, err
+	}
+
+	data.TotalCollateralBase = values[0].(*big.Int)
+	data.TotalDebtBase = values[1].(*big.Int)
+	data.AvailableBorrowsBase = values[2].(*big.Int)
+	data.CurrentLiquidationThreshold = values[3].(*big.Int)
+	data.LTV = values[4].(*big.Int)
+	data.HealthFactor = values[5].(*big.Int)
+
+	return data, nil
+}
+
+// checkHealthFactor makes sure withdrawing or borrowing params.Amount of params.Asset
+// keeps the health factor of the sender at or above 1.
+// Withdrawn assets are assumed to be used as collateral
+func (l *AaveOperation) checkHealthFactor(ctx context.Context,
+	action ContractAction, params TransactionParams) error {
+
+	// borrowed debt is taken by the beneficiary
+	account := params.Sender
+	if action == LoanBorrow {
+		account = params.GetBeneficiaryOwner()
+	}
+
+	data, err := l.GetAccountData(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	if data.TotalDebtBase.Sign() == 0 && action != LoanBorrow {
+		return nil
+	}
+
+	asset := l.reserveAsset(params.Asset)
+
+	config, err := l.getReserveConfiguration(ctx, asset)
+	if err != nil {
+		return err
+	}
+
+	price, err := l.getAssetPrice(ctx, asset)
+	if err != nil {
+		return err
+	}
+
+	value := new(big.Int).Mul(params.Amount, price)
+	value.Quo(value, new(big.Int).Exp(big.NewInt(10), config.Decimals, nil))
+
+	// sum of every collateral weighted by its liquidation threshold
+	weightedCollateral := new(big.Int).Mul(data.TotalCollateralBase, data.CurrentLiquidationThreshold)
+	debt := new(big.Int).Set(data.TotalDebtBase)
+
+	switch action {
+	case LoanWithdraw:
+		weightedCollateral.Sub(weightedCollateral, new(big.Int).Mul(value, config.LiquidationThreshold))
+	case LoanBorrow:
+		debt.Add(debt, value)
+	default:
+		return fmt.Errorf("health factor check not supported for %s", action)
+	}
+
+	if debt.Sign() == 0 {
+		return nil
+	}
+
+	if weightedCollateral.Sign() <= 0 {
+		return ErrHealthFactorTooLow
+	}
+
+	healthFactor := new(big.Int).Mul(weightedCollateral, aaveHealthFactorOne)
+	healthFactor.Quo(healthFactor, new(big.Int).Mul(debt, aavePercentageScale))
+
+	if healthFactor.Cmp(aaveHealthFactorOne) < 0 {
+		return ErrHealthFactorTooLow
+	}
+
+	return nil
+}
+
+func (l *AaveOperation) getReserveConfiguration(ctx context.Context,
+	asset common.Address) (aaveReserveConfiguration, error) {
+
+	var config aaveReserveConfiguration
+
+	calldata, err := l.dataProviderABI.Pack("getReserveConfigurationData", asset)
+	if err != nil {
+		return config, err
+	}
+
+	dataProvider, err := l.dataProvider()
+	if err != nil {
+		return config, err
+	}
+
+	result, err := l.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &dataProvider,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return config, err
+	}
+
+	values, err := l.dataProviderABI.Unpack("getReserveConfigurationData", result)
+	if err != nil {
+		return config, err
+	}
+
+	config.Decimals = values[0].(*big.Int)
+	config.LTV = values[1].(*big.Int)
+	config.LiquidationThreshold = values[2].(*big.Int)
+
+	return config, nil
+}
+
+// getAssetPrice reads the price of asset in the base currency from the oracle
+// registered in the pool addresses provider
+func (l *AaveOperation) getAssetPrice(ctx context.Context, asset common.Address) (*big.Int, error) {
+
+	calldata, err := l.parsedABI.Pack(l.poolMethod("ADDRESSES_PROVIDER"))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := l.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &l.contract,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var provider common.Address
+	if err := l.parsedABI.UnpackIntoInterface(&provider, l.poolMethod("ADDRESSES_PROVIDER"), result); err != nil {
+		return nil, err
+	}
+
+	calldata, err = l.oracleABI.Pack("getPriceOracle")
+	if err != nil {
+		return nil, err
+	}
+
+	result, err = l.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &provider,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var oracle common.Address
+	if err := l.oracleABI.UnpackIntoInterface(&oracle, "getPriceOracle", result); err != nil {
+		return nil, err
+	}
+
+	calldata, err = l.oracleABI.Pack("getAssetPrice", asset)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err = l.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &oracle,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	price := new(big.Int)
+	err = l.oracleABI.UnpackIntoInterface(&price, "getAssetPrice", result)
+	return price, err
+}
@@ -0,0 +1,248 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testUSDC             = common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	testAddressProvider  = common.HexToAddress("0x2f39d218133AFaB8F2B819B1066c7E434Ad94E9e")
+	testAaveOracle       = common.HexToAddress("0x54586bE62E3c3580375aE3723C145253060Ca0C2")
+	testAaveUSDCATokenV3 = common.HexToAddress("0x98C23E9d8f34FEFb1B7BD6a91B7FF122F4e16F5c")
+)
+
+// newLeveragedAaveOperation mocks an account holding 1000 USD of USDC collateral
+// against debt worth debtBase, with a liquidation threshold of 80%
+func newLeveragedAaveOperation(t *testing.T, debtBase *big.Int) *AaveOperation {
+	t.Helper()
+
+	client := pkgtest.NewClient(EthChainID)
+
+	aave, err := NewAaveOperation(client, EthChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	usd := func(v int64) *big.Int { return new(big.Int).Mul(big.NewInt(v), big.NewInt(1e8)) }
+
+	method := aave.parsedABI.Methods["getUserAccountData"]
+	client.HandleContract(AaveEthereumV3ContractAddress, method.ID, pkgtest.Returns(method,
+		usd(1000), debtBase, usd(0), big.NewInt(8000), big.NewInt(7500), big.NewInt(0)))
+
+	method = aave.parsedABI.Methods["ADDRESSES_PROVIDER"]
+	client.HandleContract(AaveEthereumV3ContractAddress, method.ID, pkgtest.Returns(method, testAddressProvider))
+
+	method = aave.oracleABI.Methods["getPriceOracle"]
+	client.HandleContract(testAddressProvider, method.ID, pkgtest.Returns(method, testAaveOracle))
+
+	method = aave.oracleABI.Methods["getAssetPrice"]
+	client.HandleContract(testAaveOracle, method.ID, pkgtest.Returns(method, big.NewInt(1e8)))
+
+	method = aave.dataProviderABI.Methods["getReserveConfigurationData"]
+	client.HandleContract(ethAaveDataProviderContract, method.ID, pkgtest.Returns(method,
+		big.NewInt(6), big.NewInt(7500), big.NewInt(8000), big.NewInt(10500), big.NewInt(1000),
+		true, true, false, true, false))
+
+	method = aave.dataProviderABI.Methods["getReserveTokensAddresses"]
+	client.HandleContract(ethAaveDataProviderContract, method.ID, pkgtest.Returns(method,
+		testAaveUSDCATokenV3, common.Address{}, common.Address{}))
+
+	// no caps
+	method = aave.dataProviderABI.Methods["getReserveCaps"]
+	client.HandleContract(ethAaveDataProviderContract, method.ID, pkgtest.Returns(method,
+		big.NewInt(0), big.NewInt(0)))
+
+	method = aave.erc20ABI.Methods["balanceOf"]
+	client.HandleContract(testAaveUSDCATokenV3, method.ID, pkgtest.Returns(method, big.NewInt(1000e6)))
+
+	return aave
+}
+
+func TestAave_GetAccountData_Unit(t *testing.T) {
+	debt := new(big.Int).Mul(big.NewInt(500), big.NewInt(1e8))
+	aave := newLeveragedAaveOperation(t, debt)
+
+	data, err := aave.GetAccountData(context.Background(), testAccount)
+	require.NoError(t, err)
+
+	require.Equal(t, debt, data.TotalDebtBase)
+	require.EqualValues(t, 8000, data.CurrentLiquidationThreshold.Int64())
+	require.EqualValues(t, 7500, data.LTV.Int64())
+}
+
+func TestAave_Validate_HealthFactor_Unit(t *testing.T) {
+
+	withdraw := func(aave *AaveOperation, amount int64) error {
+		return aave.Validate(context.Background(), EthChainID, LoanWithdraw, TransactionParams{
+			Amount: big.NewInt(amount),
+			Sender: testAccount,
+			Asset:  testUSDC,
+		})
+	}
+
+	t.Run("no debt allows withdrawing everything", func(t *testing.T) {
+		aave := newLeveragedAaveOperation(t, big.NewInt(0))
+		require.NoError(t, withdraw(aave, 1000e6))
+	})
+
+	t.Run("withdrawal keeps the health factor above 1", func(t *testing.T) {
+		// (1000 - 300) * 0.8 / 500 = 1.12
+		aave := newLeveragedAaveOperation(t, new(big.Int).Mul(big.NewInt(500), big.NewInt(1e8)))
+		require.NoError(t, withdraw(aave, 300e6))
+	})
+
+	t.Run("withdrawal drops the health factor below 1", func(t *testing.T) {
+		// (1000 - 400) * 0.8 / 500 = 0.96
+		aave := newLeveragedAaveOperation(t, new(big.Int).Mul(big.NewInt(500), big.NewInt(1e8)))
+		require.ErrorIs(t, withdraw(aave, 400e6), ErrHealthFactorTooLow)
+	})
+}
+
+func TestAave_Borrow_Unit(t *testing.T) {
+
+	borrow := func(amount int64) TransactionParams {
+		return TransactionParams{
+			Amount: big.NewInt(amount),
+			Sender: testAccount,
+			Asset:  testUSDC,
+		}
+	}
+
+	t.Run("calldata", func(t *testing.T) {
+		aave := newLeveragedAaveOperation(t, big.NewInt(0))
+
+		tx, err := aave.BuildTransaction(context.Background(), EthChainID, LoanBorrow, borrow(1e6))
+		require.NoError(t, err)
+
+		// cast calldata "borrow(address,uint256,uint256,uint16,address)" 0xA0b8...eB48 1000000 2 0 0x6a22...d007
+		expected := "0xa415bcad" +
+			"000000000000000000000000a0b86991c6218b36c1d19d4a2e9eb0ce3606eb48" + // asset
+			"00000000000000000000000000000000000000000000000000000000000f4240" + // amount
+			"0000000000000000000000000000000000000000000000000000000000000002" + // interestRateMode
+			"0000000000000000000000000000000000000000000000000000000000000000" + // referralCode
+			"0000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d007" // onBehalfOf
+
+		require.Equal(t, AaveEthereumV3ContractAddress, tx.To)
+		require.Equal(t, expected, tx.Data)
+		require.Zero(t, tx.Value.Sign())
+	})
+
+	t.Run("debt taken by the delegator", func(t *testing.T) {
+		aave := newLeveragedAaveOperation(t, big.NewInt(0))
+
+		params := borrow(1e6)
+		params.Recipient = common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+
+		calldata, err := aave.GenerateCalldata(context.Background(), EthChainID, LoanBorrow, params)
+		require.NoError(t, err)
+		require.True(t, strings.HasSuffix(calldata, "000000000000000000000000000000000000000000000000000000000000beef"))
+	})
+
+	t.Run("borrow keeps the health factor at 1", func(t *testing.T) {
+		// 1000 * 0.8 / (500 + 300) = 1
+		aave := newLeveragedAaveOperation(t, new(big.Int).Mul(big.NewInt(500), big.NewInt(1e8)))
+		require.NoError(t, aave.Validate(context.Background(), EthChainID, LoanBorrow, borrow(300e6)))
+		require.ErrorIs(t, aave.Validate(context.Background(), EthChainID, LoanBorrow, borrow(301e6)), ErrHealthFactorTooLow)
+	})
+
+	t.Run("not on V2 pools", func(t *testing.T) {
+		v2, err := NewAaveOperationOffline(BscChainID, AaveProtocolDeploymentAvalonFinance)
+		require.NoError(t, err)
+		require.False(t, v2.IsSupportedAction(LoanBorrow))
+	})
+}
+
+func TestAave_Validate_Errors_Unit(t *testing.T) {
+
+	aave := newLeveragedAaveOperation(t, big.NewInt(0))
+
+	params := TransactionParams{
+		Sender: testAccount,
+		Asset:  testUSDC,
+		Amount: big.NewInt(2000e6),
+	}
+
+	t.Run("insufficient balance", func(t *testing.T) {
+		err := aave.Validate(context.Background(), EthChainID, LoanWithdraw, params)
+		require.ErrorIs(t, err, ErrInsufficientBalance)
+	})
+
+	t.Run("unsupported asset", func(t *testing.T) {
+		unsupported := params
+		unsupported.Asset = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+		err := aave.Validate(context.Background(), EthChainID, LoanSupply, unsupported)
+		require.ErrorIs(t, err, ErrAssetNotSupported)
+	})
+
+	t.Run("unsupported action", func(t *testing.T) {
+		err := aave.Validate(context.Background(), EthChainID, NativeStake, params)
+		require.ErrorIs(t, err, ErrUnsupportedAction)
+	})
+
+	t.Run("zero amount", func(t *testing.T) {
+		zero := params
+		zero.Amount = big.NewInt(0)
+
+		err := aave.Validate(context.Background(), EthChainID, LoanSupply, zero)
+		require.ErrorIs(t, err, ErrAmountTooLow)
+	})
+}
+
+func TestAave_Validate_ValidationMode_Unit(t *testing.T) {
+
+	aave := newLeveragedAaveOperation(t, big.NewInt(0))
+
+	params := func(mode ValidationMode) TransactionParams {
+		return TransactionParams{
+			Sender:    testAccount,
+			Asset:     testUSDC,
+			Amount:    big.NewInt(2000e6),
+			ExtraData: map[string]interface{}{"validation_mode": mode},
+		}
+	}
+
+	t.Run("strict checks the balance", func(t *testing.T) {
+		err := aave.Validate(context.Background(), EthChainID, LoanWithdraw, params(ValidationStrict))
+		require.ErrorIs(t, err, ErrInsufficientBalance)
+	})
+
+	t.Run("lenient skips the balance", func(t *testing.T) {
+		err := aave.Validate(context.Background(), EthChainID, LoanWithdraw, params(ValidationLenient))
+		require.NoError(t, err)
+	})
+
+	t.Run("lenient from a string", func(t *testing.T) {
+		lenient := params(ValidationLenient)
+		lenient.ExtraData["validation_mode"] = "lenient"
+
+		err := aave.Validate(context.Background(), EthChainID, LoanWithdraw, lenient)
+		require.NoError(t, err)
+	})
+
+	t.Run("lenient still checks the asset", func(t *testing.T) {
+		unsupported := params(ValidationLenient)
+		unsupported.Asset = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+		err := aave.Validate(context.Background(), EthChainID, LoanWithdraw, unsupported)
+		require.ErrorIs(t, err, ErrAssetNotSupported)
+	})
+
+	t.Run("lenient still checks the action", func(t *testing.T) {
+		err := aave.Validate(context.Background(), EthChainID, NativeStake, params(ValidationLenient))
+		require.ErrorIs(t, err, ErrUnsupportedAction)
+	})
+
+	t.Run("lenient still checks the amount", func(t *testing.T) {
+		zero := params(ValidationLenient)
+		zero.Amount = big.NewInt(0)
+
+		err := aave.Validate(context.Background(), EthChainID, LoanWithdraw, zero)
+		require.ErrorIs(t, err, ErrAmountTooLow)
+	})
+}
@@ -0,0 +1,85 @@
+package pkg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newCountingAaveOperation(t *testing.T, opts ...AaveOption) (*AaveOperation, *pkgtest.Client, common.Address) {
+	t.Helper()
+
+	aToken := common.HexToAddress("0x98C23E9d8f34FEFb1B7BD6a91B7FF122F4e16F5c")
+	client := pkgtest.NewClient(EthChainID)
+
+	aave, err := NewAaveOperation(client, EthChainID, AaveProtocolDeploymentEthereum, opts...)
+	require.NoError(t, err)
+
+	method := aave.dataProviderABI.Methods["getReserveTokensAddresses"]
+	client.HandleContract(ethAaveDataProviderContract, method.ID, pkgtest.Returns(method,
+		aToken,
+		common.HexToAddress("0x72E95b8931767C79bA4EeE721354d6E99a61D004"),
+		common.HexToAddress("0x6df1C1E379bC5a00a7b4C6e67A203333772f45A8")))
+
+	return aave, client, aToken
+}
+
+func TestAave_GetAToken_Cache(t *testing.T) {
+
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+
+	t.Run("second lookup is served from the cache", func(t *testing.T) {
+		aave, client, expected := newCountingAaveOperation(t)
+
+		for i := 0; i < 2; i++ {
+			aToken, err := aave.getAToken(context.Background(), usdc)
+			require.NoError(t, err)
+			require.Equal(t, expected, aToken)
+		}
+
+		require.Equal(t, 1, client.CallCount())
+	})
+
+	t.Run("clearing the cache forces a new lookup", func(t *testing.T) {
+		aave, client, _ := newCountingAaveOperation(t)
+
+		_, err := aave.getAToken(context.Background(), usdc)
+		require.NoError(t, err)
+
+		aave.ClearATokenCache()
+
+		_, err = aave.getAToken(context.Background(), usdc)
+		require.NoError(t, err)
+
+		require.Equal(t, 2, client.CallCount())
+	})
+
+	t.Run("cache can be disabled", func(t *testing.T) {
+		aave, client, _ := newCountingAaveOperation(t, WithATokenCacheTTL(0))
+
+		for i := 0; i < 2; i++ {
+			_, err := aave.getAToken(context.Background(), usdc)
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, 2, client.CallCount())
+	})
+
+	t.Run("expired entries are refetched", func(t *testing.T) {
+		aave, client, _ := newCountingAaveOperation(t, WithATokenCacheTTL(time.Millisecond))
+
+		_, err := aave.getAToken(context.Background(), usdc)
+		require.NoError(t, err)
+
+		time.Sleep(5 * time.Millisecond)
+
+		_, err = aave.getAToken(context.Background(), usdc)
+		require.NoError(t, err)
+
+		require.Equal(t, 2, client.CallCount())
+	})
+}
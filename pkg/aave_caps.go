@@ -0,0 +1,123 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// GetSupplyCap returns the most of asset the pool can hold, in the decimals of
+// the asset. A nil cap means supplies are not capped
+func (l *AaveOperation) GetSupplyCap(ctx context.Context, asset common.Address) (*big.Int, error) {
+	_, supplyCap, err := l.getReserveCaps(ctx, asset)
+	return supplyCap, err
+}
+
+// GetBorrowCap returns the most of asset that can be borrowed from the pool, in
+// the decimals of the asset. A nil cap means borrows are not capped
+func (l *AaveOperation) GetBorrowCap(ctx context.Context, asset common.Address) (*big.Int, error) {
+	borrowCap, _, err := l.getReserveCaps(ctx, asset)
+	return borrowCap, err
+}
+
+// getReserveCaps reads the caps of the reserve from the data provider. They are
+// configured in whole tokens so they are scaled by the decimals of the asset.
+// V2 pools have no caps
+func (l *AaveOperation) getReserveCaps(ctx context.Context,
+	asset common.Address) (borrowCap, supplyCap *big.Int, err error) {
+
+	if err := isAaveChainSupported(l.chainID, l.fork); err != nil {
+		return nil, nil, err
+	}
+
+	if l.isV2() {
+		return nil, nil, nil
+	}
+
+	values, err := l.callDataProvider(ctx, "getReserveCaps", asset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config, err := l.getReserveConfiguration(ctx, asset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unit := new(big.Int).Exp(big.NewInt(10), config.Decimals, nil)
+
+	scale := func(v interface{}) *big.Int {
+		c := v.(*big.Int)
+		if c.Sign() == 0 {
+			return nil
+		}
+		return new(big.Int).Mul(c, unit)
+	}
+
+	return scale(values[0]), scale(values[1]), nil
+}
+
+// checkSupplyCap returns a *SupplyCapError when supplying amount of asset would
+// take the aToken supply of the reserve over its supply cap
+func (l *AaveOperation) checkSupplyCap(ctx context.Context, asset common.Address, amount *big.Int) error {
+
+	supplyCap, err := l.GetSupplyCap(ctx, asset)
+	if err != nil {
+		return fmt.Errorf("could not fetch the supply cap of %s: %w", asset, err)
+	}
+
+	if supplyCap == nil {
+		return nil
+	}
+
+	values, err := l.callDataProvider(ctx, "getATokenTotalSupply", asset)
+	if err != nil {
+		return fmt.Errorf("could not fetch the total supply of %s: %w", asset, err)
+	}
+
+	totalSupply := values[0].(*big.Int)
+
+	remaining := new(big.Int).Sub(supplyCap, totalSupply)
+	if remaining.Sign() < 0 {
+		remaining.SetInt64(0)
+	}
+
+	if amount.Cmp(remaining) > 0 {
+		return &SupplyCapError{
+			Asset:       asset,
+			SupplyCap:   supplyCap,
+			TotalSupply: totalSupply,
+			Remaining:   remaining,
+		}
+	}
+
+	return nil
+}
+
+// callDataProvider calls the view method of the data provider of the deployment
+func (l *AaveOperation) callDataProvider(ctx context.Context, method string,
+	args ...interface{}) ([]interface{}, error) {
+
+	calldata, err := l.dataProviderABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	dataProvider, err := l.dataProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := l.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &dataProvider,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.dataProviderABI.Unpack(method, result)
+}
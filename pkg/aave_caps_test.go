@@ -0,0 +1,62 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAave_Caps_Unit(t *testing.T) {
+
+	aave := newLeveragedAaveOperation(t, big.NewInt(0))
+	client := aave.client.(*pkgtest.Client)
+
+	// 1000 USDC can be supplied and 500 borrowed, 990 are supplied already
+	method := aave.dataProviderABI.Methods["getReserveCaps"]
+	client.HandleContract(ethAaveDataProviderContract, method.ID, pkgtest.Returns(method,
+		big.NewInt(500), big.NewInt(1000)))
+
+	method = aave.dataProviderABI.Methods["getATokenTotalSupply"]
+	client.HandleContract(ethAaveDataProviderContract, method.ID, pkgtest.Returns(method,
+		big.NewInt(990e6)))
+
+	supplyCap, err := aave.GetSupplyCap(context.Background(), testUSDC)
+	require.NoError(t, err)
+	require.Zero(t, supplyCap.Cmp(big.NewInt(1000e6)))
+
+	borrowCap, err := aave.GetBorrowCap(context.Background(), testUSDC)
+	require.NoError(t, err)
+	require.Zero(t, borrowCap.Cmp(big.NewInt(500e6)))
+
+	supply := func(amount int64) error {
+		return aave.Validate(context.Background(), EthChainID, LoanSupply, TransactionParams{
+			Amount: big.NewInt(amount),
+			Sender: testAccount,
+			Asset:  testUSDC,
+		})
+	}
+
+	require.NoError(t, supply(10e6))
+
+	err = supply(10e6 + 1)
+	require.ErrorIs(t, err, ErrSupplyCapExceeded)
+
+	var capErr *SupplyCapError
+	require.True(t, errors.As(err, &capErr))
+	require.Zero(t, capErr.Remaining.Cmp(big.NewInt(10e6)))
+
+	// a zero cap disables it
+	method = aave.dataProviderABI.Methods["getReserveCaps"]
+	client.HandleContract(ethAaveDataProviderContract, method.ID, pkgtest.Returns(method,
+		big.NewInt(0), big.NewInt(0)))
+
+	supplyCap, err = aave.GetSupplyCap(context.Background(), testUSDC)
+	require.NoError(t, err)
+	require.Nil(t, supplyCap)
+
+	require.NoError(t, supply(1e12))
+}
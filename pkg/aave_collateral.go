@@ -0,0 +1,71 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// aaveExtraDataUseAsCollateral is the ExtraData key selecting whether the
+// supplied asset is enabled or disabled as collateral
+const aaveExtraDataUseAsCollateral = "use_as_collateral"
+
+// useAsCollateral returns the collateral flag set in ExtraData
+func useAsCollateral(params TransactionParams) (bool, error) {
+
+	v, ok := params.ExtraData[aaveExtraDataUseAsCollateral]
+	if !ok {
+		return false, errors.New("use_as_collateral must be provided in extra data")
+	}
+
+	enable, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("invalid use_as_collateral: %T is not a bool", v)
+	}
+
+	return enable, nil
+}
+
+// generateCollateralCalldata packs setUserUseReserveAsCollateral. The native
+// token maps to the wrapped reserve it is supplied as
+func (l *AaveOperation) generateCollateralCalldata(params TransactionParams) (string, error) {
+
+	enable, err := useAsCollateral(params)
+	if err != nil {
+		return "", err
+	}
+
+	calldata, err := l.parsedABI.Pack("setUserUseReserveAsCollateral",
+		l.reserveAsset(params.Asset), enable)
+	if err != nil {
+		return "", err
+	}
+
+	return HexPrefix + hex.EncodeToString(calldata), nil
+}
+
+// validateCollateral makes sure the sender has supplied the asset before it
+// is enabled as collateral
+func (l *AaveOperation) validateCollateral(ctx context.Context, params TransactionParams) error {
+
+	enable, err := useAsCollateral(params)
+	if err != nil {
+		return err
+	}
+
+	if !enable {
+		return nil
+	}
+
+	_, balance, err := l.GetBalance(ctx, l.chainID, params.Sender, params.Asset)
+	if err != nil {
+		return err
+	}
+
+	if balance.Sign() == 0 {
+		return fmt.Errorf("asset %s is not supplied", params.Asset)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAave_SetCollateral_Unit(t *testing.T) {
+
+	newAave := func(t *testing.T, supplied *big.Int) *AaveOperation {
+		client := pkgtest.NewClient(EthChainID)
+
+		aave, err := NewAaveOperation(client, EthChainID, AaveProtocolDeploymentEthereum)
+		require.NoError(t, err)
+
+		method := aave.dataProviderABI.Methods["getReserveTokensAddresses"]
+		client.HandleContract(ethAaveDataProviderContract, method.ID, pkgtest.Returns(method,
+			testAaveUSDCATokenV3, common.Address{}, common.Address{}))
+
+		method = aave.erc20ABI.Methods["balanceOf"]
+		client.HandleContract(testAaveUSDCATokenV3, method.ID, pkgtest.Returns(method, supplied))
+
+		return aave
+	}
+
+	collateral := func(v interface{}) TransactionParams {
+		return TransactionParams{
+			Sender:    testAccount,
+			Asset:     testUSDC,
+			ExtraData: map[string]interface{}{"use_as_collateral": v},
+		}
+	}
+
+	t.Run("calldata", func(t *testing.T) {
+		aave := newAave(t, big.NewInt(0))
+
+		tx, err := aave.BuildTransaction(context.Background(), EthChainID, LoanSetCollateral, collateral(true))
+		require.NoError(t, err)
+
+		// cast calldata "setUserUseReserveAsCollateral(address,bool)" 0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48 true
+		require.Equal(t, "0x5a3b74b9000000000000000000000000a0b86991c6218b36c1d19d4a2e9eb0ce3606eb480000000000000000000000000000000000000000000000000000000000000001", tx.Data)
+		require.Equal(t, AaveEthereumV3ContractAddress, tx.To)
+		require.Zero(t, tx.Value.Sign())
+	})
+
+	t.Run("native token toggles the wrapped reserve on the pool", func(t *testing.T) {
+		aave := newAave(t, big.NewInt(0))
+
+		params := collateral(false)
+		params.Asset = common.HexToAddress(nativeDenomAddress)
+
+		tx, err := aave.BuildTransaction(context.Background(), EthChainID, LoanSetCollateral, params)
+		require.NoError(t, err)
+
+		// cast calldata "setUserUseReserveAsCollateral(address,bool)" 0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2 false
+		expected := "0x5a3b74b9" +
+			"000000000000000000000000c02aaa39b223fe8d0a0e5c4f27ead9083c756cc2" + // asset
+			"0000000000000000000000000000000000000000000000000000000000000000" // useAsCollateral
+
+		require.Equal(t, expected, tx.Data)
+		require.Equal(t, AaveEthereumV3ContractAddress, tx.To)
+	})
+
+	t.Run("enabling requires a supplied balance", func(t *testing.T) {
+		err := newAave(t, big.NewInt(0)).Validate(context.Background(), EthChainID, LoanSetCollateral, collateral(true))
+		require.Error(t, err)
+
+		err = newAave(t, big.NewInt(1e6)).Validate(context.Background(), EthChainID, LoanSetCollateral, collateral(true))
+		require.NoError(t, err)
+	})
+
+	t.Run("disabling does not read balances", func(t *testing.T) {
+		err := newAave(t, big.NewInt(0)).Validate(context.Background(), EthChainID, LoanSetCollateral, collateral(false))
+		require.NoError(t, err)
+	})
+
+	t.Run("flag must be a bool", func(t *testing.T) {
+		aave := newAave(t, big.NewInt(1e6))
+
+		_, err := aave.GenerateCalldata(context.Background(), EthChainID, LoanSetCollateral, collateral("yes"))
+		require.Error(t, err)
+
+		_, err = aave.GenerateCalldata(context.Background(), EthChainID, LoanSetCollateral, TransactionParams{Asset: testUSDC})
+		require.Error(t, err)
+	})
+}
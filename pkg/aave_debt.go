@@ -0,0 +1,63 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// GetDebtBalance retrieves the variable and stable debt account owes on the
+// reserve of asset. Reserves without a stable debt token report a zero stable debt
+func (l *AaveOperation) GetDebtBalance(ctx context.Context,
+	account, asset common.Address) (variable, stable *big.Int, err error) {
+
+	if err := isAaveChainSupported(l.chainID, l.fork); err != nil {
+		return nil, nil, err
+	}
+
+	_, stableDebtToken, variableDebtToken, err := l.getReserveTokens(ctx, l.reserveAsset(asset))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	variable, err = l.debtTokenBalance(ctx, variableDebtToken, account)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stable, err = l.debtTokenBalance(ctx, stableDebtToken, account)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return variable, stable, nil
+}
+
+// debtTokenBalance reads the balance of account in debtToken, debt tokens
+// being scaled up with the accrued interest
+func (l *AaveOperation) debtTokenBalance(ctx context.Context,
+	debtToken, account common.Address) (*big.Int, error) {
+
+	if debtToken == (common.Address{}) {
+		return big.NewInt(0), nil
+	}
+
+	calldata, err := l.erc20ABI.Pack("balanceOf", account)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := l.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &debtToken,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := new(big.Int)
+	err = l.erc20ABI.UnpackIntoInterface(&balance, "balanceOf", result)
+	return balance, err
+}
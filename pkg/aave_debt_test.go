@@ -0,0 +1,66 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAave_GetDebtBalance_Unit(t *testing.T) {
+
+	var (
+		stableDebtToken   = common.HexToAddress("0xB0fe3D292f4bd50De902Ba5bDF120Ad66E9d7a39")
+		variableDebtToken = common.HexToAddress("0x72E95b8931767C79bA4EeE721354d6E99a61D004")
+	)
+
+	newAave := func(t *testing.T, stable common.Address) *AaveOperation {
+		client := pkgtest.NewClient(EthChainID)
+
+		aave, err := NewAaveOperation(client, EthChainID, AaveProtocolDeploymentEthereum)
+		require.NoError(t, err)
+
+		method := aave.dataProviderABI.Methods["getReserveTokensAddresses"]
+		client.HandleContract(ethAaveDataProviderContract, method.ID, pkgtest.Returns(method,
+			testAaveUSDCATokenV3, stable, variableDebtToken))
+
+		method = aave.erc20ABI.Methods["balanceOf"]
+		client.HandleContract(variableDebtToken, method.ID, pkgtest.Returns(method, big.NewInt(250e6)))
+		client.HandleContract(stableDebtToken, method.ID, pkgtest.Returns(method, big.NewInt(10e6)))
+
+		return aave
+	}
+
+	t.Run("variable and stable debt", func(t *testing.T) {
+		variable, stable, err := newAave(t, stableDebtToken).GetDebtBalance(context.Background(), testAccount, testUSDC)
+		require.NoError(t, err)
+
+		require.Equal(t, big.NewInt(250e6), variable)
+		require.Equal(t, big.NewInt(10e6), stable)
+	})
+
+	t.Run("reserve without stable debt token", func(t *testing.T) {
+		variable, stable, err := newAave(t, common.Address{}).GetDebtBalance(context.Background(), testAccount, testUSDC)
+		require.NoError(t, err)
+
+		require.Equal(t, big.NewInt(250e6), variable)
+		require.Zero(t, stable.Sign())
+	})
+
+	t.Run("unsupported asset", func(t *testing.T) {
+		client := pkgtest.NewClient(EthChainID)
+
+		aave, err := NewAaveOperation(client, EthChainID, AaveProtocolDeploymentEthereum)
+		require.NoError(t, err)
+
+		method := aave.dataProviderABI.Methods["getReserveTokensAddresses"]
+		client.HandleContract(ethAaveDataProviderContract, method.ID, pkgtest.Returns(method,
+			common.Address{}, common.Address{}, common.Address{}))
+
+		_, _, err = aave.GetDebtBalance(context.Background(), testAccount, testUSDC)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,98 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// aaveExtraDataDelegatee is the ExtraData key of the account allowed to
+// borrow on behalf of the sender
+const aaveExtraDataDelegatee = "delegatee"
+
+const aaveDebtTokenABI = `
+[
+  {
+    "inputs": [
+      { "internalType": "address", "name": "delegatee", "type": "address" },
+      { "internalType": "uint256", "name": "amount", "type": "uint256" }
+    ],
+    "name": "approveDelegation",
+    "outputs": [],
+    "stateMutability": "nonpayable",
+    "type": "function"
+  }
+]`
+
+// creditDelegatee returns the delegatee set in ExtraData
+func creditDelegatee(params TransactionParams) (common.Address, error) {
+
+	v, ok := params.ExtraData[aaveExtraDataDelegatee]
+	if !ok {
+		return common.Address{}, errors.New("delegatee must be provided in extra data")
+	}
+
+	delegatee, err := toAddress(v)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid delegatee: %w", err)
+	}
+
+	if delegatee == (common.Address{}) {
+		return common.Address{}, errors.New("delegatee can not be the zero address")
+	}
+
+	return delegatee, nil
+}
+
+// generateDelegationCalldata packs approveDelegation, letting the delegatee
+// borrow up to params.Amount of the asset against the collateral of the sender.
+// It must be sent to the variable debt token of the asset
+func (l *AaveOperation) generateDelegationCalldata(params TransactionParams) (string, error) {
+
+	delegatee, err := creditDelegatee(params)
+	if err != nil {
+		return "", err
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(aaveDebtTokenABI))
+	if err != nil {
+		return "", err
+	}
+
+	calldata, err := parsedABI.Pack("approveDelegation", delegatee, params.Amount)
+	if err != nil {
+		return "", err
+	}
+
+	return HexPrefix + hex.EncodeToString(calldata), nil
+}
+
+// validateDelegation checks the amount and the delegatee of the credit delegation
+func (l *AaveOperation) validateDelegation(params TransactionParams) error {
+
+	if err := params.ValidateAmount(); err != nil {
+		return err
+	}
+
+	delegatee, err := creditDelegatee(params)
+	if err != nil {
+		return err
+	}
+
+	if delegatee == params.Sender {
+		return errors.New("delegatee can not be the sender")
+	}
+
+	return nil
+}
+
+// variableDebtToken returns the variable debt token of the reserve of asset
+func (l *AaveOperation) variableDebtToken(ctx context.Context, asset common.Address) (common.Address, error) {
+	_, _, variableDebt, err := l.getReserveTokens(ctx, l.reserveAsset(asset))
+	return variableDebt, err
+}
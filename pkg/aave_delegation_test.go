@@ -0,0 +1,70 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAave_DelegateCredit_Unit(t *testing.T) {
+
+	client := pkgtest.NewClient(EthChainID)
+
+	aave, err := NewAaveOperation(client, EthChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	delegatee := common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+	variableDebt := common.HexToAddress("0x72E95b8931767C79bA4EeE721354d6E99a61D004")
+
+	method := aave.dataProviderABI.Methods["getReserveTokensAddresses"]
+	client.HandleContract(ethAaveDataProviderContract, method.ID, pkgtest.Returns(method,
+		testAaveUSDCATokenV3, common.Address{}, variableDebt))
+
+	delegate := func(extraData map[string]interface{}) TransactionParams {
+		return TransactionParams{
+			Sender:    testAccount,
+			Asset:     testUSDC,
+			Amount:    big.NewInt(1000e6),
+			ExtraData: extraData,
+		}
+	}
+
+	t.Run("calldata", func(t *testing.T) {
+		tx, err := aave.BuildTransaction(context.Background(), EthChainID, LoanDelegateCredit,
+			delegate(map[string]interface{}{"delegatee": delegatee.Hex()}))
+		require.NoError(t, err)
+
+		// cast calldata "approveDelegation(address,uint256)" 0x000000000000000000000000000000000000bEEF 1000000000
+		require.Equal(t, "0xc04a8a10"+
+			"000000000000000000000000000000000000000000000000000000000000beef"+
+			"000000000000000000000000000000000000000000000000000000003b9aca00", tx.Data)
+		require.Equal(t, variableDebt, tx.To)
+		require.Zero(t, tx.Value.Sign())
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		err := aave.Validate(context.Background(), EthChainID, LoanDelegateCredit,
+			delegate(map[string]interface{}{"delegatee": delegatee}))
+		require.NoError(t, err)
+
+		unsupported := delegate(map[string]interface{}{"delegatee": delegatee})
+		unsupported.Asset = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+		err = aave.Validate(context.Background(), EthChainID, LoanDelegateCredit, unsupported)
+		require.ErrorIs(t, err, ErrAssetNotSupported)
+
+		for name, extraData := range map[string]map[string]interface{}{
+			"missing delegatee": {},
+			"zero delegatee":    {"delegatee": common.Address{}},
+			"invalid delegatee": {"delegatee": "0xzz"},
+			"sender delegatee":  {"delegatee": testAccount},
+		} {
+			err := aave.Validate(context.Background(), EthChainID, LoanDelegateCredit, delegate(extraData))
+			require.Error(t, err, name)
+		}
+	})
+}
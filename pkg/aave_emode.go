@@ -0,0 +1,142 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// aaveExtraDataEModeCategory is the ExtraData key holding the eMode category to enter
+const aaveExtraDataEModeCategory = "emode_category"
+
+// aaveEModeCategory is the configuration of an efficiency mode category.
+// LTV, liquidation threshold and bonus are in basis points
+type aaveEModeCategory struct {
+	Ltv                  uint16
+	LiquidationThreshold uint16
+	LiquidationBonus     uint16
+	PriceSource          common.Address
+	Label                string
+}
+
+// GetUserEMode returns the efficiency mode category account is in, 0 when none
+func (l *AaveOperation) GetUserEMode(ctx context.Context, account common.Address) (uint8, error) {
+
+	if err := isAaveChainSupported(l.chainID, l.fork); err != nil {
+		return 0, err
+	}
+
+	calldata, err := l.parsedABI.Pack("getUserEMode", account)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := l.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &l.contract,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	category := new(big.Int)
+	if err := l.parsedABI.UnpackIntoInterface(&category, "getUserEMode", result); err != nil {
+		return 0, err
+	}
+
+	if !category.IsUint64() || category.Uint64() > 255 {
+		return 0, fmt.Errorf("invalid eMode category %s", category)
+	}
+
+	return uint8(category.Uint64()), nil
+}
+
+// getEModeCategory fetches the configuration of the eMode category id
+func (l *AaveOperation) getEModeCategory(ctx context.Context, id uint8) (aaveEModeCategory, error) {
+
+	var category aaveEModeCategory
+
+	calldata, err := l.parsedABI.Pack("getEModeCategoryData", id)
+	if err != nil {
+		return category, err
+	}
+
+	result, err := l.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &l.contract,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return category, err
+	}
+
+	values, err := l.parsedABI.Unpack("getEModeCategoryData", result)
+	if err != nil {
+		return category, err
+	}
+
+	category = *abi.ConvertType(values[0], new(aaveEModeCategory)).(*aaveEModeCategory)
+	return category, nil
+}
+
+// eModeCategory returns the category set in ExtraData
+func eModeCategory(params TransactionParams) (uint8, error) {
+
+	v, ok := params.ExtraData[aaveExtraDataEModeCategory]
+	if !ok {
+		return 0, errors.New("emode_category must be provided in extra data")
+	}
+
+	category, err := toUint8(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid emode_category: %w", err)
+	}
+
+	return category, nil
+}
+
+// generateEModeCalldata packs setUserEMode. Category 0 leaves efficiency mode
+func (l *AaveOperation) generateEModeCalldata(params TransactionParams) (string, error) {
+
+	category, err := eModeCategory(params)
+	if err != nil {
+		return "", err
+	}
+
+	calldata, err := l.parsedABI.Pack("setUserEMode", category)
+	if err != nil {
+		return "", err
+	}
+
+	return HexPrefix + hex.EncodeToString(calldata), nil
+}
+
+// validateEMode makes sure the requested category is defined by the pool.
+// Undefined categories have no liquidation threshold
+func (l *AaveOperation) validateEMode(ctx context.Context, params TransactionParams) error {
+
+	id, err := eModeCategory(params)
+	if err != nil {
+		return err
+	}
+
+	if id == 0 {
+		return nil
+	}
+
+	category, err := l.getEModeCategory(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if category.LiquidationThreshold == 0 {
+		return fmt.Errorf("eMode category %d is not defined", id)
+	}
+
+	return nil
+}
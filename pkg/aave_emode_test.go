@@ -0,0 +1,80 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAave_EMode_Unit(t *testing.T) {
+
+	client := pkgtest.NewClient(EthChainID)
+
+	aave, err := NewAaveOperation(client, EthChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	userEMode := aave.parsedABI.Methods["getUserEMode"]
+	client.HandleContract(AaveEthereumV3ContractAddress, userEMode.ID, pkgtest.Returns(userEMode, big.NewInt(1)))
+
+	// only category 1 is defined on the pool
+	categoryData := aave.parsedABI.Methods["getEModeCategoryData"]
+	client.HandleContract(AaveEthereumV3ContractAddress, categoryData.ID, func(msg ethereum.CallMsg) ([]byte, error) {
+		args, err := categoryData.Inputs.Unpack(msg.Data[4:])
+		if err != nil {
+			return nil, err
+		}
+
+		category := aaveEModeCategory{}
+		if args[0].(uint8) == 1 {
+			category = aaveEModeCategory{Ltv: 9300, LiquidationThreshold: 9500, LiquidationBonus: 10100, Label: "ETH correlated"}
+		}
+
+		return categoryData.Outputs.Pack(category)
+	})
+
+	emode := func(category interface{}) TransactionParams {
+		return TransactionParams{
+			Sender:    testAccount,
+			ExtraData: map[string]interface{}{"emode_category": category},
+		}
+	}
+
+	t.Run("user category", func(t *testing.T) {
+		category, err := aave.GetUserEMode(context.Background(), testAccount)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, category)
+	})
+
+	t.Run("calldata", func(t *testing.T) {
+		tx, err := aave.BuildTransaction(context.Background(), EthChainID, LoanSetEMode, emode(float64(1)))
+		require.NoError(t, err)
+
+		// cast calldata "setUserEMode(uint8)" 1
+		require.Equal(t, "0x28530a470000000000000000000000000000000000000000000000000000000000000001", tx.Data)
+		require.Equal(t, AaveEthereumV3ContractAddress, tx.To)
+		require.Zero(t, tx.Value.Sign())
+	})
+
+	t.Run("native asset still targets the pool", func(t *testing.T) {
+		params := emode(1)
+		params.Asset = common.HexToAddress(nativeDenomAddress)
+
+		tx, err := aave.BuildTransaction(context.Background(), EthChainID, LoanSetEMode, params)
+		require.NoError(t, err)
+		require.Equal(t, AaveEthereumV3ContractAddress, tx.To)
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		require.NoError(t, aave.Validate(context.Background(), EthChainID, LoanSetEMode, emode(1)))
+		// leaving eMode is always allowed
+		require.NoError(t, aave.Validate(context.Background(), EthChainID, LoanSetEMode, emode(0)))
+		require.Error(t, aave.Validate(context.Background(), EthChainID, LoanSetEMode, emode(2)))
+		require.Error(t, aave.Validate(context.Background(), EthChainID, LoanSetEMode, emode(256)))
+		require.Error(t, aave.Validate(context.Background(), EthChainID, LoanSetEMode, TransactionParams{}))
+	})
+}
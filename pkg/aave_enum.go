@@ -20,17 +20,20 @@ const (
 	AaveProtocolDeploymentAvalonFinance
 	// AaveProtocolDeploymentPolygon is a AaveProtocolDeployment of type Polygon.
 	AaveProtocolDeploymentPolygon
+	// AaveProtocolDeploymentRadiant is a AaveProtocolDeployment of type Radiant.
+	AaveProtocolDeploymentRadiant
 )
 
 var ErrInvalidAaveProtocolDeployment = errors.New("not a valid AaveProtocolDeployment")
 
-const _AaveProtocolDeploymentName = "ethereumsparkavalon_financepolygon"
+const _AaveProtocolDeploymentName = "ethereumsparkavalon_financepolygonradiant"
 
 var _AaveProtocolDeploymentMap = map[AaveProtocolDeployment]string{
 	AaveProtocolDeploymentEthereum:      _AaveProtocolDeploymentName[0:8],
 	AaveProtocolDeploymentSpark:         _AaveProtocolDeploymentName[8:13],
 	AaveProtocolDeploymentAvalonFinance: _AaveProtocolDeploymentName[13:27],
 	AaveProtocolDeploymentPolygon:       _AaveProtocolDeploymentName[27:34],
+	AaveProtocolDeploymentRadiant:       _AaveProtocolDeploymentName[34:41],
 }
 
 // String implements the Stringer interface.
@@ -53,6 +56,7 @@ var _AaveProtocolDeploymentValue = map[string]AaveProtocolDeployment{
 	_AaveProtocolDeploymentName[8:13]:  AaveProtocolDeploymentSpark,
 	_AaveProtocolDeploymentName[13:27]: AaveProtocolDeploymentAvalonFinance,
 	_AaveProtocolDeploymentName[27:34]: AaveProtocolDeploymentPolygon,
+	_AaveProtocolDeploymentName[34:41]: AaveProtocolDeploymentRadiant,
 }
 
 // ParseAaveProtocolDeployment attempts to convert a string to a AaveProtocolDeployment.
@@ -0,0 +1,116 @@
+package pkg
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	// aaveExtraDataFlashLoanReceiver is the ExtraData key of the contract
+	// receiving the flash loan, it must implement executeOperation
+	aaveExtraDataFlashLoanReceiver = "flashloan_receiver"
+	// aaveExtraDataFlashLoanParams is the ExtraData key of the bytes passed
+	// through to the receiver
+	aaveExtraDataFlashLoanParams = "flashloan_params"
+)
+
+// flashLoanReceiver returns the receiver set in ExtraData
+func flashLoanReceiver(params TransactionParams) (common.Address, error) {
+
+	v, ok := params.ExtraData[aaveExtraDataFlashLoanReceiver]
+	if !ok {
+		return common.Address{}, errors.New("flashloan_receiver must be provided in extra data")
+	}
+
+	receiver, err := toAddress(v)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid flashloan_receiver: %w", err)
+	}
+
+	if receiver == (common.Address{}) {
+		return common.Address{}, errors.New("flashloan_receiver can not be the zero address")
+	}
+
+	return receiver, nil
+}
+
+// flashLoanParams returns the bytes set in ExtraData, either raw or hex
+// encoded. They default to empty
+func flashLoanParams(params TransactionParams) ([]byte, error) {
+
+	switch v := params.ExtraData[aaveExtraDataFlashLoanParams].(type) {
+	case nil:
+		return []byte{}, nil
+	case []byte:
+		return v, nil
+	case string:
+		if !strings.HasPrefix(v, HexPrefix) {
+			v = HexPrefix + v
+		}
+
+		data, err := hexutil.Decode(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid flashloan_params: %w", err)
+		}
+
+		return data, nil
+	default:
+		return nil, fmt.Errorf("invalid flashloan_params: unsupported type %T", v)
+	}
+}
+
+// generateFlashLoanCalldata packs flashLoanSimple. The pool sends the asset to
+// the receiver which must pay it back with the premium in the same transaction
+func (l *AaveOperation) generateFlashLoanCalldata(params TransactionParams) (string, error) {
+
+	if IsNativeToken(params.Asset) {
+		return "", fmt.Errorf("%w: flash loans borrow the wrapped native token", ErrAssetNotSupported)
+	}
+
+	receiver, err := flashLoanReceiver(params)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := flashLoanParams(params)
+	if err != nil {
+		return "", err
+	}
+
+	referralCode, err := l.supplyReferralCode(params)
+	if err != nil {
+		return "", err
+	}
+
+	calldata, err := l.parsedABI.Pack("flashLoanSimple",
+		receiver, params.Asset, params.Amount, data, referralCode)
+	if err != nil {
+		return "", err
+	}
+
+	return HexPrefix + hex.EncodeToString(calldata), nil
+}
+
+// validateFlashLoan checks the amount and the receiver of the flash loan
+func (l *AaveOperation) validateFlashLoan(params TransactionParams) error {
+
+	if err := params.ValidateAmount(); err != nil {
+		return err
+	}
+
+	if IsNativeToken(params.Asset) {
+		return fmt.Errorf("%w: flash loans borrow the wrapped native token", ErrAssetNotSupported)
+	}
+
+	if _, err := flashLoanReceiver(params); err != nil {
+		return err
+	}
+
+	_, err := flashLoanParams(params)
+	return err
+}
@@ -0,0 +1,86 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAave_FlashLoanSimple_Unit(t *testing.T) {
+
+	aave, err := NewAaveOperation(pkgtest.NewClient(EthChainID), EthChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	receiver := common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+
+	flashLoan := func(extraData map[string]interface{}) TransactionParams {
+		return TransactionParams{
+			Sender:    testAccount,
+			Asset:     testUSDC,
+			Amount:    big.NewInt(1000e6),
+			ExtraData: extraData,
+		}
+	}
+
+	t.Run("calldata", func(t *testing.T) {
+		tx, err := aave.BuildTransaction(context.Background(), EthChainID, LoanFlashLoan, flashLoan(map[string]interface{}{
+			"flashloan_receiver": receiver.Hex(),
+			"flashloan_params":   "0x1234",
+			"referral_code":      0,
+		}))
+		require.NoError(t, err)
+
+		// cast calldata "flashLoanSimple(address,address,uint256,bytes,uint16)" 0x000000000000000000000000000000000000bEEF 0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48 1000000000 0x1234 0
+		require.Equal(t, "0x42b0b77c"+
+			"000000000000000000000000000000000000000000000000000000000000beef"+
+			"000000000000000000000000a0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"+
+			"000000000000000000000000000000000000000000000000000000003b9aca00"+
+			"00000000000000000000000000000000000000000000000000000000000000a0"+
+			"0000000000000000000000000000000000000000000000000000000000000000"+
+			"0000000000000000000000000000000000000000000000000000000000000002"+
+			"1234000000000000000000000000000000000000000000000000000000000000", tx.Data)
+		require.Equal(t, AaveEthereumV3ContractAddress, tx.To)
+		require.Zero(t, tx.Value.Sign())
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		err := aave.Validate(context.Background(), EthChainID, LoanFlashLoan, flashLoan(map[string]interface{}{
+			"flashloan_receiver": receiver,
+		}))
+		require.NoError(t, err)
+
+		unsupported := flashLoan(map[string]interface{}{"flashloan_receiver": receiver})
+		unsupported.Asset = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+		err = aave.Validate(context.Background(), EthChainID, LoanFlashLoan, unsupported)
+		require.ErrorIs(t, err, ErrAssetNotSupported)
+
+		zero := flashLoan(map[string]interface{}{"flashloan_receiver": receiver})
+		zero.Amount = big.NewInt(0)
+
+		err = aave.Validate(context.Background(), EthChainID, LoanFlashLoan, zero)
+		require.ErrorIs(t, err, ErrAmountTooLow)
+	})
+
+	t.Run("invalid extra data", func(t *testing.T) {
+		for name, extraData := range map[string]map[string]interface{}{
+			"missing receiver": {"referral_code": 0},
+			"zero receiver":    {"flashloan_receiver": common.Address{}, "referral_code": 0},
+			"invalid params":   {"flashloan_receiver": receiver, "flashloan_params": "0xzz", "referral_code": 0},
+		} {
+			_, err := aave.GenerateCalldata(context.Background(), EthChainID, LoanFlashLoan, flashLoan(extraData))
+			require.Error(t, err, name)
+		}
+	})
+
+	t.Run("not available on V2 pools", func(t *testing.T) {
+		radiant, err := NewAaveOperation(pkgtest.NewClient(BscChainID), BscChainID, AaveProtocolDeploymentRadiant)
+		require.NoError(t, err)
+
+		require.False(t, radiant.IsSupportedAction(LoanFlashLoan))
+	})
+}
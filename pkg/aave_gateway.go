@@ -0,0 +1,150 @@
+package pkg
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const aaveWrappedTokenGatewayABI = `
+[
+  {
+    "name": "depositETH",
+    "type": "function",
+    "stateMutability": "payable",
+    "inputs": [
+      {
+        "name": "pool",
+        "type": "address"
+      },
+      {
+        "name": "onBehalfOf",
+        "type": "address"
+      },
+      {
+        "name": "referralCode",
+        "type": "uint16"
+      }
+    ],
+    "outputs": []
+  },
+  {
+    "name": "withdrawETH",
+    "type": "function",
+    "stateMutability": "nonpayable",
+    "inputs": [
+      {
+        "name": "pool",
+        "type": "address"
+      },
+      {
+        "name": "amount",
+        "type": "uint256"
+      },
+      {
+        "name": "to",
+        "type": "address"
+      }
+    ],
+    "outputs": []
+  }
+]`
+
+// aaveWrappedTokenGateway wraps the native token before supplying it to the pool
+// and unwraps it on withdrawal
+type aaveWrappedTokenGateway struct {
+	gateway       common.Address
+	wrappedNative common.Address
+}
+
+// aaveWrappedTokenGateways lists the WrappedTokenGatewayV3 deployments of the
+// official Aave pools by chain
+var aaveWrappedTokenGateways = map[int64]aaveWrappedTokenGateway{
+	EthChainID.Int64(): {
+		gateway:       common.HexToAddress("0xD322A49006FC828F9B5B37Ab215F99B4E5caB19C"),
+		wrappedNative: common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"),
+	},
+	// wraps BNB into WBNB
+	BscChainID.Int64(): {
+		gateway:       common.HexToAddress("0x0c2C95b24529664fE55D4437D7A31175CFE6c4f7"),
+		wrappedNative: common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c"),
+	},
+}
+
+// wrappedTokenGateway returns the native token gateway of the deployment if any
+func (l *AaveOperation) wrappedTokenGateway() (aaveWrappedTokenGateway, bool) {
+	if l.fork != AaveProtocolDeploymentEthereum {
+		return aaveWrappedTokenGateway{}, false
+	}
+
+	gw, ok := aaveWrappedTokenGateways[l.chainID.Int64()]
+	return gw, ok
+}
+
+// reserveAsset maps the native denom to the wrapped token the pool actually holds
+func (l *AaveOperation) reserveAsset(asset common.Address) common.Address {
+	if !IsNativeToken(asset) {
+		return asset
+	}
+
+	if gw, ok := l.wrappedTokenGateway(); ok {
+		return gw.wrappedNative
+	}
+
+	return asset
+}
+
+// contractFor returns the contract the calldata for params must be sent to.
+// Native token supplies and withdrawals go through the gateway, everything else to the pool
+func (l *AaveOperation) contractFor(action ContractAction, params TransactionParams) common.Address {
+	if action == LoanSetEMode || action == LoanSetCollateral || action == LoanFlashLoan ||
+		!IsNativeToken(params.Asset) {
+		return l.contract
+	}
+
+	if gw, ok := l.wrappedTokenGateway(); ok {
+		return gw.gateway
+	}
+
+	return l.contract
+}
+
+// generateGatewayCalldata packs the native token variants of supply and withdraw.
+// Withdrawing requires the sender to have approved the gateway to spend its aWETH
+func (l *AaveOperation) generateGatewayCalldata(action ContractAction, params TransactionParams) (string, error) {
+
+	if _, ok := l.wrappedTokenGateway(); !ok {
+		return "", fmt.Errorf("%w %s on this deployment", ErrAssetNotSupported, params.Asset)
+	}
+
+	var calldata []byte
+	var err error
+
+	switch action {
+	case LoanSupply:
+
+		var referalCode uint16
+		referalCode, err = l.supplyReferralCode(params)
+		if err != nil {
+			return "", err
+		}
+
+		calldata, err = l.gatewayABI.Pack("depositETH",
+			l.contract, params.GetBeneficiaryOwner(), referalCode)
+
+	case LoanWithdraw:
+
+		calldata, err = l.gatewayABI.Pack("withdrawETH",
+			l.contract, params.Amount, params.GetBeneficiaryOwner())
+
+	default:
+		return "", ErrUnsupportedAction
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return HexPrefix + hex.EncodeToString(calldata), nil
+}
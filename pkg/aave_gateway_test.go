@@ -0,0 +1,153 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAave_WrappedTokenGateway_Unit(t *testing.T) {
+
+	native := common.HexToAddress(nativeDenomAddress)
+	gateway := aaveWrappedTokenGateways[EthChainID.Int64()].gateway
+
+	aave, err := NewAaveOperation(pkgtest.NewClient(EthChainID), EthChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	t.Run("native token is supported", func(t *testing.T) {
+		require.True(t, aave.IsSupportedAsset(context.Background(), EthChainID, native))
+
+		assets, err := aave.GetSupportedAssets(context.Background(), EthChainID)
+		require.NoError(t, err)
+		require.Contains(t, assets, native)
+	})
+
+	t.Run("supply goes through depositETH", func(t *testing.T) {
+		tx, err := aave.BuildTransaction(context.Background(), EthChainID, LoanSupply, TransactionParams{
+			Amount:    big.NewInt(1e18),
+			Sender:    testAccount,
+			Asset:     native,
+			ExtraData: map[string]interface{}{"referral_code": uint16(0)},
+		})
+		require.NoError(t, err)
+
+		expected := "0x474cf53d" +
+			"00000000000000000000000087870bca3f3fd6335c3f4ce8392d69350b4fa4e2" + // pool
+			"0000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d007" + // onBehalfOf
+			"0000000000000000000000000000000000000000000000000000000000000000" // referralCode
+
+		require.Equal(t, gateway, tx.To)
+		require.Equal(t, expected, tx.Data)
+		require.Equal(t, big.NewInt(1e18), tx.Value)
+	})
+
+	t.Run("withdraw goes through withdrawETH", func(t *testing.T) {
+		tx, err := aave.BuildTransaction(context.Background(), EthChainID, LoanWithdraw, TransactionParams{
+			Amount: big.NewInt(1e18),
+			Sender: testAccount,
+			Asset:  native,
+		})
+		require.NoError(t, err)
+
+		expected := "0x80500d20" +
+			"00000000000000000000000087870bca3f3fd6335c3f4ce8392d69350b4fa4e2" + // pool
+			"0000000000000000000000000000000000000000000000000de0b6b3a7640000" + // amount
+			"0000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d007" // to
+
+		require.Equal(t, gateway, tx.To)
+		require.Equal(t, expected, tx.Data)
+		require.Zero(t, tx.Value.Sign())
+	})
+
+	t.Run("contract addresses by action", func(t *testing.T) {
+		address, ok := aave.GetGatewayAddress(EthChainID)
+		require.True(t, ok)
+		require.Equal(t, gateway, address)
+
+		to, err := aave.GetContractAddressForAction(EthChainID, LoanSupply)
+		require.NoError(t, err)
+		require.Equal(t, AaveEthereumV3ContractAddress, to)
+
+		_, err = aave.GetContractAddressForAction(EthChainID, LoanDelegateCredit)
+		require.Error(t, err)
+
+		_, err = aave.GetContractAddressForAction(EthChainID, NativeStake)
+		require.ErrorIs(t, err, ErrUnsupportedAction)
+	})
+
+	t.Run("deployments without a gateway reject the native token", func(t *testing.T) {
+		spark, err := NewAaveOperation(pkgtest.NewClient(EthChainID), EthChainID, AaveProtocolDeploymentSpark)
+		require.NoError(t, err)
+
+		require.False(t, spark.IsSupportedAsset(context.Background(), EthChainID, native))
+
+		_, ok := spark.GetGatewayAddress(EthChainID)
+		require.False(t, ok)
+
+		_, err = spark.GenerateCalldata(context.Background(), EthChainID, LoanSupply, TransactionParams{
+			Amount:    big.NewInt(1e18),
+			Sender:    testAccount,
+			Asset:     native,
+			ExtraData: map[string]interface{}{"referral_code": uint16(0)},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestAave_WrappedTokenGateway_BSC_Unit(t *testing.T) {
+
+	native := common.HexToAddress(nativeDenomAddress)
+	gateway := aaveWrappedTokenGateways[BscChainID.Int64()].gateway
+
+	aave, err := NewAaveOperation(pkgtest.NewClient(BscChainID), BscChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	require.True(t, aave.IsSupportedAsset(context.Background(), BscChainID, native))
+
+	t.Run("BNB supply goes through depositETH", func(t *testing.T) {
+		tx, err := aave.BuildTransaction(context.Background(), BscChainID, LoanSupply, TransactionParams{
+			Amount: big.NewInt(1e18),
+			Sender: testAccount,
+			Asset:  native,
+		})
+		require.NoError(t, err)
+
+		expected := "0x474cf53d" +
+			"0000000000000000000000006807dc923806fe8fd134338eabca509979a7e0cb" + // pool
+			"0000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d007" + // onBehalfOf
+			"0000000000000000000000000000000000000000000000000000000000000000" // referralCode
+
+		require.Equal(t, gateway, tx.To)
+		require.Equal(t, expected, tx.Data)
+		require.Equal(t, big.NewInt(1e18), tx.Value)
+	})
+
+	t.Run("BNB withdraw goes through withdrawETH", func(t *testing.T) {
+		tx, err := aave.BuildTransaction(context.Background(), BscChainID, LoanWithdraw, TransactionParams{
+			Amount: big.NewInt(1e18),
+			Sender: testAccount,
+			Asset:  native,
+		})
+		require.NoError(t, err)
+
+		expected := "0x80500d20" +
+			"0000000000000000000000006807dc923806fe8fd134338eabca509979a7e0cb" + // pool
+			"0000000000000000000000000000000000000000000000000de0b6b3a7640000" + // amount
+			"0000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d007" // to
+
+		require.Equal(t, gateway, tx.To)
+		require.Equal(t, expected, tx.Data)
+		require.Zero(t, tx.Value.Sign())
+	})
+
+	t.Run("forks on BSC have no gateway", func(t *testing.T) {
+		avalon, err := NewAaveOperation(pkgtest.NewClient(BscChainID), BscChainID, AaveProtocolDeploymentAvalonFinance)
+		require.NoError(t, err)
+
+		require.False(t, avalon.IsSupportedAsset(context.Background(), BscChainID, native))
+	})
+}
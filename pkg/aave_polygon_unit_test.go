@@ -0,0 +1,30 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAave_Polygon_Unit(t *testing.T) {
+
+	aave, err := NewAaveOperation(pkgtest.NewClient(PolygonChainID), PolygonChainID, AaveProtocolDeploymentPolygon)
+	require.NoError(t, err)
+
+	tx, err := aave.BuildTransaction(context.Background(), PolygonChainID, LoanSupply, TransactionParams{
+		Amount: big.NewInt(1e6),
+		Sender: testAccount,
+		Asset:  common.HexToAddress("0x3c499c542cEF5E3811e1192ce70d8cC03d5c3359"),
+		ExtraData: map[string]interface{}{
+			"referral_code": uint16(0),
+		},
+	})
+	require.NoError(t, err)
+
+	// supplies go to the pool, not the data provider
+	require.Equal(t, AavePolygonV3ContractAddress, tx.To)
+}
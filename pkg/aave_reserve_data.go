@@ -0,0 +1,87 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ReserveData is the state of a reserve as returned by getReserveData of the pool.
+// Indexes and rates are rays, i.e scaled by 1e27, and rates are yearly.
+// Amounts are in the decimals of the reserve asset
+type ReserveData struct {
+	// Configuration is the bitmap of the reserve parameters, LTV in the lowest 16 bits
+	Configuration             *big.Int
+	LiquidityIndex            *big.Int
+	CurrentLiquidityRate      *big.Int
+	VariableBorrowIndex       *big.Int
+	CurrentVariableBorrowRate *big.Int
+	CurrentStableBorrowRate   *big.Int
+	// LastUpdateTimestamp is the unix time the indexes were last updated at
+	LastUpdateTimestamp    uint64
+	ID                     uint16
+	AToken                 common.Address
+	StableDebtToken        common.Address
+	VariableDebtToken      common.Address
+	InterestRateStrategy   common.Address
+	AccruedToTreasury      *big.Int
+	Unbacked               *big.Int
+	IsolationModeTotalDebt *big.Int
+}
+
+// GetReserveData reads the reserve of asset from the pool. Only V3 pools are supported
+func (l *AaveOperation) GetReserveData(ctx context.Context, asset common.Address) (ReserveData, error) {
+
+	var data ReserveData
+
+	if err := isAaveChainSupported(l.chainID, l.fork); err != nil {
+		return data, err
+	}
+
+	if l.isV2() {
+		return data, errors.New("reserve data can only be read from V3 pools")
+	}
+
+	calldata, err := l.parsedABI.Pack("getReserveData", l.reserveAsset(asset))
+	if err != nil {
+		return data, err
+	}
+
+	result, err := l.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &l.contract,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return data, err
+	}
+
+	values, err := l.parsedABI.Unpack("getReserveData", result)
+	if err != nil {
+		return data, err
+	}
+
+	data.Configuration = values[0].(*big.Int)
+	data.LiquidityIndex = values[1].(*big.Int)
+	data.CurrentLiquidityRate = values[2].(*big.Int)
+	data.VariableBorrowIndex = values[3].(*big.Int)
+	data.CurrentVariableBorrowRate = values[4].(*big.Int)
+	data.CurrentStableBorrowRate = values[5].(*big.Int)
+	data.LastUpdateTimestamp = values[6].(*big.Int).Uint64()
+	data.ID = values[7].(uint16)
+	data.AToken = values[8].(common.Address)
+	data.StableDebtToken = values[9].(common.Address)
+	data.VariableDebtToken = values[10].(common.Address)
+	data.InterestRateStrategy = values[11].(common.Address)
+	data.AccruedToTreasury = values[12].(*big.Int)
+	data.Unbacked = values[13].(*big.Int)
+	data.IsolationModeTotalDebt = values[14].(*big.Int)
+
+	if data.AToken == (common.Address{}) {
+		return ReserveData{}, ErrAssetNotSupported
+	}
+
+	return data, nil
+}
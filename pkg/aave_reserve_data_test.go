@@ -0,0 +1,49 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAave_GetReserveData_Unit(t *testing.T) {
+
+	client := pkgtest.NewClient(EthChainID)
+
+	aave, err := NewAaveOperation(client, EthChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	ray := new(big.Int).Exp(big.NewInt(10), big.NewInt(27), nil)
+	variableDebt := common.HexToAddress("0x72E95b8931767C79bA4EeE721354d6E99a61D004")
+
+	method := aave.parsedABI.Methods["getReserveData"]
+	client.HandleContract(AaveEthereumV3ContractAddress, method.ID, pkgtest.Returns(method,
+		big.NewInt(7500), ray, big.NewInt(1e9), ray, big.NewInt(2e9), big.NewInt(0),
+		big.NewInt(1700000000), uint16(3), testAaveUSDCATokenV3, common.Address{}, variableDebt,
+		common.Address{}, big.NewInt(10), big.NewInt(0), big.NewInt(0)))
+
+	data, err := aave.GetReserveData(context.Background(), testUSDC)
+	require.NoError(t, err)
+
+	require.Zero(t, data.LiquidityIndex.Cmp(ray))
+	require.EqualValues(t, 1e9, data.CurrentLiquidityRate.Int64())
+	require.EqualValues(t, 2e9, data.CurrentVariableBorrowRate.Int64())
+	require.EqualValues(t, 1700000000, data.LastUpdateTimestamp)
+	require.EqualValues(t, 3, data.ID)
+	require.Equal(t, testAaveUSDCATokenV3, data.AToken)
+	require.Equal(t, variableDebt, data.VariableDebtToken)
+	require.EqualValues(t, 10, data.AccruedToTreasury.Int64())
+
+	// reserves without an aToken are not listed
+	client.HandleContract(AaveEthereumV3ContractAddress, method.ID, pkgtest.Returns(method,
+		big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0),
+		big.NewInt(0), uint16(0), common.Address{}, common.Address{}, common.Address{},
+		common.Address{}, big.NewInt(0), big.NewInt(0), big.NewInt(0)))
+
+	_, err = aave.GetReserveData(context.Background(), testUSDC)
+	require.ErrorIs(t, err, ErrAssetNotSupported)
+}
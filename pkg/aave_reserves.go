@@ -0,0 +1,112 @@
+package pkg
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// defaultReservesCacheTTL is how long the reserves of the pool are reused
+// before WithDynamicAssets operations read them again
+const defaultReservesCacheTTL = 10 * time.Minute
+
+// SyncSupportedAssets replaces the static list of supported assets with the
+// reserves listed by the pool. The static list keeps being used until a sync succeeds
+func (l *AaveOperation) SyncSupportedAssets(ctx context.Context) error {
+
+	if err := isAaveChainSupported(l.chainID, l.fork); err != nil {
+		return err
+	}
+
+	reserves, err := l.getReservesList(ctx)
+	if err != nil {
+		return err
+	}
+
+	l.reservesMu.Lock()
+	defer l.reservesMu.Unlock()
+
+	l.reserves = reserves
+	l.reservesSyncedAt = time.Now()
+	return nil
+}
+
+// RefreshSupportedAssets syncs the reserves again when the operation uses them,
+// i.e with WithDynamicAssets or once SyncSupportedAssets succeeded. Operations
+// relying on the static list are left untouched
+func (l *AaveOperation) RefreshSupportedAssets(ctx context.Context) error {
+
+	l.reservesMu.RLock()
+	synced := l.reserves != nil
+	l.reservesMu.RUnlock()
+
+	if !l.dynamicAssets && !synced {
+		return nil
+	}
+
+	return l.SyncSupportedAssets(ctx)
+}
+
+// supportedAssets returns the synced reserves if any, the static list of the deployment otherwise.
+// Operations using dynamic assets sync the reserves first once they are stale
+func (l *AaveOperation) supportedAssets(ctx context.Context) []common.Address {
+
+	// the previous list is kept if the pool can not be reached
+	if l.dynamicAssets && l.areReservesStale() {
+		_ = l.SyncSupportedAssets(ctx)
+	}
+
+	l.reservesMu.RLock()
+	defer l.reservesMu.RUnlock()
+
+	if l.reserves != nil {
+		assets := make([]common.Address, len(l.reserves))
+		copy(assets, l.reserves)
+		return assets
+	}
+
+	return l.staticSupportedAssets()
+}
+
+func (l *AaveOperation) areReservesStale() bool {
+	l.reservesMu.RLock()
+	defer l.reservesMu.RUnlock()
+
+	return l.reserves == nil || time.Since(l.reservesSyncedAt) > defaultReservesCacheTTL
+}
+
+// staticSupportedAssets returns the assets listed for the deployment in tokenSupportedMap
+func (l *AaveOperation) staticSupportedAssets() []common.Address {
+
+	addrs := tokenSupportedMap[l.chainID.Int64()][l.GetName()]
+
+	assets := make([]common.Address, 0, len(addrs))
+	for _, v := range addrs {
+		assets = append(assets, common.HexToAddress(v))
+	}
+
+	return assets
+}
+
+// getReservesList reads every reserve initialized on the pool
+func (l *AaveOperation) getReservesList(ctx context.Context) ([]common.Address, error) {
+
+	calldata, err := l.parsedABI.Pack("getReservesList")
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := l.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &l.contract,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var reserves []common.Address
+	err = l.parsedABI.UnpackIntoInterface(&reserves, "getReservesList", result)
+	return reserves, err
+}
@@ -0,0 +1,100 @@
+package pkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAave_SyncSupportedAssets_Unit(t *testing.T) {
+
+	gho := common.HexToAddress("0x40D16FC0246aD3160Ccc09B8D0D3A2cD28aE6C2f")
+
+	t.Run("synced reserves replace the static list", func(t *testing.T) {
+		client := pkgtest.NewClient(EthChainID)
+
+		aave, err := NewAaveOperation(client, EthChainID, AaveProtocolDeploymentEthereum)
+		require.NoError(t, err)
+
+		method := aave.parsedABI.Methods["getReservesList"]
+		client.HandleContract(AaveEthereumV3ContractAddress, method.ID, pkgtest.Returns(method,
+			[]common.Address{testUSDC, gho}))
+
+		require.False(t, aave.IsSupportedAsset(context.Background(), EthChainID, gho))
+
+		require.NoError(t, aave.SyncSupportedAssets(context.Background()))
+
+		require.True(t, aave.IsSupportedAsset(context.Background(), EthChainID, gho))
+		require.False(t, aave.IsSupportedAsset(context.Background(), EthChainID,
+			common.HexToAddress("0xdac17f958d2ee523a2206206994597c13d831ec7")))
+
+		assets, err := aave.GetSupportedAssets(context.Background(), EthChainID)
+		require.NoError(t, err)
+		require.Equal(t, []common.Address{testUSDC, gho, common.HexToAddress(nativeDenomAddress)}, assets)
+	})
+
+	t.Run("static list is kept when the pool can not be reached", func(t *testing.T) {
+		aave, err := NewAaveOperation(pkgtest.NewClient(EthChainID), EthChainID, AaveProtocolDeploymentEthereum)
+		require.NoError(t, err)
+
+		require.Error(t, aave.SyncSupportedAssets(context.Background()))
+
+		assets, err := aave.GetSupportedAssets(context.Background(), EthChainID)
+		require.NoError(t, err)
+		require.Len(t, assets, len(tokenSupportedMap[EthChainID.Int64()][AaveV3])+1)
+		require.True(t, aave.IsSupportedAsset(context.Background(), EthChainID, testUSDC))
+	})
+}
+
+func TestAave_WithDynamicAssets_Unit(t *testing.T) {
+
+	gho := common.HexToAddress("0x40D16FC0246aD3160Ccc09B8D0D3A2cD28aE6C2f")
+
+	t.Run("reserves are read from the pool once", func(t *testing.T) {
+		client := pkgtest.NewClient(EthChainID)
+
+		aave, err := NewAaveOperation(client, EthChainID, AaveProtocolDeploymentEthereum, WithDynamicAssets(true))
+		require.NoError(t, err)
+
+		var calls int
+
+		method := aave.parsedABI.Methods["getReservesList"]
+		reserves := pkgtest.Returns(method, []common.Address{testUSDC, gho})
+		client.HandleContract(AaveEthereumV3ContractAddress, method.ID, func(msg ethereum.CallMsg) ([]byte, error) {
+			calls++
+			return reserves(msg)
+		})
+
+		require.True(t, aave.IsSupportedAsset(context.Background(), EthChainID, gho))
+
+		assets, err := aave.GetSupportedAssets(context.Background(), EthChainID)
+		require.NoError(t, err)
+		require.Equal(t, []common.Address{testUSDC, gho, common.HexToAddress(nativeDenomAddress)}, assets)
+
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("static list is used offline", func(t *testing.T) {
+		aave, err := NewAaveOperation(pkgtest.NewClient(EthChainID), EthChainID, AaveProtocolDeploymentEthereum, WithDynamicAssets(true))
+		require.NoError(t, err)
+
+		require.True(t, aave.IsSupportedAsset(context.Background(), EthChainID, testUSDC))
+		require.False(t, aave.IsSupportedAsset(context.Background(), EthChainID, gho))
+	})
+
+	t.Run("static by default", func(t *testing.T) {
+		client := pkgtest.NewClient(EthChainID)
+
+		aave, err := NewAaveOperation(client, EthChainID, AaveProtocolDeploymentEthereum)
+		require.NoError(t, err)
+
+		method := aave.parsedABI.Methods["getReservesList"]
+		client.HandleContract(AaveEthereumV3ContractAddress, method.ID, pkgtest.Returns(method, []common.Address{gho}))
+
+		require.False(t, aave.IsSupportedAsset(context.Background(), EthChainID, gho))
+	})
+}
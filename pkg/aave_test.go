@@ -8,6 +8,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/stretchr/testify/require"
@@ -38,15 +39,19 @@ func TestAave_New(t *testing.T) {
 	})
 
 	t.Run("network id check fails", func(t *testing.T) {
-		_, err := NewAaveOperation(getTestClient(t, ChainBSC), big.NewInt(1), AaveProtocolDeploymentEthereum)
-		require.Error(t, err)
-		require.Contains(t, err.Error(), "network id of client")
+		aave, err := NewAaveOperation(getTestClient(t, ChainBSC), big.NewInt(1), AaveProtocolDeploymentEthereum)
+		require.NoError(t, err)
+
+		err = aave.Validate(context.Background(), big.NewInt(1), LoanSupply, TransactionParams{})
+		require.ErrorIs(t, err, ErrNetworkMismatch)
 	})
 
 	t.Run("network id of bsc network client does not match eth chain", func(t *testing.T) {
-		_, err := NewAaveOperation(getTestClient(t, ChainBSC), big.NewInt(1), AaveProtocolDeploymentEthereum)
-		require.Error(t, err)
-		require.Contains(t, err.Error(), "network id of client")
+		aave, err := NewAaveOperation(getTestClient(t, ChainBSC), big.NewInt(1), AaveProtocolDeploymentEthereum)
+		require.NoError(t, err)
+
+		_, err = aave.GenerateCalldata(context.Background(), big.NewInt(1), LoanSupply, TransactionParams{})
+		require.ErrorIs(t, err, ErrNetworkMismatch)
 	})
 }
 
@@ -98,6 +103,50 @@ func TestAave_GetSupportedAsset(t *testing.T) {
 	})
 }
 
+func TestAave_SyncSupportedAssets(t *testing.T) {
+
+	aave, err := NewAaveOperation(getTestClient(t, ChainETH), big.NewInt(1), AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	static := aave.staticSupportedAssets()
+
+	require.NoError(t, aave.SyncSupportedAssets(context.Background()))
+
+	reserves := aave.supportedAssets(context.Background())
+	require.NotEmpty(t, reserves)
+
+	for _, asset := range static {
+		if !containsAddress(reserves, asset) {
+			t.Logf("%s is listed statically but is not a reserve of the pool", asset)
+		}
+	}
+
+	for _, asset := range reserves {
+		if !containsAddress(static, asset) {
+			t.Logf("reserve %s is missing from the static list", asset)
+		}
+	}
+}
+
+func TestAave_WithDynamicAssets(t *testing.T) {
+
+	client := getTestClient(t, ChainETH)
+
+	static, err := NewAaveOperation(client, big.NewInt(1), AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	dynamic, err := NewAaveOperation(client, big.NewInt(1), AaveProtocolDeploymentEthereum, WithDynamicAssets(true))
+	require.NoError(t, err)
+
+	staticAssets, err := static.GetSupportedAssets(context.Background(), big.NewInt(1))
+	require.NoError(t, err)
+
+	dynamicAssets, err := dynamic.GetSupportedAssets(context.Background(), big.NewInt(1))
+	require.NoError(t, err)
+
+	require.Subset(t, dynamicAssets, staticAssets)
+}
+
 func TestAave_IsSupportedAsset(t *testing.T) {
 
 	aave, err := NewAaveOperation(getTestClient(t, ChainETH), big.NewInt(1), AaveProtocolDeploymentEthereum)
@@ -196,6 +245,84 @@ func TestAave_GetAToken(t *testing.T) {
 	}
 }
 
+func TestAave_GetReserveTokens(t *testing.T) {
+
+	aave, err := NewAaveOperation(getTestClient(t, ChainETH), big.NewInt(1), AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	aToken, stableDebt, variableDebt, err := aave.getReserveTokens(context.Background(),
+		common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"))
+	require.NoError(t, err)
+
+	require.Equal(t, common.HexToAddress("0x98C23E9d8f34FEFb1B7BD6a91B7FF122F4e16F5c"), aToken)
+
+	for _, token := range []common.Address{aToken, stableDebt, variableDebt} {
+		require.NotEqual(t, common.Address{}, token)
+	}
+
+	require.NotEqual(t, aToken, stableDebt)
+	require.NotEqual(t, aToken, variableDebt)
+	require.NotEqual(t, stableDebt, variableDebt)
+}
+
+func TestAave_GetCaps(t *testing.T) {
+
+	aave, err := NewAaveOperation(getTestClient(t, ChainETH), big.NewInt(1), AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	supplyCap, err := aave.GetSupplyCap(context.Background(), testUSDC)
+	require.NoError(t, err)
+	require.NotNil(t, supplyCap)
+
+	borrowCap, err := aave.GetBorrowCap(context.Background(), testUSDC)
+	require.NoError(t, err)
+	require.NotNil(t, borrowCap)
+
+	// USDC can not be borrowed beyond what is supplied
+	require.True(t, borrowCap.Cmp(supplyCap) <= 0)
+
+	err = aave.Validate(context.Background(), big.NewInt(1), LoanSupply, TransactionParams{
+		Amount: new(big.Int).Add(supplyCap, big.NewInt(1)),
+		Sender: hotWallet,
+		Asset:  testUSDC,
+	})
+	require.ErrorIs(t, err, ErrSupplyCapExceeded)
+}
+
+func TestAave_GetReserveData(t *testing.T) {
+
+	aave, err := NewAaveOperation(getTestClient(t, ChainETH), big.NewInt(1), AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	data, err := aave.GetReserveData(context.Background(), testUSDC)
+	require.NoError(t, err)
+
+	require.Equal(t, common.HexToAddress("0x98C23E9d8f34FEFb1B7BD6a91B7FF122F4e16F5c"), data.AToken)
+
+	// the liquidity index starts at 1 ray and only grows
+	ray := new(big.Int).Exp(big.NewInt(10), big.NewInt(27), nil)
+	require.True(t, data.LiquidityIndex.Cmp(ray) > 0)
+	require.True(t, data.VariableBorrowIndex.Cmp(ray) > 0)
+	require.NotZero(t, data.LastUpdateTimestamp)
+}
+
+func TestAave_GetUserReserveData(t *testing.T) {
+
+	aave, err := NewAaveOperation(getTestClient(t, ChainETH), big.NewInt(1), AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	// the Aave collector receives the reserve factor in aTokens and never borrows
+	collector := common.HexToAddress("0x464C71f6c2F760DdA6093dCB91C24c39e5d6e18c")
+
+	data, err := aave.GetUserReserveData(context.Background(), testUSDC, collector)
+	require.NoError(t, err)
+
+	require.True(t, data.CurrentATokenBalance.Sign() > 0)
+	require.Zero(t, data.CurrentStableDebt.Sign())
+	require.Zero(t, data.CurrentVariableDebt.Sign())
+	require.True(t, data.LiquidityRate.Sign() > 0)
+}
+
 func TestAave_Validate(t *testing.T) {
 
 	aave, err := NewAaveOperation(getTestClient(t, ChainETH), big.NewInt(1), AaveProtocolDeploymentEthereum)
@@ -380,3 +507,65 @@ func TestAave_GenerateCalldata_Supply(t *testing.T) {
 
 	require.Equal(t, expectedCalldata, calldata)
 }
+
+// leveragedTestWallet is a borrower with an outstanding position on the Ethereum V3 pool
+var leveragedTestWallet = common.HexToAddress("0x3DdfA8eC3052539b6C9549F12cEA2C295cfF5296")
+
+func TestAave_GetAccountData(t *testing.T) {
+
+	aave, err := NewAaveOperation(getTestClient(t, ChainETH), big.NewInt(1), AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	t.Run("empty wallet", func(t *testing.T) {
+		data, err := aave.GetAccountData(context.Background(), emptyTestWallet)
+		require.NoError(t, err)
+
+		require.Zero(t, data.TotalCollateralBase.Sign())
+		require.Zero(t, data.TotalDebtBase.Sign())
+		// accounts without debt report the max uint256 health factor
+		require.Equal(t, abi.MaxUint256, data.HealthFactor)
+	})
+
+	t.Run("leveraged wallet", func(t *testing.T) {
+		data, err := aave.GetAccountData(context.Background(), leveragedTestWallet)
+		require.NoError(t, err)
+		require.Positive(t, data.TotalDebtBase.Sign(), "wallet has no outstanding debt")
+
+		// collateral * liquidation threshold / debt, the threshold in basis points
+		healthFactor := new(big.Int).Mul(data.TotalCollateralBase, data.CurrentLiquidationThreshold)
+		healthFactor.Mul(healthFactor, aaveHealthFactorOne)
+		healthFactor.Quo(healthFactor, new(big.Int).Mul(data.TotalDebtBase, aavePercentageScale))
+
+		// the pool rounds the weighted threshold, allow 0.1%
+		diff := new(big.Int).Sub(healthFactor, data.HealthFactor)
+		tolerance := new(big.Int).Quo(data.HealthFactor, big.NewInt(1000))
+		require.LessOrEqual(t, diff.CmpAbs(tolerance), 0,
+			"computed health factor %s, pool reports %s", healthFactor, data.HealthFactor)
+	})
+}
+
+func TestAave_GetDebtBalance(t *testing.T) {
+
+	borrower := leveragedTestWallet
+
+	aave, err := NewAaveOperation(getTestClient(t, ChainETH), big.NewInt(1), AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	data, err := aave.GetAccountData(context.Background(), borrower)
+	require.NoError(t, err)
+	require.Positive(t, data.TotalDebtBase.Sign(), "wallet has no outstanding debt")
+
+	assets, err := aave.GetSupportedAssets(context.Background(), big.NewInt(1))
+	require.NoError(t, err)
+
+	total := big.NewInt(0)
+	for _, asset := range assets {
+		variable, stable, err := aave.GetDebtBalance(context.Background(), borrower, asset)
+		require.NoError(t, err)
+
+		total.Add(total, variable)
+		total.Add(total, stable)
+	}
+
+	require.Positive(t, total.Sign())
+}
@@ -0,0 +1,283 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAave_EstimateGas_Unit(t *testing.T) {
+
+	client := pkgtest.NewClient(EthChainID)
+	client.HandleEstimateGas(func(ethereum.CallMsg) (uint64, error) { return 200_000, nil })
+
+	aave, err := NewAaveOperation(client, EthChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	_, err = aave.EstimateGas(context.Background(), EthChainID, LoanSupply, TransactionParams{
+		Amount: big.NewInt(1e6),
+		Sender: testAccount,
+		Asset:  common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"),
+		ExtraData: map[string]interface{}{
+			"referral_code": uint16(0),
+		},
+	})
+	require.NoError(t, err)
+
+	msgs := client.EstimateGasCalls()
+	require.Len(t, msgs, 1)
+	require.Equal(t, AaveEthereumV3ContractAddress, *msgs[0].To)
+	// supplying an ERC20 does not send any ETH
+	require.Zero(t, msgs[0].Value.Sign())
+}
+
+func TestAave_RepayWithATokens_Unit(t *testing.T) {
+
+	aave := newLeveragedAaveOperation(t, new(big.Int).Mul(big.NewInt(500), big.NewInt(1e8)))
+
+	repay := func(amount int64, useATokens bool) TransactionParams {
+		return TransactionParams{
+			Amount:    big.NewInt(amount),
+			Sender:    testAccount,
+			Asset:     testUSDC,
+			ExtraData: map[string]interface{}{"use_atokens": useATokens},
+		}
+	}
+
+	t.Run("calldata", func(t *testing.T) {
+		calldata, err := aave.GenerateCalldata(context.Background(), EthChainID, LoanRepay, repay(100e6, true))
+		require.NoError(t, err)
+
+		require.Equal(t,
+			"0x2dad97d4"+
+				"000000000000000000000000a0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"+
+				"0000000000000000000000000000000000000000000000000000000005f5e100"+
+				"0000000000000000000000000000000000000000000000000000000000000002",
+			calldata)
+	})
+
+	t.Run("repaying without use_atokens is not supported", func(t *testing.T) {
+		_, err := aave.GenerateCalldata(context.Background(), EthChainID, LoanRepay, repay(100e6, false))
+		require.Error(t, err)
+
+		require.Error(t, aave.Validate(context.Background(), EthChainID, LoanRepay, repay(100e6, false)))
+	})
+
+	t.Run("aToken balance covers the repay", func(t *testing.T) {
+		require.NoError(t, aave.Validate(context.Background(), EthChainID, LoanRepay, repay(500e6, true)))
+	})
+
+	t.Run("aToken balance does not cover the repay", func(t *testing.T) {
+		require.Error(t, aave.Validate(context.Background(), EthChainID, LoanRepay, repay(2000e6, true)))
+	})
+}
+
+func TestAave_ReferralCode_Unit(t *testing.T) {
+
+	supply := func(extra map[string]interface{}) TransactionParams {
+		return TransactionParams{
+			Amount:    big.NewInt(1e6),
+			Sender:    testAccount,
+			Asset:     testUSDC,
+			ExtraData: extra,
+		}
+	}
+
+	pack := func(aave *AaveOperation, code uint16) string {
+		calldata, err := aave.parsedABI.Pack("supply", testUSDC, big.NewInt(1e6), testAccount, code)
+		require.NoError(t, err)
+		return HexPrefix + common.Bytes2Hex(calldata)
+	}
+
+	t.Run("missing referral code defaults to 0", func(t *testing.T) {
+		aave, err := NewAaveOperation(pkgtest.NewClient(EthChainID), EthChainID, AaveProtocolDeploymentEthereum)
+		require.NoError(t, err)
+
+		calldata, err := aave.GenerateCalldata(context.Background(), EthChainID, LoanSupply, supply(nil))
+		require.NoError(t, err)
+		require.Equal(t, pack(aave, 0), calldata)
+
+		withZero, err := aave.GenerateCalldata(context.Background(), EthChainID, LoanSupply,
+			supply(map[string]interface{}{"referral_code": 0}))
+		require.NoError(t, err)
+		require.Equal(t, withZero, calldata)
+	})
+
+	t.Run("configured referral code", func(t *testing.T) {
+		aave, err := NewAaveOperation(pkgtest.NewClient(EthChainID), EthChainID,
+			AaveProtocolDeploymentEthereum, WithAaveReferralCode(42))
+		require.NoError(t, err)
+
+		calldata, err := aave.GenerateCalldata(context.Background(), EthChainID, LoanSupply, supply(nil))
+		require.NoError(t, err)
+		require.Equal(t, pack(aave, 42), calldata)
+	})
+
+	t.Run("extra data overrides the configured code", func(t *testing.T) {
+		aave, err := NewAaveOperation(pkgtest.NewClient(EthChainID), EthChainID,
+			AaveProtocolDeploymentEthereum, WithAaveReferralCode(42))
+		require.NoError(t, err)
+
+		calldata, err := aave.GenerateCalldata(context.Background(), EthChainID, LoanSupply,
+			supply(map[string]interface{}{"referral_code": uint16(7)}))
+		require.NoError(t, err)
+		require.Equal(t, pack(aave, 7), calldata)
+	})
+}
+
+func TestAave_ReferralCode_Coercion_Unit(t *testing.T) {
+
+	aave, err := NewAaveOperation(pkgtest.NewClient(EthChainID), EthChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	for _, code := range []interface{}{7, int64(7), uint64(7), float64(7), "7", big.NewInt(7)} {
+		calldata, err := aave.GenerateCalldata(context.Background(), EthChainID, LoanSupply, TransactionParams{
+			Amount:    big.NewInt(1e6),
+			Sender:    testAccount,
+			Asset:     testUSDC,
+			ExtraData: map[string]interface{}{"referral_code": code},
+		})
+		require.NoError(t, err)
+		require.True(t, strings.HasSuffix(calldata, "0000000000000000000000000000000000000000000000000000000000000007"))
+	}
+
+	_, err = aave.GenerateCalldata(context.Background(), EthChainID, LoanSupply, TransactionParams{
+		Amount:    big.NewInt(1e6),
+		Sender:    testAccount,
+		Asset:     testUSDC,
+		ExtraData: map[string]interface{}{"referral_code": 70000},
+	})
+	require.Error(t, err)
+}
+
+func TestAave_Gnosis_Unit(t *testing.T) {
+
+	client := pkgtest.NewClient(GnosisChainID)
+
+	aave, err := NewAaveOperation(client, GnosisChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	require.Equal(t, AaveGnosisV3ContractAddress, aave.GetContractAddress(GnosisChainID))
+
+	sDAI := common.HexToAddress("0xaf204776c7245bF4147c2612BF6e5972Ee483701")
+	require.True(t, aave.IsSupportedAsset(context.Background(), GnosisChainID, sDAI))
+	// xDai has to be wrapped first, there is no gateway configured
+	require.False(t, aave.IsSupportedAsset(context.Background(), GnosisChainID, common.HexToAddress(nativeDenomAddress)))
+
+	dataProvider, err := aave.dataProvider()
+	require.NoError(t, err)
+	require.Equal(t, gnosisAaveDataProviderContract, dataProvider)
+
+	_, err = NewAaveOperation(client, GnosisChainID, AaveProtocolDeploymentSpark)
+	require.Error(t, err)
+}
+
+func TestAave_V2_Unit(t *testing.T) {
+
+	usdt := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+
+	params := TransactionParams{
+		Amount: big.NewInt(1e18),
+		Sender: testAccount,
+		Asset:  usdt,
+		ExtraData: map[string]interface{}{
+			"referral_code": uint16(0),
+		},
+	}
+
+	avalon, err := NewAaveOperation(pkgtest.NewClient(BscChainID), BscChainID, AaveProtocolDeploymentAvalonFinance)
+	require.NoError(t, err)
+
+	aave, err := NewAaveOperation(pkgtest.NewClient(BscChainID), BscChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	t.Run("V2 pools deposit", func(t *testing.T) {
+		calldata, err := avalon.GenerateCalldata(context.Background(), BscChainID, LoanSupply, params)
+		require.NoError(t, err)
+
+		// cast calldata "deposit(address,uint256,address,uint16)" 0x55d398326f99059fF775485246999027B3197955 1000000000000000000 0x6a22640F02F8c8b576a3193674c4aE97e0f8d007 0
+		require.Equal(t, "0xe8eda9df00000000000000000000000055d398326f99059ff775485246999027b31979550000000000000000000000000000000000000000000000000de0b6b3a76400000000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d0070000000000000000000000000000000000000000000000000000000000000000", calldata)
+	})
+
+	t.Run("V3 pools supply", func(t *testing.T) {
+		calldata, err := aave.GenerateCalldata(context.Background(), BscChainID, LoanSupply, params)
+		require.NoError(t, err)
+
+		// cast calldata "supply(address,uint256,address,uint16)" 0x55d398326f99059fF775485246999027B3197955 1000000000000000000 0x6a22640F02F8c8b576a3193674c4aE97e0f8d007 0
+		require.Equal(t, "0x617ba03700000000000000000000000055d398326f99059ff775485246999027b31979550000000000000000000000000000000000000000000000000de0b6b3a76400000000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d0070000000000000000000000000000000000000000000000000000000000000000", calldata)
+	})
+
+	t.Run("withdraw is shared", func(t *testing.T) {
+		v2, err := avalon.GenerateCalldata(context.Background(), BscChainID, LoanWithdraw, params)
+		require.NoError(t, err)
+
+		v3, err := aave.GenerateCalldata(context.Background(), BscChainID, LoanWithdraw, params)
+		require.NoError(t, err)
+
+		require.Equal(t, v3, v2)
+	})
+
+	t.Run("eMode is V3 only", func(t *testing.T) {
+		require.False(t, avalon.IsSupportedAction(LoanSetEMode))
+		require.False(t, avalon.IsSupportedAction(LoanRepay))
+
+		_, err := avalon.GenerateCalldata(context.Background(), BscChainID, LoanSetEMode, TransactionParams{
+			ExtraData: map[string]interface{}{"emode_category": 1},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestAave_Radiant_Unit(t *testing.T) {
+
+	usdt := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+
+	radiant, err := NewAaveOperation(pkgtest.NewClient(BscChainID), BscChainID, AaveProtocolDeploymentRadiant)
+	require.NoError(t, err)
+
+	require.Equal(t, Radiant, radiant.GetName())
+	require.Equal(t, "2", radiant.GetVersion())
+	require.Equal(t, RadiantBnbContractAddress, radiant.GetContractAddress(BscChainID))
+	require.True(t, radiant.IsSupportedAsset(context.Background(), BscChainID, usdt))
+
+	dataProvider, err := radiant.dataProvider()
+	require.NoError(t, err)
+	require.Equal(t, radiantBnbDataProviderContract, dataProvider)
+
+	params := TransactionParams{
+		Amount: big.NewInt(1e18),
+		Sender: testAccount,
+		Asset:  usdt,
+		ExtraData: map[string]interface{}{
+			"referral_code": uint16(0),
+		},
+	}
+
+	t.Run("supply", func(t *testing.T) {
+		tx, err := radiant.BuildTransaction(context.Background(), BscChainID, LoanSupply, params)
+		require.NoError(t, err)
+
+		// cast calldata "deposit(address,uint256,address,uint16)" 0x55d398326f99059fF775485246999027B3197955 1000000000000000000 0x6a22640F02F8c8b576a3193674c4aE97e0f8d007 0
+		require.Equal(t, "0xe8eda9df00000000000000000000000055d398326f99059ff775485246999027b31979550000000000000000000000000000000000000000000000000de0b6b3a76400000000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d0070000000000000000000000000000000000000000000000000000000000000000", tx.Data)
+		require.Equal(t, RadiantBnbContractAddress, tx.To)
+	})
+
+	t.Run("withdraw", func(t *testing.T) {
+		calldata, err := radiant.GenerateCalldata(context.Background(), BscChainID, LoanWithdraw, params)
+		require.NoError(t, err)
+
+		// cast calldata "withdraw(address,uint256,address)" 0x55d398326f99059fF775485246999027B3197955 1000000000000000000 0x6a22640F02F8c8b576a3193674c4aE97e0f8d007
+		require.Equal(t, "0x69328dec00000000000000000000000055d398326f99059ff775485246999027b31979550000000000000000000000000000000000000000000000000de0b6b3a76400000000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d007", calldata)
+	})
+
+	t.Run("only on BSC", func(t *testing.T) {
+		_, err := NewAaveOperation(pkgtest.NewClient(EthChainID), EthChainID, AaveProtocolDeploymentRadiant)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,57 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// UserReserveData is the position of a user in a single reserve as returned by
+// getUserReserveData of the data provider. Balances and debts are in the
+// decimals of the reserve asset, rates are yearly rays, i.e scaled by 1e27
+type UserReserveData struct {
+	// CurrentATokenBalance is the supplied amount with the interest accrued so far
+	CurrentATokenBalance *big.Int
+	CurrentStableDebt    *big.Int
+	CurrentVariableDebt  *big.Int
+	// PrincipalStableDebt is the stable debt without the interest accrued since
+	// StableRateLastUpdated
+	PrincipalStableDebt *big.Int
+	// ScaledVariableDebt is the variable debt divided by the variable borrow index
+	ScaledVariableDebt *big.Int
+	StableBorrowRate   *big.Int
+	LiquidityRate      *big.Int
+	// StableRateLastUpdated is the unix time the stable rate of the user was set at
+	StableRateLastUpdated    uint64
+	UsageAsCollateralEnabled bool
+}
+
+// GetUserReserveData reads the supply, debts and collateral flag of user in the
+// reserve of asset. Unlike GetBalance it covers the debts of the user as well
+func (l *AaveOperation) GetUserReserveData(ctx context.Context,
+	asset, user common.Address) (UserReserveData, error) {
+
+	var data UserReserveData
+
+	if err := isAaveChainSupported(l.chainID, l.fork); err != nil {
+		return data, err
+	}
+
+	values, err := l.callDataProvider(ctx, "getUserReserveData", l.reserveAsset(asset), user)
+	if err != nil {
+		return data, err
+	}
+
+	data.CurrentATokenBalance = values[0].(*big.Int)
+	data.CurrentStableDebt = values[1].(*big.Int)
+	data.CurrentVariableDebt = values[2].(*big.Int)
+	data.PrincipalStableDebt = values[3].(*big.Int)
+	data.ScaledVariableDebt = values[4].(*big.Int)
+	data.StableBorrowRate = values[5].(*big.Int)
+	data.LiquidityRate = values[6].(*big.Int)
+	data.StableRateLastUpdated = values[7].(*big.Int).Uint64()
+	data.UsageAsCollateralEnabled = values[8].(bool)
+
+	return data, nil
+}
@@ -0,0 +1,45 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAave_GetUserReserveData_Unit(t *testing.T) {
+
+	client := pkgtest.NewClient(EthChainID)
+
+	aave, err := NewAaveOperation(client, EthChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	ray := new(big.Int).Exp(big.NewInt(10), big.NewInt(27), nil)
+
+	// 100 USDC supplied as collateral against 40 USDC of variable debt
+	method := aave.dataProviderABI.Methods["getUserReserveData"]
+	client.HandleContract(ethAaveDataProviderContract, method.ID, func(msg ethereum.CallMsg) ([]byte, error) {
+		args, err := method.Inputs.Unpack(msg.Data[4:])
+		require.NoError(t, err)
+		require.Equal(t, testUSDC, args[0].(common.Address))
+		require.Equal(t, testAccount, args[1].(common.Address))
+
+		return method.Outputs.Pack(big.NewInt(100e6), big.NewInt(0), big.NewInt(40e6), big.NewInt(0),
+			big.NewInt(38e6), big.NewInt(0), ray, big.NewInt(0), true)
+	})
+
+	data, err := aave.GetUserReserveData(context.Background(), testUSDC, testAccount)
+	require.NoError(t, err)
+
+	require.EqualValues(t, 100e6, data.CurrentATokenBalance.Int64())
+	require.Zero(t, data.CurrentStableDebt.Sign())
+	require.EqualValues(t, 40e6, data.CurrentVariableDebt.Int64())
+	require.EqualValues(t, 38e6, data.ScaledVariableDebt.Int64())
+	require.Zero(t, data.LiquidityRate.Cmp(ray))
+	require.Zero(t, data.StableRateLastUpdated)
+	require.True(t, data.UsageAsCollateralEnabled)
+}
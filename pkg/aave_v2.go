@@ -0,0 +1,146 @@
+package pkg
+
+// aaveV2ABI is the subset of the V2 LendingPool the operation relies on.
+// V2 pools take deposits with deposit and expose the addresses provider
+// through getAddressesProvider
+const aaveV2ABI = `
+ [
+   {
+     "name": "withdraw",
+     "type": "function",
+     "inputs": [
+       {
+         "type": "address"
+       },
+       {
+         "type": "uint256"
+       },
+       {
+         "type": "address"
+       }
+     ]
+   },
+   {
+     "name": "deposit",
+     "type": "function",
+     "inputs": [
+       {
+         "type": "address"
+       },
+       {
+         "type": "uint256"
+       },
+       {
+         "type": "address"
+       },
+       {
+         "type": "uint16"
+       }
+     ]
+   },
+   {
+     "name": "getUserAccountData",
+     "type": "function",
+     "stateMutability": "view",
+     "inputs": [
+       {
+         "name": "user",
+         "type": "address"
+       }
+     ],
+     "outputs": [
+       {
+         "name": "totalCollateralETH",
+         "type": "uint256"
+       },
+       {
+         "name": "totalDebtETH",
+         "type": "uint256"
+       },
+       {
+         "name": "availableBorrowsETH",
+         "type": "uint256"
+       },
+       {
+         "name": "currentLiquidationThreshold",
+         "type": "uint256"
+       },
+       {
+         "name": "ltv",
+         "type": "uint256"
+       },
+       {
+         "name": "healthFactor",
+         "type": "uint256"
+       }
+     ]
+   },
+   {
+     "name": "setUserUseReserveAsCollateral",
+     "type": "function",
+     "inputs": [
+       {
+         "name": "asset",
+         "type": "address"
+       },
+       {
+         "name": "useAsCollateral",
+         "type": "bool"
+       }
+     ]
+   },
+   {
+     "name": "getReservesList",
+     "type": "function",
+     "stateMutability": "view",
+     "inputs": [],
+     "outputs": [
+       {
+         "type": "address[]"
+       }
+     ]
+   },
+   {
+     "name": "getAddressesProvider",
+     "type": "function",
+     "stateMutability": "view",
+     "inputs": [],
+     "outputs": [
+       {
+         "type": "address"
+       }
+     ]
+   }
+ ]
+	`
+
+// aaveV2PoolMethods maps the V3 pool methods to their V2 counterpart
+var aaveV2PoolMethods = map[string]string{
+	"supply":             "deposit",
+	"ADDRESSES_PROVIDER": "getAddressesProvider",
+}
+
+// isV2 reports whether the pool of the deployment is a V2 LendingPool
+func (l *AaveOperation) isV2() bool { return l.version == "2" }
+
+// poolMethod translates a V3 pool method name to its name in the ABI of the pool version
+func (l *AaveOperation) poolMethod(name string) string {
+	if !l.isV2() {
+		return name
+	}
+
+	if method, ok := aaveV2PoolMethods[name]; ok {
+		return method
+	}
+
+	return name
+}
+
+// poolABI returns the pool ABI matching version
+func poolABI(version string) string {
+	if version == "2" {
+		return aaveV2ABI
+	}
+
+	return aaveV3ABI
+}
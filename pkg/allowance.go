@@ -0,0 +1,143 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const erc20AllowanceABI = `
+[
+  {
+    "constant": true,
+    "inputs": [
+      { "name": "owner", "type": "address" },
+      { "name": "spender", "type": "address" }
+    ],
+    "name": "allowance",
+    "outputs": [{ "name": "", "type": "uint256" }],
+    "payable": false,
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "constant": false,
+    "inputs": [
+      { "name": "spender", "type": "address" },
+      { "name": "amount", "type": "uint256" }
+    ],
+    "name": "approve",
+    "outputs": [{ "name": "", "type": "bool" }],
+    "payable": false,
+    "stateMutability": "nonpayable",
+    "type": "function"
+  }
+]`
+
+// extraDataCheckAllowance opts Validate into checking that the sender approved
+// the protocol to pull the amount. It is independent of the validation mode
+const extraDataCheckAllowance = "check_allowance"
+
+// ErrInsufficientAllowance is returned when the sender has not approved the
+// protocol to spend the amount. Use errors.As with *AllowanceError to get the
+// approval that is required
+var ErrInsufficientAllowance = errors.New("allowance not enough")
+
+// AllowanceError is the detail of an ErrInsufficientAllowance
+type AllowanceError struct {
+	Token     common.Address
+	Spender   common.Address
+	Allowance *big.Int
+	// Required is the amount Spender must be approved for
+	Required *big.Int
+}
+
+func (e *AllowanceError) Error() string {
+	return fmt.Sprintf("%s: %s approved %s on %s, %s required",
+		ErrInsufficientAllowance, e.Spender, e.Allowance, e.Token, e.Required)
+}
+
+func (e *AllowanceError) Unwrap() error { return ErrInsufficientAllowance }
+
+// ApprovalCalldata returns the calldata of the approve call to send to Token
+// so that the validated action succeeds
+func (e *AllowanceError) ApprovalCalldata() (string, error) {
+	return ApprovalCalldata(e.Spender, e.Required)
+}
+
+// ApprovalCalldata packs the ERC20 approve(spender, amount) call
+func ApprovalCalldata(spender common.Address, amount *big.Int) (string, error) {
+
+	if err := (TransactionParams{Amount: amount}).checkAmountRange(); err != nil {
+		return "", err
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(erc20AllowanceABI))
+	if err != nil {
+		return "", err
+	}
+
+	calldata, err := parsedABI.Pack("approve", spender, amount)
+	if err != nil {
+		return "", err
+	}
+
+	return hexutil.Encode(calldata), nil
+}
+
+// checkAllowance returns an *AllowanceError when params.ExtraData asks for the
+// allowance to be checked and the sender approved spender for less than amount of token
+func checkAllowance(ctx context.Context, client EthClient, params TransactionParams,
+	token, spender common.Address, amount *big.Int) error {
+
+	if !allowanceCheckRequested(params) {
+		return nil
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(erc20AllowanceABI))
+	if err != nil {
+		return err
+	}
+
+	calldata, err := parsedABI.Pack("allowance", params.Sender, spender)
+	if err != nil {
+		return err
+	}
+
+	result, err := client.CallContract(ctx, ethereum.CallMsg{
+		To:   &token,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("could not fetch the allowance of %s: %w", token, err)
+	}
+
+	allowance := new(big.Int)
+	if err := parsedABI.UnpackIntoInterface(&allowance, "allowance", result); err != nil {
+		return err
+	}
+
+	if allowance.Cmp(amount) < 0 {
+		return &AllowanceError{
+			Token:     token,
+			Spender:   spender,
+			Allowance: allowance,
+			Required:  new(big.Int).Set(amount),
+		}
+	}
+
+	return nil
+}
+
+// allowanceCheckRequested reports whether params.ExtraData opts into the allowance check
+func allowanceCheckRequested(params TransactionParams) bool {
+	v, ok := params.ExtraData[extraDataCheckAllowance].(bool)
+	return ok && v
+}
@@ -0,0 +1,43 @@
+//go:build integration
+// +build integration
+
+package pkg
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAave_CheckAllowance(t *testing.T) {
+
+	aave, err := NewAaveOperation(getTestClient(t, ChainETH), EthChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	// an account that never approved the pool
+	sender := common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+
+	params := TransactionParams{
+		Amount:    big.NewInt(1e6),
+		Sender:    sender,
+		Asset:     testUSDC,
+		ExtraData: map[string]interface{}{"check_allowance": true},
+	}
+
+	err = aave.Validate(context.Background(), EthChainID, LoanSupply, params)
+	require.ErrorIs(t, err, ErrInsufficientAllowance)
+
+	var allowanceErr *AllowanceError
+	require.True(t, errors.As(err, &allowanceErr))
+	require.Equal(t, AaveEthereumV3ContractAddress, allowanceErr.Spender)
+	require.Zero(t, allowanceErr.Allowance.Sign())
+	require.Zero(t, allowanceErr.Required.Cmp(params.Amount))
+
+	// the allowance is not checked unless asked for
+	params.ExtraData = nil
+	require.NoError(t, aave.Validate(context.Background(), EthChainID, LoanSupply, params))
+}
@@ -0,0 +1,125 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// handleAllowance mocks the allowance of token to return amount
+func handleAllowance(t *testing.T, client *pkgtest.Client, token common.Address, amount *big.Int) {
+	t.Helper()
+
+	parsedABI, err := abi.JSON(strings.NewReader(erc20AllowanceABI))
+	require.NoError(t, err)
+
+	method := parsedABI.Methods["allowance"]
+	client.HandleContract(token, method.ID, pkgtest.Returns(method, amount))
+}
+
+func TestApprovalCalldata(t *testing.T) {
+
+	calldata, err := ApprovalCalldata(AaveEthereumV3ContractAddress, big.NewInt(1e6))
+	require.NoError(t, err)
+
+	// cast calldata "approve(address,uint256)" 0x87870Bca3F3fD6335C3F4ce8392D69350B4fA4E2 1000000
+	require.Equal(t, "0x095ea7b3"+
+		"00000000000000000000000087870bca3f3fd6335c3f4ce8392d69350b4fa4e2"+
+		"00000000000000000000000000000000000000000000000000000000000f4240", calldata)
+}
+
+func TestAave_CheckAllowance_Unit(t *testing.T) {
+
+	aave := newLeveragedAaveOperation(t, big.NewInt(0))
+	client := aave.client.(*pkgtest.Client)
+
+	handleAllowance(t, client, testUSDC, big.NewInt(100e6))
+
+	supply := func(amount int64, check bool) TransactionParams {
+		return TransactionParams{
+			Amount:    big.NewInt(amount),
+			Sender:    testAccount,
+			Asset:     testUSDC,
+			ExtraData: map[string]interface{}{"check_allowance": check},
+		}
+	}
+
+	require.NoError(t, aave.Validate(context.Background(), EthChainID, LoanSupply, supply(100e6, true)))
+
+	// the allowance is only read when asked for
+	allowanceCalls := func() int {
+		n := 0
+		for _, call := range client.Calls() {
+			if *call.To == testUSDC {
+				n++
+			}
+		}
+		return n
+	}
+
+	calls := allowanceCalls()
+	require.NoError(t, aave.Validate(context.Background(), EthChainID, LoanSupply, supply(200e6, false)))
+	require.Equal(t, calls, allowanceCalls())
+
+	err := aave.Validate(context.Background(), EthChainID, LoanSupply, supply(200e6, true))
+	require.ErrorIs(t, err, ErrInsufficientAllowance)
+
+	var allowanceErr *AllowanceError
+	require.True(t, errors.As(err, &allowanceErr))
+	require.Equal(t, testUSDC, allowanceErr.Token)
+	require.Equal(t, AaveEthereumV3ContractAddress, allowanceErr.Spender)
+	require.Zero(t, allowanceErr.Allowance.Cmp(big.NewInt(100e6)))
+	require.Zero(t, allowanceErr.Required.Cmp(big.NewInt(200e6)))
+
+	calldata, err := allowanceErr.ApprovalCalldata()
+	require.NoError(t, err)
+
+	expected, err := ApprovalCalldata(AaveEthereumV3ContractAddress, big.NewInt(200e6))
+	require.NoError(t, err)
+	require.Equal(t, expected, calldata)
+}
+
+func TestERC4626_CheckAllowance_Unit(t *testing.T) {
+
+	client := pkgtest.NewClient(EthChainID)
+
+	sdai, err := NewSavingsDAIOperation(client, EthChainID)
+	require.NoError(t, err)
+
+	handleAllowance(t, client, testDAI, big.NewInt(10))
+
+	// 6 shares are worth 12 DAI
+	method := sdai.parsedABI.Methods["previewMint"]
+	client.HandleContract(SavingsDAIContractAddress, method.ID, pkgtest.Returns(method, big.NewInt(12)))
+
+	validate := func(amount int64, shares bool) error {
+		return sdai.Validate(context.Background(), EthChainID, ERC20Stake, TransactionParams{
+			Sender: testAccount,
+			Asset:  testDAI,
+			Amount: big.NewInt(amount),
+			ExtraData: map[string]interface{}{
+				"shares":          shares,
+				"check_allowance": true,
+				"validation_mode": "lenient",
+			},
+		})
+	}
+
+	require.NoError(t, validate(10, false))
+	require.ErrorIs(t, validate(11, false), ErrInsufficientAllowance)
+
+	err = validate(6, true)
+	require.ErrorIs(t, err, ErrInsufficientAllowance)
+
+	var allowanceErr *AllowanceError
+	require.True(t, errors.As(err, &allowanceErr))
+	require.Equal(t, SavingsDAIContractAddress, allowanceErr.Spender)
+	require.Zero(t, allowanceErr.Required.Cmp(big.NewInt(12)))
+}
@@ -0,0 +1,107 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// extraDataStrictDecimals opts Validate into rejecting amounts that are not
+// in the smallest unit of the asset. It is set to the decimals the amount was
+// scaled with, e.g 18 for an amount converted from a decimal string with 1e18
+const extraDataStrictDecimals = "strict_decimals"
+
+// ErrAmountPrecision is returned when an amount carries more decimals than the
+// asset has, e.g a fraction of a micro USDC
+var ErrAmountPrecision = errors.New("amount is more precise than the asset decimals")
+
+// AssetMetadataUser is implemented by the operations validating amounts against
+// the decimals of their assets. The registry sets the metadata of WithAssetMetadata
+// on them, the decimals of the assets it does not know are read from the chain
+type AssetMetadataUser interface {
+	SetAssetMetadata(metadata AssetMetadata)
+}
+
+// checkAmountPrecision checks, when params.ExtraData sets strict_decimals, that
+// params.Amount is scaled with the decimals of token. Amounts are packed as is so
+// an amount scaled with more decimals would move more tokens than intended, e.g
+// 1e12 scaled with 18 decimals is 1 micro USDC but is sent as 1,000,000 USDC.
+// ErrAmountPrecision is returned when the amount is more precise than the token.
+// The decimals are taken from metadata, which can be nil, or read from the chain
+func checkAmountPrecision(ctx context.Context, client EthClient, metadata AssetMetadata,
+	chainID *big.Int, params TransactionParams, token common.Address) error {
+
+	v, ok := params.ExtraData[extraDataStrictDecimals]
+	if !ok {
+		return nil
+	}
+
+	amountDecimals, err := toUint8(v)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", extraDataStrictDecimals, err)
+	}
+
+	decimals, err := tokenDecimals(ctx, client, metadata, chainID, token)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case amountDecimals > decimals:
+		return fmt.Errorf("%w: %s has %d decimals, the amount is scaled with %d",
+			ErrAmountPrecision, token, decimals, amountDecimals)
+	case amountDecimals < decimals:
+		return fmt.Errorf("%s has %d decimals, the amount is scaled with %d",
+			token, decimals, amountDecimals)
+	}
+
+	return nil
+}
+
+// tokenDecimals returns the decimals of the native token, of token in metadata
+// or reads them from the ERC20
+func tokenDecimals(ctx context.Context, client EthClient, metadata AssetMetadata,
+	chainID *big.Int, token common.Address) (uint8, error) {
+
+	if IsNativeToken(token) {
+		_, decimals, err := NativeToken(chainID)
+		return decimals, err
+	}
+
+	if metadata != nil {
+		if info, ok := metadata.AssetInfo(chainID, token); ok {
+			return info.Decimals, nil
+		}
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(erc20MetadataABI))
+	if err != nil {
+		return 0, err
+	}
+
+	calldata, err := parsedABI.Pack("decimals")
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := client.CallContract(ctx, ethereum.CallMsg{
+		To:   &token,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("could not fetch the decimals of %s: %w", token, err)
+	}
+
+	var decimals uint8
+	if err := parsedABI.UnpackIntoInterface(&decimals, "decimals", result); err != nil {
+		return 0, err
+	}
+
+	return decimals, nil
+}
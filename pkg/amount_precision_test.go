@@ -0,0 +1,110 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAave_Validate_StrictDecimals(t *testing.T) {
+
+	aave := newLeveragedAaveOperation(t, big.NewInt(0))
+	client := aave.client.(*pkgtest.Client)
+
+	parsedABI, err := abi.JSON(strings.NewReader(erc20MetadataABI))
+	require.NoError(t, err)
+
+	method := parsedABI.Methods["decimals"]
+	client.HandleContract(testUSDC, method.ID, pkgtest.Returns(method, uint8(6)))
+
+	// 1 USDC scaled with 18 decimals and an extra wei
+	amount, ok := new(big.Int).SetString("1000000000000000001", 10)
+	require.True(t, ok)
+
+	params := TransactionParams{
+		Amount:    amount,
+		Sender:    testAccount,
+		Asset:     testUSDC,
+		ExtraData: map[string]interface{}{"strict_decimals": 18},
+	}
+
+	err = aave.Validate(context.Background(), EthChainID, LoanSupply, params)
+	require.ErrorIs(t, err, ErrAmountPrecision)
+
+	// 1 micro USDC scaled with 18 decimals would be packed as 1,000,000 USDC
+	params.Amount = big.NewInt(1e12)
+	err = aave.Validate(context.Background(), EthChainID, LoanSupply, params)
+	require.ErrorIs(t, err, ErrAmountPrecision)
+
+	// 1.50 USDC scaled with 2 decimals would be packed as 150 micro USDC
+	params.Amount = big.NewInt(150)
+	params.ExtraData = map[string]interface{}{"strict_decimals": 2}
+	require.ErrorContains(t, aave.Validate(context.Background(), EthChainID, LoanSupply, params),
+		"the amount is scaled with 2")
+
+	// the amount is in the smallest unit already
+	params.Amount = big.NewInt(1_000_001)
+	params.ExtraData = map[string]interface{}{"strict_decimals": 6}
+	require.NoError(t, aave.Validate(context.Background(), EthChainID, LoanSupply, params))
+
+	t.Run("not strict", func(t *testing.T) {
+		params := params
+		params.Amount = amount
+		params.ExtraData = nil
+
+		calls := client.CallCount()
+		require.NoError(t, aave.Validate(context.Background(), EthChainID, LoanSupply, params))
+
+		for _, call := range client.Calls()[calls:] {
+			require.NotEqual(t, testUSDC, *call.To)
+		}
+	})
+
+	t.Run("decimals of the tokens registry", func(t *testing.T) {
+		aave.SetAssetMetadata(stubAssetMetadata{testUSDC: {Address: testUSDC, Symbol: "USDC", Decimals: 6}})
+		defer aave.SetAssetMetadata(nil)
+
+		params := params
+		params.Amount = big.NewInt(1e12)
+		params.ExtraData = map[string]interface{}{"strict_decimals": 18}
+
+		calls := client.CallCount()
+		require.ErrorIs(t, aave.Validate(context.Background(), EthChainID, LoanSupply, params), ErrAmountPrecision)
+
+		for _, call := range client.Calls()[calls:] {
+			require.NotEqual(t, testUSDC, *call.To)
+		}
+	})
+
+	t.Run("invalid decimals", func(t *testing.T) {
+		params := params
+		params.ExtraData = map[string]interface{}{"strict_decimals": "eighteen"}
+
+		require.ErrorContains(t, aave.Validate(context.Background(), EthChainID, LoanSupply, params),
+			"invalid strict_decimals")
+	})
+}
+
+func TestCheckAmountPrecision_Native(t *testing.T) {
+
+	params := TransactionParams{
+		Amount:    big.NewInt(1),
+		Sender:    testAccount,
+		Asset:     common.HexToAddress(nativeDenomAddress),
+		ExtraData: map[string]interface{}{"strict_decimals": 18},
+	}
+
+	// native decimals are known without a node
+	client := NewOfflineClient(BscChainID)
+	require.NoError(t, checkAmountPrecision(context.Background(), client, nil, BscChainID, params, params.Asset))
+
+	params.ExtraData["strict_decimals"] = 19
+	require.ErrorIs(t, checkAmountPrecision(context.Background(), client, nil, BscChainID, params, params.Asset),
+		ErrAmountPrecision)
+}
@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocol_NilAmount(t *testing.T) {
+
+	link := common.HexToAddress("0x514910771AF9Ca656af840dff83E8264EcF986CA")
+	native := common.HexToAddress(nativeDenomAddress)
+
+	lido, err := NewLidoOperation(pkgtest.NewClient(EthChainID), EthChainID)
+	require.NoError(t, err)
+
+	ankr, err := NewAnkrOperation(pkgtest.NewClient(EthChainID), EthChainID)
+	require.NoError(t, err)
+
+	aave, err := NewAaveOperation(pkgtest.NewClient(EthChainID), EthChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	compound, err := NewCompoundOperation(newCompoundMarket(t, link).client(), EthChainID,
+		common.HexToAddress(CompoundV3USDCPool))
+	require.NoError(t, err)
+
+	listaStaking, err := NewListaStakingOperation(pkgtest.NewClient(BscChainID), BscChainID)
+	require.NoError(t, err)
+
+	listaLending, err := NewListaLendingOperation(pkgtest.NewClient(BscChainID), BscChainID)
+	require.NoError(t, err)
+
+	tt := []struct {
+		name     string
+		protocol Protocol
+		chainID  *big.Int
+		asset    common.Address
+	}{
+		{name: "lido", protocol: lido, chainID: EthChainID, asset: native},
+		{name: "ankr", protocol: ankr, chainID: EthChainID, asset: native},
+		{name: "aave", protocol: aave, chainID: EthChainID, asset: testUSDC},
+		{name: "aave native", protocol: aave, chainID: EthChainID, asset: native},
+		{name: "compound", protocol: compound, chainID: EthChainID, asset: link},
+		{name: "lista staking", protocol: listaStaking, chainID: BscChainID, asset: native},
+		{name: "lista lending", protocol: listaLending, chainID: BscChainID, asset: slisBNBTokenAddress},
+	}
+
+	for _, v := range tt {
+		t.Run(v.name, func(t *testing.T) {
+			params := TransactionParams{
+				Sender: testAccount,
+				Asset:  v.asset,
+				ExtraData: map[string]interface{}{
+					"referral_code": 0,
+					"use_atokens":   true,
+				},
+			}
+
+			for _, action := range v.protocol.Capabilities().SupportedActions {
+				// switching eMode, collateral or managers takes no amount
+				if action == LoanSetEMode || action == LoanSetCollateral || action == LoanAllowManager {
+					continue
+				}
+
+				require.NotPanics(t, func() {
+					err := v.protocol.Validate(context.Background(), v.chainID, action, params)
+					require.ErrorIs(t, err, ErrAmountRequired, action.String())
+
+					// calldata not carrying the amount can still be generated
+					_, err = v.protocol.GenerateCalldata(context.Background(), v.chainID, action, params)
+					if err != nil {
+						require.ErrorIs(t, err, ErrAmountRequired, action.String())
+					}
+				}, action.String())
+			}
+		})
+	}
+}
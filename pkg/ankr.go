@@ -3,7 +3,6 @@ package pkg
 import (
 	"context"
 	"encoding/hex"
-	"errors"
 	"fmt"
 	"math/big"
 	"strings"
@@ -11,7 +10,6 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 const ankrABI = `
@@ -34,9 +32,63 @@ const ankrABI = `
    }
  ]`
 
-var ankrEthER20Account = common.HexToAddress("0xE95A203B1a91a908F9B9CE46459d101078c2c3cb")
+// ankrPolygonABI is the swap pool Ankr uses for liquid staking on Polygon.
+// nativeToCeros selects the direction, MATIC to ankrMATIC when true
+const ankrPolygonABI = `
+ [
+   {
+     "name": "swapEth",
+     "type": "function",
+     "stateMutability": "payable",
+     "inputs": [
+       { "internalType": "bool", "name": "nativeToCeros", "type": "bool" },
+       { "internalType": "uint256", "name": "amountIn", "type": "uint256" },
+       { "internalType": "address", "name": "receiver", "type": "address" }
+     ],
+     "outputs": [{ "internalType": "uint256", "name": "amountOut", "type": "uint256" }]
+   },
+   {
+     "name": "swap",
+     "type": "function",
+     "stateMutability": "nonpayable",
+     "inputs": [
+       { "internalType": "bool", "name": "nativeToCeros", "type": "bool" },
+       { "internalType": "uint256", "name": "amountIn", "type": "uint256" },
+       { "internalType": "address", "name": "receiver", "type": "address" }
+     ],
+     "outputs": [{ "internalType": "uint256", "name": "amountOut", "type": "uint256" }]
+   }
+ ]`
 
-// AnkrOperation implements the Protocol interface for Ankr
+// ankrBnbABI is the BNB staking pool of Ankr. unstakeCerts burns ankrBNB
+// shares and queues the BNB they are worth for the sender
+const ankrBnbABI = `
+ [
+   {
+     "name": "stakeCerts",
+     "type": "function",
+     "stateMutability": "payable",
+     "inputs": []
+   },
+   {
+     "name": "unstakeCerts",
+     "type": "function",
+     "stateMutability": "nonpayable",
+     "inputs": [
+       { "internalType": "uint256", "name": "shares", "type": "uint256" }
+     ]
+   }
+ ]`
+
+var (
+	ankrEthER20Account   = common.HexToAddress("0xE95A203B1a91a908F9B9CE46459d101078c2c3cb")
+	ankrMaticER20Account = common.HexToAddress("0x0E9b89007eEE9c958c0EDA24eF70723C2C93dD58")
+	ankrBnbER20Account   = common.HexToAddress("0x52F24a5e03aee338Da5fd9Df68D2b6FAe1178827")
+)
+
+// AnkrOperation implements the Protocol interface for Ankr.
+// ETH is staked into ankrETH on Ethereum, MATIC into ankrMATIC on Polygon
+// and BNB into ankrBNB on BSC
 type AnkrOperation struct {
 	parsedABI abi.ABI
 	contract  common.Address
@@ -44,11 +96,35 @@ type AnkrOperation struct {
 	version   string
 	erc20ABI  abi.ABI
 
-	client *ethclient.Client
+	// certToken is the liquid staking token minted on chainID
+	certToken common.Address
+
+	client   EthClient
+	observer Observer
+
+	// disabled through the registry, see SetProtocolEnabled
+	deprecation
 }
 
-func NewAnkrOperation(client *ethclient.Client, chainID *big.Int) (*AnkrOperation, error) {
-	parsedABI, err := abi.JSON(strings.NewReader(ankrABI))
+var _ Protocol = (*AnkrOperation)(nil)
+
+func NewAnkrOperation(client EthClient, chainID *big.Int) (*AnkrOperation, error) {
+
+	var contract, certToken common.Address
+	var abiJSON string
+
+	switch {
+	case IsEth(chainID):
+		contract, certToken, abiJSON = AnkrContractAddress, ankrEthER20Account, ankrABI
+	case IsPolygon(chainID):
+		contract, certToken, abiJSON = AnkrPolygonContractAddress, ankrMaticER20Account, ankrPolygonABI
+	case IsBnb(chainID):
+		contract, certToken, abiJSON = AnkrBnbContractAddress, ankrBnbER20Account, ankrBnbABI
+	default:
+		return nil, ErrChainUnsupported
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
 	if err != nil {
 		return nil, err
 	}
@@ -60,21 +136,45 @@ func NewAnkrOperation(client *ethclient.Client, chainID *big.Int) (*AnkrOperatio
 
 	return &AnkrOperation{
 		parsedABI: parsedABI,
-		contract:  AnkrContractAddress,
+		contract:  contract,
 		chainID:   chainID,
 		version:   "3",
 		client:    client,
 		erc20ABI:  erc20ABI,
+		certToken: certToken,
 	}, nil
 }
 
 // GenerateCalldata creates the necessary blockchain transaction data
 func (a *AnkrOperation) GenerateCalldata(ctx context.Context, chainID *big.Int,
 	action ContractAction, params TransactionParams) (string, error) {
-	if chainID.Int64() != 1 {
+	if err := a.deprecation.check(a.GetUniqueKey()); err != nil {
+		return "", err
+	}
+
+	if err := params.checkAmountRange(); err != nil {
+		return "", err
+	}
+
+	return observeCalldata(a.observer, a.GetName(), action, func() (string, error) {
+		return a.generateCalldata(ctx, chainID, action, params)
+	})
+}
+
+func (a *AnkrOperation) generateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+	if !a.isSupportedChain(chainID) {
 		return "", ErrChainUnsupported
 	}
 
+	if IsPolygon(a.chainID) {
+		return a.generatePolygonCalldata(action, params)
+	}
+
+	if IsBnb(a.chainID) {
+		return a.generateBnbCalldata(action, params)
+	}
+
 	var calldata []byte
 	var err error
 
@@ -88,6 +188,10 @@ func (a *AnkrOperation) GenerateCalldata(ctx context.Context, chainID *big.Int,
 
 	case NativeUnStake:
 
+		if params.Amount == nil {
+			return "", ErrAmountRequired
+		}
+
 		calldata, err = a.parsedABI.Pack("unstakeAETH", params.Amount)
 		if err != nil {
 			return "", err
@@ -95,31 +199,124 @@ func (a *AnkrOperation) GenerateCalldata(ctx context.Context, chainID *big.Int,
 
 	default:
 
-		return "", errors.New("operation not supported")
+		return "", ErrUnsupportedAction
 	}
 
 	return HexPrefix + hex.EncodeToString(calldata), nil
 }
 
+// generatePolygonCalldata swaps MATIC for ankrMATIC when staking and
+// ankrMATIC back to MATIC when unstaking through the swap pool
+func (a *AnkrOperation) generatePolygonCalldata(action ContractAction,
+	params TransactionParams) (string, error) {
+
+	if params.Amount == nil {
+		return "", ErrAmountRequired
+	}
+
+	var calldata []byte
+	var err error
+
+	switch action {
+	case NativeStake:
+		calldata, err = a.parsedABI.Pack("swapEth", true, params.Amount, params.GetBeneficiaryOwner())
+	case NativeUnStake:
+		calldata, err = a.parsedABI.Pack("swap", false, params.Amount, params.GetBeneficiaryOwner())
+	default:
+		return "", ErrUnsupportedAction
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return HexPrefix + hex.EncodeToString(calldata), nil
+}
+
+// generateBnbCalldata stakes BNB for ankrBNB and unstakes params.Amount of
+// ankrBNB shares, not BNB, through the staking pool
+func (a *AnkrOperation) generateBnbCalldata(action ContractAction,
+	params TransactionParams) (string, error) {
+
+	var calldata []byte
+	var err error
+
+	switch action {
+	case NativeStake:
+		calldata, err = a.parsedABI.Pack("stakeCerts")
+	case NativeUnStake:
+		if params.Amount == nil {
+			return "", ErrAmountRequired
+		}
+
+		calldata, err = a.parsedABI.Pack("unstakeCerts", params.Amount)
+	default:
+		return "", ErrUnsupportedAction
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return HexPrefix + hex.EncodeToString(calldata), nil
+}
+
+// EstimateGas estimates the gas needed to execute the generated calldata.
+// Staking sends params.Amount along with the call
+func (a *AnkrOperation) EstimateGas(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (uint64, error) {
+
+	tx, err := a.BuildTransaction(ctx, chainID, action, params)
+	if err != nil {
+		return 0, err
+	}
+
+	return estimateGas(ctx, a.client, params.Sender, tx)
+}
+
+// BuildTransaction generates the calldata for the action together with
+// the contract to call and the value to send
+func (a *AnkrOperation) BuildTransaction(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (*Transaction, error) {
+
+	calldata, err := a.GenerateCalldata(ctx, chainID, action, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{
+		To:    a.GetContractAddress(chainID),
+		Data:  calldata,
+		Value: a.CallValue(action, params),
+	}, nil
+}
+
 // Validate checks if the provided parameters are valid for the specified action
 func (l *AnkrOperation) Validate(ctx context.Context,
 	chainID *big.Int, action ContractAction, params TransactionParams) error {
 
-	if chainID.Int64() != 1 {
+	if err := l.deprecation.check(l.GetUniqueKey()); err != nil {
+		return err
+	}
+
+	if !l.isSupportedChain(chainID) {
 		return ErrChainUnsupported
 	}
 
 	if !l.IsSupportedAsset(ctx, l.chainID, params.Asset) {
-		return fmt.Errorf("asset not supported %s", params.Asset)
+		return fmt.Errorf("%w %s", ErrAssetNotSupported, params.Asset)
 	}
 
-	if action == NativeStake {
-		return nil
+	if !l.IsSupportedAction(action) {
+		return ErrUnsupportedAction
 	}
 
-	// only validate amount during withdrawal
-	if params.Amount.Cmp(big.NewInt(0)) <= 0 {
-		return errors.New("amount to unstake must be greater than zero")
+	if err := params.ValidateAmount(); err != nil {
+		return err
+	}
+
+	if action == NativeStake || params.lenientValidation(ValidationStrict) {
+		return nil
 	}
 
 	_, balance, err := l.GetBalance(ctx, l.chainID, params.Sender, params.Asset)
@@ -128,19 +325,20 @@ func (l *AnkrOperation) Validate(ctx context.Context,
 	}
 
 	if balance.Cmp(params.Amount) == -1 {
-		return errors.New("your balance is not enough")
+		return ErrInsufficientBalance
 	}
 
 	return nil
 }
 
-// GetBalance retrieves the balance for a specified account and asset
+// GetBalance retrieves the balance of the liquid staking token of the chain,
+// e.g ankrBNB on BSC, whatever asset is given. It is the amount unstaking takes
 func (l *AnkrOperation) GetBalance(ctx context.Context, chainID *big.Int,
 	account, _ common.Address) (common.Address, *big.Int, error) {
 
 	var address common.Address
 
-	if chainID.Int64() != 1 {
+	if !l.isSupportedChain(chainID) {
 		return address, nil, ErrChainUnsupported
 	}
 
@@ -150,7 +348,7 @@ func (l *AnkrOperation) GetBalance(ctx context.Context, chainID *big.Int,
 	}
 
 	result, err := l.client.CallContract(context.Background(), ethereum.CallMsg{
-		To:   &ankrEthER20Account,
+		To:   &l.certToken,
 		Data: callData,
 	}, nil)
 	if err != nil {
@@ -159,23 +357,33 @@ func (l *AnkrOperation) GetBalance(ctx context.Context, chainID *big.Int,
 
 	balance := new(big.Int)
 	err = l.erc20ABI.UnpackIntoInterface(&balance, "balanceOf", result)
-	return ankrEthER20Account, balance, err
+	return l.certToken, balance, err
 }
 
-// GetSupportedAssets returns a list of assets supported by the protocol on the specified chain
+// GetSupportedAssets returns the native token staked and the liquid staking
+// token of the chain, unstaked and returned by GetBalance
 func (l *AnkrOperation) GetSupportedAssets(ctx context.Context, chainID *big.Int) ([]common.Address, error) {
+	if !l.isSupportedChain(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
 	return []common.Address{
 		common.HexToAddress(nativeDenomAddress),
+		l.certToken,
 	}, nil
 }
 
+func (l *AnkrOperation) isSupportedChain(chain *big.Int) bool {
+	return l.chainID.Cmp(chain) == 0
+}
+
 // IsSupportedAsset checks if the specified asset is supported on the given chain
 func (l *AnkrOperation) IsSupportedAsset(ctx context.Context, chainID *big.Int, asset common.Address) bool {
-	if chainID.Int64() != 1 {
+	if !l.isSupportedChain(chainID) {
 		return false
 	}
 
-	return IsNativeToken(asset) || asset.Hex() == ankrEthER20Account.Hex()
+	return IsNativeToken(asset) || asset == l.certToken
 }
 
 // GetProtocolConfig returns the protocol config for a specific chain
@@ -197,8 +405,48 @@ func (l *AnkrOperation) GetType() ProtocolType { return TypeStake }
 // GetContractAddress returns the contract address for a specific chain
 func (l *AnkrOperation) GetContractAddress(chainID *big.Int) common.Address { return l.contract }
 
+// GetContractAddressForAction returns the contract the calldata of action is sent to,
+// the same for every action
+func (l *AnkrOperation) GetContractAddressForAction(chainID *big.Int, action ContractAction) (common.Address, error) {
+	return contractAddressForAction(l, chainID, action)
+}
+
 // Name returns the human readable name for the protocol
 func (l *AnkrOperation) GetName() string { return Ankr }
 
 // GetVersion returns the version of the protocol
 func (l *AnkrOperation) GetVersion() string { return l.version }
+
+// GetUniqueKey returns the key identifying the protocol across chains
+func (l *AnkrOperation) GetUniqueKey() string { return protocolKey(l.GetName(), l.chainID) }
+
+// CallValue returns the native amount to send along with the calldata.
+// The stakeAndClaimAethC, swapEth and stakeCerts calls are payable and take
+// the staked ETH, MATIC or BNB as msg.value
+func (l *AnkrOperation) CallValue(action ContractAction, params TransactionParams) *big.Int {
+	if action == NativeStake {
+		return params.nativeCallValue()
+	}
+
+	return big.NewInt(0)
+}
+
+// Capabilities describes the actions, chains and extra data supported by the protocol
+func (l *AnkrOperation) Capabilities() ProtocolCapabilities {
+	return ProtocolCapabilities{
+		SupportedActions:  []ContractAction{NativeStake, NativeUnStake},
+		SupportedChains:   []*big.Int{l.chainID},
+		RequiresExtraData: []string{},
+	}
+}
+
+// IsSupportedAction reports whether calldata can be generated for action
+func (l *AnkrOperation) IsSupportedAction(action ContractAction) bool {
+	return l.Capabilities().Supports(action)
+}
+
+// SetObserver implements Observable
+func (a *AnkrOperation) SetObserver(observer Observer) {
+	a.observer = observer
+	a.client = observeClient(a.client, a.GetName(), observer)
+}
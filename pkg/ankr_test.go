@@ -143,3 +143,19 @@ func TestAnkr_GetBalance(t *testing.T) {
 
 	validateSymbolFromToken(t, client, token, "ankrETH")
 }
+
+func TestAnkr_Bnb_GetBalance(t *testing.T) {
+
+	client := getTestClient(t, ChainBSC)
+
+	ankr, err := NewAnkrOperation(client, BscChainID)
+	require.NoError(t, err)
+
+	token, bal, err := ankr.GetBalance(context.Background(), BscChainID,
+		emptyTestWallet, common.HexToAddress(nativeDenomAddress))
+
+	require.NoError(t, err)
+	require.NotNil(t, bal)
+
+	validateSymbolFromToken(t, client, token, "ankrBNB")
+}
@@ -0,0 +1,252 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var testAccount = common.HexToAddress("0x6a22640F02F8c8b576a3193674c4aE97e0f8d007")
+
+func newTestAnkrOperation(t *testing.T, ankrETHBalance *big.Int) *AnkrOperation {
+	t.Helper()
+
+	client := pkgtest.NewClient(EthChainID)
+
+	ankr, err := NewAnkrOperation(client, EthChainID)
+	require.NoError(t, err)
+
+	method := ankr.erc20ABI.Methods["balanceOf"]
+	client.HandleContract(ankrEthER20Account, method.ID, pkgtest.Returns(method, ankrETHBalance))
+
+	return ankr
+}
+
+func TestAnkr_Validate_Unit(t *testing.T) {
+
+	t.Run("unstaking more than the ankrETH balance", func(t *testing.T) {
+		ankr := newTestAnkrOperation(t, big.NewInt(10))
+
+		err := ankr.Validate(context.Background(), EthChainID, NativeUnStake, TransactionParams{
+			Amount: big.NewInt(11),
+			Asset:  common.HexToAddress(nativeDenomAddress),
+			Sender: testAccount,
+		})
+		require.ErrorIs(t, err, ErrInsufficientBalance)
+	})
+
+	t.Run("unstaking within the ankrETH balance", func(t *testing.T) {
+		ankr := newTestAnkrOperation(t, big.NewInt(10))
+
+		err := ankr.Validate(context.Background(), EthChainID, NativeUnStake, TransactionParams{
+			Amount: big.NewInt(10),
+			Asset:  common.HexToAddress(nativeDenomAddress),
+			Sender: testAccount,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("missing amount", func(t *testing.T) {
+		ankr := newTestAnkrOperation(t, big.NewInt(10))
+
+		for _, action := range []ContractAction{NativeStake, NativeUnStake} {
+			err := ankr.Validate(context.Background(), EthChainID, action, TransactionParams{
+				Asset:  common.HexToAddress(nativeDenomAddress),
+				Sender: testAccount,
+			})
+			require.ErrorIs(t, err, ErrAmountRequired)
+		}
+	})
+
+	t.Run("unsupported asset", func(t *testing.T) {
+		ankr := newTestAnkrOperation(t, big.NewInt(10))
+
+		err := ankr.Validate(context.Background(), EthChainID, NativeStake, TransactionParams{
+			Amount: big.NewInt(1),
+			Asset:  testUSDC,
+			Sender: testAccount,
+		})
+		require.ErrorIs(t, err, ErrAssetNotSupported)
+	})
+
+	t.Run("unsupported action", func(t *testing.T) {
+		ankr := newTestAnkrOperation(t, big.NewInt(10))
+
+		err := ankr.Validate(context.Background(), EthChainID, LoanSupply, TransactionParams{
+			Amount: big.NewInt(1),
+			Asset:  common.HexToAddress(nativeDenomAddress),
+			Sender: testAccount,
+		})
+		require.ErrorIs(t, err, ErrUnsupportedAction)
+	})
+
+	t.Run("staking does not read balances", func(t *testing.T) {
+		ankr := newTestAnkrOperation(t, big.NewInt(0))
+
+		err := ankr.Validate(context.Background(), EthChainID, NativeStake, TransactionParams{
+			Amount: big.NewInt(1e18),
+			Asset:  common.HexToAddress(nativeDenomAddress),
+			Sender: testAccount,
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestAnkr_GetBalance_Unit(t *testing.T) {
+	ankr := newTestAnkrOperation(t, big.NewInt(42))
+
+	token, bal, err := ankr.GetBalance(context.Background(), EthChainID, testAccount, common.Address{})
+	require.NoError(t, err)
+	require.Equal(t, ankrEthER20Account, token)
+	require.Equal(t, big.NewInt(42), bal)
+}
+
+func TestAnkr_Polygon_Unit(t *testing.T) {
+
+	client := pkgtest.NewClient(PolygonChainID)
+
+	ankr, err := NewAnkrOperation(client, PolygonChainID)
+	require.NoError(t, err)
+
+	method := ankr.erc20ABI.Methods["balanceOf"]
+	client.HandleContract(ankrMaticER20Account, method.ID, pkgtest.Returns(method, big.NewInt(7)))
+
+	params := TransactionParams{
+		Amount: big.NewInt(1e18),
+		Asset:  common.HexToAddress(nativeDenomAddress),
+		Sender: testAccount,
+	}
+
+	t.Run("stake swaps MATIC for ankrMATIC", func(t *testing.T) {
+		tx, err := ankr.BuildTransaction(context.Background(), PolygonChainID, NativeStake, params)
+		require.NoError(t, err)
+
+		// cast calldata "swapEth(bool,uint256,address)" true 1000000000000000000 0x6a22...d007
+		expected := "0x4f6df21a" +
+			"0000000000000000000000000000000000000000000000000000000000000001" + // nativeToCeros
+			"0000000000000000000000000000000000000000000000000de0b6b3a7640000" + // amountIn
+			"0000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d007" // receiver
+
+		require.Equal(t, AnkrPolygonContractAddress, tx.To)
+		require.Equal(t, expected, tx.Data)
+		require.Equal(t, params.Amount, tx.Value)
+	})
+
+	t.Run("unstake swaps ankrMATIC for MATIC", func(t *testing.T) {
+		tx, err := ankr.BuildTransaction(context.Background(), PolygonChainID, NativeUnStake, params)
+		require.NoError(t, err)
+
+		// cast calldata "swap(bool,uint256,address)" false 1000000000000000000 0x6a22...d007
+		expected := "0x5b56e038" +
+			"0000000000000000000000000000000000000000000000000000000000000000" + // nativeToCeros
+			"0000000000000000000000000000000000000000000000000de0b6b3a7640000" + // amountIn
+			"0000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d007" // receiver
+
+		require.Equal(t, expected, tx.Data)
+		require.Zero(t, tx.Value.Sign())
+	})
+
+	t.Run("ethereum is not served", func(t *testing.T) {
+		_, err := ankr.GenerateCalldata(context.Background(), EthChainID, NativeStake, params)
+		require.ErrorIs(t, err, ErrChainUnsupported)
+	})
+
+	t.Run("balance is read from ankrMATIC", func(t *testing.T) {
+		token, bal, err := ankr.GetBalance(context.Background(), PolygonChainID, testAccount, common.Address{})
+		require.NoError(t, err)
+		require.Equal(t, ankrMaticER20Account, token)
+		require.Equal(t, big.NewInt(7), bal)
+
+		require.True(t, ankr.IsSupportedAsset(context.Background(), PolygonChainID, ankrMaticER20Account))
+		require.False(t, ankr.IsSupportedAsset(context.Background(), PolygonChainID, ankrEthER20Account))
+	})
+
+	t.Run("unsupported chain", func(t *testing.T) {
+		_, err := NewAnkrOperation(pkgtest.NewClient(GnosisChainID), GnosisChainID)
+		require.ErrorIs(t, err, ErrChainUnsupported)
+	})
+}
+
+func TestAnkr_Bnb_Unit(t *testing.T) {
+
+	client := pkgtest.NewClient(BscChainID)
+
+	ankr, err := NewAnkrOperation(client, BscChainID)
+	require.NoError(t, err)
+
+	// 5 ankrBNB held, the BNB balance of the account is never read
+	method := ankr.erc20ABI.Methods["balanceOf"]
+	client.HandleContract(ankrBnbER20Account, method.ID, pkgtest.Returns(method, big.NewInt(5e18)))
+
+	native := common.HexToAddress(nativeDenomAddress)
+
+	t.Run("stake", func(t *testing.T) {
+		tx, err := ankr.BuildTransaction(context.Background(), BscChainID, NativeStake, TransactionParams{
+			Amount: big.NewInt(1e18),
+			Asset:  native,
+			Sender: testAccount,
+		})
+		require.NoError(t, err)
+
+		// cast calldata "stakeCerts()"
+		require.Equal(t, "0xac76d450", tx.Data)
+		require.Equal(t, AnkrBnbContractAddress, tx.To)
+		require.Equal(t, big.NewInt(1e18), tx.Value)
+	})
+
+	t.Run("unstake burns ankrBNB shares", func(t *testing.T) {
+		tx, err := ankr.BuildTransaction(context.Background(), BscChainID, NativeUnStake, TransactionParams{
+			Amount: big.NewInt(3e18),
+			Asset:  ankrBnbER20Account,
+			Sender: testAccount,
+		})
+		require.NoError(t, err)
+
+		// cast calldata "unstakeCerts(uint256)" 3000000000000000000
+		expected := "0x0d904ce2" +
+			"00000000000000000000000000000000000000000000000029a2241af62c0000" // shares
+
+		require.Equal(t, expected, tx.Data)
+		require.Equal(t, AnkrBnbContractAddress, tx.To)
+		require.Zero(t, tx.Value.Sign())
+
+		_, err = ankr.GenerateCalldata(context.Background(), BscChainID, NativeUnStake, TransactionParams{})
+		require.ErrorIs(t, err, ErrAmountRequired)
+	})
+
+	t.Run("unstake is validated against the ankrBNB balance", func(t *testing.T) {
+		for _, asset := range []common.Address{native, ankrBnbER20Account} {
+			err := ankr.Validate(context.Background(), BscChainID, NativeUnStake, TransactionParams{
+				Amount: big.NewInt(5e18),
+				Asset:  asset,
+				Sender: testAccount,
+			})
+			require.NoError(t, err)
+
+			err = ankr.Validate(context.Background(), BscChainID, NativeUnStake, TransactionParams{
+				Amount: big.NewInt(6e18),
+				Asset:  asset,
+				Sender: testAccount,
+			})
+			require.ErrorIs(t, err, ErrInsufficientBalance)
+		}
+	})
+
+	t.Run("balance is read from ankrBNB", func(t *testing.T) {
+		token, bal, err := ankr.GetBalance(context.Background(), BscChainID, testAccount, native)
+		require.NoError(t, err)
+		require.Equal(t, ankrBnbER20Account, token)
+		require.Equal(t, big.NewInt(5e18), bal)
+
+		require.False(t, ankr.IsSupportedAsset(context.Background(), BscChainID, ankrEthER20Account))
+	})
+
+	t.Run("other chains are not served", func(t *testing.T) {
+		_, err := ankr.GenerateCalldata(context.Background(), EthChainID, NativeStake, TransactionParams{})
+		require.ErrorIs(t, err, ErrChainUnsupported)
+	})
+}
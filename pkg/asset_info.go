@@ -0,0 +1,228 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const erc20MetadataABI = `
+[
+  {
+    "constant": true,
+    "inputs": [],
+    "name": "symbol",
+    "outputs": [{ "name": "", "type": "string" }],
+    "payable": false,
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "constant": true,
+    "inputs": [],
+    "name": "decimals",
+    "outputs": [{ "name": "", "type": "uint8" }],
+    "payable": false,
+    "stateMutability": "view",
+    "type": "function"
+  }
+]`
+
+// nativeSymbols are the symbols of the native token of each chain
+var nativeSymbols = map[int64]string{
+	EthChainID.Int64():       "ETH",
+	BscChainID.Int64():       "BNB",
+	PolygonChainID.Int64():   "POL",
+	GnosisChainID.Int64():    "XDAI",
+	AvalancheChainID.Int64(): "AVAX",
+}
+
+// nativeDecimals is the number of decimals of the native token of every supported chain
+const nativeDecimals = 18
+
+// NativeToken returns the symbol and decimals of the native token of chainID,
+// e.g to render the amounts of the native denom
+func NativeToken(chainID *big.Int) (symbol string, decimals uint8, err error) {
+	if chainID == nil || !chainID.IsInt64() {
+		return "", 0, fmt.Errorf("native token of chain %s %w", chainID, ErrChainUnsupported)
+	}
+
+	symbol, ok := nativeSymbols[chainID.Int64()]
+	if !ok {
+		return "", 0, fmt.Errorf("native token of chain %s %w", chainID, ErrChainUnsupported)
+	}
+
+	return symbol, nativeDecimals, nil
+}
+
+// AssetInfo is a supported asset along with its token metadata
+type AssetInfo struct {
+	Address  common.Address
+	Symbol   string
+	Decimals uint8
+}
+
+// AssetMetadata resolves token metadata without going to the chain,
+// e.g from the tokens registry
+type AssetMetadata interface {
+	// AssetInfo returns the metadata of asset and whether it is known
+	AssetInfo(chainID *big.Int, asset common.Address) (AssetInfo, bool)
+}
+
+// GetSupportedAssetsDetailed returns the supported assets of protocol with their
+// symbol and decimals. Assets known to metadata, which can be nil, are not read
+// from the chain. The others are read with a single Multicall3 call, falling back
+// to one call per asset on chains without Multicall3
+func GetSupportedAssetsDetailed(ctx context.Context, client EthClient, protocol Protocol,
+	chainID *big.Int, metadata AssetMetadata) ([]AssetInfo, error) {
+
+	assets, err := protocol.GetSupportedAssets(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(erc20MetadataABI))
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]AssetInfo, len(assets))
+
+	// indexes of the assets to read from the chain
+	var unresolved []int
+
+	for i, asset := range assets {
+		if metadata != nil {
+			if info, ok := metadata.AssetInfo(chainID, asset); ok {
+				infos[i] = info
+				continue
+			}
+		}
+
+		if IsNativeToken(asset) {
+			symbol, decimals, err := NativeToken(chainID)
+			if err != nil {
+				return nil, err
+			}
+
+			infos[i] = AssetInfo{Address: asset, Symbol: symbol, Decimals: decimals}
+			continue
+		}
+
+		unresolved = append(unresolved, i)
+	}
+
+	if len(unresolved) == 0 {
+		return infos, nil
+	}
+
+	symbolCalldata, err := parsedABI.Pack("symbol")
+	if err != nil {
+		return nil, err
+	}
+
+	decimalsCalldata, err := parsedABI.Pack("decimals")
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]multicallCall, 0, 2*len(unresolved))
+	for _, i := range unresolved {
+		calls = append(calls,
+			multicallCall{Target: assets[i], CallData: symbolCalldata},
+			multicallCall{Target: assets[i], CallData: decimalsCalldata})
+	}
+
+	results, err := multicall(ctx, client, calls)
+	if err != nil {
+		results, err = callSequential(ctx, client, calls)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for n, i := range unresolved {
+		info := AssetInfo{Address: assets[i]}
+
+		symbol, err := unpackSymbol(parsedABI, results[2*n])
+		if err != nil {
+			return nil, fmt.Errorf("could not read the symbol of %s: %w", assets[i], err)
+		}
+
+		info.Symbol = symbol
+
+		if err := parsedABI.UnpackIntoInterface(&info.Decimals, "decimals", results[2*n+1]); err != nil {
+			return nil, fmt.Errorf("could not read the decimals of %s: %w", assets[i], err)
+		}
+
+		infos[i] = info
+	}
+
+	return infos, nil
+}
+
+// unpackSymbol decodes the result of symbol(). A few early tokens like MKR
+// return a bytes32 instead of a string
+func unpackSymbol(parsedABI abi.ABI, result []byte) (string, error) {
+	var symbol string
+	if err := parsedABI.UnpackIntoInterface(&symbol, "symbol", result); err == nil {
+		return symbol, nil
+	}
+
+	if len(result) != 32 {
+		return "", fmt.Errorf("unexpected symbol of %d bytes", len(result))
+	}
+
+	return string(bytes.TrimRight(result, "\x00")), nil
+}
+
+// callSequential runs calls one after the other and returns their results in order
+func callSequential(ctx context.Context, client EthClient, calls []multicallCall) ([][]byte, error) {
+
+	results := make([][]byte, 0, len(calls))
+
+	for _, call := range calls {
+		target := call.Target
+
+		result, err := client.CallContract(ctx, ethereum.CallMsg{
+			To:   &target,
+			Data: call.CallData,
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// GetSupportedAssetsDetailed returns the supported assets of the protocol registered
+// at address with their symbol and decimals, see GetSupportedAssetsDetailed
+func (r *ProtocolRegistryImpl) GetSupportedAssetsDetailed(ctx context.Context, chainID *big.Int,
+	address common.Address) ([]AssetInfo, error) {
+
+	protocol, err := r.GetProtocol(chainID, address)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := r.GetChainConfig(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := r.dial(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetSupportedAssetsDetailed(ctx, client, protocol, chainID, r.assetMetadata)
+}
@@ -0,0 +1,37 @@
+//go:build integration
+// +build integration
+
+package pkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSupportedAssetsDetailed_Aave(t *testing.T) {
+
+	client := getTestClient(t, ChainETH)
+
+	aave, err := NewAaveOperation(client, EthChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	assets, err := GetSupportedAssetsDetailed(context.Background(), client, aave, EthChainID, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, assets)
+
+	bySymbol := make(map[string]AssetInfo, len(assets))
+	for _, asset := range assets {
+		bySymbol[asset.Symbol] = asset
+	}
+
+	require.Equal(t, AssetInfo{
+		Address:  common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+		Symbol:   "USDC",
+		Decimals: 6,
+	}, bySymbol["USDC"])
+	require.EqualValues(t, 8, bySymbol["WBTC"].Decimals)
+	require.EqualValues(t, 18, bySymbol["WETH"].Decimals)
+}
@@ -0,0 +1,166 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+type stubAssetMetadata map[common.Address]AssetInfo
+
+func (s stubAssetMetadata) AssetInfo(_ *big.Int, asset common.Address) (AssetInfo, bool) {
+	info, ok := s[asset]
+	return info, ok
+}
+
+func TestGetSupportedAssetsDetailed_Unit(t *testing.T) {
+
+	parsedABI, err := abi.JSON(strings.NewReader(erc20MetadataABI))
+	require.NoError(t, err)
+
+	newClient := func() *pkgtest.Client {
+		client := pkgtest.NewClient(EthChainID)
+
+		method := parsedABI.Methods["symbol"]
+		client.HandleContract(testDAI, method.ID, pkgtest.Returns(method, "DAI"))
+
+		method = parsedABI.Methods["decimals"]
+		client.HandleContract(testDAI, method.ID, pkgtest.Returns(method, uint8(18)))
+
+		return client
+	}
+
+	t.Run("read from the chain", func(t *testing.T) {
+		client := newClient()
+
+		sdai, err := NewSavingsDAIOperation(client, EthChainID)
+		require.NoError(t, err)
+
+		// Multicall3 is not mocked so the metadata is read one call at a time
+		assets, err := GetSupportedAssetsDetailed(context.Background(), client, sdai, EthChainID, nil)
+		require.NoError(t, err)
+		require.Equal(t, []AssetInfo{{Address: testDAI, Symbol: "DAI", Decimals: 18}}, assets)
+	})
+
+	t.Run("known assets are not read", func(t *testing.T) {
+		client := newClient()
+
+		sdai, err := NewSavingsDAIOperation(client, EthChainID)
+		require.NoError(t, err)
+
+		metadata := stubAssetMetadata{testDAI: {Address: testDAI, Symbol: "Dai", Decimals: 18}}
+
+		assets, err := GetSupportedAssetsDetailed(context.Background(), client, sdai, EthChainID, metadata)
+		require.NoError(t, err)
+		require.Equal(t, []AssetInfo{metadata[testDAI]}, assets)
+		require.Zero(t, client.CallCount())
+	})
+
+	t.Run("native token", func(t *testing.T) {
+		client := pkgtest.NewClient(AvalancheChainID)
+
+		benqi, err := NewBenqiOperation(client, AvalancheChainID)
+		require.NoError(t, err)
+
+		assets, err := GetSupportedAssetsDetailed(context.Background(), client, benqi, AvalancheChainID, nil)
+		require.NoError(t, err)
+		require.Equal(t, []AssetInfo{{
+			Address:  common.HexToAddress(nativeDenomAddress),
+			Symbol:   "AVAX",
+			Decimals: 18,
+		}}, assets)
+	})
+}
+
+func TestNativeToken(t *testing.T) {
+
+	tt := []struct {
+		chainID *big.Int
+		symbol  string
+	}{
+		{chainID: EthChainID, symbol: "ETH"},
+		{chainID: BscChainID, symbol: "BNB"},
+		{chainID: PolygonChainID, symbol: "POL"},
+		{chainID: GnosisChainID, symbol: "XDAI"},
+		{chainID: AvalancheChainID, symbol: "AVAX"},
+	}
+
+	for _, v := range tt {
+		symbol, decimals, err := NativeToken(v.chainID)
+		require.NoError(t, err)
+		require.Equal(t, v.symbol, symbol)
+		require.Equal(t, uint8(18), decimals)
+	}
+
+	_, _, err := NativeToken(big.NewInt(999))
+	require.ErrorIs(t, err, ErrChainUnsupported)
+
+	_, _, err = NativeToken(nil)
+	require.ErrorIs(t, err, ErrChainUnsupported)
+}
+
+func TestUnpackSymbol(t *testing.T) {
+
+	parsedABI, err := abi.JSON(strings.NewReader(erc20MetadataABI))
+	require.NoError(t, err)
+
+	result, err := parsedABI.Methods["symbol"].Outputs.Pack("USDC")
+	require.NoError(t, err)
+
+	symbol, err := unpackSymbol(parsedABI, result)
+	require.NoError(t, err)
+	require.Equal(t, "USDC", symbol)
+
+	// MKR returns its symbol as a bytes32
+	var mkr [32]byte
+	copy(mkr[:], "MKR")
+
+	symbol, err = unpackSymbol(parsedABI, mkr[:])
+	require.NoError(t, err)
+	require.Equal(t, "MKR", symbol)
+}
+
+func TestProtocolRegistry_GetSupportedAssetsDetailed_Unit(t *testing.T) {
+
+	native := common.HexToAddress(nativeDenomAddress)
+	metadata := stubAssetMetadata{native: {Address: native, Symbol: "AVAX.e", Decimals: 18}}
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: AvalancheChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(AvalancheChainID, pkgtest.NewClient(AvalancheChainID)),
+		WithAssetMetadata(metadata))
+	require.NoError(t, err)
+
+	assets, err := registry.GetSupportedAssetsDetailed(context.Background(), AvalancheChainID, BenqiSAVAXContractAddress)
+	require.NoError(t, err)
+	require.Equal(t, []AssetInfo{metadata[native]}, assets)
+
+	_, err = registry.GetSupportedAssetsDetailed(context.Background(), AvalancheChainID, testDAI)
+	require.Error(t, err)
+}
+
+func TestProtocolRegistry_AssetMetadataUser_Unit(t *testing.T) {
+
+	metadata := stubAssetMetadata{testUSDC: {Address: testUSDC, Symbol: "USDC", Decimals: 6}}
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: AvalancheChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(AvalancheChainID, pkgtest.NewClient(AvalancheChainID)),
+		WithAssetMetadata(metadata))
+	require.NoError(t, err)
+
+	protocol, err := registry.GetProtocol(AvalancheChainID, AaveAvalancheV3ContractAddress)
+	require.NoError(t, err)
+
+	aave, ok := protocol.(*AaveOperation)
+	require.True(t, ok)
+	require.Equal(t, AssetMetadata(metadata), aave.metadata)
+}
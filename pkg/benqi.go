@@ -0,0 +1,338 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const benqiSAVAXABI = `
+[
+  {
+    "inputs": [],
+    "name": "submit",
+    "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }],
+    "stateMutability": "payable",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "uint256", "name": "shareAmount", "type": "uint256" }
+    ],
+    "name": "requestUnlock",
+    "outputs": [],
+    "stateMutability": "nonpayable",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "address", "name": "account", "type": "address" }
+    ],
+    "name": "balanceOf",
+    "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }],
+    "stateMutability": "view",
+    "type": "function"
+  }
+]`
+
+// BenqiOperation implements liquid staking of AVAX into sAVAX.
+// Unstaking requests an unlock of sAVAX shares which can be redeemed for
+// AVAX once the cooldown period is over
+// https://benqi.fi
+type BenqiOperation struct {
+	contract  common.Address
+	parsedABI abi.ABI
+	chainID   *big.Int
+	client    EthClient
+	observer  Observer
+
+	// verified on first use, see networkCheck
+	network networkCheck
+
+	// disabled through the registry, see SetProtocolEnabled
+	deprecation
+}
+
+var _ Protocol = (*BenqiOperation)(nil)
+
+func NewBenqiOperation(client EthClient, chainID *big.Int, opts ...NetworkOption) (*BenqiOperation, error) {
+
+	if !IsAvalanche(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(benqiSAVAXABI))
+	if err != nil {
+		return nil, err
+	}
+
+	b := &BenqiOperation{
+		parsedABI: parsedABI,
+		chainID:   chainID,
+		client:    client,
+		contract:  BenqiSAVAXContractAddress,
+	}
+
+	for _, opt := range opts {
+		opt(&b.network)
+	}
+
+	return b, nil
+}
+
+// GenerateCalldata creates the necessary blockchain transaction data.
+// For NativeUnStake params.Amount is the amount of sAVAX shares to unlock
+func (b *BenqiOperation) GenerateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+	if err := b.deprecation.check(b.GetUniqueKey()); err != nil {
+		return "", err
+	}
+
+	if err := params.checkAmountRange(); err != nil {
+		return "", err
+	}
+
+	return observeCalldata(b.observer, b.GetName(), action, func() (string, error) {
+		return b.generateCalldata(ctx, chainID, action, params)
+	})
+}
+
+func (b *BenqiOperation) generateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+
+	if !b.isSupportedChain(chainID) {
+		return "", ErrChainUnsupported
+	}
+
+	if err := b.network.ensure(ctx, b.client, b.chainID); err != nil {
+		return "", err
+	}
+
+	var calldata []byte
+	var err error
+
+	switch action {
+	case NativeStake:
+		calldata, err = b.parsedABI.Pack("submit")
+	case NativeUnStake:
+		if params.Amount == nil {
+			return "", ErrAmountRequired
+		}
+
+		calldata, err = b.parsedABI.Pack("requestUnlock", params.Amount)
+	default:
+		return "", ErrUnsupportedAction
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return HexPrefix + hex.EncodeToString(calldata), nil
+}
+
+// EstimateGas estimates the gas needed to execute the generated calldata
+func (b *BenqiOperation) EstimateGas(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (uint64, error) {
+
+	tx, err := b.BuildTransaction(ctx, chainID, action, params)
+	if err != nil {
+		return 0, err
+	}
+
+	return estimateGas(ctx, b.client, params.Sender, tx)
+}
+
+// BuildTransaction generates the calldata for the action together with
+// the contract to call and the value to send
+func (b *BenqiOperation) BuildTransaction(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (*Transaction, error) {
+
+	calldata, err := b.GenerateCalldata(ctx, chainID, action, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{
+		To:    b.GetContractAddress(chainID),
+		Data:  calldata,
+		Value: b.CallValue(action, params),
+	}, nil
+}
+
+// Validate checks if the provided parameters are valid for the specified action
+func (b *BenqiOperation) Validate(ctx context.Context,
+	chainID *big.Int, action ContractAction, params TransactionParams) error {
+
+	if err := b.deprecation.check(b.GetUniqueKey()); err != nil {
+		return err
+	}
+
+	if !b.isSupportedChain(chainID) {
+		return ErrChainUnsupported
+	}
+
+	if err := b.network.ensure(ctx, b.client, b.chainID); err != nil {
+		return err
+	}
+
+	if !b.IsSupportedAsset(ctx, chainID, params.Asset) {
+		return fmt.Errorf("%w %s", ErrAssetNotSupported, params.Asset)
+	}
+
+	if err := params.ValidateAmount(); err != nil {
+		return err
+	}
+
+	if !b.IsSupportedAction(action) {
+		return ErrUnsupportedAction
+	}
+
+	if params.lenientValidation(ValidationStrict) {
+		return nil
+	}
+
+	var balance *big.Int
+	var err error
+
+	switch action {
+	case NativeStake:
+		balance, err = b.client.BalanceAt(ctx, params.Sender, nil)
+	case NativeUnStake:
+		_, balance, err = b.GetBalance(ctx, chainID, params.Sender, params.Asset)
+	default:
+		return ErrUnsupportedAction
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if balance.Cmp(params.Amount) < 0 {
+		return ErrInsufficientBalance
+	}
+
+	return nil
+}
+
+// GetBalance retrieves the sAVAX balance of account
+func (b *BenqiOperation) GetBalance(ctx context.Context, chainID *big.Int,
+	account, _ common.Address) (common.Address, *big.Int, error) {
+
+	if !b.isSupportedChain(chainID) {
+		return common.Address{}, nil, ErrChainUnsupported
+	}
+
+	calldata, err := b.parsedABI.Pack("balanceOf", account)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	result, err := b.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &b.contract,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	balance := new(big.Int)
+	err = b.parsedABI.UnpackIntoInterface(&balance, "balanceOf", result)
+	return b.contract, balance, err
+}
+
+// GetSupportedAssets returns a list of assets supported by the protocol on the specified chain
+func (b *BenqiOperation) GetSupportedAssets(ctx context.Context, chainID *big.Int) ([]common.Address, error) {
+	if !b.isSupportedChain(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	return []common.Address{
+		common.HexToAddress(nativeDenomAddress),
+	}, nil
+}
+
+func (b *BenqiOperation) isSupportedChain(chain *big.Int) bool {
+	return b.chainID.Cmp(chain) == 0
+}
+
+// IsSupportedAsset checks if the specified asset is supported on the given chain
+func (b *BenqiOperation) IsSupportedAsset(ctx context.Context, chainID *big.Int, asset common.Address) bool {
+	if !b.isSupportedChain(chainID) {
+		return false
+	}
+
+	return IsNativeToken(asset) || asset == b.contract
+}
+
+// GetProtocolConfig returns the protocol config for a specific chain
+func (b *BenqiOperation) GetProtocolConfig(chainID *big.Int) ProtocolConfig {
+	return ProtocolConfig{
+		ChainID:  b.chainID,
+		ABI:      b.parsedABI,
+		Type:     TypeStake,
+		Contract: b.contract,
+	}
+}
+
+// GetABI returns the ABI of the protocol's contract
+func (b *BenqiOperation) GetABI(chainID *big.Int) abi.ABI { return b.parsedABI }
+
+// GetType returns the protocol type
+func (b *BenqiOperation) GetType() ProtocolType { return TypeStake }
+
+// GetContractAddress returns the contract address for a specific chain
+func (b *BenqiOperation) GetContractAddress(chainID *big.Int) common.Address {
+	return b.contract
+}
+
+// GetContractAddressForAction returns the contract the calldata of action is sent to,
+// the same for every action
+func (b *BenqiOperation) GetContractAddressForAction(chainID *big.Int, action ContractAction) (common.Address, error) {
+	return contractAddressForAction(b, chainID, action)
+}
+
+// Name returns the human readable name for the protocol
+func (b *BenqiOperation) GetName() string { return Benqi }
+
+// GetVersion returns the version of the protocol
+func (b *BenqiOperation) GetVersion() string { return "1" }
+
+// GetUniqueKey returns the key identifying the protocol across chains
+func (b *BenqiOperation) GetUniqueKey() string { return protocolKey(b.GetName(), b.chainID) }
+
+// CallValue returns the native amount to send along with the calldata.
+// The submit call is payable and takes the staked AVAX as msg.value
+func (b *BenqiOperation) CallValue(action ContractAction, params TransactionParams) *big.Int {
+	if action == NativeStake {
+		return params.nativeCallValue()
+	}
+
+	return big.NewInt(0)
+}
+
+// Capabilities describes the actions, chains and extra data supported by the protocol
+func (b *BenqiOperation) Capabilities() ProtocolCapabilities {
+	return ProtocolCapabilities{
+		SupportedActions:  []ContractAction{NativeStake, NativeUnStake},
+		SupportedChains:   []*big.Int{b.chainID},
+		RequiresExtraData: []string{},
+	}
+}
+
+// IsSupportedAction reports whether calldata can be generated for action
+func (b *BenqiOperation) IsSupportedAction(action ContractAction) bool {
+	return b.Capabilities().Supports(action)
+}
+
+// SetObserver implements Observable
+func (b *BenqiOperation) SetObserver(observer Observer) {
+	b.observer = observer
+	b.client = observeClient(b.client, b.GetName(), observer)
+}
@@ -0,0 +1,80 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBenqiOperation(t *testing.T, avax, sAVAX *big.Int) *BenqiOperation {
+	t.Helper()
+
+	client := pkgtest.NewClient(AvalancheChainID)
+	client.SetBalance(testAccount, avax)
+
+	benqi, err := NewBenqiOperation(client, AvalancheChainID)
+	require.NoError(t, err)
+
+	client.HandleReturns(BenqiSAVAXContractAddress, benqi.parsedABI.Methods["balanceOf"], sAVAX)
+
+	return benqi
+}
+
+func TestBenqi_BuildTransaction(t *testing.T) {
+
+	native := common.HexToAddress(nativeDenomAddress)
+
+	runBuildCases(t, newTestBenqiOperation(t, big.NewInt(0), big.NewInt(0)), AvalancheChainID, []buildCase{
+		{
+			name:   "stake submits the AVAX as value",
+			action: NativeStake,
+			params: TransactionParams{Amount: big.NewInt(1e18), Sender: testAccount, Asset: native},
+			to:     BenqiSAVAXContractAddress,
+			data:   "0x5bcb2fc6",
+			value:  big.NewInt(1e18),
+		},
+		{
+			name:   "unstake requests an unlock of the shares",
+			action: NativeUnStake,
+			params: TransactionParams{Amount: big.NewInt(1e18), Sender: testAccount, Asset: BenqiSAVAXContractAddress},
+			to:     BenqiSAVAXContractAddress,
+			data: "0xc9d2ff9d" +
+				"0000000000000000000000000000000000000000000000000de0b6b3a7640000", // shareAmount
+		},
+		{
+			name:    "other chains",
+			chainID: EthChainID,
+			action:  NativeStake,
+			params:  TransactionParams{Amount: big.NewInt(1), Asset: native},
+			err:     ErrChainUnsupported,
+		},
+	})
+}
+
+func TestBenqi_Validate(t *testing.T) {
+
+	params := func(amount int64) TransactionParams {
+		return TransactionParams{
+			Amount: big.NewInt(amount),
+			Sender: testAccount,
+			Asset:  common.HexToAddress(nativeDenomAddress),
+		}
+	}
+
+	benqi := newTestBenqiOperation(t, big.NewInt(100), big.NewInt(50))
+
+	runValidateCases(t, benqi, AvalancheChainID, []validateCase{
+		{action: NativeStake, params: params(100), valid: true},
+		{action: NativeStake, params: params(101)},
+		{action: NativeUnStake, params: params(50), valid: true},
+		{action: NativeUnStake, params: params(51)},
+	})
+
+	_, balance, err := benqi.GetBalance(context.Background(), AvalancheChainID, testAccount, BenqiSAVAXContractAddress)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(50), balance)
+}
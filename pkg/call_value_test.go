@@ -0,0 +1,52 @@
+package pkg
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallValue(t *testing.T) {
+
+	amount := big.NewInt(1e18)
+	params := TransactionParams{Amount: amount}
+
+	tt := []struct {
+		name     string
+		protocol Protocol
+		action   ContractAction
+		expected *big.Int
+	}{
+		{"lido stake", &LidoOperation{}, NativeStake, amount},
+		{"ankr stake", &AnkrOperation{}, NativeStake, amount},
+		{"ankr unstake", &AnkrOperation{}, NativeUnStake, big.NewInt(0)},
+		{"lista stake", &ListaStakingOperation{}, NativeStake, amount},
+		{"rocketpool stake", &RocketpoolOperation{}, NativeStake, amount},
+		{"rocketpool unstake", &RocketpoolOperation{}, NativeUnStake, big.NewInt(0)},
+		{"aave supply", &AaveOperation{}, LoanSupply, big.NewInt(0)},
+		{"aave withdraw", &AaveOperation{}, LoanWithdraw, big.NewInt(0)},
+		{"compound supply", &CompoundOperation{}, LoanSupply, big.NewInt(0)},
+	}
+
+	for _, v := range tt {
+		t.Run(v.name, func(t *testing.T) {
+			value := v.protocol.CallValue(v.action, params)
+			require.Zero(t, v.expected.Cmp(value))
+		})
+	}
+}
+
+func TestCallValue_NilAmount(t *testing.T) {
+	value := (&LidoOperation{}).CallValue(NativeStake, TransactionParams{})
+	require.Zero(t, value.Sign())
+}
+
+func TestCallValue_ReturnsCopy(t *testing.T) {
+	amount := big.NewInt(100)
+
+	value := (&LidoOperation{}).CallValue(NativeStake, TransactionParams{Amount: amount})
+	value.SetInt64(1)
+
+	require.EqualValues(t, 100, amount.Int64())
+}
@@ -0,0 +1,129 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+// unpackCalldata checks calldata calls method and returns its decoded arguments
+func unpackCalldata(t *testing.T, parsedABI abi.ABI, method, calldata string) []interface{} {
+	t.Helper()
+
+	data, err := hexutil.Decode(calldata)
+	require.NoError(t, err)
+
+	m, ok := parsedABI.Methods[method]
+	require.True(t, ok)
+	require.True(t, bytes.HasPrefix(data, m.ID), "calldata does not call %s", method)
+
+	args, err := m.Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+
+	return args
+}
+
+func FuzzAave_GenerateCalldata(f *testing.F) {
+
+	// cast calldata "withdraw(address,uint256,address)" 0xc0ffee254729296a45a3885639AC7E10F9d54979 500000000000000000 0x0000000000000000000000000000000000000000
+	f.Add(common.HexToAddress("0xc0ffee254729296a45a3885639AC7E10F9d54979").Bytes(),
+		common.Address{}.Bytes(), big.NewInt(500000000000000000).Bytes(), uint16(0))
+	// cast calldata "supply(address,uint256,address,uint16)" 0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984 1000000000000000000 0x0000000000000000000000000000000000000000 10
+	f.Add(common.HexToAddress("0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984").Bytes(),
+		common.Address{}.Bytes(), big.NewInt(1000000000000000000).Bytes(), uint16(10))
+	// 2^256+5 does not fit the uint256 argument
+	f.Add(common.HexToAddress("0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984").Bytes(),
+		common.Address{}.Bytes(), new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(5)).Bytes(), uint16(0))
+
+	aave, err := NewAaveOperation(pkgtest.NewClient(EthChainID), EthChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(f, err)
+
+	f.Fuzz(func(t *testing.T, assetBytes, senderBytes, amountBytes []byte, referralCode uint16) {
+
+		params := TransactionParams{
+			Asset:  common.BytesToAddress(assetBytes),
+			Sender: common.BytesToAddress(senderBytes),
+			Amount: new(big.Int).SetBytes(amountBytes),
+			ExtraData: map[string]interface{}{
+				"referral_code": referralCode,
+			},
+		}
+
+		// the native token goes through the WETH gateway
+		if IsNativeToken(params.Asset) {
+			t.Skip()
+		}
+
+		supply, err := aave.GenerateCalldata(context.Background(), EthChainID, LoanSupply, params)
+		withdraw, withdrawErr := aave.GenerateCalldata(context.Background(), EthChainID, LoanWithdraw, params)
+
+		// the packer would truncate amounts wider than the uint256 argument
+		if params.Amount.Cmp(abi.MaxUint256) > 0 {
+			require.Error(t, err)
+			require.Error(t, withdrawErr)
+			return
+		}
+
+		require.NoError(t, err)
+		require.NoError(t, withdrawErr)
+
+		args := unpackCalldata(t, aave.parsedABI, "supply", supply)
+		require.Equal(t, params.Asset, args[0])
+		require.Zero(t, params.Amount.Cmp(args[1].(*big.Int)))
+		require.Equal(t, params.GetBeneficiaryOwner(), args[2])
+		require.Equal(t, referralCode, args[3])
+
+		args = unpackCalldata(t, aave.parsedABI, "withdraw", withdraw)
+		require.Equal(t, params.Asset, args[0])
+		require.Zero(t, params.Amount.Cmp(args[1].(*big.Int)))
+		require.Equal(t, params.GetBeneficiaryOwner(), args[2])
+	})
+}
+
+func FuzzCompound_SupplyWithdraw(f *testing.F) {
+
+	// cast calldata "supply(address,uint256)" 0x514910771AF9Ca656af840dff83E8264EcF986CA 1000000000000000000
+	f.Add(common.HexToAddress("0x514910771AF9Ca656af840dff83E8264EcF986CA").Bytes(),
+		big.NewInt(1000000000000000000).Bytes())
+	// 2^256+5 does not fit the uint256 argument
+	f.Add(common.HexToAddress("0x514910771AF9Ca656af840dff83E8264EcF986CA").Bytes(),
+		new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(5)).Bytes())
+
+	compound, err := NewCompoundOperation(newCompoundMarket(f).client(),
+		EthChainID, common.HexToAddress(CompoundV3USDCPool))
+	require.NoError(f, err)
+
+	f.Fuzz(func(t *testing.T, assetBytes, amountBytes []byte) {
+
+		params := TransactionParams{
+			Asset:  common.BytesToAddress(assetBytes),
+			Amount: new(big.Int).SetBytes(amountBytes),
+		}
+
+		supply, err := compound.GenerateCalldata(context.Background(), EthChainID, LoanSupply, params)
+		withdraw, withdrawErr := compound.GenerateCalldata(context.Background(), EthChainID, LoanWithdraw, params)
+
+		// the packer would truncate amounts wider than the uint256 argument
+		if params.Amount.Cmp(abi.MaxUint256) > 0 {
+			require.Error(t, err)
+			require.Error(t, withdrawErr)
+			return
+		}
+
+		require.NoError(t, err)
+		require.NoError(t, withdrawErr)
+
+		for method, calldata := range map[string]string{"supply": supply, "withdraw": withdraw} {
+			args := unpackCalldata(t, compound.parsedABI, method, calldata)
+			require.Equal(t, params.Asset, args[0])
+			require.Zero(t, params.Amount.Cmp(args[1].(*big.Int)))
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "regenerate the golden files in testdata")
+
+const calldataGoldenFile = "calldata_golden.json"
+
+// goldenCalldata is the calldata a protocol generates for an action and its params
+type goldenCalldata struct {
+	Name     string                 `json:"name"`
+	Protocol string                 `json:"protocol"`
+	ChainID  int64                  `json:"chain_id"`
+	Action   string                 `json:"action"`
+	Asset    common.Address         `json:"asset"`
+	Amount   *big.Int               `json:"amount"`
+	Sender   common.Address         `json:"sender"`
+	Extra    map[string]interface{} `json:"extra_data,omitempty"`
+	Calldata string                 `json:"calldata"`
+}
+
+// TestCalldata_Golden asserts the calldata of every protocol against
+// testdata/calldata_golden.json. Run with -update to regenerate it
+func TestCalldata_Golden(t *testing.T) {
+
+	newProtocol := func(p Protocol, err error) Protocol {
+		require.NoError(t, err)
+		return p
+	}
+
+	aave := newProtocol(NewAaveOperation(pkgtest.NewClient(EthChainID), EthChainID, AaveProtocolDeploymentEthereum))
+	radiant := newProtocol(NewAaveOperation(pkgtest.NewClient(BscChainID), BscChainID, AaveProtocolDeploymentRadiant))
+	compound := newProtocol(NewCompoundOperation(newCompoundMarket(t).client(),
+		EthChainID, common.HexToAddress(CompoundV3USDCPool)))
+	lido := newProtocol(NewLidoOperation(pkgtest.NewClient(EthChainID), EthChainID))
+	lidoPolygon := newProtocol(NewLidoOperation(pkgtest.NewClient(PolygonChainID), PolygonChainID))
+	ankr := newProtocol(NewAnkrOperation(pkgtest.NewClient(EthChainID), EthChainID))
+	lista := newProtocol(NewListaStakingOperation(pkgtest.NewClient(BscChainID), BscChainID))
+
+	native := common.HexToAddress(nativeDenomAddress)
+	uni := common.HexToAddress("0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984")
+	link := common.HexToAddress("0x514910771AF9Ca656af840dff83E8264EcF986CA")
+	bscUSDT := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+
+	tt := []struct {
+		name     string
+		protocol Protocol
+		chainID  *big.Int
+		action   ContractAction
+		params   TransactionParams
+	}{
+		{
+			name: "aave supply with referral code", protocol: aave, chainID: EthChainID, action: LoanSupply,
+			params: TransactionParams{Asset: uni, Amount: big.NewInt(1e18),
+				ExtraData: map[string]interface{}{"referral_code": 10}},
+		},
+		{
+			name: "aave withdraw", protocol: aave, chainID: EthChainID, action: LoanWithdraw,
+			params: TransactionParams{Asset: common.HexToAddress("0xc0ffee254729296a45a3885639AC7E10F9d54979"),
+				Amount: big.NewInt(5e17)},
+		},
+		{
+			name: "aave flash loan", protocol: aave, chainID: EthChainID, action: LoanFlashLoan,
+			params: TransactionParams{Asset: uni, Amount: big.NewInt(1e18), ExtraData: map[string]interface{}{
+				"flashloan_receiver": "0x000000000000000000000000000000000000bEEF",
+				"flashloan_params":   "0x1234",
+				"referral_code":      0,
+			}},
+		},
+		{
+			name: "radiant deposit", protocol: radiant, chainID: BscChainID, action: LoanSupply,
+			params: TransactionParams{Asset: bscUSDT, Amount: big.NewInt(1e18), Sender: testAccount,
+				ExtraData: map[string]interface{}{"referral_code": 0}},
+		},
+		{
+			name: "radiant withdraw", protocol: radiant, chainID: BscChainID, action: LoanWithdraw,
+			params: TransactionParams{Asset: bscUSDT, Amount: big.NewInt(1e18), Sender: testAccount},
+		},
+		{
+			name: "compound supply", protocol: compound, chainID: EthChainID, action: LoanSupply,
+			params: TransactionParams{Asset: link, Amount: big.NewInt(1e18)},
+		},
+		{
+			name: "compound withdraw", protocol: compound, chainID: EthChainID, action: LoanWithdraw,
+			params: TransactionParams{Asset: link, Amount: big.NewInt(1e18)},
+		},
+		{
+			name: "lido stake", protocol: lido, chainID: EthChainID, action: NativeStake,
+			params: TransactionParams{Asset: native, Amount: big.NewInt(1e18),
+				Sender: common.HexToAddress("0xB4FBF271143F4FBf7B91A5ded31805e42b2208d6")},
+		},
+		{
+			name: "lido polygon stake", protocol: lidoPolygon, chainID: PolygonChainID, action: NativeStake,
+			params: TransactionParams{Asset: native, Amount: big.NewInt(1e18), Sender: testAccount},
+		},
+		{
+			name: "ankr stake", protocol: ankr, chainID: EthChainID, action: NativeStake,
+			params: TransactionParams{Asset: native, Amount: big.NewInt(1e18), Sender: testAccount},
+		},
+		{
+			name: "ankr unstake", protocol: ankr, chainID: EthChainID, action: NativeUnStake,
+			params: TransactionParams{Asset: native, Amount: big.NewInt(3987509938965136896), Sender: testAccount},
+		},
+		{
+			name: "lista stake", protocol: lista, chainID: BscChainID, action: NativeStake,
+			params: TransactionParams{Asset: native, Amount: big.NewInt(1e18), Sender: testAccount},
+		},
+	}
+
+	actual := make([]goldenCalldata, 0, len(tt))
+
+	for _, v := range tt {
+		calldata, err := v.protocol.GenerateCalldata(context.Background(), v.chainID, v.action, v.params)
+		require.NoError(t, err, v.name)
+
+		actual = append(actual, goldenCalldata{
+			Name:     v.name,
+			Protocol: v.protocol.GetName(),
+			ChainID:  v.chainID.Int64(),
+			Action:   v.action.String(),
+			Asset:    v.params.Asset,
+			Amount:   v.params.Amount,
+			Sender:   v.params.Sender,
+			Extra:    v.params.ExtraData,
+			Calldata: calldata,
+		})
+	}
+
+	data, err := json.MarshalIndent(actual, "", "  ")
+	require.NoError(t, err)
+
+	path := filepath.Join("testdata", calldataGoldenFile)
+
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(path, append(data, '\n'), 0o644))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expected), string(data), "calldata changed, run go test -run TestCalldata_Golden -update if it is intended")
+}
@@ -0,0 +1,389 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+	"github.com/stretchr/testify/require"
+)
+
+// offlineCase is a protocol built on an offline client together with the
+// asset and extra data its actions are generated with
+type offlineCase struct {
+	name      string
+	protocol  Protocol
+	chainID   *big.Int
+	asset     common.Address
+	extraData map[string]interface{}
+
+	// calls made by each action for an amount of 1e6
+	calls map[ContractAction]offlineCall
+}
+
+// offlineCall is the method called by an action with its arguments and the value sent
+type offlineCall struct {
+	method string
+	args   []interface{}
+	value  int64
+}
+
+// offlineCases builds every protocol without reading the chain. Compound reads
+// the collateral list of the market when created, it is served by a fake market.
+// Rocket Pool resolves its contracts through the Rocket Pool storage, the
+// operation is built from the deposit pool and rETH addresses instead
+func offlineCases(t *testing.T) []offlineCase {
+	t.Helper()
+
+	must := func(p Protocol, err error) Protocol {
+		t.Helper()
+		require.NoError(t, err)
+		return p
+	}
+
+	aaveExtraData := map[string]interface{}{
+		"referral_code":      0,
+		"use_atokens":        true,
+		"emode_category":     1,
+		"use_as_collateral":  true,
+		"flashloan_receiver": testAccount.Hex(),
+		"delegatee":          common.HexToAddress("0x1").Hex(),
+	}
+
+	native := common.HexToAddress(nativeDenomAddress)
+	amount := big.NewInt(1e6)
+
+	aaveV3Calls := func(asset common.Address) map[ContractAction]offlineCall {
+		return map[ContractAction]offlineCall{
+			LoanSupply:         {method: "supply", args: []interface{}{asset, amount, testAccount, uint16(0)}},
+			LoanWithdraw:       {method: "withdraw", args: []interface{}{asset, amount, testAccount}},
+			LoanBorrow:         {method: "borrow", args: []interface{}{asset, amount, big.NewInt(2), uint16(0), testAccount}},
+			LoanRepay:          {method: "repayWithATokens", args: []interface{}{asset, amount, big.NewInt(2)}},
+			LoanSetEMode:       {method: "setUserEMode", args: []interface{}{uint8(1)}},
+			LoanSetCollateral:  {method: "setUserUseReserveAsCollateral", args: []interface{}{asset, true}},
+			LoanFlashLoan:      {method: "flashLoanSimple", args: []interface{}{testAccount, asset, amount, []byte{}, uint16(0)}},
+			LoanDelegateCredit: {method: "approveDelegation", args: []interface{}{common.HexToAddress("0x1"), amount}},
+		}
+	}
+
+	aaveV2Calls := map[ContractAction]offlineCall{
+		LoanSupply:         {method: "deposit", args: []interface{}{testUSDC, amount, testAccount, uint16(0)}},
+		LoanWithdraw:       {method: "withdraw", args: []interface{}{testUSDC, amount, testAccount}},
+		LoanSetCollateral:  {method: "setUserUseReserveAsCollateral", args: []interface{}{testUSDC, true}},
+		LoanDelegateCredit: {method: "approveDelegation", args: []interface{}{common.HexToAddress("0x1"), amount}},
+	}
+
+	erc4626Calls := map[ContractAction]offlineCall{
+		ERC20Stake:   {method: "deposit", args: []interface{}{amount, testAccount}},
+		ERC20UnStake: {method: "withdraw", args: []interface{}{amount, testAccount, testAccount}},
+	}
+
+	link := common.HexToAddress("0x514910771AF9Ca656af840dff83E8264EcF986CA")
+	manager := common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+
+	rocketpoolABI, err := abi.JSON(strings.NewReader(RocketPoolABI))
+	require.NoError(t, err)
+
+	depositPool := common.HexToAddress("0xDD3f50F8A6CafbE9b31a427582963f465E745AF8")
+	reth := common.HexToAddress("0xae78736Cd615f374D3085123A210448E74Fc6393")
+
+	var recipient [32]byte
+	copy(recipient[12:], testAccount.Bytes())
+
+	return []offlineCase{
+		{
+			name:      "aave",
+			protocol:  must(NewAaveOperationOffline(EthChainID, AaveProtocolDeploymentEthereum)),
+			chainID:   EthChainID,
+			asset:     testUSDC,
+			extraData: aaveExtraData,
+			calls:     aaveV3Calls(testUSDC),
+		},
+		{
+			name:      "spark",
+			protocol:  must(NewAaveOperationOffline(EthChainID, AaveProtocolDeploymentSpark)),
+			chainID:   EthChainID,
+			asset:     testDAI,
+			extraData: aaveExtraData,
+			calls:     aaveV3Calls(testDAI),
+		},
+		{
+			name:      "avalon",
+			protocol:  must(NewAaveOperationOffline(BscChainID, AaveProtocolDeploymentAvalonFinance)),
+			chainID:   BscChainID,
+			asset:     testUSDC,
+			extraData: aaveExtraData,
+			calls:     aaveV2Calls,
+		},
+		{
+			name:      "radiant",
+			protocol:  must(NewAaveOperationOffline(BscChainID, AaveProtocolDeploymentRadiant)),
+			chainID:   BscChainID,
+			asset:     testUSDC,
+			extraData: aaveExtraData,
+			calls:     aaveV2Calls,
+		},
+		{
+			name: "compound",
+			protocol: must(NewCompoundOperation(newCompoundMarket(t, link).client(), EthChainID,
+				common.HexToAddress(CompoundV3USDCPool))),
+			chainID:   EthChainID,
+			asset:     link,
+			extraData: map[string]interface{}{"manager": manager.Hex(), "allowed": true},
+			calls: map[ContractAction]offlineCall{
+				LoanSupply:       {method: "supply", args: []interface{}{link, amount}},
+				LoanWithdraw:     {method: "withdraw", args: []interface{}{link, amount}},
+				LoanAllowManager: {method: "allow", args: []interface{}{manager, true}},
+			},
+		},
+		{
+			name: "rocketpool",
+			protocol: &RocketpoolOperation{
+				parsedABI:    rocketpoolABI,
+				chainID:      EthChainID,
+				contract:     &rocketpool.Contract{Address: &depositPool},
+				rethContract: &rocketpool.Contract{Address: &reth},
+			},
+			chainID: EthChainID,
+			asset:   native,
+			calls: map[ContractAction]offlineCall{
+				NativeStake:   {method: "deposit", value: 1e6},
+				NativeUnStake: {method: "burn", args: []interface{}{amount}},
+			},
+		},
+		{
+			name:     "lido",
+			protocol: must(NewLidoOperation(NewOfflineClient(EthChainID), EthChainID)),
+			chainID:  EthChainID,
+			asset:    native,
+			calls: map[ContractAction]offlineCall{
+				NativeStake: {method: "submit", args: []interface{}{testAccount}, value: 1e6},
+			},
+		},
+		{
+			name:     "ankr",
+			protocol: must(NewAnkrOperation(NewOfflineClient(EthChainID), EthChainID)),
+			chainID:  EthChainID,
+			asset:    native,
+			calls: map[ContractAction]offlineCall{
+				NativeStake:   {method: "stakeAndClaimAethC", value: 1e6},
+				NativeUnStake: {method: "unstakeAETH", args: []interface{}{amount}},
+			},
+		},
+		{
+			name:     "lista",
+			protocol: must(NewListaStakingOperation(NewOfflineClient(BscChainID), BscChainID)),
+			chainID:  BscChainID,
+			asset:    native,
+			calls: map[ContractAction]offlineCall{
+				NativeStake:   {method: "deposit", value: 1e6},
+				NativeUnStake: {method: "requestWithdraw", args: []interface{}{amount}},
+			},
+		},
+		{
+			name:     "lista lending",
+			protocol: must(NewListaLendingOperation(NewOfflineClient(BscChainID), BscChainID)),
+			chainID:  BscChainID,
+			asset:    native,
+			calls: map[ContractAction]offlineCall{
+				LoanSupply:   {method: "deposit", args: []interface{}{testAccount, native, amount}},
+				LoanWithdraw: {method: "withdraw", args: []interface{}{testAccount, native, amount}},
+				LoanBorrow:   {method: "borrow", args: []interface{}{native, amount}},
+				LoanRepay:    {method: "payback", args: []interface{}{native, amount}},
+			},
+		},
+		{
+			name:     "venus",
+			protocol: must(NewVenusOperation(NewOfflineClient(BscChainID), BscChainID)),
+			chainID:  BscChainID,
+			asset:    native,
+			calls: map[ContractAction]offlineCall{
+				LoanSupply:   {method: "mint", value: 1e6},
+				LoanWithdraw: {method: "redeemUnderlying", args: []interface{}{amount}},
+			},
+		},
+		{
+			name:     "benqi",
+			protocol: must(NewBenqiOperation(NewOfflineClient(AvalancheChainID), AvalancheChainID)),
+			chainID:  AvalancheChainID,
+			asset:    native,
+			calls: map[ContractAction]offlineCall{
+				NativeStake:   {method: "submit", value: 1e6},
+				NativeUnStake: {method: "requestUnlock", args: []interface{}{amount}},
+			},
+		},
+		{
+			name:      "eigenlayer",
+			protocol:  must(NewEigenLayerOperation(NewOfflineClient(EthChainID), EthChainID)),
+			chainID:   EthChainID,
+			asset:     testStETH,
+			extraData: map[string]interface{}{"strategy": testEigenLayerStETHStrategy.Hex()},
+			calls: map[ContractAction]offlineCall{
+				ERC20Stake: {method: "depositIntoStrategy", args: []interface{}{testEigenLayerStETHStrategy, testStETH, amount}},
+			},
+		},
+		{
+			name:     "pendle",
+			protocol: must(NewPendleOperation(NewOfflineClient(EthChainID), EthChainID, []common.Address{testPendleSY})),
+			chainID:  EthChainID,
+			asset:    testPendleWstETH,
+			extraData: map[string]interface{}{
+				"sy":         testPendleSY.Hex(),
+				"min_shares": "1",
+			},
+			calls: map[ContractAction]offlineCall{
+				ERC20Stake: {method: "deposit", args: []interface{}{testAccount, testPendleWstETH, amount, big.NewInt(1)}},
+			},
+		},
+		{
+			name:     "stargate",
+			protocol: must(NewStargateOperation(NewOfflineClient(EthChainID), EthChainID)),
+			chainID:  EthChainID,
+			asset:    testUSDC,
+			extraData: map[string]interface{}{
+				"destination_chain_id": PolygonChainID,
+				"native_fee":           "1000000000000000",
+			},
+			calls: map[ContractAction]offlineCall{
+				BridgeSend: {method: "sendToken", value: 1e15, args: []interface{}{
+					stargateSendParam{
+						DstEid:       30109,
+						To:           recipient,
+						AmountLD:     amount,
+						MinAmountLD:  big.NewInt(995_000),
+						ExtraOptions: []byte{},
+						ComposeMsg:   []byte{},
+						OftCmd:       []byte{},
+					},
+					stargateMessagingFee{NativeFee: big.NewInt(1e15), LzTokenFee: big.NewInt(0)},
+					testAccount,
+				}},
+			},
+		},
+		{
+			name:     "sdai",
+			protocol: must(NewSavingsDAIOperation(NewOfflineClient(EthChainID), EthChainID)),
+			chainID:  EthChainID,
+			asset:    testDAI,
+			calls:    erc4626Calls,
+		},
+		{
+			name:     "spark savings",
+			protocol: must(NewSparkSavingsOperation(NewOfflineClient(EthChainID), EthChainID, SavingsUSDSContractAddress)),
+			chainID:  EthChainID,
+			asset:    savingsUSDSAsset,
+			calls:    erc4626Calls,
+		},
+		{
+			name:     "yearn",
+			protocol: must(NewYearnOperation(NewOfflineClient(EthChainID), EthChainID, YearnV3USDCVaultAddress)),
+			chainID:  EthChainID,
+			asset:    testUSDC,
+			calls:    erc4626Calls,
+		},
+	}
+}
+
+func TestGenerateCalldata_Offline(t *testing.T) {
+
+	debtTokenABI, err := abi.JSON(strings.NewReader(aaveDebtTokenABI))
+	require.NoError(t, err)
+
+	for _, tc := range offlineCases(t) {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			require.NotEmpty(t, tc.protocol.Capabilities().SupportedActions)
+
+			for _, action := range tc.protocol.Capabilities().SupportedActions {
+				expected, ok := tc.calls[action]
+				require.True(t, ok, "no expected call for %s", action)
+
+				params := TransactionParams{
+					Amount:    big.NewInt(1e6),
+					Sender:    testAccount,
+					Asset:     tc.asset,
+					ExtraData: tc.extraData,
+				}
+
+				calldata, err := tc.protocol.GenerateCalldata(context.Background(), tc.chainID, action, params)
+				require.NoError(t, err, action.String())
+
+				data, err := hexutil.Decode(calldata)
+				require.NoError(t, err, action.String())
+				require.GreaterOrEqual(t, len(data), 4, action.String())
+
+				// approveDelegation is sent to the debt token rather than the pool
+				parsedABI := tc.protocol.GetABI(tc.chainID)
+				if action == LoanDelegateCredit {
+					parsedABI = debtTokenABI
+				}
+
+				method, err := parsedABI.MethodById(data[:4])
+				require.NoError(t, err, action.String())
+				require.Equal(t, expected.method, method.RawName, action.String())
+
+				args, err := method.Inputs.Pack(expected.args...)
+				require.NoError(t, err, action.String())
+				require.Equal(t, hexutil.Encode(args), hexutil.Encode(data[4:]), action.String())
+
+				require.EqualValues(t, expected.value, tc.protocol.CallValue(action, params).Int64(), action.String())
+			}
+		})
+	}
+}
+
+func TestGenerateCalldata_AmountOutOfRange(t *testing.T) {
+
+	// packing 2^256+5 as an uint256 would encode 5
+	overflowing := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(5))
+
+	for _, tc := range offlineCases(t) {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			for _, action := range tc.protocol.Capabilities().SupportedActions {
+				for _, amount := range []*big.Int{overflowing, big.NewInt(-1)} {
+					params := TransactionParams{
+						Amount:    amount,
+						Sender:    testAccount,
+						Asset:     tc.asset,
+						ExtraData: tc.extraData,
+					}
+
+					_, err := tc.protocol.GenerateCalldata(context.Background(), tc.chainID, action, params)
+					require.Error(t, err, "%s with %s", action, amount)
+
+					_, err = tc.protocol.BuildTransaction(context.Background(), tc.chainID, action, params)
+					require.Error(t, err, "%s with %s", action, amount)
+				}
+			}
+		})
+	}
+
+	_, err := ApprovalCalldata(testAccount, overflowing)
+	require.Error(t, err)
+}
+
+func TestOfflineClient(t *testing.T) {
+
+	client := NewOfflineClient(BscChainID)
+
+	networkID, err := client.NetworkID(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, networkID.Cmp(BscChainID))
+
+	lista, err := NewListaStakingOperation(client, BscChainID)
+	require.NoError(t, err)
+
+	_, _, err = lista.GetBalance(context.Background(), BscChainID, testAccount, common.Address{})
+	require.ErrorIs(t, err, ErrOffline)
+
+	_, err = client.BalanceAt(context.Background(), testAccount, nil)
+	require.ErrorIs(t, err, ErrOffline)
+}
@@ -0,0 +1,42 @@
+package pkg
+
+import (
+	"math/big"
+)
+
+// ProtocolCapabilities describes what a protocol supports so callers can
+// discover it without trial and error
+type ProtocolCapabilities struct {
+	SupportedActions []ContractAction `json:"supported_actions"`
+	SupportedChains  []*big.Int       `json:"supported_chains"`
+	// RequiresExtraData lists the ExtraData keys needed by at least one
+	// of the supported actions
+	RequiresExtraData []string `json:"requires_extra_data"`
+}
+
+// Supports reports whether action is one of the supported actions
+func (c ProtocolCapabilities) Supports(action ContractAction) bool {
+	for _, v := range c.SupportedActions {
+		if v == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MarshalText encodes the action with its String name
+func (a ContractAction) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes an action from its String name
+func (a *ContractAction) UnmarshalText(text []byte) error {
+	action, err := ParseContractAction(string(text))
+	if err != nil {
+		return err
+	}
+
+	*a = action
+	return nil
+}
@@ -0,0 +1,69 @@
+package pkg
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilities(t *testing.T) {
+
+	t.Run("aave referral code is optional", func(t *testing.T) {
+		aave, err := NewAaveOperation(pkgtest.NewClient(EthChainID), EthChainID, AaveProtocolDeploymentEthereum)
+		require.NoError(t, err)
+
+		capabilities := aave.Capabilities()
+		require.NotContains(t, capabilities.RequiresExtraData, "referral_code")
+		require.True(t, capabilities.Supports(LoanSupply))
+		require.True(t, capabilities.Supports(LoanWithdraw))
+		require.False(t, capabilities.Supports(NativeStake))
+		require.Equal(t, EthChainID, capabilities.SupportedChains[0])
+	})
+
+	t.Run("aave with a default referral code", func(t *testing.T) {
+		aave, err := NewAaveOperation(pkgtest.NewClient(EthChainID), EthChainID,
+			AaveProtocolDeploymentEthereum, WithAaveReferralCode(1))
+		require.NoError(t, err)
+
+		require.NotContains(t, aave.Capabilities().RequiresExtraData, "referral_code")
+	})
+
+	t.Run("lido only stakes", func(t *testing.T) {
+		lido, err := NewLidoOperation(pkgtest.NewClient(EthChainID), EthChainID)
+		require.NoError(t, err)
+
+		capabilities := lido.Capabilities()
+		require.Equal(t, []ContractAction{NativeStake}, capabilities.SupportedActions)
+		require.Empty(t, capabilities.RequiresExtraData)
+	})
+
+	t.Run("actions are encoded by name", func(t *testing.T) {
+		b, err := json.Marshal(ProtocolCapabilities{SupportedActions: []ContractAction{LoanSupply, NativeStake}})
+		require.NoError(t, err)
+		require.Contains(t, string(b), `"supported_actions":["loan_supply","native_stake"]`)
+
+		var decoded ProtocolCapabilities
+		require.NoError(t, json.Unmarshal(b, &decoded))
+		require.Equal(t, []ContractAction{LoanSupply, NativeStake}, decoded.SupportedActions)
+	})
+}
+
+func TestProtocolRegistry_Capabilities(t *testing.T) {
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+		WithDisabledProtocols(RocketPool, Compound))
+	require.NoError(t, err)
+
+	capabilities := registry.Capabilities(EthChainID)
+	require.Len(t, capabilities, len(registry.ListProtocols(EthChainID)))
+	// the registry supplies with its default referral code
+	require.NotContains(t, capabilities[AaveEthereumV3ContractAddress.Hex()].RequiresExtraData, "referral_code")
+	require.Equal(t, []ContractAction{NativeStake}, capabilities[LidoContractAddress.Hex()].SupportedActions)
+
+	require.Empty(t, registry.Capabilities(BscChainID))
+}
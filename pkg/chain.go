@@ -0,0 +1,54 @@
+package pkg
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// ENUM(ETH,BSC,POLYGON,GNOSIS,AVALANCHE)
+//
+// Chain is the name of a supported chain
+type Chain string
+
+// ChainFromID returns the Chain of chainID
+func ChainFromID(chainID *big.Int) (Chain, error) {
+	switch {
+	case chainID == nil:
+		return Chain(""), ErrChainUnsupported
+	case IsEth(chainID):
+		return ChainETH, nil
+	case IsBnb(chainID):
+		return ChainBSC, nil
+	case IsPolygon(chainID):
+		return ChainPOLYGON, nil
+	case IsGnosis(chainID):
+		return ChainGNOSIS, nil
+	case IsAvalanche(chainID):
+		return ChainAVALANCHE, nil
+	default:
+		return Chain(""), fmt.Errorf("%w: %s", ErrChainUnsupported, chainID)
+	}
+}
+
+// IDFromChain returns the chain id of chain
+func IDFromChain(chain Chain) (*big.Int, error) {
+	var chainID *big.Int
+
+	switch chain {
+	case ChainETH:
+		chainID = EthChainID
+	case ChainBSC:
+		chainID = BscChainID
+	case ChainPOLYGON:
+		chainID = PolygonChainID
+	case ChainGNOSIS:
+		chainID = GnosisChainID
+	case ChainAVALANCHE:
+		chainID = AvalancheChainID
+	default:
+		return nil, fmt.Errorf("%s is %w", chain, ErrInvalidChain)
+	}
+
+	// the chain ids are shared package variables
+	return new(big.Int).Set(chainID), nil
+}
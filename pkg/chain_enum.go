@@ -1,6 +1,3 @@
-//go:build integration
-// +build integration
-//
 // Code generated by go-enum DO NOT EDIT.
 // Version:
 // Revision:
@@ -21,6 +18,10 @@ const (
 	ChainBSC Chain = "BSC"
 	// ChainPOLYGON is a Chain of type POLYGON.
 	ChainPOLYGON Chain = "POLYGON"
+	// ChainGNOSIS is a Chain of type GNOSIS.
+	ChainGNOSIS Chain = "GNOSIS"
+	// ChainAVALANCHE is a Chain of type AVALANCHE.
+	ChainAVALANCHE Chain = "AVALANCHE"
 )
 
 var ErrInvalidChain = errors.New("not a valid Chain")
@@ -38,9 +39,11 @@ func (x Chain) IsValid() bool {
 }
 
 var _ChainValue = map[string]Chain{
-	"ETH":     ChainETH,
-	"BSC":     ChainBSC,
-	"POLYGON": ChainPOLYGON,
+	"ETH":       ChainETH,
+	"BSC":       ChainBSC,
+	"POLYGON":   ChainPOLYGON,
+	"GNOSIS":    ChainGNOSIS,
+	"AVALANCHE": ChainAVALANCHE,
 }
 
 // ParseChain attempts to convert a string to a Chain.
@@ -0,0 +1,61 @@
+package pkg
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChain(t *testing.T) {
+
+	for _, chain := range []Chain{ChainETH, ChainBSC, ChainPOLYGON, ChainGNOSIS, ChainAVALANCHE} {
+		parsed, err := ParseChain(chain.String())
+		require.NoError(t, err)
+		require.Equal(t, chain, parsed)
+		require.True(t, chain.IsValid())
+	}
+
+	_, err := ParseChain("eth")
+	require.ErrorIs(t, err, ErrInvalidChain)
+	require.False(t, Chain("SOLANA").IsValid())
+}
+
+func TestChainFromID(t *testing.T) {
+
+	tt := []struct {
+		chainID *big.Int
+		chain   Chain
+	}{
+		{chainID: EthChainID, chain: ChainETH},
+		{chainID: BscChainID, chain: ChainBSC},
+		{chainID: PolygonChainID, chain: ChainPOLYGON},
+		{chainID: GnosisChainID, chain: ChainGNOSIS},
+		{chainID: AvalancheChainID, chain: ChainAVALANCHE},
+	}
+
+	for _, v := range tt {
+		chain, err := ChainFromID(v.chainID)
+		require.NoError(t, err)
+		require.Equal(t, v.chain, chain)
+
+		chainID, err := IDFromChain(v.chain)
+		require.NoError(t, err)
+		require.Zero(t, chainID.Cmp(v.chainID), v.chain.String())
+	}
+
+	_, err := ChainFromID(big.NewInt(999))
+	require.ErrorIs(t, err, ErrChainUnsupported)
+
+	_, err = ChainFromID(nil)
+	require.ErrorIs(t, err, ErrChainUnsupported)
+
+	_, err = IDFromChain(Chain("SOLANA"))
+	require.ErrorIs(t, err, ErrInvalidChain)
+
+	// the returned id is a copy
+	chainID, err := IDFromChain(ChainETH)
+	require.NoError(t, err)
+	chainID.SetInt64(2)
+	require.Equal(t, int64(1), EthChainID.Int64())
+}
@@ -0,0 +1,148 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// EthClient is the subset of the go-ethereum client used by the protocol operations.
+// *ethclient.Client satisfies it, and unit tests can use the mock in the pkgtest package
+type EthClient interface {
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	NetworkID(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+}
+
+// GasEstimator is implemented by protocols that can estimate the gas needed
+// to execute the calldata they generate
+type GasEstimator interface {
+	EstimateGas(ctx context.Context, chainID *big.Int, action ContractAction, params TransactionParams) (uint64, error)
+}
+
+// estimateGas asks the node how much gas sending tx from sender needs
+func estimateGas(ctx context.Context, client EthClient,
+	sender common.Address, tx *Transaction) (uint64, error) {
+
+	data, err := hexutil.Decode(tx.Data)
+	if err != nil {
+		return 0, err
+	}
+
+	return client.EstimateGas(ctx, ethereum.CallMsg{
+		From:  sender,
+		To:    &tx.To,
+		Data:  data,
+		Value: tx.Value,
+	})
+}
+
+// ErrNetworkMismatch is returned on first use of an operation whose client is
+// connected to another chain than the one it was created for
+var ErrNetworkMismatch = errors.New("network id does not match")
+
+// networkCheck verifies the network id of the client of an operation on first
+// use rather than when it is created, so building a registry does not block on
+// the nodes and a flaky one only fails the operations that use it
+type networkCheck struct {
+	mu       sync.Mutex
+	verified bool
+	// skip disables the check, e.g for forked nodes reporting another network id
+	skip bool
+}
+
+// NetworkOption configures the network id check of an operation. It is accepted
+// by the constructors of every operation verifying the network of its client
+type NetworkOption func(*networkCheck)
+
+// WithSkipNetworkCheck stops the operation from verifying the network id of its
+// client, e.g when testing against a fork whose node reports another network id.
+// The check is on by default
+func WithSkipNetworkCheck() NetworkOption {
+	return func(n *networkCheck) {
+		n.skip = true
+	}
+}
+
+// ensure checks once that client is connected to chainID. Failures are not
+// cached and the network id is fetched again on the next call
+func (n *networkCheck) ensure(ctx context.Context, client EthClient, chainID *big.Int) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.verified || n.skip {
+		return nil
+	}
+
+	networkID, err := client.NetworkID(ctx)
+	if err != nil {
+		return fmt.Errorf("client.NetworkID: could not fetch network id.. %w", err)
+	}
+
+	if networkID.Cmp(chainID) != 0 {
+		return fmt.Errorf("%w: client is on %d, chainID provided is %d",
+			ErrNetworkMismatch, networkID.Int64(), chainID.Int64())
+	}
+
+	n.verified = true
+	return nil
+}
+
+// ErrOffline is returned by the node calls of an offline client
+var ErrOffline = errors.New("offline client can not reach a node")
+
+// offlineClient reports its chain id as the network id and fails every other call
+type offlineClient struct {
+	chainID *big.Int
+}
+
+// NewOfflineClient returns a client for operations that only pack calldata, e.g
+// in unit tests. It reports chainID as the network id so the network check of
+// the operations passes, every other call fails with ErrOffline
+func NewOfflineClient(chainID *big.Int) EthClient {
+	return offlineClient{chainID: new(big.Int).Set(chainID)}
+}
+
+func (c offlineClient) CallContract(context.Context, ethereum.CallMsg, *big.Int) ([]byte, error) {
+	return nil, ErrOffline
+}
+
+func (c offlineClient) BalanceAt(context.Context, common.Address, *big.Int) (*big.Int, error) {
+	return nil, ErrOffline
+}
+
+func (c offlineClient) NetworkID(context.Context) (*big.Int, error) {
+	return new(big.Int).Set(c.chainID), nil
+}
+
+func (c offlineClient) EstimateGas(context.Context, ethereum.CallMsg) (uint64, error) {
+	return 0, ErrOffline
+}
+
+// networkIDClient reports a fixed network id on behalf of the client it wraps
+type networkIDClient struct {
+	EthClient
+
+	networkID *big.Int
+}
+
+// OverrideNetworkID returns a client reporting networkID from NetworkID and
+// forwarding every other call to client. It lets one node, e.g a local fork,
+// serve operations of another chain id
+func OverrideNetworkID(client EthClient, networkID *big.Int) EthClient {
+	return &networkIDClient{EthClient: client, networkID: new(big.Int).Set(networkID)}
+}
+
+// Unwrap returns the wrapped client
+func (c *networkIDClient) Unwrap() EthClient { return c.EthClient }
+
+func (c *networkIDClient) NetworkID(context.Context) (*big.Int, error) {
+	return new(big.Int).Set(c.networkID), nil
+}
@@ -0,0 +1,156 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// rpcLimitExceededCode is the JSON-RPC error code nodes answer with when rate limited
+const rpcLimitExceededCode = -32005
+
+// retryClient retries the calls of an EthClient failing with transient errors.
+// Reverts and other deterministic errors are returned right away
+type retryClient struct {
+	EthClient
+
+	attempts  int
+	baseDelay time.Duration
+}
+
+// NewRetryClient wraps client so CallContract, BalanceAt and NetworkID are
+// attempted up to attempts times, waiting baseDelay doubled after every failure.
+// EstimateGas is not retried since it mostly fails because the call reverts
+func NewRetryClient(client EthClient, attempts int, baseDelay time.Duration) EthClient {
+	if attempts <= 1 {
+		return client
+	}
+
+	return &retryClient{
+		EthClient: client,
+		attempts:  attempts,
+		baseDelay: baseDelay,
+	}
+}
+
+// Unwrap returns the wrapped client
+func (c *retryClient) Unwrap() EthClient { return c.EthClient }
+
+func (c *retryClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var result []byte
+
+	err := c.retry(ctx, func() error {
+		var err error
+		result, err = c.EthClient.CallContract(ctx, msg, blockNumber)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	var balance *big.Int
+
+	err := c.retry(ctx, func() error {
+		var err error
+		balance, err = c.EthClient.BalanceAt(ctx, account, blockNumber)
+		return err
+	})
+
+	return balance, err
+}
+
+func (c *retryClient) NetworkID(ctx context.Context) (*big.Int, error) {
+	var networkID *big.Int
+
+	err := c.retry(ctx, func() error {
+		var err error
+		networkID, err = c.EthClient.NetworkID(ctx)
+		return err
+	})
+
+	return networkID, err
+}
+
+// retry runs fn until it succeeds, fails with a non transient error, the
+// attempts are exhausted or ctx is done
+func (c *retryClient) retry(ctx context.Context, fn func() error) error {
+
+	delay := c.baseDelay
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || attempt >= c.attempts || !isTransientError(err) {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		delay *= 2
+	}
+}
+
+// isTransientError reports whether err is worth retrying: rate limits,
+// server side failures and dropped connections
+func isTransientError(err error) bool {
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	// reverts carry the revert data and will fail the same way again
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		return false
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == rpcLimitExceededCode {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "rate limit") || strings.Contains(msg, "too many requests")
+}
+
+// unwrapClient returns the client wrapped by the registry, if any
+func unwrapClient(client EthClient) EthClient {
+	for {
+		c, ok := client.(interface{ Unwrap() EthClient })
+		if !ok {
+			return client
+		}
+
+		client = c.Unwrap()
+	}
+}
@@ -0,0 +1,126 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+type revertError struct{}
+
+func (revertError) Error() string          { return "execution reverted" }
+func (revertError) ErrorData() interface{} { return "0x" }
+
+// failingHandler fails the first failures calls with err then returns result
+func failingHandler(failures int, err error, result []byte) (pkgtest.CallHandler, *int) {
+	calls := 0
+	return func(ethereum.CallMsg) ([]byte, error) {
+		calls++
+		if calls <= failures {
+			return nil, err
+		}
+
+		return result, nil
+	}, &calls
+}
+
+func TestRetryClient(t *testing.T) {
+
+	rateLimited := rpc.HTTPError{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"}
+	result := common.LeftPadBytes(big.NewInt(42).Bytes(), 32)
+
+	t.Run("transient errors are retried", func(t *testing.T) {
+		client := pkgtest.NewClient(EthChainID)
+		handler, calls := failingHandler(2, rateLimited, result)
+		client.HandleFunc(handler)
+
+		got, err := NewRetryClient(client, 3, time.Millisecond).
+			CallContract(context.Background(), ethereum.CallMsg{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, result, got)
+		require.Equal(t, 3, *calls)
+	})
+
+	t.Run("attempts are exhausted", func(t *testing.T) {
+		client := pkgtest.NewClient(EthChainID)
+		handler, calls := failingHandler(5, rateLimited, result)
+		client.HandleFunc(handler)
+
+		_, err := NewRetryClient(client, 3, time.Millisecond).
+			CallContract(context.Background(), ethereum.CallMsg{}, nil)
+		require.ErrorAs(t, err, &rpc.HTTPError{})
+		require.Equal(t, 3, *calls)
+	})
+
+	t.Run("reverts are not retried", func(t *testing.T) {
+		client := pkgtest.NewClient(EthChainID)
+		handler, calls := failingHandler(1, revertError{}, result)
+		client.HandleFunc(handler)
+
+		_, err := NewRetryClient(client, 3, time.Millisecond).
+			CallContract(context.Background(), ethereum.CallMsg{}, nil)
+		require.Error(t, err)
+		require.Equal(t, 1, *calls)
+	})
+
+	t.Run("context cancellation stops the backoff", func(t *testing.T) {
+		client := pkgtest.NewClient(EthChainID)
+		handler, calls := failingHandler(5, rateLimited, result)
+		client.HandleFunc(handler)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := NewRetryClient(client, 5, time.Hour).CallContract(ctx, ethereum.CallMsg{}, nil)
+		require.Error(t, err)
+		require.Equal(t, 1, *calls)
+	})
+
+	t.Run("a single attempt returns the client", func(t *testing.T) {
+		client := pkgtest.NewClient(EthChainID)
+		require.Same(t, client, NewRetryClient(client, 1, time.Millisecond))
+	})
+}
+
+func TestIsTransientError(t *testing.T) {
+	require.True(t, isTransientError(rpc.HTTPError{StatusCode: http.StatusBadGateway}))
+	require.True(t, isTransientError(errors.New("exceeded the rate limit")))
+	require.False(t, isTransientError(rpc.HTTPError{StatusCode: http.StatusUnauthorized}))
+	require.False(t, isTransientError(revertError{}))
+	require.False(t, isTransientError(context.Canceled))
+	require.False(t, isTransientError(errors.New("abi: cannot unmarshal")))
+}
+
+func TestProtocolRegistry_WithRetry(t *testing.T) {
+
+	client := pkgtest.NewClient(EthChainID)
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(EthChainID, client),
+		WithDisabledProtocols(AaveV3, SparkLend, Ankr, RocketPool, Compound, EigenLayer),
+		WithRetry(3, time.Millisecond))
+	require.NoError(t, err)
+
+	lido, err := registry.GetProtocol(EthChainID, LidoContractAddress)
+	require.NoError(t, err)
+
+	handler, calls := failingHandler(2, rpc.HTTPError{StatusCode: http.StatusServiceUnavailable},
+		common.LeftPadBytes(big.NewInt(42).Bytes(), 32))
+	client.HandleFunc(handler)
+
+	_, balance, err := lido.GetBalance(context.Background(), EthChainID, testAccount, common.HexToAddress(nativeDenomAddress))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(42), balance)
+	require.Equal(t, 3, *calls)
+}
@@ -7,11 +7,12 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 const compoundv3ABI = `
@@ -28,6 +29,21 @@ const compoundv3ABI = `
       }
     ]
   },
+  {
+    "name": "withdrawTo",
+    "type": "function",
+    "inputs": [
+      {
+        "type": "address"
+      },
+      {
+        "type": "address"
+      },
+      {
+        "type": "uint256"
+      }
+    ]
+  },
   {
     "name": "supply",
     "type": "function",
@@ -40,6 +56,33 @@ const compoundv3ABI = `
       }
     ]
   },
+  {
+    "name": "supplyTo",
+    "type": "function",
+    "inputs": [
+      {
+        "type": "address"
+      },
+      {
+        "type": "address"
+      },
+      {
+        "type": "uint256"
+      }
+    ]
+  },
+  {
+    "name": "allow",
+    "type": "function",
+    "inputs": [
+      {
+        "type": "address"
+      },
+      {
+        "type": "bool"
+      }
+    ]
+  },
   {
     "inputs": [
       {
@@ -135,6 +178,103 @@ const compoundv3ABI = `
     ],
     "stateMutability": "view",
     "type": "function"
+  },
+  {
+    "inputs": [
+      {
+        "internalType": "address",
+        "name": "asset",
+        "type": "address"
+      }
+    ],
+    "name": "getAssetInfoByAddress",
+    "outputs": [
+      {
+        "internalType": "uint8",
+        "name": "offset",
+        "type": "uint8"
+      },
+      {
+        "internalType": "address",
+        "name": "asset",
+        "type": "address"
+      },
+      {
+        "internalType": "address",
+        "name": "priceFeed",
+        "type": "address"
+      },
+      {
+        "internalType": "uint64",
+        "name": "scale",
+        "type": "uint64"
+      },
+      {
+        "internalType": "uint64",
+        "name": "borrowCollateralFactor",
+        "type": "uint64"
+      },
+      {
+        "internalType": "uint64",
+        "name": "liquidateCollateralFactor",
+        "type": "uint64"
+      },
+      {
+        "internalType": "uint64",
+        "name": "liquidationFactor",
+        "type": "uint64"
+      },
+      {
+        "internalType": "uint128",
+        "name": "supplyCap",
+        "type": "uint128"
+      }
+    ],
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      {
+        "internalType": "address",
+        "name": "",
+        "type": "address"
+      }
+    ],
+    "name": "totalsCollateral",
+    "outputs": [
+      {
+        "internalType": "uint128",
+        "name": "totalSupplyAsset",
+        "type": "uint128"
+      },
+      {
+        "internalType": "uint128",
+        "name": "_reserved",
+        "type": "uint128"
+      }
+    ],
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      {
+        "internalType": "address",
+        "name": "priceFeed",
+        "type": "address"
+      }
+    ],
+    "name": "getPrice",
+    "outputs": [
+      {
+        "internalType": "uint256",
+        "name": "",
+        "type": "uint256"
+      }
+    ],
+    "stateMutability": "view",
+    "type": "function"
   }
 ]
 `
@@ -146,13 +286,21 @@ const (
 	CompoundV3PolygonUSDTPool = "0xaeB318360f27748Acb200CE616E389A6C9409a07"
 )
 
+// compoundUSDMarkets lists the markets whose price feeds are denominated in USD,
+// the feeds of the WETH market are denominated in ETH
+var compoundUSDMarkets = map[common.Address]bool{
+	common.HexToAddress(CompoundV3USDCPool):        true,
+	common.HexToAddress(CompoundV3PolygonUSDCPool): true,
+	common.HexToAddress(CompoundV3PolygonUSDTPool): true,
+}
+
 var poolMaps = map[int64][]string{
 	1:   {CompoundV3ETHPool, CompoundV3USDCPool},
 	137: {CompoundV3PolygonUSDCPool, CompoundV3PolygonUSDTPool},
 }
 
 // dynamically registers all supported pools
-func registerCompoundRegistry(registry ProtocolRegistry, client *ethclient.Client, chainID int64) error {
+func registerCompoundRegistry(registry ProtocolRegistry, client EthClient, chainID int64) error {
 	protocols, ok := poolMaps[chainID]
 	if !ok {
 		return nil
@@ -179,41 +327,98 @@ type CompoundOperation struct {
 	chainID   *big.Int
 	version   string
 
+	assetsMu sync.RWMutex
 	// assets that are supported in this pool
 	supportedAssets []common.Address
+	// when supportedAssets was last pulled from the market
+	assetsRefreshedAt time.Time
+	// how old supportedAssets can get before GetSupportedAssets refreshes it.
+	// Zero means the list is never refreshed lazily
+	assetsMaxAge time.Duration
+
+	client   EthClient
+	observer Observer
+	metadata AssetMetadata
+
+	// disabled through the registry, see SetProtocolEnabled
+	deprecation
+}
+
+var _ Protocol = (*CompoundOperation)(nil)
+
+// CompoundOption configures optional behaviour of a CompoundOperation
+type CompoundOption func(*CompoundOperation)
 
-	client *ethclient.Client
+// WithSupportedAssetsMaxAge makes GetSupportedAssets refresh the market's
+// collateral list once it is older than maxAge
+func WithSupportedAssetsMaxAge(maxAge time.Duration) CompoundOption {
+	return func(c *CompoundOperation) {
+		c.assetsMaxAge = maxAge
+	}
 }
 
-func NewCompoundOperation(client *ethclient.Client, chainID *big.Int,
-	marketPool common.Address) (*CompoundOperation, error) {
+func NewCompoundOperation(client EthClient, chainID *big.Int,
+	marketPool common.Address, opts ...CompoundOption) (*CompoundOperation, error) {
 
 	parsedABI, err := abi.JSON(strings.NewReader(compoundv3ABI))
 	if err != nil {
 		return nil, err
 	}
 
-	supportedAssets, err := getSupportedAssets(parsedABI, client, marketPool)
+	supportedAssets, err := getSupportedAssets(context.Background(), parsedABI, client, marketPool)
 	if err != nil {
 		return nil, err
 	}
 
-	if chainID.Int64() != EthChainID.Int64() && chainID.Int64() != PolygonChainID.Int64() {
+	if !IsEth(chainID) && !IsPolygon(chainID) {
 		return nil, errors.New("unsupported chain id")
 	}
 
-	return &CompoundOperation{
-		supportedAssets: supportedAssets,
-		parsedABI:       parsedABI,
-		contract:        marketPool,
-		chainID:         chainID,
-		version:         "3",
-		client:          client,
-	}, nil
+	c := &CompoundOperation{
+		supportedAssets:   supportedAssets,
+		assetsRefreshedAt: time.Now(),
+		parsedABI:         parsedABI,
+		contract:          marketPool,
+		chainID:           chainID,
+		version:           "3",
+		client:            client,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
 }
 
-func getSupportedAssets(parsedPoolABI abi.ABI,
-	client *ethclient.Client, marketPool common.Address) ([]common.Address, error) {
+// RefreshSupportedAssets pulls the collateral list of the market again so newly
+// listed assets are picked up without recreating the operation
+func (c *CompoundOperation) RefreshSupportedAssets(ctx context.Context) error {
+	supportedAssets, err := getSupportedAssets(ctx, c.parsedABI, c.client, c.contract)
+	if err != nil {
+		return err
+	}
+
+	c.assetsMu.Lock()
+	defer c.assetsMu.Unlock()
+
+	c.supportedAssets = supportedAssets
+	c.assetsRefreshedAt = time.Now()
+	return nil
+}
+
+func (c *CompoundOperation) isAssetListStale() bool {
+	c.assetsMu.RLock()
+	defer c.assetsMu.RUnlock()
+
+	return c.assetsMaxAge > 0 && time.Since(c.assetsRefreshedAt) > c.assetsMaxAge
+}
+
+// getSupportedAssets lists the collaterals of the market. The asset infos are
+// batched in a single Multicall3 call, falling back to one call per asset
+// on chains without Multicall3
+func getSupportedAssets(ctx context.Context, parsedPoolABI abi.ABI,
+	client EthClient, marketPool common.Address) ([]common.Address, error) {
 
 	numAssetsCallData, err := parsedPoolABI.Pack("numAssets")
 	if err != nil {
@@ -225,7 +430,7 @@ func getSupportedAssets(parsedPoolABI abi.ABI,
 		Data: numAssetsCallData,
 	}
 
-	result, err := client.CallContract(context.Background(), msg, nil)
+	result, err := client.CallContract(ctx, msg, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -237,21 +442,60 @@ func getSupportedAssets(parsedPoolABI abi.ABI,
 		return nil, fmt.Errorf("failed to unpack output: %v", err)
 	}
 
-	var supportedTokens = make([]common.Address, 0, numAssets)
+	if numAssets == 0 {
+		return []common.Address{}, nil
+	}
+
+	supportedTokens, err := getAssetInfosBatched(ctx, parsedPoolABI, client, marketPool, numAssets)
+	if err == nil {
+		return supportedTokens, nil
+	}
+
+	return getAssetInfosSequential(ctx, parsedPoolABI, client, marketPool, numAssets)
+}
+
+// getAssetInfosBatched fetches the info of every collateral asset with Multicall3
+func getAssetInfosBatched(ctx context.Context, parsedPoolABI abi.ABI,
+	client EthClient, marketPool common.Address, numAssets uint8) ([]common.Address, error) {
+
+	calls := make([]multicallCall, 0, numAssets)
 
-	// Fetch info for each collateral asset
 	for i := uint8(0); i < numAssets; i++ {
-		var assetInfo struct {
-			Offset                    uint8
-			Asset                     common.Address
-			PriceFeed                 common.Address
-			Scale                     uint64
-			BorrowCollateralFactor    uint64
-			LiquidateCollateralFactor uint64
-			LiquidationFactor         uint64
-			SupplyCap                 *big.Int
+		assetInfoCalldata, err := parsedPoolABI.Pack("getAssetInfo", i)
+		if err != nil {
+			return nil, err
+		}
+
+		calls = append(calls, multicallCall{Target: marketPool, CallData: assetInfoCalldata})
+	}
+
+	results, err := multicall(ctx, client, calls)
+	if err != nil {
+		return nil, err
+	}
+
+	var supportedTokens = make([]common.Address, 0, numAssets)
+
+	for _, result := range results {
+		assetInfo, err := unpackAssetInfo(parsedPoolABI, result)
+		if err != nil {
+			return nil, err
 		}
 
+		supportedTokens = append(supportedTokens, assetInfo.Asset)
+	}
+
+	return supportedTokens, nil
+}
+
+// getAssetInfosSequential fetches the info of every collateral asset one call at a time
+func getAssetInfosSequential(ctx context.Context, parsedPoolABI abi.ABI,
+	client EthClient, marketPool common.Address, numAssets uint8) ([]common.Address, error) {
+
+	var supportedTokens = make([]common.Address, 0, numAssets)
+
+	// Fetch info for each collateral asset
+	for i := uint8(0); i < numAssets; i++ {
 		assetInfoCalldata, err := parsedPoolABI.Pack("getAssetInfo", i)
 		if err != nil {
 			return nil, err
@@ -262,14 +506,14 @@ func getSupportedAssets(parsedPoolABI abi.ABI,
 			Data: assetInfoCalldata,
 		}
 
-		result, err := client.CallContract(context.Background(), msg, nil)
+		result, err := client.CallContract(ctx, msg, nil)
 		if err != nil {
 			return nil, err
 		}
 
-		err = parsedPoolABI.UnpackIntoInterface(&assetInfo, "getAssetInfo", result)
+		assetInfo, err := unpackAssetInfo(parsedPoolABI, result)
 		if err != nil {
-			return nil, fmt.Errorf("failed to unpack output: %v", err)
+			return nil, err
 		}
 
 		supportedTokens = append(supportedTokens, assetInfo.Asset)
@@ -278,13 +522,68 @@ func getSupportedAssets(parsedPoolABI abi.ABI,
 	return supportedTokens, nil
 }
 
+// compoundAssetInfo is the configuration of a collateral asset of a market
+type compoundAssetInfo struct {
+	Offset                    uint8
+	Asset                     common.Address
+	PriceFeed                 common.Address
+	Scale                     uint64
+	BorrowCollateralFactor    uint64
+	LiquidateCollateralFactor uint64
+	LiquidationFactor         uint64
+	SupplyCap                 *big.Int
+}
+
+// unpackAssetInfo decodes a getAssetInfo or getAssetInfoByAddress result
+func unpackAssetInfo(parsedPoolABI abi.ABI, result []byte) (compoundAssetInfo, error) {
+	var assetInfo compoundAssetInfo
+
+	err := parsedPoolABI.UnpackIntoInterface(&assetInfo, "getAssetInfo", result)
+	if err != nil {
+		return compoundAssetInfo{}, fmt.Errorf("failed to unpack output: %v", err)
+	}
+
+	return assetInfo, nil
+}
+
+const (
+	// compoundExtraDataManager is the ExtraData key of the account LoanAllowManager authorizes
+	compoundExtraDataManager = "manager"
+	// compoundExtraDataAllowed is the ExtraData key granting or revoking the manager
+	compoundExtraDataAllowed = "allowed"
+)
+
 // GenerateCalldata creates the necessary blockchain transaction data
 func (a *CompoundOperation) GenerateCalldata(ctx context.Context, chainID *big.Int,
 	action ContractAction, params TransactionParams) (string, error) {
-	if chainID.Int64() != 1 {
+	if err := a.deprecation.check(a.GetUniqueKey()); err != nil {
+		return "", err
+	}
+
+	if err := params.checkAmountRange(); err != nil {
+		return "", err
+	}
+
+	return observeCalldata(a.observer, a.GetName(), action, func() (string, error) {
+		return a.generateCalldata(ctx, chainID, action, params)
+	})
+}
+
+func (a *CompoundOperation) generateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+	if !IsEth(chainID) {
 		return "", ErrChainUnsupported
 	}
 
+	// permissions apply to the whole account rather than an amount
+	if action == LoanAllowManager {
+		return a.allow(params)
+	}
+
+	if params.Amount == nil {
+		return "", ErrAmountRequired
+	}
+
 	switch action {
 	case LoanSupply:
 		return a.supply(params)
@@ -295,52 +594,250 @@ func (a *CompoundOperation) GenerateCalldata(ctx context.Context, chainID *big.I
 	}
 }
 
+// withdraw sends the asset to the sender, or to params.Recipient with withdrawTo
 func (c *CompoundOperation) withdraw(opts TransactionParams) (string, error) {
-	calldata, err := c.parsedABI.Pack("withdraw", opts.Asset, opts.Amount)
+
+	method := "withdraw"
+	args := []interface{}{opts.Asset, opts.Amount}
+
+	if to := opts.GetBeneficiaryOwner(); to != opts.Sender {
+		method = "withdrawTo"
+		args = []interface{}{to, opts.Asset, opts.Amount}
+	}
+
+	calldata, err := c.parsedABI.Pack(method, args...)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate calldata for %s: %w", "withdraw", err)
+		return "", fmt.Errorf("failed to generate calldata for %s: %w", method, err)
 	}
 
 	return HexPrefix + hex.EncodeToString(calldata), nil
 }
 
+// supply credits the sender, or params.Recipient with supplyTo
 func (c *CompoundOperation) supply(opts TransactionParams) (string, error) {
 
-	calldata, err := c.parsedABI.Pack("supply", opts.Asset, opts.Amount)
+	method := "supply"
+	args := []interface{}{opts.Asset, opts.Amount}
+
+	if to := opts.GetBeneficiaryOwner(); to != opts.Sender {
+		method = "supplyTo"
+		args = []interface{}{to, opts.Asset, opts.Amount}
+	}
+
+	calldata, err := c.parsedABI.Pack(method, args...)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate calldata for %s: %w", "supply", err)
+		return "", fmt.Errorf("failed to generate calldata for %s: %w", method, err)
 	}
 
 	return HexPrefix + hex.EncodeToString(calldata), nil
 }
 
+// allow grants or revokes the permission of the manager set in ExtraData
+// to withdraw and transfer on behalf of the sender
+func (c *CompoundOperation) allow(opts TransactionParams) (string, error) {
+
+	manager, allowed, err := compoundManager(opts)
+	if err != nil {
+		return "", err
+	}
+
+	calldata, err := c.parsedABI.Pack("allow", manager, allowed)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate calldata for %s: %w", "allow", err)
+	}
+
+	return HexPrefix + hex.EncodeToString(calldata), nil
+}
+
+// compoundManager returns the manager and whether it is allowed from ExtraData
+func compoundManager(params TransactionParams) (common.Address, bool, error) {
+
+	v, ok := params.ExtraData[compoundExtraDataManager]
+	if !ok {
+		return common.Address{}, false, errors.New("manager must be provided in extra data")
+	}
+
+	manager, err := toAddress(v)
+	if err != nil {
+		return common.Address{}, false, fmt.Errorf("invalid manager: %w", err)
+	}
+
+	if manager == (common.Address{}) {
+		return common.Address{}, false, errors.New("manager can not be the zero address")
+	}
+
+	v, ok = params.ExtraData[compoundExtraDataAllowed]
+	if !ok {
+		return common.Address{}, false, errors.New("allowed must be provided in extra data")
+	}
+
+	allowed, ok := v.(bool)
+	if !ok {
+		return common.Address{}, false, fmt.Errorf("invalid allowed: %T is not a bool", v)
+	}
+
+	return manager, allowed, nil
+}
+
+// EstimateGas estimates the gas needed to execute the generated calldata
+func (c *CompoundOperation) EstimateGas(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (uint64, error) {
+
+	tx, err := c.BuildTransaction(ctx, chainID, action, params)
+	if err != nil {
+		return 0, err
+	}
+
+	return estimateGas(ctx, c.client, params.Sender, tx)
+}
+
+// BuildTransaction generates the calldata for the action together with
+// the contract to call and the value to send
+func (c *CompoundOperation) BuildTransaction(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (*Transaction, error) {
+
+	calldata, err := c.GenerateCalldata(ctx, chainID, action, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{
+		To:    c.GetContractAddress(chainID),
+		Data:  calldata,
+		Value: c.CallValue(action, params),
+	}, nil
+}
+
 // Validate checks if the provided parameters are valid for the specified action
 func (l *CompoundOperation) Validate(ctx context.Context,
 	chainID *big.Int, action ContractAction, params TransactionParams) error {
 
-	if chainID.Int64() != 1 {
+	if err := l.deprecation.check(l.GetUniqueKey()); err != nil {
+		return err
+	}
+
+	if !IsEth(chainID) {
 		return ErrChainUnsupported
 	}
 
+	if action == LoanAllowManager {
+		_, _, err := compoundManager(params)
+		return err
+	}
+
 	if !l.IsSupportedAsset(ctx, l.chainID, params.Asset) {
-		return fmt.Errorf("asset not supported %s", params.Asset)
+		return fmt.Errorf("%w %s", ErrAssetNotSupported, params.Asset)
 	}
 
 	if action != LoanSupply && action != LoanWithdraw {
-		return errors.New("action not supported")
+		return ErrUnsupportedAction
+	}
+
+	if err := params.ValidateAmount(); err != nil {
+		return err
+	}
+
+	if err := checkAmountPrecision(ctx, l.client, l.metadata, l.chainID, params, params.Asset); err != nil {
+		return err
 	}
 
 	if action == LoanSupply {
-		return nil
+		if err := l.checkSupplyCap(ctx, params.Asset, params.Amount); err != nil {
+			return err
+		}
+
+		return checkAllowance(ctx, l.client, params, params.Asset, l.contract, params.Amount)
+	}
+
+	return nil
+}
+
+// checkSupplyCap returns a *SupplyCapError when supplying amount of asset would
+// take the collateral held by the market over the asset's supplyCap
+func (c *CompoundOperation) checkSupplyCap(ctx context.Context, asset common.Address, amount *big.Int) error {
+
+	assetInfo, err := c.getAssetInfo(ctx, asset)
+	if err != nil {
+		return fmt.Errorf("could not fetch the supply cap of %s: %w", asset, err)
 	}
 
-	if params.Amount.Cmp(big.NewInt(0)) <= 0 {
-		return errors.New("amount must be greater than zero")
+	calldata, err := c.parsedABI.Pack("totalsCollateral", asset)
+	if err != nil {
+		return err
+	}
+
+	result, err := c.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &c.contract,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("could not fetch the total supply of %s: %w", asset, err)
+	}
+
+	totalSupply := new(big.Int)
+	reserved := new(big.Int)
+	err = c.parsedABI.UnpackIntoInterface(&[]interface{}{&totalSupply, &reserved}, "totalsCollateral", result)
+	if err != nil {
+		return fmt.Errorf("failed to unpack output: %v", err)
+	}
+
+	remaining := new(big.Int).Sub(assetInfo.SupplyCap, totalSupply)
+	if remaining.Sign() < 0 {
+		remaining.SetInt64(0)
+	}
+
+	if amount.Cmp(remaining) > 0 {
+		return &SupplyCapError{
+			Asset:       asset,
+			SupplyCap:   assetInfo.SupplyCap,
+			TotalSupply: totalSupply,
+			Remaining:   remaining,
+		}
 	}
 
 	return nil
 }
 
+// getAssetInfo reads the configuration of the collateral asset from the market
+func (c *CompoundOperation) getAssetInfo(ctx context.Context, asset common.Address) (compoundAssetInfo, error) {
+
+	calldata, err := c.parsedABI.Pack("getAssetInfoByAddress", asset)
+	if err != nil {
+		return compoundAssetInfo{}, err
+	}
+
+	result, err := c.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &c.contract,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return compoundAssetInfo{}, err
+	}
+
+	return unpackAssetInfo(c.parsedABI, result)
+}
+
+// getPrice reads the price of a feed of the market with 8 decimals
+func (c *CompoundOperation) getPrice(ctx context.Context, priceFeed common.Address) (*big.Int, error) {
+
+	calldata, err := c.parsedABI.Pack("getPrice", priceFeed)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &c.contract,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	price := new(big.Int)
+	err = c.parsedABI.UnpackIntoInterface(&price, "getPrice", result)
+	return price, err
+}
+
 // GetBalance retrieves the balance for a specified account and asset
 func (l *CompoundOperation) GetBalance(ctx context.Context,
 	chainID *big.Int,
@@ -352,7 +849,7 @@ func (l *CompoundOperation) GetBalance(ctx context.Context,
 		return address, nil, errors.New("unsupported asset. cannot fetch it's balance")
 	}
 
-	if chainID.Int64() != 1 {
+	if !IsEth(chainID) {
 		return address, nil, ErrChainUnsupported
 	}
 
@@ -378,22 +875,30 @@ func (l *CompoundOperation) GetBalance(ctx context.Context,
 // GetSupportedAssets returns a list of assets supported by the protocol on the specified chain
 func (c *CompoundOperation) GetSupportedAssets(ctx context.Context,
 	chainID *big.Int) ([]common.Address, error) {
-	return c.supportedAssets, nil
+
+	if c.isAssetListStale() {
+		if err := c.RefreshSupportedAssets(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	c.assetsMu.RLock()
+	defer c.assetsMu.RUnlock()
+
+	// a copy so callers can not change the cached list
+	return append([]common.Address(nil), c.supportedAssets...), nil
 }
 
 // IsSupportedAsset checks if the specified asset is supported on the given chain
 func (c *CompoundOperation) IsSupportedAsset(ctx context.Context, chainID *big.Int, asset common.Address) bool {
-	if chainID.Int64() != 1 {
+	if !IsEth(chainID) {
 		return false
 	}
 
-	for _, addr := range c.supportedAssets {
-		if addr.Hex() == asset.Hex() {
-			return true
-		}
-	}
+	c.assetsMu.RLock()
+	defer c.assetsMu.RUnlock()
 
-	return false
+	return containsAddress(c.supportedAssets, asset)
 }
 
 // GetProtocolConfig returns the protocol config for a specific chain
@@ -415,8 +920,51 @@ func (l *CompoundOperation) GetType() ProtocolType { return TypeLoan }
 // GetContractAddress returns the contract address for a specific chain
 func (l *CompoundOperation) GetContractAddress(chainID *big.Int) common.Address { return l.contract }
 
+// GetContractAddressForAction returns the contract the calldata of action is sent to,
+// the same for every action
+func (l *CompoundOperation) GetContractAddressForAction(chainID *big.Int, action ContractAction) (common.Address, error) {
+	return contractAddressForAction(l, chainID, action)
+}
+
 // Name returns the human readable name for the protocol
 func (l *CompoundOperation) GetName() string { return Compound }
 
 // GetVersion returns the version of the protocol
 func (l *CompoundOperation) GetVersion() string { return l.version }
+
+// GetUniqueKey returns the key identifying the protocol across chains.
+// Several Compound markets live on the same chain so the market is part of the key
+func (l *CompoundOperation) GetUniqueKey() string {
+	return protocolKey(l.GetName(), l.chainID) + ":" + l.contract.Hex()
+}
+
+// CallValue returns the native amount to send along with the calldata.
+// ERC20 collateral is pulled with an allowance so nothing is sent
+func (l *CompoundOperation) CallValue(action ContractAction, params TransactionParams) *big.Int {
+	return big.NewInt(0)
+}
+
+// Capabilities describes the actions, chains and extra data supported by the protocol
+func (l *CompoundOperation) Capabilities() ProtocolCapabilities {
+	return ProtocolCapabilities{
+		SupportedActions:  []ContractAction{LoanSupply, LoanWithdraw, LoanAllowManager},
+		SupportedChains:   []*big.Int{l.chainID},
+		RequiresExtraData: []string{compoundExtraDataManager, compoundExtraDataAllowed},
+	}
+}
+
+// IsSupportedAction reports whether calldata can be generated for action
+func (l *CompoundOperation) IsSupportedAction(action ContractAction) bool {
+	return l.Capabilities().Supports(action)
+}
+
+// SetObserver implements Observable
+func (a *CompoundOperation) SetObserver(observer Observer) {
+	a.observer = observer
+	a.client = observeClient(a.client, a.GetName(), observer)
+}
+
+// SetAssetMetadata implements AssetMetadataUser
+func (a *CompoundOperation) SetAssetMetadata(metadata AssetMetadata) {
+	a.metadata = metadata
+}
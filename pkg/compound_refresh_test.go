@@ -0,0 +1,246 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// compoundMarket fakes the asset views of a Compound V3 market.
+// Multicall3 is only deployed when multicall is set
+type compoundMarket struct {
+	mu        sync.Mutex
+	abi       abi.ABI
+	assets    []common.Address
+	multicall bool
+	// supply caps and total supplies of the assets, assets without a cap are uncapped
+	supplyCaps  map[common.Address]*big.Int
+	totalSupply map[common.Address]*big.Int
+	// number of calls made to the node
+	calls int
+}
+
+func newCompoundMarket(t testing.TB, assets ...common.Address) *compoundMarket {
+	t.Helper()
+
+	parsedABI, err := abi.JSON(strings.NewReader(compoundv3ABI))
+	require.NoError(t, err)
+
+	return &compoundMarket{
+		abi:         parsedABI,
+		assets:      assets,
+		supplyCaps:  map[common.Address]*big.Int{},
+		totalSupply: map[common.Address]*big.Int{},
+	}
+}
+
+// client returns a mock client serving the market
+func (m *compoundMarket) client() *pkgtest.Client {
+	client := pkgtest.NewClient(EthChainID)
+	client.HandleFunc(m.call)
+	return client
+}
+
+func (m *compoundMarket) setAssets(assets ...common.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.assets = assets
+}
+
+func (m *compoundMarket) setSupplyCap(asset common.Address, supplyCap, totalSupply *big.Int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.supplyCaps[asset] = supplyCap
+	m.totalSupply[asset] = totalSupply
+}
+
+func (m *compoundMarket) assetInfo(method abi.Method, i uint8) ([]byte, error) {
+	supplyCap, ok := m.supplyCaps[m.assets[i]]
+	if !ok {
+		supplyCap = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	}
+
+	return method.Outputs.Pack(i, m.assets[i], common.Address{},
+		uint64(1e8), uint64(0), uint64(0), uint64(0), supplyCap)
+}
+
+func (m *compoundMarket) call(msg ethereum.CallMsg) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls++
+
+	if *msg.To != Multicall3Address {
+		return m.view(msg.Data)
+	}
+
+	// calls to an address without code return nothing
+	if !m.multicall {
+		return nil, nil
+	}
+
+	multicallABI, err := abi.JSON(strings.NewReader(multicall3ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	aggregate := multicallABI.Methods["aggregate3"]
+
+	args, err := aggregate.Inputs.Unpack(msg.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	calls := *abi.ConvertType(args[0], new([]multicallCall)).(*[]multicallCall)
+
+	results := make([]multicallResult, 0, len(calls))
+	for _, call := range calls {
+		data, err := m.view(call.CallData)
+		results = append(results, multicallResult{Success: err == nil, ReturnData: data})
+	}
+
+	return aggregate.Outputs.Pack(results)
+}
+
+func (m *compoundMarket) view(data []byte) ([]byte, error) {
+	numAssets := m.abi.Methods["numAssets"]
+	assetInfo := m.abi.Methods["getAssetInfo"]
+	assetInfoByAddress := m.abi.Methods["getAssetInfoByAddress"]
+	totalsCollateral := m.abi.Methods["totalsCollateral"]
+
+	switch {
+	case bytes.HasPrefix(data, numAssets.ID):
+		return numAssets.Outputs.Pack(uint8(len(m.assets)))
+
+	case bytes.HasPrefix(data, assetInfo.ID):
+		args, err := assetInfo.Inputs.Unpack(data[4:])
+		if err != nil {
+			return nil, err
+		}
+
+		return m.assetInfo(assetInfo, args[0].(uint8))
+
+	case bytes.HasPrefix(data, assetInfoByAddress.ID):
+		args, err := assetInfoByAddress.Inputs.Unpack(data[4:])
+		if err != nil {
+			return nil, err
+		}
+
+		for i, asset := range m.assets {
+			if asset == args[0].(common.Address) {
+				return m.assetInfo(assetInfoByAddress, uint8(i))
+			}
+		}
+
+		return nil, errors.New("bad asset")
+
+	case bytes.HasPrefix(data, totalsCollateral.ID):
+		args, err := totalsCollateral.Inputs.Unpack(data[4:])
+		if err != nil {
+			return nil, err
+		}
+
+		totalSupply, ok := m.totalSupply[args[0].(common.Address)]
+		if !ok {
+			totalSupply = big.NewInt(0)
+		}
+
+		return totalsCollateral.Outputs.Pack(totalSupply, big.NewInt(0))
+
+	default:
+		return nil, errors.New("unexpected call")
+	}
+}
+
+func TestCompound_RefreshSupportedAssets(t *testing.T) {
+
+	wbtc := common.HexToAddress("0x2260FAC5E5542a773Aa44fBCfeDf7C193bc2C599")
+	link := common.HexToAddress("0x514910771AF9Ca656af840dff83E8264EcF986CA")
+
+	t.Run("explicit refresh picks up new collateral", func(t *testing.T) {
+		market := newCompoundMarket(t, wbtc)
+
+		compoundImpl, err := NewCompoundOperation(market.client(),
+			EthChainID, common.HexToAddress(CompoundV3USDCPool))
+		require.NoError(t, err)
+
+		require.False(t, compoundImpl.IsSupportedAsset(context.Background(), EthChainID, link))
+
+		market.setAssets(wbtc, link)
+		require.NoError(t, compoundImpl.RefreshSupportedAssets(context.Background()))
+
+		require.True(t, compoundImpl.IsSupportedAsset(context.Background(), EthChainID, link))
+
+		assets, err := compoundImpl.GetSupportedAssets(context.Background(), EthChainID)
+		require.NoError(t, err)
+		require.Equal(t, []common.Address{wbtc, link}, assets)
+	})
+
+	t.Run("stale list is refreshed lazily", func(t *testing.T) {
+		market := newCompoundMarket(t, wbtc)
+
+		compoundImpl, err := NewCompoundOperation(market.client(),
+			EthChainID, common.HexToAddress(CompoundV3USDCPool), WithSupportedAssetsMaxAge(time.Millisecond))
+		require.NoError(t, err)
+
+		market.setAssets(wbtc, link)
+		time.Sleep(5 * time.Millisecond)
+
+		assets, err := compoundImpl.GetSupportedAssets(context.Background(), EthChainID)
+		require.NoError(t, err)
+		require.Equal(t, []common.Address{wbtc, link}, assets)
+	})
+
+	t.Run("list is not refreshed without a max age", func(t *testing.T) {
+		market := newCompoundMarket(t, wbtc)
+
+		compoundImpl, err := NewCompoundOperation(market.client(),
+			EthChainID, common.HexToAddress(CompoundV3USDCPool))
+		require.NoError(t, err)
+
+		market.setAssets(wbtc, link)
+
+		assets, err := compoundImpl.GetSupportedAssets(context.Background(), EthChainID)
+		require.NoError(t, err)
+		require.Equal(t, []common.Address{wbtc}, assets)
+	})
+
+	t.Run("returned list is a copy", func(t *testing.T) {
+		market := newCompoundMarket(t, wbtc)
+
+		compoundImpl, err := NewCompoundOperation(market.client(),
+			EthChainID, common.HexToAddress(CompoundV3USDCPool))
+		require.NoError(t, err)
+
+		assets, err := compoundImpl.GetSupportedAssets(context.Background(), EthChainID)
+		require.NoError(t, err)
+		assets[0] = link
+
+		require.False(t, compoundImpl.IsSupportedAsset(context.Background(), EthChainID, link))
+
+		assets, err = compoundImpl.GetSupportedAssets(context.Background(), EthChainID)
+		require.NoError(t, err)
+		require.Equal(t, []common.Address{wbtc}, assets)
+	})
+}
+
+func TestCompound_GetUniqueKey(t *testing.T) {
+
+	usdc, err := NewCompoundOperation(newCompoundMarket(t).client(), EthChainID, common.HexToAddress(CompoundV3USDCPool))
+	require.NoError(t, err)
+
+	require.Equal(t, "compound:1:"+common.HexToAddress(CompoundV3USDCPool).Hex(), usdc.GetUniqueKey())
+}
@@ -5,6 +5,7 @@ package pkg
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"strings"
 	"testing"
@@ -114,12 +115,12 @@ func TestCompound_GetSupportedAssets(t *testing.T) {
 	parsedABI, err := abi.JSON(strings.NewReader(compoundv3ABI))
 	require.NoError(t, err)
 
-	assets, err := getSupportedAssets(parsedABI, client, common.HexToAddress(CompoundV3ETHPool))
+	assets, err := getSupportedAssets(context.Background(), parsedABI, client, common.HexToAddress(CompoundV3ETHPool))
 	require.NoError(t, err)
 
 	require.NotEmpty(t, assets)
 
-	assets, err = getSupportedAssets(parsedABI, client, common.HexToAddress(CompoundV3USDCPool))
+	assets, err = getSupportedAssets(context.Background(), parsedABI, client, common.HexToAddress(CompoundV3USDCPool))
 	require.NoError(t, err)
 
 	require.NotEmpty(t, assets)
@@ -195,3 +196,26 @@ func TestCompoundV3_Validate_ETH_Market(t *testing.T) {
 		require.NoError(t, err)
 	}
 }
+
+func TestCompoundV3_Validate_SupplyCap(t *testing.T) {
+
+	compoundImpl, err := NewCompoundOperation(getTestClient(t, ChainETH), big.NewInt(1),
+		common.HexToAddress(CompoundV3ETHPool))
+	require.NoError(t, err)
+
+	wbtc := common.HexToAddress("0x2260FAC5E5542a773Aa44fBCfeDf7C193bc2C599")
+
+	// more WBTC than will ever exist
+	amount, _ := new(big.Int).SetString("100000000000000000000", 10)
+
+	err = compoundImpl.Validate(context.Background(), big.NewInt(1), LoanSupply, TransactionParams{
+		Amount: amount,
+		Asset:  wbtc,
+		Sender: hotWallet,
+	})
+	require.ErrorIs(t, err, ErrSupplyCapExceeded)
+
+	var capErr *SupplyCapError
+	require.True(t, errors.As(err, &capErr))
+	require.True(t, capErr.Remaining.Cmp(amount) < 0)
+}
@@ -0,0 +1,166 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompound_GenerateCalldata_WithdrawTo_Unit(t *testing.T) {
+
+	link := common.HexToAddress("0x514910771AF9Ca656af840dff83E8264EcF986CA")
+	receiver := common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+
+	compound, err := NewCompoundOperation(newCompoundMarket(t, link).client(), EthChainID,
+		common.HexToAddress(CompoundV3USDCPool))
+	require.NoError(t, err)
+
+	withdraw := func(recipient common.Address) string {
+		calldata, err := compound.GenerateCalldata(context.Background(), EthChainID, LoanWithdraw, TransactionParams{
+			Sender:    testAccount,
+			Recipient: recipient,
+			Asset:     link,
+			Amount:    big.NewInt(1e18),
+		})
+		require.NoError(t, err)
+
+		return calldata
+	}
+
+	// cast calldata "withdraw(address,uint256)" 0x514910771AF9Ca656af840dff83E8264EcF986CA 1000000000000000000
+	expected := "0xf3fef3a3" +
+		"000000000000000000000000514910771af9ca656af840dff83e8264ecf986ca" +
+		"0000000000000000000000000000000000000000000000000de0b6b3a7640000"
+
+	require.Equal(t, expected, withdraw(common.Address{}))
+	require.Equal(t, expected, withdraw(testAccount))
+
+	// cast calldata "withdrawTo(address,address,uint256)" 0x000000000000000000000000000000000000bEEF 0x514910771AF9Ca656af840dff83E8264EcF986CA 1000000000000000000
+	require.Equal(t, "0xc3b35a7e"+
+		"000000000000000000000000000000000000000000000000000000000000beef"+
+		"000000000000000000000000514910771af9ca656af840dff83e8264ecf986ca"+
+		"0000000000000000000000000000000000000000000000000de0b6b3a7640000", withdraw(receiver))
+}
+
+func TestCompound_GenerateCalldata_SupplyTo_Unit(t *testing.T) {
+
+	link := common.HexToAddress("0x514910771AF9Ca656af840dff83E8264EcF986CA")
+	receiver := common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+
+	compound, err := NewCompoundOperation(newCompoundMarket(t, link).client(), EthChainID,
+		common.HexToAddress(CompoundV3USDCPool))
+	require.NoError(t, err)
+
+	supply := func(recipient common.Address) string {
+		calldata, err := compound.GenerateCalldata(context.Background(), EthChainID, LoanSupply, TransactionParams{
+			Sender:    testAccount,
+			Recipient: recipient,
+			Asset:     link,
+			Amount:    big.NewInt(1e18),
+		})
+		require.NoError(t, err)
+
+		return calldata
+	}
+
+	// cast calldata "supply(address,uint256)" 0x514910771AF9Ca656af840dff83E8264EcF986CA 1000000000000000000
+	expected := "0xf2b9fdb8" +
+		"000000000000000000000000514910771af9ca656af840dff83e8264ecf986ca" +
+		"0000000000000000000000000000000000000000000000000de0b6b3a7640000"
+
+	require.Equal(t, expected, supply(common.Address{}))
+	require.Equal(t, expected, supply(testAccount))
+
+	// cast calldata "supplyTo(address,address,uint256)" 0x000000000000000000000000000000000000bEEF 0x514910771AF9Ca656af840dff83E8264EcF986CA 1000000000000000000
+	require.Equal(t, "0x4232cd63"+
+		"000000000000000000000000000000000000000000000000000000000000beef"+
+		"000000000000000000000000514910771af9ca656af840dff83e8264ecf986ca"+
+		"0000000000000000000000000000000000000000000000000de0b6b3a7640000", supply(receiver))
+}
+
+func TestCompound_AllowManager_Unit(t *testing.T) {
+
+	manager := common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+
+	compound, err := NewCompoundOperation(newCompoundMarket(t).client(), EthChainID,
+		common.HexToAddress(CompoundV3USDCPool))
+	require.NoError(t, err)
+
+	allow := func(extraData map[string]interface{}) TransactionParams {
+		return TransactionParams{Sender: testAccount, ExtraData: extraData}
+	}
+
+	t.Run("calldata", func(t *testing.T) {
+		tx, err := compound.BuildTransaction(context.Background(), EthChainID, LoanAllowManager,
+			allow(map[string]interface{}{"manager": manager.Hex(), "allowed": true}))
+		require.NoError(t, err)
+
+		// cast calldata "allow(address,bool)" 0x000000000000000000000000000000000000bEEF true
+		require.Equal(t, "0x110496e5"+
+			"000000000000000000000000000000000000000000000000000000000000beef"+
+			"0000000000000000000000000000000000000000000000000000000000000001", tx.Data)
+		require.Equal(t, common.HexToAddress(CompoundV3USDCPool), tx.To)
+		require.Zero(t, tx.Value.Sign())
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		err := compound.Validate(context.Background(), EthChainID, LoanAllowManager,
+			allow(map[string]interface{}{"manager": manager, "allowed": false}))
+		require.NoError(t, err)
+
+		for name, extraData := range map[string]map[string]interface{}{
+			"missing manager": {"allowed": true},
+			"invalid manager": {"manager": "0xbeef", "allowed": true},
+			"zero manager":    {"manager": common.Address{}, "allowed": true},
+			"missing allowed": {"manager": manager},
+			"invalid allowed": {"manager": manager, "allowed": "yes"},
+		} {
+			err := compound.Validate(context.Background(), EthChainID, LoanAllowManager, allow(extraData))
+			require.Error(t, err, name)
+		}
+	})
+}
+
+func TestCompound_Validate_SupplyCap_Unit(t *testing.T) {
+
+	link := common.HexToAddress("0x514910771AF9Ca656af840dff83E8264EcF986CA")
+
+	market := newCompoundMarket(t, link)
+
+	compound, err := NewCompoundOperation(market.client(), EthChainID,
+		common.HexToAddress(CompoundV3USDCPool))
+	require.NoError(t, err)
+
+	supply := func(amount int64) error {
+		return compound.Validate(context.Background(), EthChainID, LoanSupply, TransactionParams{
+			Sender: testAccount,
+			Asset:  link,
+			Amount: big.NewInt(amount),
+		})
+	}
+
+	require.NoError(t, supply(1e18))
+
+	market.setSupplyCap(link, big.NewInt(100), big.NewInt(90))
+
+	require.NoError(t, supply(10))
+
+	err = supply(11)
+	require.ErrorIs(t, err, ErrSupplyCapExceeded)
+
+	var capErr *SupplyCapError
+	require.True(t, errors.As(err, &capErr))
+	require.Equal(t, link, capErr.Asset)
+	require.Equal(t, int64(10), capErr.Remaining.Int64())
+
+	// at the cap nothing can be supplied anymore
+	market.setSupplyCap(link, big.NewInt(100), big.NewInt(100))
+
+	err = supply(1)
+	require.True(t, errors.As(err, &capErr))
+	require.Zero(t, capErr.Remaining.Sign())
+}
@@ -3,6 +3,7 @@ package pkg
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -14,15 +15,19 @@ const (
 )
 
 const (
-	EthChainStr     = "1"
-	BscChainStr     = "56"
-	PolygonChainStr = "137"
+	EthChainStr       = "1"
+	BscChainStr       = "56"
+	PolygonChainStr   = "137"
+	GnosisChainStr    = "100"
+	AvalancheChainStr = "43114"
 )
 
 var (
-	EthChainID     = big.NewInt(1)
-	BscChainID     = big.NewInt(56)
-	PolygonChainID = big.NewInt(137)
+	EthChainID       = big.NewInt(1)
+	BscChainID       = big.NewInt(56)
+	PolygonChainID   = big.NewInt(137)
+	GnosisChainID    = big.NewInt(100)
+	AvalancheChainID = big.NewInt(43114)
 )
 
 // Hex prefix
@@ -30,6 +35,25 @@ const HexPrefix = "0x"
 
 var ErrChainUnsupported = errors.New("chain not supported")
 
+// ErrAmountRequired is returned when an action needs params.Amount and it is nil
+var ErrAmountRequired = errors.New("amount is required")
+
+var (
+	// ErrAssetNotSupported is returned when the protocol does not handle the asset
+	ErrAssetNotSupported = errors.New("asset not supported")
+	// ErrInsufficientBalance is returned when the sender can not cover the amount
+	ErrInsufficientBalance = errors.New("balance not enough")
+	// ErrUnsupportedAction is returned when the protocol can not perform the action
+	ErrUnsupportedAction = errors.New("action not supported")
+	// ErrAmountTooLow is returned when the amount is below what the protocol accepts
+	ErrAmountTooLow = errors.New("amount too low")
+	// ErrProtocolAlreadyRegistered is returned when RegisterProtocol is called for a
+	// taken address or unique key
+	ErrProtocolAlreadyRegistered = errors.New("protocol already registered")
+	// ErrProtocolDeprecated is returned by the protocols disabled with SetProtocolEnabled
+	ErrProtocolDeprecated = errors.New("protocol deprecated")
+)
+
 type (
 	ProtocolName    = string
 	ProtocolMethod  = string
@@ -52,32 +76,81 @@ type Protocol interface {
 	GetType() ProtocolType
 	GetName() string
 	GetVersion() string
+	// GetUniqueKey returns the name of the protocol qualified with its chain,
+	// e.g aave_v3:1, so deployments of the same protocol can be told apart
+	GetUniqueKey() string
 	GetContractAddress(chainID *big.Int) common.Address
+	// GetContractAddressForAction returns the contract the calldata generated for
+	// action is sent to when it differs between actions, e.g Rocketpool unstaking
+	// on the rETH token. GetContractAddress stays the primary contract
+	GetContractAddressForAction(chainID *big.Int, action ContractAction) (common.Address, error)
+	// CallValue returns the native amount that must be sent as msg.value
+	// along with the calldata generated for the action
+	CallValue(action ContractAction, params TransactionParams) *big.Int
+	// BuildTransaction generates the calldata for the action alongside the
+	// contract it must be sent to and the value to attach
+	BuildTransaction(ctx context.Context, chainID *big.Int, action ContractAction, params TransactionParams) (*Transaction, error)
+	// Capabilities describes the actions, chains and extra data the protocol supports
+	Capabilities() ProtocolCapabilities
+	// IsSupportedAction reports whether the protocol can generate calldata for the action
+	IsSupportedAction(action ContractAction) bool
 }
 
 const (
-	AaveV3        ProtocolName = "aave_v3"
-	SparkLend     ProtocolName = "spark_lend"
-	Lido          ProtocolName = "lido"
-	RocketPool    ProtocolName = "rocket_pool"
-	Ankr          ProtocolName = "ankr"
-	Renzo         ProtocolName = "renzo"
-	Compound      ProtocolName = "compound"
-	ListaDao      ProtocolName = "lista_dao"
-	AvalonFinance ProtocolName = "avalon_finance"
+	AaveV3          ProtocolName = "aave_v3"
+	SparkLend       ProtocolName = "spark_lend"
+	Lido            ProtocolName = "lido"
+	RocketPool      ProtocolName = "rocket_pool"
+	Ankr            ProtocolName = "ankr"
+	Renzo           ProtocolName = "renzo"
+	Compound        ProtocolName = "compound"
+	ListaDao        ProtocolName = "lista_dao"
+	ListaDaoLending ProtocolName = "lista_dao_lending"
+	AvalonFinance   ProtocolName = "avalon_finance"
+	Venus           ProtocolName = "venus"
+	Benqi           ProtocolName = "benqi"
+	Pendle          ProtocolName = "pendle"
+	EigenLayer      ProtocolName = "eigenlayer"
+	Stargate        ProtocolName = "stargate"
+	Yearn           ProtocolName = "yearn_v3"
+	ERC4626         ProtocolName = "erc4626"
+	SavingsDAI      ProtocolName = "savings_dai"
+	SparkSavings    ProtocolName = "spark_savings"
+	Radiant         ProtocolName = "radiant"
 )
 
 var (
-	AaveEthereumV3ContractAddress ContractAddress = common.HexToAddress("0x87870bca3f3fd6335c3f4ce8392d69350b4fa4e2")
-	AaveBnbV3ContractAddress      ContractAddress = common.HexToAddress("0x6807dc923806fE8Fd134338EABCA509979a7e0cB")
-	AavePolygonV3ContractAddress  ContractAddress = common.HexToAddress("0x794a61358D6845594F94dc1DB02A252b5b4814aD")
-	SparkLendContractAddress      ContractAddress = common.HexToAddress("0xC13e21B648A5Ee794902342038FF3aDAB66BE987")
-	LidoContractAddress           ContractAddress = common.HexToAddress("0xae7ab96520de3a18e5e111b5eaab095312d7fe84")
-	RocketPoolStorageAddress      ContractAddress = common.HexToAddress("0x1d8f8f00cfa6758d7bE78336684788Fb0ee0Fa46")
-	AnkrContractAddress           ContractAddress = common.HexToAddress("0x84db6ee82b7cf3b47e8f19270abde5718b936670")
-	RenzoManagerAddress           ContractAddress = common.HexToAddress("0x74a09653A083691711cF8215a6ab074BB4e99ef5")
-	AvalonFinanceContractAddress  ContractAddress = common.HexToAddress("0xf9278C7c4AEfAC4dDfd0D496f7a1C39cA6BCA6d4")
-	ListaDaoContractAddress       ContractAddress = common.HexToAddress("0x1adB950d8bB3dA4bE104211D5AB038628e477fE6")
+	AaveEthereumV3ContractAddress        ContractAddress = common.HexToAddress("0x87870bca3f3fd6335c3f4ce8392d69350b4fa4e2")
+	AaveBnbV3ContractAddress             ContractAddress = common.HexToAddress("0x6807dc923806fE8Fd134338EABCA509979a7e0cB")
+	AavePolygonV3ContractAddress         ContractAddress = common.HexToAddress("0x794a61358D6845594F94dc1DB02A252b5b4814aD")
+	AaveGnosisV3ContractAddress          ContractAddress = common.HexToAddress("0xb50201558B00496A145fE76f7424749556E326D8")
+	AaveAvalancheV3ContractAddress       ContractAddress = common.HexToAddress("0x794a61358D6845594F94dc1DB02A252b5b4814aD")
+	SparkLendContractAddress             ContractAddress = common.HexToAddress("0xC13e21B648A5Ee794902342038FF3aDAB66BE987")
+	LidoContractAddress                  ContractAddress = common.HexToAddress("0xae7ab96520de3a18e5e111b5eaab095312d7fe84")
+	LidoPolygonContractAddress           ContractAddress = common.HexToAddress("0xfd225C9e6601C9d38d8F98d8731BF59eFcF8C0E3")
+	RocketPoolStorageAddress             ContractAddress = common.HexToAddress("0x1d8f8f00cfa6758d7bE78336684788Fb0ee0Fa46")
+	AnkrContractAddress                  ContractAddress = common.HexToAddress("0x84db6ee82b7cf3b47e8f19270abde5718b936670")
+	AnkrPolygonContractAddress           ContractAddress = common.HexToAddress("0x62A509BA95c75Cabc7190469025E5aBeE4eDdb2a")
+	AnkrBnbContractAddress               ContractAddress = common.HexToAddress("0x9e347Af362059bf2E55839002c699F7A5BaFE86E")
+	RenzoManagerAddress                  ContractAddress = common.HexToAddress("0x74a09653A083691711cF8215a6ab074BB4e99ef5")
+	AvalonFinanceContractAddress         ContractAddress = common.HexToAddress("0xf9278C7c4AEfAC4dDfd0D496f7a1C39cA6BCA6d4")
+	RadiantBnbContractAddress            ContractAddress = common.HexToAddress("0xd50Cf00b6e600Dd036Ba8eF475677d816d6c4281")
+	ListaDaoContractAddress              ContractAddress = common.HexToAddress("0x1adB950d8bB3dA4bE104211D5AB038628e477fE6")
+	ListaDaoInteractionContractAddress   ContractAddress = common.HexToAddress("0xB68443Ee3e828baD1526b3e0Bdf2Dfc6b1975ec4")
+	VenusVBNBContractAddress             ContractAddress = common.HexToAddress("0xA07c5b74C9B40447a954e1466938b865b6BBea36")
+	BenqiSAVAXContractAddress            ContractAddress = common.HexToAddress("0x2b2C81e08f1Af8835a78Bb2A90AE924ACE0eA4bE")
+	PendleRouterContractAddress          ContractAddress = common.HexToAddress("0x888888888889758F76e7103c6CbF23ABbF58F946")
+	EigenLayerStrategyManagerAddress     ContractAddress = common.HexToAddress("0x858646372CC42E1A627fcE94aa7A7033e7CF075A")
+	StargateUSDCEthContractAddress       ContractAddress = common.HexToAddress("0xc026395860Db2d07ee33e05fE50ed7bD583189C7")
+	StargateUSDCBnbContractAddress       ContractAddress = common.HexToAddress("0x962Bd449E630b0d928f308Ce63f1A21F02576057")
+	StargateUSDCPolygonContractAddress   ContractAddress = common.HexToAddress("0x9Aa02D4Fae7F58b8E8f34c66E756cC734DAc7fe4")
+	StargateUSDCAvalancheContractAddress ContractAddress = common.HexToAddress("0x5634c4a5FEd09819E3c46D86A965Dd9447d86e47")
+	YearnV3USDCVaultAddress              ContractAddress = common.HexToAddress("0xBe53A109B494E5c9f97b9Cd39Fe969BE68BF6204")
+	YearnV3DAIVaultAddress               ContractAddress = common.HexToAddress("0x028eC7330ff87667b6dfb0D94b954c820195336c")
+	SavingsDAIContractAddress            ContractAddress = common.HexToAddress("0x83F20F44975D03b1B09e64809B757c47f942BEeA")
+	SavingsDAIGnosisContractAddress      ContractAddress = common.HexToAddress("0xaf204776c7245bF4147c2612BF6e5972Ee483701")
+	SavingsUSDSContractAddress           ContractAddress = common.HexToAddress("0xa3931d71877C0E7a3148CB7Eb4463524FEc27fbD")
+	AaveOracleEthereumAddress            ContractAddress = common.HexToAddress("0x54586bE62E3c3580375aE3723C145253060Ca0C2")
 )
 
 const (
@@ -96,6 +169,13 @@ type ProtocolConfig struct {
 	Type     ProtocolType
 }
 
+// Transaction is a ready to send call generated by a protocol
+type Transaction struct {
+	To    common.Address
+	Data  string
+	Value *big.Int
+}
+
 // TransactionParams encapsulates parameters needed to generate calldata for transactions.
 type TransactionParams struct {
 	Amount       *big.Int
@@ -114,6 +194,79 @@ func (params TransactionParams) GetBeneficiaryOwner() common.Address {
 	return params.Recipient
 }
 
+// ValidateAmount checks the amount is set, greater than zero and fits in an uint256
+func (params TransactionParams) ValidateAmount() error {
+	if params.Amount == nil {
+		return ErrAmountRequired
+	}
+
+	if params.Amount.Sign() <= 0 {
+		return fmt.Errorf("%w: amount must be greater than zero", ErrAmountTooLow)
+	}
+
+	return params.checkAmountRange()
+}
+
+// checkAmountRange checks the amount, when set, can be packed as an uint256.
+// Packing does not fail on amounts out of range but wraps them, 2^256+5
+// would be encoded as 5. Actions sending no amount leave it nil or zero
+func (params TransactionParams) checkAmountRange() error {
+	if params.Amount == nil {
+		return nil
+	}
+
+	if params.Amount.Sign() < 0 {
+		return fmt.Errorf("%w: amount must not be negative", ErrAmountTooLow)
+	}
+
+	if params.Amount.Cmp(abi.MaxUint256) > 0 {
+		return errors.New("amount exceeds the maximum uint256 value")
+	}
+
+	return nil
+}
+
+// ValidationMode selects which checks Validate runs
+type ValidationMode string
+
+const (
+	// ValidationStrict also checks the sender holds enough to cover the amount
+	ValidationStrict ValidationMode = "strict"
+	// ValidationLenient skips the balance checks but still validates the asset,
+	// the action and the amount. It suits multi step flows where the funds only
+	// reach the sender in an earlier step, e.g swapping USDT to BNB before staking
+	ValidationLenient ValidationMode = "lenient"
+)
+
+// extraDataValidationMode is the ExtraData key selecting the ValidationMode
+const extraDataValidationMode = "validation_mode"
+
+// lenientValidation reports whether Validate should skip the balance checks.
+// defaultMode applies when ExtraData["validation_mode"] is not set, any
+// value other than lenient is treated as strict
+func (params TransactionParams) lenientValidation(defaultMode ValidationMode) bool {
+
+	mode := defaultMode
+
+	switch v := params.ExtraData[extraDataValidationMode].(type) {
+	case ValidationMode:
+		mode = v
+	case string:
+		mode = ValidationMode(v)
+	}
+
+	return mode == ValidationLenient
+}
+
+// nativeCallValue returns a copy of the amount to attach to payable calls
+func (params TransactionParams) nativeCallValue() *big.Int {
+	if params.Amount == nil {
+		return big.NewInt(0)
+	}
+
+	return new(big.Int).Set(params.Amount)
+}
+
 const (
 	LoanSupply ContractAction = iota
 	LoanWithdraw
@@ -123,6 +276,21 @@ const (
 	ERC20UnStake
 	LoanBorrow
 	LoanRepay
+	// LoanSetEMode switches the efficiency mode category of the sender
+	LoanSetEMode
+	// LoanSetCollateral enables or disables a supplied asset as collateral
+	LoanSetCollateral
+	// LoanFlashLoan borrows an asset that must be repaid within the same transaction
+	LoanFlashLoan
+	// BridgeSend moves an asset to another chain
+	BridgeSend
+	// LoanDelegateCredit allows another account to borrow against the collateral of the sender
+	LoanDelegateCredit
+	// LoanAllowManager allows or disallows a manager to act on the positions of the sender
+	LoanAllowManager
+	// ERC20Approve allows a protocol to pull an asset of the sender. No protocol
+	// supports it, GenerateBatch generates it for the steps needing an allowance
+	ERC20Approve
 )
 
 func (a ContractAction) String() string {
@@ -135,14 +303,58 @@ func (a ContractAction) String() string {
 		return "native_stake"
 	case NativeUnStake:
 		return "native_unstake"
+	case ERC20Stake:
+		return "erc20_stake"
+	case ERC20UnStake:
+		return "erc20_unstake"
+	case LoanBorrow:
+		return "loan_borrow"
+	case LoanRepay:
+		return "loan_repay"
+	case LoanSetEMode:
+		return "loan_set_emode"
+	case LoanSetCollateral:
+		return "loan_set_collateral"
+	case LoanFlashLoan:
+		return "loan_flash_loan"
+	case BridgeSend:
+		return "bridge_send"
+	case LoanDelegateCredit:
+		return "loan_delegate_credit"
+	case LoanAllowManager:
+		return "loan_allow_manager"
+	case ERC20Approve:
+		return "erc20_approve"
 	default:
 		return ""
 	}
 }
 
+// ParseContractAction returns the action matching the name produced by String
+func ParseContractAction(name string) (ContractAction, error) {
+	for _, action := range []ContractAction{
+		LoanSupply, LoanWithdraw, NativeStake, NativeUnStake,
+		ERC20Stake, ERC20UnStake, LoanBorrow, LoanRepay,
+		LoanSetEMode, LoanSetCollateral, LoanFlashLoan, BridgeSend,
+		LoanDelegateCredit, LoanAllowManager, ERC20Approve,
+	} {
+		if action.String() == name {
+			return action, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown action %q", name)
+}
+
 const (
 	TypeLoan  ProtocolType = "Loan"
 	TypeStake ProtocolType = "Stake"
+	// TypeBridge moves assets between chains
+	TypeBridge ProtocolType = "Bridge"
+	// TypeVault deposits assets into yield bearing vaults
+	TypeVault ProtocolType = "Vault"
+	// TypeSwap exchanges one asset for another
+	TypeSwap ProtocolType = "Swap"
 )
 
 // ProtocolRegistry defines methods for managing and accessing DeFi
@@ -150,8 +362,12 @@ type ProtocolRegistry interface {
 	// GetChainConfig retrieves the configuration for a specific chain
 	GetChainConfig(chainID *big.Int) (ChainConfig, error)
 
-	// RegisterProtocol adds a new protocol to the registry for a specific chain
-	RegisterProtocol(chainID *big.Int, address common.Address, protocol Protocol) error
+	// ListChains returns the ids of the configured chains in ascending order
+	ListChains() []*big.Int
+
+	// RegisterProtocol adds a new protocol to the registry for a specific chain,
+	// enabled unless registered WithEnabled(false)
+	RegisterProtocol(chainID *big.Int, address common.Address, protocol Protocol, opts ...RegisterOption) error
 
 	// GetProtocol retrieves a protocol by its contract address and chain ID
 	GetProtocol(chainID *big.Int, address common.Address) (Protocol, error)
@@ -161,6 +377,18 @@ type ProtocolRegistry interface {
 
 	// ListProtocolsByType lists all protocols of a specific type for a given chain
 	ListProtocolsByType(chainID *big.Int, protocolType ProtocolType) []Protocol
+
+	// GetProtocolByKey retrieves a protocol by the key returned by its GetUniqueKey
+	GetProtocolByKey(key string) (Protocol, error)
+
+	// SetProtocolEnabled disables a registered protocol without unregistering it,
+	// or enables it back
+	SetProtocolEnabled(chainID *big.Int, address common.Address, enabled bool) error
+}
+
+// protocolKey qualifies a protocol name with the chain it is deployed on
+func protocolKey(name ProtocolName, chainID *big.Int) string {
+	return name + ":" + chainID.String()
 }
 
 // IsBnb checks if the provided chain matches the BSC chain id
@@ -171,3 +399,10 @@ func IsEth(chainID *big.Int) bool { return chainID.Cmp(EthChainID) == 0 }
 
 // IsPolygon checks if the provided chain maches polygon id
 func IsPolygon(chainID *big.Int) bool { return chainID.Cmp(PolygonChainID) == 0 }
+
+// IsGnosis checks if the provided chain matches the gnosis chain id.
+// The native token of the chain is xDai
+func IsGnosis(chainID *big.Int) bool { return chainID.Cmp(GnosisChainID) == 0 }
+
+// IsAvalanche checks if the provided chain matches the avalanche C-Chain id
+func IsAvalanche(chainID *big.Int) bool { return chainID.Cmp(AvalancheChainID) == 0 }
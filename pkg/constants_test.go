@@ -0,0 +1,54 @@
+package pkg
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseContractAction(t *testing.T) {
+
+	for _, action := range []ContractAction{
+		LoanSupply, LoanWithdraw, NativeStake, NativeUnStake,
+		ERC20Stake, ERC20UnStake, LoanBorrow, LoanRepay,
+		LoanSetEMode, LoanSetCollateral, LoanFlashLoan, BridgeSend,
+		LoanDelegateCredit, LoanAllowManager, ERC20Approve,
+	} {
+		parsed, err := ParseContractAction(action.String())
+		require.NoError(t, err)
+		require.Equal(t, action, parsed)
+	}
+
+	_, err := ParseContractAction("unknown")
+	require.Error(t, err)
+}
+
+func TestTransactionParams_ValidateAmount(t *testing.T) {
+
+	tt := []struct {
+		name     string
+		amount   *big.Int
+		hasError bool
+	}{
+		{name: "nil amount", amount: nil, hasError: true},
+		{name: "zero amount", amount: big.NewInt(0), hasError: true},
+		{name: "negative amount", amount: big.NewInt(-1), hasError: true},
+		{name: "positive amount", amount: big.NewInt(1)},
+		{name: "max uint256", amount: abi.MaxUint256},
+		{name: "overflowing uint256", amount: new(big.Int).Add(abi.MaxUint256, big.NewInt(1)), hasError: true},
+	}
+
+	for _, v := range tt {
+		t.Run(v.name, func(t *testing.T) {
+			err := TransactionParams{Amount: v.amount}.ValidateAmount()
+			if v.hasError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
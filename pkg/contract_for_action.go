@@ -0,0 +1,17 @@
+package pkg
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// contractAddressForAction is the GetContractAddressForAction of the protocols
+// sending every supported action to GetContractAddress
+func contractAddressForAction(protocol Protocol, chainID *big.Int, action ContractAction) (common.Address, error) {
+	if !protocol.IsSupportedAction(action) {
+		return common.Address{}, ErrUnsupportedAction
+	}
+
+	return protocol.GetContractAddress(chainID), nil
+}
@@ -0,0 +1,50 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultActions maps the intents of every protocol type to their action
+var defaultActions = map[ProtocolType]map[string]ContractAction{
+	TypeStake: {
+		"stake":   NativeStake,
+		"unstake": NativeUnStake,
+	},
+	TypeLoan: {
+		"supply":   LoanSupply,
+		"lend":     LoanSupply,
+		"withdraw": LoanWithdraw,
+		"borrow":   LoanBorrow,
+		"repay":    LoanRepay,
+	},
+	TypeVault: {
+		"deposit":  ERC20Stake,
+		"stake":    ERC20Stake,
+		"withdraw": ERC20UnStake,
+		"unstake":  ERC20UnStake,
+	},
+	TypeBridge: {
+		"bridge": BridgeSend,
+		"send":   BridgeSend,
+	},
+}
+
+// DefaultAction returns the action protocols of protocolType usually perform
+// for intent, e.g stake is NativeStake for TypeStake and supply is LoanSupply
+// for TypeLoan. Intents are case insensitive. Protocols staking an ERC20, such
+// as EigenLayer, still need ERC20Stake to be set explicitly
+func DefaultAction(protocolType ProtocolType, intent string) (ContractAction, error) {
+
+	actions, ok := defaultActions[protocolType]
+	if !ok {
+		return 0, fmt.Errorf("no default actions for protocol type %q", protocolType)
+	}
+
+	action, ok := actions[strings.ToLower(strings.TrimSpace(intent))]
+	if !ok {
+		return 0, fmt.Errorf("%w: no %s action for intent %q", ErrUnsupportedAction, protocolType, intent)
+	}
+
+	return action, nil
+}
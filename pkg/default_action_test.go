@@ -0,0 +1,44 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultAction(t *testing.T) {
+
+	tt := []struct {
+		protocolType ProtocolType
+		intent       string
+		action       ContractAction
+	}{
+		{protocolType: TypeStake, intent: "stake", action: NativeStake},
+		{protocolType: TypeStake, intent: "unstake", action: NativeUnStake},
+		{protocolType: TypeLoan, intent: "supply", action: LoanSupply},
+		{protocolType: TypeLoan, intent: "lend", action: LoanSupply},
+		{protocolType: TypeLoan, intent: "withdraw", action: LoanWithdraw},
+		{protocolType: TypeLoan, intent: "borrow", action: LoanBorrow},
+		{protocolType: TypeLoan, intent: "repay", action: LoanRepay},
+		{protocolType: TypeVault, intent: "deposit", action: ERC20Stake},
+		{protocolType: TypeVault, intent: "withdraw", action: ERC20UnStake},
+		{protocolType: TypeBridge, intent: "bridge", action: BridgeSend},
+		{protocolType: TypeStake, intent: " Stake ", action: NativeStake},
+	}
+
+	for _, v := range tt {
+		action, err := DefaultAction(v.protocolType, v.intent)
+		require.NoError(t, err, "%s %s", v.protocolType, v.intent)
+		require.Equal(t, v.action, action, "%s %s", v.protocolType, v.intent)
+	}
+
+	// intents of another type
+	_, err := DefaultAction(TypeStake, "supply")
+	require.ErrorIs(t, err, ErrUnsupportedAction)
+
+	_, err = DefaultAction(TypeLoan, "stake")
+	require.ErrorIs(t, err, ErrUnsupportedAction)
+
+	_, err = DefaultAction(TypeSwap, "swap")
+	require.Error(t, err)
+}
@@ -0,0 +1,15 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+)
+
+// DepositLimiter is implemented by staking protocols that bound how much
+// can be deposited at once. It lets callers size a deposit before
+// generating the calldata
+type DepositLimiter interface {
+	// GetDepositLimits returns the minimum and maximum amount accepted by a
+	// deposit. A nil max means deposits are not capped
+	GetDepositLimits(ctx context.Context, chainID *big.Int) (min, max *big.Int, err error)
+}
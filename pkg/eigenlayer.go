@@ -0,0 +1,478 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const eigenLayerStrategyManagerABI = `
+[
+  {
+    "inputs": [
+      { "internalType": "address", "name": "strategy", "type": "address" },
+      { "internalType": "address", "name": "token", "type": "address" },
+      { "internalType": "uint256", "name": "amount", "type": "uint256" }
+    ],
+    "name": "depositIntoStrategy",
+    "outputs": [{ "internalType": "uint256", "name": "shares", "type": "uint256" }],
+    "stateMutability": "nonpayable",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "address", "name": "staker", "type": "address" },
+      { "internalType": "address", "name": "strategy", "type": "address" }
+    ],
+    "name": "stakerStrategyShares",
+    "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }],
+    "stateMutability": "view",
+    "type": "function"
+  }
+]`
+
+const eigenLayerStrategyABI = `
+[
+  {
+    "inputs": [],
+    "name": "underlyingToken",
+    "outputs": [{ "internalType": "address", "name": "", "type": "address" }],
+    "stateMutability": "view",
+    "type": "function"
+  }
+]`
+
+const (
+	eigenLayerExtraDataStrategy = "strategy"
+	eigenLayerExtraDataToken    = "token"
+)
+
+// eigenLayerStrategies maps the LST strategies to their underlying token
+var eigenLayerStrategies = map[common.Address]common.Address{
+	// stETH
+	common.HexToAddress("0x93c4b944D05dfe6df7645A86cd2206016c51564D"): common.HexToAddress("0xae7ab96520DE3A18E5e111B5EaAb095312D7fE84"),
+	// rETH
+	common.HexToAddress("0x1BeE69b7dFFfA4E2d53C2a2Df135C388AD25dCD2"): common.HexToAddress("0xae78736Cd615f374D3085123A210448E74Fc6393"),
+	// cbETH
+	common.HexToAddress("0x54945180dB7943c0ed0FEE7EdaB2Bd24620256bc"): common.HexToAddress("0xBe9895146f7AF43049ca1c1AE358B0541Ea49704"),
+}
+
+// EigenLayerOperation restakes LSTs into EigenLayer strategies through the
+// StrategyManager. The strategy is selected with ExtraData["strategy"] and
+// the token with ExtraData["token"], params.Asset being used when it is omitted
+// https://eigenlayer.xyz
+type EigenLayerOperation struct {
+	contract    common.Address
+	parsedABI   abi.ABI
+	strategyABI abi.ABI
+	erc20ABI    abi.ABI
+	chainID     *big.Int
+	client      EthClient
+	observer    Observer
+	metadata    AssetMetadata
+
+	// verified on first use, see networkCheck
+	network networkCheck
+
+	// disabled through the registry, see SetProtocolEnabled
+	deprecation
+}
+
+var _ Protocol = (*EigenLayerOperation)(nil)
+
+func NewEigenLayerOperation(client EthClient, chainID *big.Int, opts ...NetworkOption) (*EigenLayerOperation, error) {
+
+	if !IsEth(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(eigenLayerStrategyManagerABI))
+	if err != nil {
+		return nil, err
+	}
+
+	strategyABI, err := abi.JSON(strings.NewReader(eigenLayerStrategyABI))
+	if err != nil {
+		return nil, err
+	}
+
+	erc20ABI, err := abi.JSON(strings.NewReader(erc20BalanceOfABI))
+	if err != nil {
+		return nil, err
+	}
+
+	e := &EigenLayerOperation{
+		parsedABI:   parsedABI,
+		strategyABI: strategyABI,
+		erc20ABI:    erc20ABI,
+		chainID:     chainID,
+		client:      client,
+		contract:    EigenLayerStrategyManagerAddress,
+	}
+
+	for _, opt := range opts {
+		opt(&e.network)
+	}
+
+	return e, nil
+}
+
+// GenerateCalldata creates the necessary blockchain transaction data
+func (e *EigenLayerOperation) GenerateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+	if err := e.deprecation.check(e.GetUniqueKey()); err != nil {
+		return "", err
+	}
+
+	if err := params.checkAmountRange(); err != nil {
+		return "", err
+	}
+
+	return observeCalldata(e.observer, e.GetName(), action, func() (string, error) {
+		return e.generateCalldata(ctx, chainID, action, params)
+	})
+}
+
+func (e *EigenLayerOperation) generateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+
+	if !e.isSupportedChain(chainID) {
+		return "", ErrChainUnsupported
+	}
+
+	if err := e.network.ensure(ctx, e.client, e.chainID); err != nil {
+		return "", err
+	}
+
+	if action != ERC20Stake {
+		return "", ErrUnsupportedAction
+	}
+
+	if params.Amount == nil {
+		return "", ErrAmountRequired
+	}
+
+	strategy, token, err := e.strategyAndToken(params)
+	if err != nil {
+		return "", err
+	}
+
+	calldata, err := e.parsedABI.Pack("depositIntoStrategy", strategy, token, params.Amount)
+	if err != nil {
+		return "", err
+	}
+
+	return HexPrefix + hex.EncodeToString(calldata), nil
+}
+
+// EstimateGas estimates the gas needed to execute the generated calldata
+func (e *EigenLayerOperation) EstimateGas(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (uint64, error) {
+
+	tx, err := e.BuildTransaction(ctx, chainID, action, params)
+	if err != nil {
+		return 0, err
+	}
+
+	return estimateGas(ctx, e.client, params.Sender, tx)
+}
+
+// BuildTransaction generates the calldata for the action together with
+// the contract to call and the value to send
+func (e *EigenLayerOperation) BuildTransaction(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (*Transaction, error) {
+
+	calldata, err := e.GenerateCalldata(ctx, chainID, action, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{
+		To:    e.GetContractAddress(chainID),
+		Data:  calldata,
+		Value: e.CallValue(action, params),
+	}, nil
+}
+
+// Validate checks if the provided parameters are valid for the specified action.
+// The token must be the underlying token of the strategy
+func (e *EigenLayerOperation) Validate(ctx context.Context,
+	chainID *big.Int, action ContractAction, params TransactionParams) error {
+
+	if err := e.deprecation.check(e.GetUniqueKey()); err != nil {
+		return err
+	}
+
+	if !e.isSupportedChain(chainID) {
+		return ErrChainUnsupported
+	}
+
+	if err := e.network.ensure(ctx, e.client, e.chainID); err != nil {
+		return err
+	}
+
+	if action != ERC20Stake {
+		return ErrUnsupportedAction
+	}
+
+	strategy, token, err := e.strategyAndToken(params)
+	if err != nil {
+		return err
+	}
+
+	if err := params.ValidateAmount(); err != nil {
+		return err
+	}
+
+	if err := checkAmountPrecision(ctx, e.client, e.metadata, e.chainID, params, token); err != nil {
+		return err
+	}
+
+	underlying, err := e.underlyingToken(ctx, strategy)
+	if err != nil {
+		return err
+	}
+
+	if underlying != token {
+		return fmt.Errorf("token %s is not the underlying token of strategy %s", token, strategy)
+	}
+
+	if err := checkAllowance(ctx, e.client, params, token, e.contract, params.Amount); err != nil {
+		return err
+	}
+
+	if params.lenientValidation(ValidationStrict) {
+		return nil
+	}
+
+	balance, err := e.balanceOf(ctx, token, params.Sender)
+	if err != nil {
+		return err
+	}
+
+	if balance.Cmp(params.Amount) < 0 {
+		return ErrInsufficientBalance
+	}
+
+	return nil
+}
+
+// GetBalance retrieves the shares account holds in the strategy of the
+// underlying token asset
+func (e *EigenLayerOperation) GetBalance(ctx context.Context, chainID *big.Int,
+	account, asset common.Address) (common.Address, *big.Int, error) {
+
+	if !e.isSupportedChain(chainID) {
+		return common.Address{}, nil, ErrChainUnsupported
+	}
+
+	strategy, ok := eigenLayerStrategyOf(asset)
+	if !ok {
+		return common.Address{}, nil, fmt.Errorf("%w %s", ErrAssetNotSupported, asset)
+	}
+
+	calldata, err := e.parsedABI.Pack("stakerStrategyShares", account, strategy)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	result, err := e.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &e.contract,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	shares := new(big.Int)
+	err = e.parsedABI.UnpackIntoInterface(&shares, "stakerStrategyShares", result)
+	return asset, shares, err
+}
+
+// eigenLayerStrategyOf returns the known strategy of the underlying token
+func eigenLayerStrategyOf(token common.Address) (common.Address, bool) {
+	for strategy, underlying := range eigenLayerStrategies {
+		if underlying == token {
+			return strategy, true
+		}
+	}
+
+	return common.Address{}, false
+}
+
+// GetSupportedAssets returns the underlying tokens of the known strategies,
+// sorted by address as the strategies are kept in a map
+func (e *EigenLayerOperation) GetSupportedAssets(ctx context.Context, chainID *big.Int) ([]common.Address, error) {
+	if !e.isSupportedChain(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	assets := make([]common.Address, 0, len(eigenLayerStrategies))
+	for _, token := range eigenLayerStrategies {
+		assets = append(assets, token)
+	}
+
+	sort.Slice(assets, func(i, j int) bool { return assets[i].Hex() < assets[j].Hex() })
+
+	return assets, nil
+}
+
+func (e *EigenLayerOperation) isSupportedChain(chain *big.Int) bool {
+	return e.chainID.Cmp(chain) == 0
+}
+
+// IsSupportedAsset checks if the specified asset is supported on the given chain
+func (e *EigenLayerOperation) IsSupportedAsset(ctx context.Context, chainID *big.Int, asset common.Address) bool {
+	if !e.isSupportedChain(chainID) {
+		return false
+	}
+
+	for _, token := range eigenLayerStrategies {
+		if token == asset {
+			return true
+		}
+	}
+
+	return false
+}
+
+// strategyAndToken returns the strategy and token selected by params
+func (e *EigenLayerOperation) strategyAndToken(params TransactionParams) (common.Address, common.Address, error) {
+
+	value, ok := params.ExtraData[eigenLayerExtraDataStrategy]
+	if !ok {
+		return common.Address{}, common.Address{}, errors.New("strategy must be provided in extra data")
+	}
+
+	strategy, err := toAddress(value)
+	if err != nil {
+		return common.Address{}, common.Address{}, fmt.Errorf("invalid strategy: %w", err)
+	}
+
+	value, ok = params.ExtraData[eigenLayerExtraDataToken]
+	if !ok {
+		return strategy, params.Asset, nil
+	}
+
+	token, err := toAddress(value)
+	if err != nil {
+		return common.Address{}, common.Address{}, fmt.Errorf("invalid token: %w", err)
+	}
+
+	return strategy, token, nil
+}
+
+func (e *EigenLayerOperation) underlyingToken(ctx context.Context, strategy common.Address) (common.Address, error) {
+
+	calldata, err := e.strategyABI.Pack("underlyingToken")
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	result, err := e.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &strategy,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	var token common.Address
+	err = e.strategyABI.UnpackIntoInterface(&token, "underlyingToken", result)
+	return token, err
+}
+
+func (e *EigenLayerOperation) balanceOf(ctx context.Context, token, account common.Address) (*big.Int, error) {
+
+	calldata, err := e.erc20ABI.Pack("balanceOf", account)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := e.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &token,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := new(big.Int)
+	err = e.erc20ABI.UnpackIntoInterface(&balance, "balanceOf", result)
+	return balance, err
+}
+
+// GetProtocolConfig returns the protocol config for a specific chain
+func (e *EigenLayerOperation) GetProtocolConfig(chainID *big.Int) ProtocolConfig {
+	return ProtocolConfig{
+		ChainID:  e.chainID,
+		ABI:      e.parsedABI,
+		Type:     TypeStake,
+		Contract: e.contract,
+	}
+}
+
+// GetABI returns the ABI of the protocol's contract
+func (e *EigenLayerOperation) GetABI(chainID *big.Int) abi.ABI { return e.parsedABI }
+
+// GetType returns the protocol type
+func (e *EigenLayerOperation) GetType() ProtocolType { return TypeStake }
+
+// GetContractAddress returns the contract address for a specific chain
+func (e *EigenLayerOperation) GetContractAddress(chainID *big.Int) common.Address {
+	return e.contract
+}
+
+// GetContractAddressForAction returns the contract the calldata of action is sent to,
+// the same for every action
+func (e *EigenLayerOperation) GetContractAddressForAction(chainID *big.Int, action ContractAction) (common.Address, error) {
+	return contractAddressForAction(e, chainID, action)
+}
+
+// Name returns the human readable name for the protocol
+func (e *EigenLayerOperation) GetName() string { return EigenLayer }
+
+// GetVersion returns the version of the protocol
+func (e *EigenLayerOperation) GetVersion() string { return "1" }
+
+// GetUniqueKey returns the key identifying the protocol across chains
+func (e *EigenLayerOperation) GetUniqueKey() string { return protocolKey(e.GetName(), e.chainID) }
+
+// CallValue returns the native amount to send along with the calldata.
+// Tokens are pulled by the StrategyManager with an allowance so nothing is sent
+func (e *EigenLayerOperation) CallValue(action ContractAction, params TransactionParams) *big.Int {
+	return big.NewInt(0)
+}
+
+// Capabilities describes the actions, chains and extra data supported by the protocol
+func (e *EigenLayerOperation) Capabilities() ProtocolCapabilities {
+	return ProtocolCapabilities{
+		SupportedActions:  []ContractAction{ERC20Stake},
+		SupportedChains:   []*big.Int{e.chainID},
+		RequiresExtraData: []string{eigenLayerExtraDataStrategy},
+	}
+}
+
+// IsSupportedAction reports whether calldata can be generated for action
+func (e *EigenLayerOperation) IsSupportedAction(action ContractAction) bool {
+	return e.Capabilities().Supports(action)
+}
+
+// SetObserver implements Observable
+func (e *EigenLayerOperation) SetObserver(observer Observer) {
+	e.observer = observer
+	e.client = observeClient(e.client, e.GetName(), observer)
+}
+
+// SetAssetMetadata implements AssetMetadataUser
+func (e *EigenLayerOperation) SetAssetMetadata(metadata AssetMetadata) {
+	e.metadata = metadata
+}
@@ -0,0 +1,136 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testEigenLayerStETHStrategy = common.HexToAddress("0x93c4b944D05dfe6df7645A86cd2206016c51564D")
+	testStETH                   = common.HexToAddress("0xae7ab96520DE3A18E5e111B5EaAb095312D7fE84")
+)
+
+// testEigenLayerDeposit deposits 1 stETH into its strategy
+const testEigenLayerDeposit = "0xe7a050aa" +
+	"00000000000000000000000093c4b944d05dfe6df7645a86cd2206016c51564d" + // strategy
+	"000000000000000000000000ae7ab96520de3a18e5e111b5eaab095312d7fe84" + // token
+	"0000000000000000000000000000000000000000000000000de0b6b3a7640000" // amount
+
+func newTestEigenLayerOperation(t *testing.T, stETH, shares *big.Int) *EigenLayerOperation {
+	t.Helper()
+
+	client := pkgtest.NewClient(EthChainID)
+
+	eigen, err := NewEigenLayerOperation(client, EthChainID)
+	require.NoError(t, err)
+
+	client.HandleReturns(testEigenLayerStETHStrategy, eigen.strategyABI.Methods["underlyingToken"], testStETH)
+	client.HandleReturns(testStETH, eigen.erc20ABI.Methods["balanceOf"], stETH)
+	client.HandleReturns(EigenLayerStrategyManagerAddress, eigen.parsedABI.Methods["stakerStrategyShares"], shares)
+
+	return eigen
+}
+
+func TestEigenLayer_BuildTransaction(t *testing.T) {
+
+	runBuildCases(t, newTestEigenLayerOperation(t, big.NewInt(0), big.NewInt(0)), EthChainID, []buildCase{
+		{
+			name:   "deposit into strategy",
+			action: ERC20Stake,
+			params: TransactionParams{
+				Amount:    big.NewInt(1e18),
+				Sender:    testAccount,
+				Asset:     testStETH,
+				ExtraData: map[string]interface{}{"strategy": testEigenLayerStETHStrategy.Hex()},
+			},
+			to:   EigenLayerStrategyManagerAddress,
+			data: testEigenLayerDeposit,
+		},
+		{
+			name:   "token from extra data",
+			action: ERC20Stake,
+			params: TransactionParams{
+				Amount: big.NewInt(1e18),
+				ExtraData: map[string]interface{}{
+					"strategy": testEigenLayerStETHStrategy,
+					"token":    testStETH.Hex(),
+				},
+			},
+			to:   EigenLayerStrategyManagerAddress,
+			data: testEigenLayerDeposit,
+		},
+		{
+			name:   "missing strategy",
+			action: ERC20Stake,
+			params: TransactionParams{Amount: big.NewInt(1e18), Asset: testStETH},
+			fails:  true,
+		},
+	})
+}
+
+func TestEigenLayer_Validate(t *testing.T) {
+
+	params := func(token common.Address, amount int64) TransactionParams {
+		return TransactionParams{
+			Amount:    big.NewInt(amount),
+			Sender:    testAccount,
+			Asset:     token,
+			ExtraData: map[string]interface{}{"strategy": testEigenLayerStETHStrategy},
+		}
+	}
+
+	runValidateCases(t, newTestEigenLayerOperation(t, big.NewInt(100), big.NewInt(7)), EthChainID, []validateCase{
+		{action: ERC20Stake, params: params(testStETH, 100), valid: true},
+		{action: ERC20Stake, params: params(testStETH, 101)},
+		// not the underlying token of the strategy
+		{action: ERC20Stake, params: params(testUSDC, 1)},
+		{action: ERC20UnStake, params: params(testStETH, 1)},
+	})
+}
+
+func TestEigenLayer_GetBalance(t *testing.T) {
+
+	eigen := newTestEigenLayerOperation(t, big.NewInt(0), big.NewInt(7))
+	client := eigen.client.(*pkgtest.Client)
+
+	token, shares, err := eigen.GetBalance(context.Background(), EthChainID, testAccount, testStETH)
+	require.NoError(t, err)
+	require.Equal(t, testStETH, token)
+	require.Equal(t, big.NewInt(7), shares)
+
+	calls := client.Calls()
+	require.Equal(t, EigenLayerStrategyManagerAddress, *calls[len(calls)-1].To)
+
+	// the shares are read from the strategy of the token
+	expected := "7a7e0d92" +
+		"0000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d007" + // staker
+		"00000000000000000000000093c4b944d05dfe6df7645a86cd2206016c51564d" // strategy
+	require.Equal(t, expected, common.Bytes2Hex(calls[len(calls)-1].Data))
+
+	_, _, err = eigen.GetBalance(context.Background(), EthChainID, testAccount, testEigenLayerStETHStrategy)
+	require.ErrorIs(t, err, ErrAssetNotSupported)
+}
+
+func TestEigenLayer_GetSupportedAssets(t *testing.T) {
+
+	eigen := newTestEigenLayerOperation(t, big.NewInt(0), big.NewInt(0))
+
+	assets, err := eigen.GetSupportedAssets(context.Background(), EthChainID)
+	require.NoError(t, err)
+	require.Len(t, assets, len(eigenLayerStrategies))
+
+	// the strategies are kept in a map, the order must not depend on it
+	for i := 0; i < 10; i++ {
+		again, err := eigen.GetSupportedAssets(context.Background(), EthChainID)
+		require.NoError(t, err)
+		require.Equal(t, assets, again)
+	}
+
+	require.True(t, sort.SliceIsSorted(assets, func(i, j int) bool { return assets[i].Hex() < assets[j].Hex() }))
+}
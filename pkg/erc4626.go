@@ -0,0 +1,557 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// erc4626ABI is the subset of the ERC4626 tokenized vault standard
+// https://eips.ethereum.org/EIPS/eip-4626
+const erc4626ABI = `
+[
+  {
+    "inputs": [
+      { "internalType": "uint256", "name": "assets", "type": "uint256" },
+      { "internalType": "address", "name": "receiver", "type": "address" }
+    ],
+    "name": "deposit",
+    "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }],
+    "stateMutability": "nonpayable",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "uint256", "name": "shares", "type": "uint256" },
+      { "internalType": "address", "name": "receiver", "type": "address" }
+    ],
+    "name": "mint",
+    "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }],
+    "stateMutability": "nonpayable",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "uint256", "name": "assets", "type": "uint256" },
+      { "internalType": "address", "name": "receiver", "type": "address" },
+      { "internalType": "address", "name": "owner", "type": "address" }
+    ],
+    "name": "withdraw",
+    "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }],
+    "stateMutability": "nonpayable",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "uint256", "name": "shares", "type": "uint256" },
+      { "internalType": "address", "name": "receiver", "type": "address" },
+      { "internalType": "address", "name": "owner", "type": "address" }
+    ],
+    "name": "redeem",
+    "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }],
+    "stateMutability": "nonpayable",
+    "type": "function"
+  },
+  {
+    "inputs": [],
+    "name": "asset",
+    "outputs": [{ "internalType": "address", "name": "", "type": "address" }],
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "uint256", "name": "shares", "type": "uint256" }
+    ],
+    "name": "convertToAssets",
+    "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }],
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "uint256", "name": "shares", "type": "uint256" }
+    ],
+    "name": "previewMint",
+    "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }],
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "address", "name": "account", "type": "address" }
+    ],
+    "name": "balanceOf",
+    "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }],
+    "stateMutability": "view",
+    "type": "function"
+  }
+]`
+
+// erc4626ExtraDataShares switches the amount from assets to vault shares
+const erc4626ExtraDataShares = "shares"
+
+// ERC4626Operation deposits into and withdraws from any ERC4626 tokenized vault.
+// ERC20Stake deposits params.Amount of the asset and ERC20UnStake withdraws it.
+// With ExtraData["shares"] set to true params.Amount is a number of vault shares
+// and the vault is called with mint and redeem instead. The asset of the vault
+// is read from the vault on first use and pulled with an allowance
+type ERC4626Operation struct {
+	name      ProtocolName
+	version   string
+	contract  common.Address
+	parsedABI abi.ABI
+	chainID   *big.Int
+	client    EthClient
+	observer  Observer
+	metadata  AssetMetadata
+
+	// verified on first use, see networkCheck
+	network networkCheck
+
+	assetMu sync.Mutex
+	// underlying asset of the vault, zero until fetched
+	asset common.Address
+
+	// disabled through the registry, see SetProtocolEnabled
+	deprecation
+}
+
+var _ Protocol = (*ERC4626Operation)(nil)
+
+// ERC4626Option configures an ERC4626Operation.
+// NetworkOption is an ERC4626Option too
+type ERC4626Option interface {
+	applyERC4626(*ERC4626Operation)
+}
+
+type erc4626Option func(*ERC4626Operation)
+
+func (o erc4626Option) applyERC4626(e *ERC4626Operation) { o(e) }
+
+func (o NetworkOption) applyERC4626(e *ERC4626Operation) { o(&e.network) }
+
+// WithERC4626Protocol names the protocol behind the vault, ERC4626 version 1 by default
+func WithERC4626Protocol(name ProtocolName, version string) ERC4626Option {
+	return erc4626Option(func(e *ERC4626Operation) {
+		e.name = name
+		e.version = version
+	})
+}
+
+func NewERC4626Operation(client EthClient, chainID *big.Int,
+	vault common.Address, opts ...ERC4626Option) (*ERC4626Operation, error) {
+
+	parsedABI, err := abi.JSON(strings.NewReader(erc4626ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	e := &ERC4626Operation{
+		name:      ERC4626,
+		version:   "1",
+		contract:  vault,
+		parsedABI: parsedABI,
+		chainID:   chainID,
+		client:    client,
+	}
+
+	for _, opt := range opts {
+		opt.applyERC4626(e)
+	}
+
+	return e, nil
+}
+
+// GenerateCalldata creates the necessary blockchain transaction data
+func (e *ERC4626Operation) GenerateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+	if err := e.deprecation.check(e.GetUniqueKey()); err != nil {
+		return "", err
+	}
+
+	if err := params.checkAmountRange(); err != nil {
+		return "", err
+	}
+
+	return observeCalldata(e.observer, e.GetName(), action, func() (string, error) {
+		return e.generateCalldata(ctx, chainID, action, params)
+	})
+}
+
+func (e *ERC4626Operation) generateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+
+	if !e.isSupportedChain(chainID) {
+		return "", ErrChainUnsupported
+	}
+
+	if err := e.network.ensure(ctx, e.client, e.chainID); err != nil {
+		return "", err
+	}
+
+	if params.Amount == nil {
+		return "", ErrAmountRequired
+	}
+
+	shares := inShares(params)
+
+	var calldata []byte
+	var err error
+
+	switch {
+	case action == ERC20Stake && shares:
+		calldata, err = e.parsedABI.Pack("mint", params.Amount, params.GetBeneficiaryOwner())
+	case action == ERC20Stake:
+		calldata, err = e.parsedABI.Pack("deposit", params.Amount, params.GetBeneficiaryOwner())
+	case action == ERC20UnStake && shares:
+		calldata, err = e.parsedABI.Pack("redeem", params.Amount, params.GetBeneficiaryOwner(), params.Sender)
+	case action == ERC20UnStake:
+		calldata, err = e.parsedABI.Pack("withdraw", params.Amount, params.GetBeneficiaryOwner(), params.Sender)
+	default:
+		return "", ErrUnsupportedAction
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return HexPrefix + hex.EncodeToString(calldata), nil
+}
+
+// inShares reports whether params.Amount is a number of vault shares
+func inShares(params TransactionParams) bool {
+	v, ok := params.ExtraData[erc4626ExtraDataShares].(bool)
+	return ok && v
+}
+
+// EstimateGas estimates the gas needed to execute the generated calldata
+func (e *ERC4626Operation) EstimateGas(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (uint64, error) {
+
+	tx, err := e.BuildTransaction(ctx, chainID, action, params)
+	if err != nil {
+		return 0, err
+	}
+
+	return estimateGas(ctx, e.client, params.Sender, tx)
+}
+
+// BuildTransaction generates the calldata for the action together with
+// the contract to call and the value to send
+func (e *ERC4626Operation) BuildTransaction(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (*Transaction, error) {
+
+	calldata, err := e.GenerateCalldata(ctx, chainID, action, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{
+		To:    e.GetContractAddress(chainID),
+		Data:  calldata,
+		Value: e.CallValue(action, params),
+	}, nil
+}
+
+// Validate checks if the provided parameters are valid for the specified action.
+// params.Asset must be the underlying asset of the vault
+func (e *ERC4626Operation) Validate(ctx context.Context,
+	chainID *big.Int, action ContractAction, params TransactionParams) error {
+
+	if err := e.deprecation.check(e.GetUniqueKey()); err != nil {
+		return err
+	}
+
+	if !e.isSupportedChain(chainID) {
+		return ErrChainUnsupported
+	}
+
+	if err := e.network.ensure(ctx, e.client, e.chainID); err != nil {
+		return err
+	}
+
+	if !e.IsSupportedAction(action) {
+		return ErrUnsupportedAction
+	}
+
+	if err := params.ValidateAmount(); err != nil {
+		return err
+	}
+
+	asset, err := e.underlying(ctx)
+	if err != nil {
+		return err
+	}
+
+	if params.Asset != asset {
+		return fmt.Errorf("%w %s", ErrAssetNotSupported, params.Asset)
+	}
+
+	// shares are counted in the decimals of the vault
+	if !inShares(params) {
+		if err := checkAmountPrecision(ctx, e.client, e.metadata, e.chainID, params, asset); err != nil {
+			return err
+		}
+	}
+
+	if action == ERC20Stake && allowanceCheckRequested(params) {
+		required, err := e.depositAssets(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		if err := checkAllowance(ctx, e.client, params, asset, e.contract, required); err != nil {
+			return err
+		}
+	}
+
+	if params.lenientValidation(ValidationStrict) {
+		return nil
+	}
+
+	// the amount and balance to compare, in assets unless shares are requested
+	required := params.Amount
+	var balance *big.Int
+
+	switch {
+	case action == ERC20Stake:
+		required, err = e.depositAssets(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		balance, err = e.balanceOf(ctx, asset, params.Sender)
+	case inShares(params):
+		balance, err = e.balanceOf(ctx, e.contract, params.Sender)
+	default:
+		_, balance, err = e.GetBalance(ctx, chainID, params.Sender, asset)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if balance.Cmp(required) < 0 {
+		return ErrInsufficientBalance
+	}
+
+	return nil
+}
+
+// depositAssets returns the amount of the underlying asset a deposit or mint pulls
+func (e *ERC4626Operation) depositAssets(ctx context.Context, params TransactionParams) (*big.Int, error) {
+	if !inShares(params) {
+		return params.Amount, nil
+	}
+
+	return e.callUint256(ctx, "previewMint", params.Amount)
+}
+
+// GetBalance retrieves the amount of the underlying asset the vault shares
+// of account are worth
+func (e *ERC4626Operation) GetBalance(ctx context.Context, chainID *big.Int,
+	account, _ common.Address) (common.Address, *big.Int, error) {
+
+	if !e.isSupportedChain(chainID) {
+		return common.Address{}, nil, ErrChainUnsupported
+	}
+
+	asset, err := e.underlying(ctx)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	shares, err := e.balanceOf(ctx, e.contract, account)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	if shares.Sign() == 0 {
+		return asset, shares, nil
+	}
+
+	assets, err := e.callUint256(ctx, "convertToAssets", shares)
+	return asset, assets, err
+}
+
+// GetSupportedAssets returns the underlying asset of the vault
+func (e *ERC4626Operation) GetSupportedAssets(ctx context.Context, chainID *big.Int) ([]common.Address, error) {
+	if !e.isSupportedChain(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	asset, err := e.underlying(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []common.Address{asset}, nil
+}
+
+func (e *ERC4626Operation) isSupportedChain(chain *big.Int) bool {
+	return e.chainID.Cmp(chain) == 0
+}
+
+// IsSupportedAsset checks if the specified asset is supported on the given chain
+func (e *ERC4626Operation) IsSupportedAsset(ctx context.Context, chainID *big.Int, asset common.Address) bool {
+	if !e.isSupportedChain(chainID) {
+		return false
+	}
+
+	underlying, err := e.underlying(ctx)
+	if err != nil {
+		return false
+	}
+
+	return asset == underlying
+}
+
+// underlying returns the asset of the vault, fetching it on first use
+func (e *ERC4626Operation) underlying(ctx context.Context) (common.Address, error) {
+	e.assetMu.Lock()
+	defer e.assetMu.Unlock()
+
+	if e.asset != (common.Address{}) {
+		return e.asset, nil
+	}
+
+	calldata, err := e.parsedABI.Pack("asset")
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	result, err := e.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &e.contract,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("could not fetch the asset of vault %s: %w", e.contract, err)
+	}
+
+	var asset common.Address
+	if err := e.parsedABI.UnpackIntoInterface(&asset, "asset", result); err != nil {
+		return common.Address{}, err
+	}
+
+	e.asset = asset
+	return asset, nil
+}
+
+// callUint256 calls a view method of the vault taking and returning a uint256
+func (e *ERC4626Operation) callUint256(ctx context.Context, method string, value *big.Int) (*big.Int, error) {
+
+	calldata, err := e.parsedABI.Pack(method, value)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := e.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &e.contract,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	out := new(big.Int)
+	err = e.parsedABI.UnpackIntoInterface(&out, method, result)
+	return out, err
+}
+
+func (e *ERC4626Operation) balanceOf(ctx context.Context, token, account common.Address) (*big.Int, error) {
+
+	calldata, err := e.parsedABI.Pack("balanceOf", account)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := e.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &token,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := new(big.Int)
+	err = e.parsedABI.UnpackIntoInterface(&balance, "balanceOf", result)
+	return balance, err
+}
+
+// GetProtocolConfig returns the protocol config for a specific chain
+func (e *ERC4626Operation) GetProtocolConfig(chainID *big.Int) ProtocolConfig {
+	return ProtocolConfig{
+		ChainID:  e.chainID,
+		ABI:      e.parsedABI,
+		Type:     TypeVault,
+		Contract: e.contract,
+	}
+}
+
+// GetABI returns the ABI of the protocol's contract
+func (e *ERC4626Operation) GetABI(chainID *big.Int) abi.ABI { return e.parsedABI }
+
+// GetType returns the protocol type
+func (e *ERC4626Operation) GetType() ProtocolType { return TypeVault }
+
+// GetContractAddress returns the contract address for a specific chain
+func (e *ERC4626Operation) GetContractAddress(chainID *big.Int) common.Address {
+	return e.contract
+}
+
+// GetContractAddressForAction returns the contract the calldata of action is sent to,
+// the same for every action
+func (e *ERC4626Operation) GetContractAddressForAction(chainID *big.Int, action ContractAction) (common.Address, error) {
+	return contractAddressForAction(e, chainID, action)
+}
+
+// Name returns the human readable name for the protocol
+func (e *ERC4626Operation) GetName() string { return e.name }
+
+// GetVersion returns the version of the protocol
+func (e *ERC4626Operation) GetVersion() string { return e.version }
+
+// GetUniqueKey returns the key identifying the protocol across chains.
+// Several vaults live on the same chain so the vault is part of the key
+func (e *ERC4626Operation) GetUniqueKey() string {
+	return protocolKey(e.GetName(), e.chainID) + ":" + e.contract.Hex()
+}
+
+// CallValue returns the native amount to send along with the calldata.
+// The asset is pulled by the vault with an allowance so nothing is sent
+func (e *ERC4626Operation) CallValue(action ContractAction, params TransactionParams) *big.Int {
+	return big.NewInt(0)
+}
+
+// Capabilities describes the actions, chains and extra data supported by the protocol
+func (e *ERC4626Operation) Capabilities() ProtocolCapabilities {
+	return ProtocolCapabilities{
+		SupportedActions:  []ContractAction{ERC20Stake, ERC20UnStake},
+		SupportedChains:   []*big.Int{e.chainID},
+		RequiresExtraData: []string{},
+	}
+}
+
+// IsSupportedAction reports whether calldata can be generated for action
+func (e *ERC4626Operation) IsSupportedAction(action ContractAction) bool {
+	return e.Capabilities().Supports(action)
+}
+
+// SetObserver implements Observable
+func (e *ERC4626Operation) SetObserver(observer Observer) {
+	e.observer = observer
+	e.client = observeClient(e.client, e.GetName(), observer)
+}
+
+// SetAssetMetadata implements AssetMetadataUser
+func (e *ERC4626Operation) SetAssetMetadata(metadata AssetMetadata) {
+	e.metadata = metadata
+}
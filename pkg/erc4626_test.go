@@ -0,0 +1,137 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var testDAI = common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+
+func TestERC4626_GenerateCalldata_Unit(t *testing.T) {
+
+	vault, err := NewERC4626Operation(pkgtest.NewClient(EthChainID), EthChainID, SavingsDAIContractAddress)
+	require.NoError(t, err)
+
+	receiver := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	params := TransactionParams{
+		Sender:    testAccount,
+		Recipient: receiver,
+		Asset:     testDAI,
+		Amount:    big.NewInt(1e18),
+	}
+
+	shares := params
+	shares.ExtraData = map[string]interface{}{"shares": true}
+
+	tests := []struct {
+		name   string
+		action ContractAction
+		params TransactionParams
+		method string
+		owner  bool
+	}{
+		{name: "deposit", action: ERC20Stake, params: params, method: "deposit"},
+		{name: "mint", action: ERC20Stake, params: shares, method: "mint"},
+		{name: "withdraw", action: ERC20UnStake, params: params, method: "withdraw", owner: true},
+		{name: "redeem", action: ERC20UnStake, params: shares, method: "redeem", owner: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := vault.BuildTransaction(context.Background(), EthChainID, tt.action, tt.params)
+			require.NoError(t, err)
+			require.Equal(t, SavingsDAIContractAddress, tx.To)
+			require.Zero(t, tx.Value.Sign())
+
+			args := unpackCalldata(t, vault.parsedABI, tt.method, tx.Data)
+			require.Zero(t, tt.params.Amount.Cmp(args[0].(*big.Int)))
+			require.Equal(t, receiver, args[1])
+
+			if tt.owner {
+				require.Equal(t, testAccount, args[2])
+			}
+		})
+	}
+
+	_, err = vault.GenerateCalldata(context.Background(), EthChainID, LoanSupply, params)
+	require.ErrorIs(t, err, ErrUnsupportedAction)
+
+	_, err = vault.GenerateCalldata(context.Background(), BscChainID, ERC20Stake, params)
+	require.ErrorIs(t, err, ErrChainUnsupported)
+}
+
+func TestERC4626_Validate_Unit(t *testing.T) {
+
+	funded := common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+
+	client := pkgtest.NewClient(EthChainID)
+
+	vault, err := NewERC4626Operation(client, EthChainID, SavingsDAIContractAddress)
+	require.NoError(t, err)
+
+	require.Equal(t, ERC4626, vault.GetName())
+	require.Equal(t, "erc4626:1:"+SavingsDAIContractAddress.Hex(), vault.GetUniqueKey())
+
+	method := vault.parsedABI.Methods["asset"]
+	client.HandleContract(SavingsDAIContractAddress, method.ID, pkgtest.Returns(method, testDAI))
+
+	// 1 share is worth 2 DAI
+	method = vault.parsedABI.Methods["previewMint"]
+	client.HandleContract(SavingsDAIContractAddress, method.ID, func(msg ethereum.CallMsg) ([]byte, error) {
+		return method.Outputs.Pack(new(big.Int).Mul(new(big.Int).SetBytes(msg.Data[4:]), big.NewInt(2)))
+	})
+
+	method = vault.parsedABI.Methods["convertToAssets"]
+	client.HandleContract(SavingsDAIContractAddress, method.ID, func(msg ethereum.CallMsg) ([]byte, error) {
+		return method.Outputs.Pack(new(big.Int).Mul(new(big.Int).SetBytes(msg.Data[4:]), big.NewInt(2)))
+	})
+
+	// 100 DAI and 10 shares
+	method = vault.parsedABI.Methods["balanceOf"]
+	balance := func(amount *big.Int) pkgtest.CallHandler {
+		return func(msg ethereum.CallMsg) ([]byte, error) {
+			if common.BytesToAddress(msg.Data[4:]) == funded {
+				return method.Outputs.Pack(amount)
+			}
+
+			return method.Outputs.Pack(big.NewInt(0))
+		}
+	}
+	client.HandleContract(testDAI, method.ID, balance(big.NewInt(100)))
+	client.HandleContract(SavingsDAIContractAddress, method.ID, balance(big.NewInt(10)))
+
+	validate := func(action ContractAction, amount int64, shares bool) error {
+		return vault.Validate(context.Background(), EthChainID, action, TransactionParams{
+			Sender:    funded,
+			Asset:     testDAI,
+			Amount:    big.NewInt(amount),
+			ExtraData: map[string]interface{}{"shares": shares},
+		})
+	}
+
+	// deposit 100 DAI, mint 50 shares for 100 DAI
+	require.NoError(t, validate(ERC20Stake, 100, false))
+	require.ErrorIs(t, validate(ERC20Stake, 101, false), ErrInsufficientBalance)
+	require.NoError(t, validate(ERC20Stake, 50, true))
+	require.ErrorIs(t, validate(ERC20Stake, 51, true), ErrInsufficientBalance)
+
+	// the 10 shares are worth 20 DAI
+	require.NoError(t, validate(ERC20UnStake, 20, false))
+	require.ErrorIs(t, validate(ERC20UnStake, 21, false), ErrInsufficientBalance)
+	require.NoError(t, validate(ERC20UnStake, 10, true))
+	require.ErrorIs(t, validate(ERC20UnStake, 11, true), ErrInsufficientBalance)
+
+	err = vault.Validate(context.Background(), EthChainID, ERC20Stake, TransactionParams{
+		Sender: funded,
+		Asset:  testUSDC,
+		Amount: big.NewInt(1),
+	})
+	require.ErrorIs(t, err, ErrAssetNotSupported)
+}
@@ -0,0 +1,155 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// toUint16 coerces the numeric types callers commonly put in ExtraData into an uint16.
+// Values decoded from JSON arrive as float64 and are accepted when they hold an integer
+func toUint16(v interface{}) (uint16, error) {
+
+	var n int64
+
+	switch value := v.(type) {
+	case uint16:
+		return value, nil
+	case uint8:
+		n = int64(value)
+	case int:
+		n = int64(value)
+	case int32:
+		n = int64(value)
+	case int64:
+		n = value
+	case uint:
+		if uint64(value) > math.MaxUint16 {
+			return 0, fmt.Errorf("%d overflows uint16", value)
+		}
+		n = int64(value)
+	case uint32:
+		n = int64(value)
+	case uint64:
+		if value > math.MaxUint16 {
+			return 0, fmt.Errorf("%d overflows uint16", value)
+		}
+		n = int64(value)
+	case float64:
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return 0, fmt.Errorf("%v is not a finite number", value)
+		}
+		if value != math.Trunc(value) {
+			return 0, fmt.Errorf("%v is not an integer", value)
+		}
+
+		if value < 0 || value > math.MaxUint16 {
+			return 0, fmt.Errorf("%v overflows uint16", value)
+		}
+		n = int64(value)
+	case *big.Int:
+		if value == nil || !value.IsInt64() {
+			return 0, fmt.Errorf("%v overflows uint16", value)
+		}
+		n = value.Int64()
+	case string:
+		parsed, err := strconv.ParseUint(strings.TrimSpace(value), 10, 16)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a valid uint16: %w", value, err)
+		}
+		return uint16(parsed), nil
+	default:
+		return 0, fmt.Errorf("unsupported type %T", v)
+	}
+
+	if n < 0 || n > math.MaxUint16 {
+		return 0, fmt.Errorf("%d overflows uint16", n)
+	}
+
+	return uint16(n), nil
+}
+
+// toAddress coerces an address put in ExtraData either as a common.Address
+// or as its hex representation
+func toAddress(v interface{}) (common.Address, error) {
+
+	switch value := v.(type) {
+	case common.Address:
+		return value, nil
+	case string:
+		if !common.IsHexAddress(value) {
+			return common.Address{}, fmt.Errorf("%q is not a valid address", value)
+		}
+		return common.HexToAddress(value), nil
+	default:
+		return common.Address{}, fmt.Errorf("unsupported type %T", v)
+	}
+}
+
+// toUint8 coerces the same types as toUint16 into an uint8
+func toUint8(v interface{}) (uint8, error) {
+
+	n, err := toUint16(v)
+	if err != nil {
+		return 0, err
+	}
+
+	if n > math.MaxUint8 {
+		return 0, fmt.Errorf("%d overflows uint8", n)
+	}
+
+	return uint8(n), nil
+}
+
+// toBigInt coerces the numeric types callers commonly put in ExtraData into
+// a non negative *big.Int. Strings are parsed as base 10 integers
+func toBigInt(v interface{}) (*big.Int, error) {
+
+	var n *big.Int
+
+	switch value := v.(type) {
+	case *big.Int:
+		if value == nil {
+			return nil, errors.New("nil big int")
+		}
+		n = new(big.Int).Set(value)
+	case int:
+		n = big.NewInt(int64(value))
+	case int64:
+		n = big.NewInt(value)
+	case uint64:
+		n = new(big.Int).SetUint64(value)
+	case float64:
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return nil, fmt.Errorf("%v is not a finite number", value)
+		}
+		if value != math.Trunc(value) {
+			return nil, fmt.Errorf("%v is not an integer", value)
+		}
+		n, _ = big.NewFloat(value).Int(nil)
+	case string:
+		var ok bool
+		n, ok = new(big.Int).SetString(strings.TrimSpace(value), 10)
+		if !ok {
+			return nil, fmt.Errorf("%q is not a valid integer", value)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type %T", v)
+	}
+
+	if n.Sign() < 0 {
+		return nil, fmt.Errorf("%s is negative", n)
+	}
+
+	if n.Cmp(abi.MaxUint256) > 0 {
+		return nil, fmt.Errorf("%s exceeds the maximum uint256 value", n)
+	}
+
+	return n, nil
+}
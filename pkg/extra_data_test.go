@@ -0,0 +1,116 @@
+package pkg
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToUint16(t *testing.T) {
+
+	tt := []struct {
+		name     string
+		value    interface{}
+		expected uint16
+		hasError bool
+	}{
+		{name: "uint16", value: uint16(10), expected: 10},
+		{name: "int", value: int(10), expected: 10},
+		{name: "int64", value: int64(10), expected: 10},
+		{name: "uint", value: uint(10), expected: 10},
+		{name: "uint64", value: uint64(10), expected: 10},
+		{name: "float64 from json", value: float64(10), expected: 10},
+		{name: "big int", value: big.NewInt(10), expected: 10},
+		{name: "numeric string", value: "10", expected: 10},
+		{name: "max uint16", value: 65535, expected: 65535},
+		{name: "negative int", value: -1, hasError: true},
+		{name: "int overflow", value: 65536, hasError: true},
+		{name: "uint64 overflow", value: uint64(1 << 20), hasError: true},
+		{name: "fractional float", value: 1.5, hasError: true},
+		{name: "positive infinity", value: math.Inf(1), hasError: true},
+		{name: "negative infinity", value: math.Inf(-1), hasError: true},
+		{name: "not a number", value: math.NaN(), hasError: true},
+		{name: "big int overflow", value: big.NewInt(1 << 20), hasError: true},
+		{name: "nil big int", value: (*big.Int)(nil), hasError: true},
+		{name: "non numeric string", value: "ten", hasError: true},
+		{name: "string overflow", value: "65536", hasError: true},
+		{name: "unsupported type", value: true, hasError: true},
+		{name: "nil", value: nil, hasError: true},
+	}
+
+	for _, v := range tt {
+		t.Run(v.name, func(t *testing.T) {
+			value, err := toUint16(v.value)
+			if v.hasError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, v.expected, value)
+		})
+	}
+}
+
+func TestToAddress(t *testing.T) {
+
+	addr := common.HexToAddress("0x6a22640F02F8c8b576a3193674c4aE97e0f8d007")
+
+	value, err := toAddress(addr)
+	require.NoError(t, err)
+	require.Equal(t, addr, value)
+
+	value, err = toAddress("0x6a22640f02f8c8b576a3193674c4ae97e0f8d007")
+	require.NoError(t, err)
+	require.Equal(t, addr, value)
+
+	_, err = toAddress("0x1234")
+	require.Error(t, err)
+
+	_, err = toAddress(10)
+	require.Error(t, err)
+}
+
+func TestToBigInt(t *testing.T) {
+
+	tt := []struct {
+		name     string
+		value    interface{}
+		expected *big.Int
+		hasError bool
+	}{
+		{name: "big int", value: big.NewInt(10), expected: big.NewInt(10)},
+		{name: "int", value: 10, expected: big.NewInt(10)},
+		{name: "int64", value: int64(10), expected: big.NewInt(10)},
+		{name: "uint64", value: uint64(10), expected: big.NewInt(10)},
+		{name: "float64 from json", value: float64(10), expected: big.NewInt(10)},
+		{name: "numeric string", value: "1000000000000000000000", expected: new(big.Int).Mul(big.NewInt(1e18), big.NewInt(1000))},
+		{name: "negative", value: -1, hasError: true},
+		{name: "fractional float", value: 1.5, hasError: true},
+		{name: "positive infinity", value: math.Inf(1), hasError: true},
+		{name: "negative infinity", value: math.Inf(-1), hasError: true},
+		{name: "not a number", value: math.NaN(), hasError: true},
+		{name: "nil big int", value: (*big.Int)(nil), hasError: true},
+		{name: "non numeric string", value: "ten", hasError: true},
+		{name: "max uint256 string", value: abi.MaxUint256.String(), expected: abi.MaxUint256},
+		{name: "string overflowing uint256", value: new(big.Int).Add(abi.MaxUint256, big.NewInt(1)).String(), hasError: true},
+		{name: "unsupported type", value: true, hasError: true},
+	}
+
+	for _, v := range tt {
+		t.Run(v.name, func(t *testing.T) {
+			value, err := toBigInt(v.value)
+			if v.hasError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, v.expected, value)
+		})
+	}
+}
@@ -2,193 +2,171 @@ package pkg
 
 import (
 	"context"
-	"encoding/hex"
-	"errors"
 	"fmt"
 	"math/big"
-	"strings"
 
-	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-// lidoABI is the ABI definition for the Lido protocol
-const lidoABI = `
-[
-  {
-    "constant": false,
-    "inputs": [
-      {
-        "name": "_referral",
-        "type": "address"
-      }
-    ],
-    "name": "submit",
-    "outputs": [
-      {
-        "name": "",
-        "type": "uint256"
-      }
-    ],
-    "payable": true,
-    "stateMutability": "payable",
-    "type": "function"
-  },
-  {
-    "inputs": [
-      {
-        "internalType": "address",
-        "name": "account",
-        "type": "address"
-      }
-    ],
-    "name": "balanceOf",
-    "outputs": [
-      {
-        "internalType": "uint256",
-        "name": "",
-        "type": "uint256"
-      }
-    ],
-    "stateMutability": "view",
-    "type": "function"
-  }
-]`
-
-// LidoOperation implements the Protocol interface for Lido
-type LidoOperation struct {
-	parsedABI abi.ABI
-	contract  common.Address
-	chainID   *big.Int
-	version   string
+// lidoExtraDataSourceAsset is the ExtraData key naming the asset the stake
+// is funded from when it is not native ETH
+const lidoExtraDataSourceAsset = "source_asset"
+
+// lidoWETHAddress is the only non native source asset Lido stakes can be funded from
+var lidoWETHAddress = common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+
+// lidoStMaticPolygonAddress is the stMATIC token bridged to Polygon
+var lidoStMaticPolygonAddress = common.HexToAddress("0x3A58a54C066FdC0f2D55FC9C89F0415C92eBf3C4")
 
-	client *ethclient.Client
+// LidoOption configures optional behaviour of a LidoOperation.
+// NetworkOption is a LidoOption too
+type LidoOption interface {
+	applyLido(*LidoOperation)
 }
 
-func NewLidoOperation(client *ethclient.Client, chainID *big.Int) (*LidoOperation, error) {
-	parsedABI, err := abi.JSON(strings.NewReader(lidoABI))
-	if err != nil {
-		return nil, err
-	}
+type lidoOption func(*LidoOperation)
 
-	return &LidoOperation{
-		parsedABI: parsedABI,
-		contract:  LidoContractAddress,
-		chainID:   chainID,
-		version:   "3",
-		client:    client,
-	}, nil
+func (o lidoOption) applyLido(l *LidoOperation) { o(l) }
+
+func (o NetworkOption) applyLido(l *LidoOperation) { o(&l.network) }
+
+// WithLidoReferral sets the referral address passed to submit.
+// When unset the beneficiary of the stake is used as referral
+func WithLidoReferral(referral common.Address) LidoOption {
+	return lidoOption(func(l *LidoOperation) {
+		l.config.Referral = &referral
+	})
 }
 
-// GenerateCalldata creates the necessary blockchain transaction data
-func (l *LidoOperation) GenerateCalldata(ctx context.Context, chainID *big.Int,
-	action ContractAction, params TransactionParams) (string, error) {
-	if chainID.Int64() != 1 {
-		return "", ErrChainUnsupported
-	}
+// LidoOperation implements the Protocol interface for Lido.
+// ETH is staked into stETH on Ethereum and POL into stMATIC on Polygon
+type LidoOperation struct {
+	*NativeStakeOperation
+}
 
-	var calldata []byte
-	var err error
+var (
+	_ Protocol       = (*LidoOperation)(nil)
+	_ DepositLimiter = (*LidoOperation)(nil)
+)
 
-	switch action {
-	case NativeStake:
-		// TODO: change this to Balloondogs referral
-		calldata, err = l.parsedABI.Pack("submit", params.GetBeneficiaryOwner())
-		if err != nil {
-			return "", err
-		}
+func NewLidoOperation(client EthClient, chainID *big.Int, opts ...LidoOption) (*LidoOperation, error) {
+
+	config := NativeStakeConfig{
+		Name:    Lido,
+		Version: "3",
+		ChainID: chainID,
+	}
+
+	switch {
+	case IsEth(chainID):
+		config.Contract, config.Token = LidoContractAddress, LidoContractAddress
+		config.Method, config.TakesReferral = "submit", true
+	case IsPolygon(chainID):
+		config.Contract, config.Token = LidoPolygonContractAddress, lidoStMaticPolygonAddress
+		config.Method = "swapMaticForStMaticViaInstantPool"
 	default:
-		return "", errors.New("action not supported")
+		return nil, ErrChainUnsupported
 	}
 
-	return HexPrefix + hex.EncodeToString(calldata), nil
+	stake, err := NewNativeStakeOperation(client, config)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &LidoOperation{NativeStakeOperation: stake}
+
+	for _, opt := range opts {
+		opt.applyLido(l)
+	}
+
+	return l, nil
 }
 
-// Validate checks if the provided parameters are valid for the specified action
+// Validate checks if the provided parameters are valid for the specified action.
+// Stakes funded from WETH, ExtraData["source_asset"], check the WETH balance
+// of the sender who must unwrap it before sending the transaction. WETH can
+// only fund stakes on Ethereum
 func (l *LidoOperation) Validate(ctx context.Context,
 	chainID *big.Int, action ContractAction, params TransactionParams) error {
 
-	if chainID.Int64() != 1 {
+	if err := l.deprecation.check(l.GetUniqueKey()); err != nil {
+		return err
+	}
+
+	if !l.isSupportedChain(chainID) {
 		return ErrChainUnsupported
 	}
 
-	if !l.IsSupportedAsset(ctx, l.chainID, params.Asset) {
-		return fmt.Errorf("asset not supported %s", params.Asset)
+	if err := l.network.ensure(ctx, l.client, l.config.ChainID); err != nil {
+		return err
+	}
+
+	if !l.IsSupportedAsset(ctx, l.config.ChainID, params.Asset) {
+		return fmt.Errorf("%w %s", ErrAssetNotSupported, params.Asset)
 	}
 
 	if action != NativeStake {
-		return errors.New("action not supported")
+		return ErrUnsupportedAction
 	}
 
-	return nil
-}
+	fromWETH, err := stakeFromWETH(params)
+	if err != nil {
+		return err
+	}
 
-// GetBalance retrieves the balance for a specified account and asset
-func (l *LidoOperation) GetBalance(ctx context.Context,
-	chainID *big.Int, account, _ common.Address) (common.Address, *big.Int, error) {
+	if fromWETH && !IsEth(l.config.ChainID) {
+		return fmt.Errorf("source_asset %s can only fund stakes on Ethereum", lidoWETHAddress)
+	}
 
-	var address common.Address
-	if chainID.Int64() != 1 {
-		return address, nil, ErrChainUnsupported
+	if err := params.ValidateAmount(); err != nil {
+		return err
 	}
 
-	callData, err := l.parsedABI.Pack("balanceOf", account)
-	if err != nil {
-		return address, nil, err
+	if !fromWETH || params.lenientValidation(ValidationStrict) {
+		return nil
 	}
 
-	result, err := l.client.CallContract(context.Background(), ethereum.CallMsg{
-		To:   &LidoContractAddress,
-		Data: callData,
-	}, nil)
+	balance, err := l.balanceOf(ctx, lidoWETHAddress, params.Sender)
 	if err != nil {
-		return address, nil, err
+		return err
 	}
 
-	balance := new(big.Int)
-	err = l.parsedABI.UnpackIntoInterface(&balance, "balanceOf", result)
-	return LidoContractAddress, balance, err
-}
+	if balance.Cmp(params.Amount) < 0 {
+		return fmt.Errorf("WETH %w to unwrap and stake", ErrInsufficientBalance)
+	}
 
-// GetSupportedAssets returns a list of assets supported by the protocol on the specified chain
-func (l *LidoOperation) GetSupportedAssets(ctx context.Context, chainID *big.Int) ([]common.Address, error) {
-	return []common.Address{
-		common.HexToAddress(nativeDenomAddress),
-	}, nil
+	return nil
 }
 
-// IsSupportedAsset checks if the specified asset is supported on the given chain
-func (l *LidoOperation) IsSupportedAsset(ctx context.Context, chainID *big.Int, asset common.Address) bool {
-	if chainID.Int64() != 1 {
-		return false
+// stakeFromWETH reports whether ExtraData names WETH as the source of the stake
+func stakeFromWETH(params TransactionParams) (bool, error) {
+
+	v, ok := params.ExtraData[lidoExtraDataSourceAsset]
+	if !ok {
+		return false, nil
 	}
 
-	return IsNativeToken(asset)
-}
+	source, err := toAddress(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid source_asset: %w", err)
+	}
 
-// GetProtocolConfig returns the protocol config for a specific chain
-func (l *LidoOperation) GetProtocolConfig(chainID *big.Int) ProtocolConfig {
-	return ProtocolConfig{
-		ChainID:  l.chainID,
-		Contract: l.contract,
-		ABI:      l.parsedABI,
-		Type:     TypeStake,
+	switch {
+	case IsNativeToken(source):
+		return false, nil
+	case source == lidoWETHAddress:
+		return true, nil
+	default:
+		return false, fmt.Errorf("source_asset %s not supported. Lido stakes native ETH, only WETH can be unwrapped into it", source)
 	}
 }
 
-// GetABI returns the ABI of the protocol's contract
-func (l *LidoOperation) GetABI(chainID *big.Int) abi.ABI { return l.parsedABI }
-
-// GetType returns the protocol type
-func (l *LidoOperation) GetType() ProtocolType { return TypeStake }
-
-// GetContractAddress returns the contract address for a specific chain
-func (l *LidoOperation) GetContractAddress(chainID *big.Int) common.Address { return l.contract }
-
-// Name returns the human readable name for the protocol
-func (l *LidoOperation) GetName() string { return Lido }
+// GetDepositLimits returns the deposit limits of Lido. There is no minimum
+// and deposits are not capped
+func (l *LidoOperation) GetDepositLimits(ctx context.Context, chainID *big.Int) (*big.Int, *big.Int, error) {
+	if !l.isSupportedChain(chainID) {
+		return nil, nil, ErrChainUnsupported
+	}
 
-// GetVersion returns the version of the protocol
-func (l *LidoOperation) GetVersion() string { return l.version }
+	return big.NewInt(0), nil, nil
+}
@@ -30,9 +30,6 @@ const abiString = `
   }]
 		`
 
-// ENUM(ETH,BSC,POLYGON)
-type Chain string
-
 func getTestClient(t *testing.T, c Chain) *ethclient.Client {
 	client, err := ethclient.Dial(getTestRPCURL(t, c))
 	require.NoError(t, err)
@@ -131,3 +128,18 @@ func TestLido_GenerateCalldata(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expectedCalldata, calldata)
 }
+
+func TestLido_EstimateGas(t *testing.T) {
+
+	lido, err := NewLidoOperation(getTestClient(t, ChainETH), big.NewInt(1))
+	require.NoError(t, err)
+
+	gas, err := lido.EstimateGas(context.Background(), big.NewInt(1), NativeStake, TransactionParams{
+		Amount: big.NewInt(1e16),
+		Sender: hotWallet,
+		Asset:  common.HexToAddress(nativeDenomAddress),
+	})
+
+	require.NoError(t, err)
+	require.Greater(t, gas, uint64(21000))
+}
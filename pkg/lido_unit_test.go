@@ -0,0 +1,228 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLido_EstimateGas_Unit(t *testing.T) {
+
+	client := pkgtest.NewClient(EthChainID)
+	client.HandleEstimateGas(func(ethereum.CallMsg) (uint64, error) { return 80_000, nil })
+
+	lido, err := NewLidoOperation(client, EthChainID)
+	require.NoError(t, err)
+
+	gas, err := lido.EstimateGas(context.Background(), EthChainID, NativeStake, TransactionParams{
+		Amount: big.NewInt(1e18),
+		Sender: testAccount,
+		Asset:  common.HexToAddress(nativeDenomAddress),
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 80_000, gas)
+
+	msgs := client.EstimateGasCalls()
+	require.Len(t, msgs, 1)
+	require.Equal(t, LidoContractAddress, *msgs[0].To)
+	require.Equal(t, testAccount, msgs[0].From)
+	require.Equal(t, big.NewInt(1e18), msgs[0].Value)
+	require.Equal(t, "a1903eab", common.Bytes2Hex(msgs[0].Data[:4]))
+}
+
+func TestLido_Referral_Unit(t *testing.T) {
+
+	params := TransactionParams{
+		Amount: big.NewInt(1e18),
+		Sender: testAccount,
+		Asset:  common.HexToAddress(nativeDenomAddress),
+	}
+
+	t.Run("defaults to the beneficiary", func(t *testing.T) {
+		lido, err := NewLidoOperation(pkgtest.NewClient(EthChainID), EthChainID)
+		require.NoError(t, err)
+
+		calldata, err := lido.GenerateCalldata(context.Background(), EthChainID, NativeStake, params)
+		require.NoError(t, err)
+
+		expected := "0xa1903eab" +
+			"0000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d007" // _referral
+		require.Equal(t, expected, calldata)
+	})
+
+	t.Run("configured referral", func(t *testing.T) {
+		referral := common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+
+		lido, err := NewLidoOperation(pkgtest.NewClient(EthChainID), EthChainID, WithLidoReferral(referral))
+		require.NoError(t, err)
+
+		calldata, err := lido.GenerateCalldata(context.Background(), EthChainID, NativeStake, params)
+		require.NoError(t, err)
+
+		expected := "0xa1903eab" +
+			"000000000000000000000000000000000000000000000000000000000000beef" // _referral
+		require.Equal(t, expected, calldata)
+	})
+}
+
+func TestLido_GetDepositLimits_Unit(t *testing.T) {
+
+	lido, err := NewLidoOperation(pkgtest.NewClient(EthChainID), EthChainID)
+	require.NoError(t, err)
+
+	var limiter DepositLimiter = lido
+
+	min, max, err := limiter.GetDepositLimits(context.Background(), EthChainID)
+	require.NoError(t, err)
+	require.Zero(t, min.Sign())
+	require.Nil(t, max)
+
+	_, _, err = limiter.GetDepositLimits(context.Background(), BscChainID)
+	require.ErrorIs(t, err, ErrChainUnsupported)
+}
+
+func TestLido_Validate_Unit(t *testing.T) {
+
+	lido, err := NewLidoOperation(pkgtest.NewClient(EthChainID), EthChainID)
+	require.NoError(t, err)
+
+	params := TransactionParams{
+		Sender: testAccount,
+		Asset:  common.HexToAddress(nativeDenomAddress),
+	}
+
+	t.Run("missing amount", func(t *testing.T) {
+		require.ErrorIs(t, lido.Validate(context.Background(), EthChainID, NativeStake, params), ErrAmountRequired)
+	})
+
+	t.Run("zero amount", func(t *testing.T) {
+		params.Amount = big.NewInt(0)
+		require.ErrorIs(t, lido.Validate(context.Background(), EthChainID, NativeStake, params), ErrAmountTooLow)
+	})
+
+	t.Run("unsupported action", func(t *testing.T) {
+		params.Amount = big.NewInt(1e18)
+		require.ErrorIs(t, lido.Validate(context.Background(), EthChainID, NativeUnStake, params), ErrUnsupportedAction)
+	})
+
+	t.Run("unsupported asset", func(t *testing.T) {
+		usdc := params
+		usdc.Asset = testUSDC
+		require.ErrorIs(t, lido.Validate(context.Background(), EthChainID, NativeStake, usdc), ErrAssetNotSupported)
+	})
+
+	t.Run("valid amount", func(t *testing.T) {
+		params.Amount = big.NewInt(1e18)
+		require.NoError(t, lido.Validate(context.Background(), EthChainID, NativeStake, params))
+	})
+}
+
+func TestLido_Validate_WETHSource_Unit(t *testing.T) {
+
+	client := pkgtest.NewClient(EthChainID)
+
+	lido, err := NewLidoOperation(client, EthChainID)
+	require.NoError(t, err)
+
+	method := lido.parsedABI.Methods["balanceOf"]
+	client.HandleContract(lidoWETHAddress, method.ID, pkgtest.Returns(method, big.NewInt(2e18)))
+
+	stake := func(amount int64, source interface{}) TransactionParams {
+		return TransactionParams{
+			Sender:    testAccount,
+			Asset:     common.HexToAddress(nativeDenomAddress),
+			Amount:    big.NewInt(amount),
+			ExtraData: map[string]interface{}{"source_asset": source},
+		}
+	}
+
+	t.Run("enough WETH", func(t *testing.T) {
+		err := lido.Validate(context.Background(), EthChainID, NativeStake, stake(1e18, lidoWETHAddress.Hex()))
+		require.NoError(t, err)
+	})
+
+	t.Run("not enough WETH", func(t *testing.T) {
+		err := lido.Validate(context.Background(), EthChainID, NativeStake, stake(3e18, lidoWETHAddress))
+		require.ErrorIs(t, err, ErrInsufficientBalance)
+	})
+
+	t.Run("native source keeps the default behaviour", func(t *testing.T) {
+		err := lido.Validate(context.Background(), EthChainID, NativeStake, stake(3e18, nativeDenomAddress))
+		require.NoError(t, err)
+	})
+
+	t.Run("unsupported source", func(t *testing.T) {
+		err := lido.Validate(context.Background(), EthChainID, NativeStake, stake(1e18, testUSDC))
+		require.ErrorContains(t, err, "only WETH can be unwrapped")
+	})
+}
+
+func TestLido_GetBalance_Unit(t *testing.T) {
+
+	client := pkgtest.NewClient(EthChainID)
+
+	lido, err := NewLidoOperation(client, EthChainID)
+	require.NoError(t, err)
+
+	method := lido.parsedABI.Methods["balanceOf"]
+	client.HandleContract(LidoContractAddress, method.ID, pkgtest.Returns(method, big.NewInt(3e18)))
+
+	var protocol Protocol = lido
+
+	token, balance, err := protocol.GetBalance(context.Background(), EthChainID, testAccount,
+		common.HexToAddress(nativeDenomAddress))
+	require.NoError(t, err)
+
+	require.Equal(t, LidoContractAddress, token)
+	require.Equal(t, big.NewInt(3e18), balance)
+
+	_, _, err = protocol.GetBalance(context.Background(), BscChainID, testAccount, common.Address{})
+	require.ErrorIs(t, err, ErrChainUnsupported)
+}
+
+func TestLido_Polygon_Unit(t *testing.T) {
+
+	client := pkgtest.NewClient(PolygonChainID)
+
+	lido, err := NewLidoOperation(client, PolygonChainID)
+	require.NoError(t, err)
+
+	params := TransactionParams{
+		Amount: big.NewInt(1e18),
+		Sender: testAccount,
+		Asset:  common.HexToAddress(nativeDenomAddress),
+	}
+
+	tx, err := lido.BuildTransaction(context.Background(), PolygonChainID, NativeStake, params)
+	require.NoError(t, err)
+
+	// cast sig "swapMaticForStMaticViaInstantPool()"
+	require.Equal(t, "0x7c91a3f4", tx.Data)
+	require.Equal(t, LidoPolygonContractAddress, tx.To)
+	require.Equal(t, big.NewInt(1e18), tx.Value)
+
+	_, err = lido.GenerateCalldata(context.Background(), EthChainID, NativeStake, params)
+	require.ErrorIs(t, err, ErrChainUnsupported)
+
+	_, err = lido.GenerateCalldata(context.Background(), PolygonChainID, NativeUnStake, params)
+	require.ErrorIs(t, err, ErrUnsupportedAction)
+
+	params.ExtraData = map[string]interface{}{lidoExtraDataSourceAsset: lidoWETHAddress.Hex()}
+	require.Error(t, lido.Validate(context.Background(), PolygonChainID, NativeStake, params))
+
+	method := lido.parsedABI.Methods["balanceOf"]
+	client.HandleContract(lidoStMaticPolygonAddress, method.ID, pkgtest.Returns(method, big.NewInt(2e18)))
+
+	token, balance, err := lido.GetBalance(context.Background(), PolygonChainID, testAccount, params.Asset)
+	require.NoError(t, err)
+	require.Equal(t, lidoStMaticPolygonAddress, token)
+	require.Equal(t, big.NewInt(2e18), balance)
+
+	_, err = NewLidoOperation(pkgtest.NewClient(BscChainID), BscChainID)
+	require.ErrorIs(t, err, ErrChainUnsupported)
+}
@@ -0,0 +1,525 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const listaInteractionABI = `
+[
+  {
+    "inputs": [
+      { "internalType": "address", "name": "participant", "type": "address" },
+      { "internalType": "address", "name": "token", "type": "address" },
+      { "internalType": "uint256", "name": "dink", "type": "uint256" }
+    ],
+    "name": "deposit",
+    "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }],
+    "stateMutability": "nonpayable",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "address", "name": "participant", "type": "address" },
+      { "internalType": "address", "name": "token", "type": "address" },
+      { "internalType": "uint256", "name": "dink", "type": "uint256" }
+    ],
+    "name": "withdraw",
+    "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }],
+    "stateMutability": "nonpayable",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "address", "name": "token", "type": "address" },
+      { "internalType": "uint256", "name": "hayAmount", "type": "uint256" }
+    ],
+    "name": "borrow",
+    "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }],
+    "stateMutability": "nonpayable",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "address", "name": "token", "type": "address" },
+      { "internalType": "uint256", "name": "hayAmount", "type": "uint256" }
+    ],
+    "name": "payback",
+    "outputs": [{ "internalType": "int256", "name": "", "type": "int256" }],
+    "stateMutability": "nonpayable",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "address", "name": "token", "type": "address" },
+      { "internalType": "address", "name": "usr", "type": "address" }
+    ],
+    "name": "locked",
+    "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }],
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "address", "name": "token", "type": "address" },
+      { "internalType": "address", "name": "usr", "type": "address" }
+    ],
+    "name": "borrowed",
+    "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }],
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "address", "name": "token", "type": "address" },
+      { "internalType": "address", "name": "usr", "type": "address" }
+    ],
+    "name": "availableToBorrow",
+    "outputs": [{ "internalType": "int256", "name": "", "type": "int256" }],
+    "stateMutability": "view",
+    "type": "function"
+  }
+]`
+
+var lisUSDTokenAddress = common.HexToAddress("0x0782b6d8c4551B9760e74c0545a9bCD90bdc41E5")
+
+// listaCollaterals are the collateral tokens lisUSD can be borrowed against
+var listaCollaterals = []common.Address{
+	slisBNBTokenAddress,
+	common.HexToAddress("0x2170Ed0880ac9A755fd29B2688956BD959F933F8"), // ETH
+	common.HexToAddress("0x7130d2A12B9BCbFAe4f2634d864A1Ee1Ce3Ead9c"), // BTCB
+	common.HexToAddress("0xa2E3356610840701BDf5611a53974510Ae27E2e1"), // wBETH
+}
+
+// ListaPosition is the CDP of an account for a single collateral
+type ListaPosition struct {
+	Collateral        *big.Int
+	Debt              *big.Int
+	AvailableToBorrow *big.Int
+}
+
+// ListaLendingOperation implements the lisUSD CDP of lista dao.
+// Collateral is deposited through the interaction contract and lisUSD is
+// borrowed against it. params.Asset is always the collateral token
+// https://lista.org
+type ListaLendingOperation struct {
+	contract  common.Address
+	parsedABI abi.ABI
+	erc20ABI  abi.ABI
+	chainID   *big.Int
+	client    EthClient
+	observer  Observer
+	metadata  AssetMetadata
+
+	// verified on first use, see networkCheck
+	network networkCheck
+
+	// disabled through the registry, see SetProtocolEnabled
+	deprecation
+}
+
+var _ Protocol = (*ListaLendingOperation)(nil)
+
+func NewListaLendingOperation(client EthClient,
+	chainID *big.Int, opts ...NetworkOption) (*ListaLendingOperation, error) {
+
+	if chainID.Cmp(BscChainID) != 0 {
+		return nil, ErrChainUnsupported
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(listaInteractionABI))
+	if err != nil {
+		return nil, err
+	}
+
+	erc20ABI, err := abi.JSON(strings.NewReader(erc20BalanceOfABI))
+	if err != nil {
+		return nil, err
+	}
+
+	l := &ListaLendingOperation{
+		parsedABI: parsedABI,
+		erc20ABI:  erc20ABI,
+		chainID:   chainID,
+		client:    client,
+		contract:  ListaDaoInteractionContractAddress,
+	}
+
+	for _, opt := range opts {
+		opt(&l.network)
+	}
+
+	return l, nil
+}
+
+// GenerateCalldata creates the necessary blockchain transaction data
+func (l *ListaLendingOperation) GenerateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+	if err := l.deprecation.check(l.GetUniqueKey()); err != nil {
+		return "", err
+	}
+
+	if err := params.checkAmountRange(); err != nil {
+		return "", err
+	}
+
+	return observeCalldata(l.observer, l.GetName(), action, func() (string, error) {
+		return l.generateCalldata(ctx, chainID, action, params)
+	})
+}
+
+func (l *ListaLendingOperation) generateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+
+	if !l.isSupportedChain(chainID) {
+		return "", ErrChainUnsupported
+	}
+
+	if err := l.network.ensure(ctx, l.client, l.chainID); err != nil {
+		return "", err
+	}
+
+	if params.Amount == nil {
+		return "", ErrAmountRequired
+	}
+
+	var calldata []byte
+	var err error
+
+	switch action {
+	case LoanSupply:
+		calldata, err = l.parsedABI.Pack("deposit", params.GetBeneficiaryOwner(), params.Asset, params.Amount)
+	case LoanWithdraw:
+		calldata, err = l.parsedABI.Pack("withdraw", params.GetBeneficiaryOwner(), params.Asset, params.Amount)
+	case LoanBorrow:
+		calldata, err = l.parsedABI.Pack("borrow", params.Asset, params.Amount)
+	case LoanRepay:
+		calldata, err = l.parsedABI.Pack("payback", params.Asset, params.Amount)
+	default:
+		return "", ErrUnsupportedAction
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return HexPrefix + hex.EncodeToString(calldata), nil
+}
+
+// EstimateGas estimates the gas needed to execute the generated calldata
+func (l *ListaLendingOperation) EstimateGas(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (uint64, error) {
+
+	tx, err := l.BuildTransaction(ctx, chainID, action, params)
+	if err != nil {
+		return 0, err
+	}
+
+	return estimateGas(ctx, l.client, params.Sender, tx)
+}
+
+// BuildTransaction generates the calldata for the action together with
+// the contract to call and the value to send
+func (l *ListaLendingOperation) BuildTransaction(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (*Transaction, error) {
+
+	calldata, err := l.GenerateCalldata(ctx, chainID, action, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{
+		To:    l.GetContractAddress(chainID),
+		Data:  calldata,
+		Value: l.CallValue(action, params),
+	}, nil
+}
+
+// Validate checks if the provided parameters are valid for the specified action.
+// Borrowing is rejected once it exceeds what the collateral ratio allows
+func (l *ListaLendingOperation) Validate(ctx context.Context,
+	chainID *big.Int, action ContractAction, params TransactionParams) error {
+
+	if err := l.deprecation.check(l.GetUniqueKey()); err != nil {
+		return err
+	}
+
+	if !l.isSupportedChain(chainID) {
+		return ErrChainUnsupported
+	}
+
+	if err := l.network.ensure(ctx, l.client, l.chainID); err != nil {
+		return err
+	}
+
+	if !l.IsSupportedAsset(ctx, chainID, params.Asset) {
+		return fmt.Errorf("%w %s", ErrAssetNotSupported, params.Asset)
+	}
+
+	if err := params.ValidateAmount(); err != nil {
+		return err
+	}
+
+	// borrowing and repaying are amounts of lisUSD
+	token := params.Asset
+	if action == LoanBorrow || action == LoanRepay {
+		token = lisUSDTokenAddress
+	}
+
+	if err := checkAmountPrecision(ctx, l.client, l.metadata, l.chainID, params, token); err != nil {
+		return err
+	}
+
+	switch action {
+	case LoanSupply:
+		return nil
+
+	case LoanWithdraw:
+		if params.lenientValidation(ValidationStrict) {
+			return nil
+		}
+
+		position, err := l.GetPosition(ctx, params.Sender, params.Asset)
+		if err != nil {
+			return err
+		}
+
+		if position.Collateral.Cmp(params.Amount) < 0 {
+			return fmt.Errorf("collateral %w", ErrInsufficientBalance)
+		}
+
+		return nil
+
+	case LoanBorrow:
+		position, err := l.GetPosition(ctx, params.Sender, params.Asset)
+		if err != nil {
+			return err
+		}
+
+		if position.AvailableToBorrow.Cmp(params.Amount) < 0 {
+			return errors.New("borrow exceeds the collateral ratio")
+		}
+
+		return nil
+
+	case LoanRepay:
+		if params.lenientValidation(ValidationStrict) {
+			return nil
+		}
+
+		balance, err := l.balanceOf(ctx, lisUSDTokenAddress, params.Sender)
+		if err != nil {
+			return err
+		}
+
+		if balance.Cmp(params.Amount) < 0 {
+			return fmt.Errorf("lisUSD %w", ErrInsufficientBalance)
+		}
+
+		return nil
+
+	default:
+		return ErrUnsupportedAction
+	}
+}
+
+// GetPosition reads the collateral, debt and remaining borrowing power of account
+func (l *ListaLendingOperation) GetPosition(ctx context.Context,
+	account, collateral common.Address) (ListaPosition, error) {
+
+	var position ListaPosition
+	var err error
+
+	position.Collateral, err = l.call(ctx, "locked", collateral, account)
+	if err != nil {
+		return position, err
+	}
+
+	position.Debt, err = l.call(ctx, "borrowed", collateral, account)
+	if err != nil {
+		return position, err
+	}
+
+	position.AvailableToBorrow, err = l.call(ctx, "availableToBorrow", collateral, account)
+	return position, err
+}
+
+// GetBalance retrieves the balance for a specified account and asset.
+// For a collateral token it is the amount locked in the CDP, for lisUSD
+// it is the debt across every collateral
+func (l *ListaLendingOperation) GetBalance(ctx context.Context, chainID *big.Int,
+	account, asset common.Address) (common.Address, *big.Int, error) {
+
+	if !l.isSupportedChain(chainID) {
+		return common.Address{}, nil, ErrChainUnsupported
+	}
+
+	if asset == lisUSDTokenAddress {
+		debt := big.NewInt(0)
+
+		for _, collateral := range listaCollaterals {
+			borrowed, err := l.call(ctx, "borrowed", collateral, account)
+			if err != nil {
+				return common.Address{}, nil, err
+			}
+
+			debt.Add(debt, borrowed)
+		}
+
+		return lisUSDTokenAddress, debt, nil
+	}
+
+	if !l.IsSupportedAsset(ctx, chainID, asset) {
+		return common.Address{}, nil, fmt.Errorf("%w %s", ErrAssetNotSupported, asset)
+	}
+
+	locked, err := l.call(ctx, "locked", asset, account)
+	return asset, locked, err
+}
+
+// call invokes a view method of the interaction contract returning a single integer
+func (l *ListaLendingOperation) call(ctx context.Context, method string, args ...interface{}) (*big.Int, error) {
+
+	calldata, err := l.parsedABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := l.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &l.contract,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	value := new(big.Int)
+	err = l.parsedABI.UnpackIntoInterface(&value, method, result)
+	return value, err
+}
+
+func (l *ListaLendingOperation) balanceOf(ctx context.Context, token, account common.Address) (*big.Int, error) {
+
+	calldata, err := l.erc20ABI.Pack("balanceOf", account)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := l.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &token,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := new(big.Int)
+	err = l.erc20ABI.UnpackIntoInterface(&balance, "balanceOf", result)
+	return balance, err
+}
+
+// GetSupportedAssets returns the collateral tokens supported by the CDP
+func (l *ListaLendingOperation) GetSupportedAssets(ctx context.Context, chainID *big.Int) ([]common.Address, error) {
+	if !l.isSupportedChain(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	assets := make([]common.Address, len(listaCollaterals))
+	copy(assets, listaCollaterals)
+
+	return assets, nil
+}
+
+func (l *ListaLendingOperation) isSupportedChain(chain *big.Int) bool {
+	return l.chainID.Cmp(chain) == 0
+}
+
+// IsSupportedAsset checks if the specified asset is supported on the given chain
+func (l *ListaLendingOperation) IsSupportedAsset(ctx context.Context, chainID *big.Int, asset common.Address) bool {
+	if !l.isSupportedChain(chainID) {
+		return false
+	}
+
+	for _, collateral := range listaCollaterals {
+		if collateral == asset {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetProtocolConfig returns the protocol config for a specific chain
+func (l *ListaLendingOperation) GetProtocolConfig(chainID *big.Int) ProtocolConfig {
+	return ProtocolConfig{
+		ChainID:  l.chainID,
+		ABI:      l.parsedABI,
+		Type:     TypeLoan,
+		Contract: l.contract,
+	}
+}
+
+// GetABI returns the ABI of the protocol's contract
+func (l *ListaLendingOperation) GetABI(chainID *big.Int) abi.ABI { return l.parsedABI }
+
+// GetType returns the protocol type
+func (l *ListaLendingOperation) GetType() ProtocolType { return TypeLoan }
+
+// GetContractAddress returns the contract address for a specific chain
+func (l *ListaLendingOperation) GetContractAddress(chainID *big.Int) common.Address {
+	return l.contract
+}
+
+// GetContractAddressForAction returns the contract the calldata of action is sent to,
+// the same for every action
+func (l *ListaLendingOperation) GetContractAddressForAction(chainID *big.Int, action ContractAction) (common.Address, error) {
+	return contractAddressForAction(l, chainID, action)
+}
+
+// Name returns the human readable name for the protocol
+func (l *ListaLendingOperation) GetName() string { return ListaDaoLending }
+
+// GetVersion returns the version of the protocol
+func (l *ListaLendingOperation) GetVersion() string { return "1" }
+
+// GetUniqueKey returns the key identifying the protocol across chains
+func (l *ListaLendingOperation) GetUniqueKey() string { return protocolKey(l.GetName(), l.chainID) }
+
+// CallValue returns the native amount to send along with the calldata.
+// Collateral and lisUSD are ERC20s pulled with an allowance so nothing is sent
+func (l *ListaLendingOperation) CallValue(action ContractAction, params TransactionParams) *big.Int {
+	return big.NewInt(0)
+}
+
+// Capabilities describes the actions, chains and extra data supported by the protocol
+func (l *ListaLendingOperation) Capabilities() ProtocolCapabilities {
+	return ProtocolCapabilities{
+		SupportedActions:  []ContractAction{LoanSupply, LoanWithdraw, LoanBorrow, LoanRepay},
+		SupportedChains:   []*big.Int{l.chainID},
+		RequiresExtraData: []string{},
+	}
+}
+
+// IsSupportedAction reports whether calldata can be generated for action
+func (l *ListaLendingOperation) IsSupportedAction(action ContractAction) bool {
+	return l.Capabilities().Supports(action)
+}
+
+// SetObserver implements Observable
+func (l *ListaLendingOperation) SetObserver(observer Observer) {
+	l.observer = observer
+	l.client = observeClient(l.client, l.GetName(), observer)
+}
+
+// SetAssetMetadata implements AssetMetadataUser
+func (l *ListaLendingOperation) SetAssetMetadata(metadata AssetMetadata) {
+	l.metadata = metadata
+}
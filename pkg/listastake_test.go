@@ -26,7 +26,10 @@ func TestListaStaking_New(t *testing.T) {
 	})
 
 	t.Run("network id of bsc network client does not match eth chain", func(t *testing.T) {
-		_, err := NewListaStakingOperation(getTestClient(t, ChainETH), big.NewInt(56))
+		lista, err := NewListaStakingOperation(getTestClient(t, ChainETH), big.NewInt(56))
+		require.NoError(t, err)
+
+		_, err = lista.GenerateCalldata(context.Background(), big.NewInt(56), NativeStake, TransactionParams{})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "network id does not match")
 	})
@@ -39,7 +42,7 @@ func TestListaStaking_Validate(t *testing.T) {
 
 	t.Run("unsupported action", func(t *testing.T) {
 
-		err = listaStaking.Validate(context.Background(), big.NewInt(56), NativeUnStake, TransactionParams{
+		err = listaStaking.Validate(context.Background(), big.NewInt(56), ERC20Stake, TransactionParams{
 			Amount: big.NewInt(0),
 			Asset:  common.HexToAddress("0xae78736cd615f374d3085123a210448e74fc6393"),
 			Sender: common.HexToAddress(nativeDenomAddress),
@@ -92,6 +95,34 @@ func TestListaStaking_GetBalance(t *testing.T) {
 	validateSymbolFromToken(t, client, token, "slisBNB")
 }
 
+func TestListaStaking_Validate_Unstake(t *testing.T) {
+
+	listaStaking, err := NewListaStakingOperation(getTestClient(t, ChainBSC), big.NewInt(56))
+	require.NoError(t, err)
+
+	wallet := common.HexToAddress("0x6F28FeC449dbd2056b76ac666350Af8773E03873")
+
+	_, bal, err := listaStaking.GetBalance(context.Background(), big.NewInt(56), wallet, common.Address{})
+	require.NoError(t, err)
+
+	params := func(amount *big.Int) TransactionParams {
+		return TransactionParams{
+			Amount: amount,
+			Asset:  common.HexToAddress(nativeDenomAddress),
+			Sender: wallet,
+		}
+	}
+
+	err = listaStaking.Validate(context.Background(), big.NewInt(56), NativeUnStake,
+		params(new(big.Int).Add(bal, big.NewInt(1))))
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+
+	if bal.Sign() > 0 {
+		err = listaStaking.Validate(context.Background(), big.NewInt(56), NativeUnStake, params(bal))
+		require.NoError(t, err)
+	}
+}
+
 func TestListaStaking_GenerateCalldata_Supply(t *testing.T) {
 	// cast calldata "deposit()"
 	// 0xd0e30db0
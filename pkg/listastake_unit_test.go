@@ -0,0 +1,236 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListaStaking_Validate_ValidationMode_Unit(t *testing.T) {
+
+	funded := common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+
+	client := pkgtest.NewClient(BscChainID)
+	client.SetBalance(funded, big.NewInt(1e18))
+
+	lista, err := NewListaStakingOperation(client, BscChainID)
+	require.NoError(t, err)
+
+	params := func(sender common.Address, extraData map[string]interface{}) TransactionParams {
+		return TransactionParams{
+			Amount:    big.NewInt(1e17),
+			Sender:    sender,
+			Asset:     common.HexToAddress(nativeDenomAddress),
+			ExtraData: extraData,
+		}
+	}
+
+	strict := map[string]interface{}{"validation_mode": ValidationStrict}
+
+	t.Run("lenient by default", func(t *testing.T) {
+		err := lista.Validate(context.Background(), BscChainID, NativeStake, params(testAccount, nil))
+		require.NoError(t, err)
+	})
+
+	t.Run("strict without balance", func(t *testing.T) {
+		err := lista.Validate(context.Background(), BscChainID, NativeStake, params(testAccount, strict))
+		require.ErrorIs(t, err, ErrInsufficientBalance)
+	})
+
+	t.Run("strict with balance", func(t *testing.T) {
+		err := lista.Validate(context.Background(), BscChainID, NativeStake, params(funded, strict))
+		require.NoError(t, err)
+	})
+
+	t.Run("lenient still checks the action and amount", func(t *testing.T) {
+		err := lista.Validate(context.Background(), BscChainID, ERC20Stake, params(testAccount, nil))
+		require.ErrorIs(t, err, ErrUnsupportedAction)
+
+		zero := params(testAccount, nil)
+		zero.Amount = big.NewInt(0)
+
+		err = lista.Validate(context.Background(), BscChainID, NativeStake, zero)
+		require.ErrorIs(t, err, ErrAmountTooLow)
+	})
+}
+
+func TestListaStaking_Unstake_Unit(t *testing.T) {
+
+	client := pkgtest.NewClient(BscChainID)
+
+	lista, err := NewListaStakingOperation(client, BscChainID)
+	require.NoError(t, err)
+
+	method := lista.parsedABI.Methods["balanceOf"]
+	client.HandleContract(slisBNBTokenAddress, method.ID, pkgtest.Returns(method, big.NewInt(1e18)))
+
+	params := func(amount int64, extraData map[string]interface{}) TransactionParams {
+		return TransactionParams{
+			Amount:    big.NewInt(amount),
+			Sender:    testAccount,
+			Asset:     common.HexToAddress(nativeDenomAddress),
+			ExtraData: extraData,
+		}
+	}
+
+	t.Run("request withdraw", func(t *testing.T) {
+		tx, err := lista.BuildTransaction(context.Background(), BscChainID, NativeUnStake, params(1e17, nil))
+		require.NoError(t, err)
+
+		// cast calldata "requestWithdraw(uint256)" 100000000000000000
+		require.Equal(t, "0x745400c9"+
+			"000000000000000000000000000000000000000000000000016345785d8a0000", tx.Data)
+		require.Equal(t, ListaDaoContractAddress, tx.To)
+		require.Zero(t, tx.Value.Sign())
+	})
+
+	t.Run("claim withdraw", func(t *testing.T) {
+		calldata, err := lista.GenerateCalldata(context.Background(), BscChainID, NativeUnStake,
+			params(0, map[string]interface{}{"withdrawal_index": 3}))
+		require.NoError(t, err)
+
+		// cast calldata "claimWithdraw(uint256)" 3
+		require.Equal(t, "0xb13acedd"+
+			"0000000000000000000000000000000000000000000000000000000000000003", calldata)
+
+		err = lista.Validate(context.Background(), BscChainID, NativeUnStake,
+			params(0, map[string]interface{}{"withdrawal_index": 3}))
+		require.NoError(t, err)
+	})
+
+	t.Run("slisBNB balance is checked", func(t *testing.T) {
+		err := lista.Validate(context.Background(), BscChainID, NativeUnStake, params(1e18, nil))
+		require.NoError(t, err)
+
+		err = lista.Validate(context.Background(), BscChainID, NativeUnStake, params(2e18, nil))
+		require.ErrorIs(t, err, ErrInsufficientBalance)
+
+		err = lista.Validate(context.Background(), BscChainID, NativeUnStake,
+			params(2e18, map[string]interface{}{"validation_mode": ValidationLenient}))
+		require.NoError(t, err)
+	})
+}
+
+func TestListaLending_Unit(t *testing.T) {
+
+	// mockPosition sets the position of every collateral of the interaction contract
+	mockPosition := func(client *pkgtest.Client, lista *ListaLendingOperation, locked, borrowed, available int64) {
+		for name, value := range map[string]int64{
+			"locked":            locked,
+			"borrowed":          borrowed,
+			"availableToBorrow": available,
+		} {
+			method := lista.parsedABI.Methods[name]
+			client.HandleContract(ListaDaoInteractionContractAddress, method.ID, pkgtest.Returns(method, big.NewInt(value)))
+		}
+
+		method := lista.erc20ABI.Methods["balanceOf"]
+		client.HandleContract(lisUSDTokenAddress, method.ID, pkgtest.Returns(method, big.NewInt(100)))
+	}
+
+	params := func(amount int64) TransactionParams {
+		return TransactionParams{
+			Amount: big.NewInt(amount),
+			Sender: testAccount,
+			Asset:  slisBNBTokenAddress,
+		}
+	}
+
+	t.Run("calldata", func(t *testing.T) {
+		lista, err := NewListaLendingOperation(pkgtest.NewClient(BscChainID), BscChainID)
+		require.NoError(t, err)
+
+		const (
+			participant = "0000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d007"
+			slisBNB     = "000000000000000000000000b0b84d294e0c75a6abe60171b70edeb2efd14a1b"
+			amount      = "0000000000000000000000000000000000000000000000000de0b6b3a7640000"
+		)
+
+		tt := []struct {
+			action   ContractAction
+			method   string
+			expected string
+		}{
+			// cast calldata "deposit(address,address,uint256)" <participant> <slisBNB> 1e18
+			{LoanSupply, "deposit", "0x8340f549" + participant + slisBNB + amount},
+			// cast calldata "withdraw(address,address,uint256)" <participant> <slisBNB> 1e18
+			{LoanWithdraw, "withdraw", "0xd9caed12" + participant + slisBNB + amount},
+			// cast calldata "borrow(address,uint256)" <slisBNB> 1e18
+			{LoanBorrow, "borrow", "0x4b8a3529" + slisBNB + amount},
+			// cast calldata "payback(address,uint256)" <slisBNB> 1e18
+			{LoanRepay, "payback", "0x35ed8ab8" + slisBNB + amount},
+		}
+
+		for _, v := range tt {
+			t.Run(v.method, func(t *testing.T) {
+				calldata, err := lista.GenerateCalldata(context.Background(), BscChainID, v.action, params(1e18))
+				require.NoError(t, err)
+				require.Equal(t, v.expected, calldata)
+			})
+		}
+
+		_, err = lista.GenerateCalldata(context.Background(), BscChainID, NativeStake, params(1e18))
+		require.Error(t, err)
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		tt := []struct {
+			name                        string
+			locked, borrowed, available int64
+			action                      ContractAction
+			amount                      int64
+			hasError                    bool
+		}{
+			{"borrow within the collateral ratio", 1000, 100, 400, LoanBorrow, 400, false},
+			{"borrow above the collateral ratio", 1000, 100, 400, LoanBorrow, 401, true},
+			{"undercollateralized position cannot borrow", 1000, 900, -50, LoanBorrow, 1, true},
+			{"withdraw the locked collateral", 1000, 0, 0, LoanWithdraw, 1000, false},
+			{"withdraw more than the locked collateral", 1000, 0, 0, LoanWithdraw, 1001, true},
+			{"repay the lisUSD balance", 1000, 100, 0, LoanRepay, 100, false},
+			{"repay more than the lisUSD balance", 1000, 100, 0, LoanRepay, 101, true},
+		}
+
+		for _, v := range tt {
+			t.Run(v.name, func(t *testing.T) {
+				client := pkgtest.NewClient(BscChainID)
+
+				lista, err := NewListaLendingOperation(client, BscChainID)
+				require.NoError(t, err)
+
+				mockPosition(client, lista, v.locked, v.borrowed, v.available)
+
+				err = lista.Validate(context.Background(), BscChainID, v.action, params(v.amount))
+				if v.hasError {
+					require.Error(t, err)
+					return
+				}
+
+				require.NoError(t, err)
+			})
+		}
+	})
+
+	t.Run("balance", func(t *testing.T) {
+		client := pkgtest.NewClient(BscChainID)
+
+		lista, err := NewListaLendingOperation(client, BscChainID)
+		require.NoError(t, err)
+
+		mockPosition(client, lista, 1000, 100, 0)
+
+		token, collateral, err := lista.GetBalance(context.Background(), BscChainID, testAccount, slisBNBTokenAddress)
+		require.NoError(t, err)
+		require.Equal(t, slisBNBTokenAddress, token)
+		require.EqualValues(t, 1000, collateral.Int64())
+
+		// every collateral reports the same debt in the mock
+		token, debt, err := lista.GetBalance(context.Background(), BscChainID, testAccount, lisUSDTokenAddress)
+		require.NoError(t, err)
+		require.Equal(t, lisUSDTokenAddress, token)
+		require.EqualValues(t, 100*len(listaCollaterals), debt.Int64())
+	})
+}
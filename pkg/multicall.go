@@ -0,0 +1,112 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const multicall3ABI = `
+[
+  {
+    "inputs": [
+      {
+        "components": [
+          { "internalType": "address", "name": "target", "type": "address" },
+          { "internalType": "bool", "name": "allowFailure", "type": "bool" },
+          { "internalType": "bytes", "name": "callData", "type": "bytes" }
+        ],
+        "internalType": "struct Multicall3.Call3[]",
+        "name": "calls",
+        "type": "tuple[]"
+      }
+    ],
+    "name": "aggregate3",
+    "outputs": [
+      {
+        "components": [
+          { "internalType": "bool", "name": "success", "type": "bool" },
+          { "internalType": "bytes", "name": "returnData", "type": "bytes" }
+        ],
+        "internalType": "struct Multicall3.Result[]",
+        "name": "returnData",
+        "type": "tuple[]"
+      }
+    ],
+    "stateMutability": "payable",
+    "type": "function"
+  }
+]`
+
+// Multicall3Address is the address Multicall3 is deployed at on every chain it exists on
+// https://www.multicall3.com
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a6b4a28A4A1")
+
+// errMulticallUnavailable is returned when Multicall3 is not deployed on the chain
+var errMulticallUnavailable = errors.New("multicall3 is not deployed")
+
+// multicallCall is a single call batched by multicall
+type multicallCall struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+type multicallResult struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// multicall runs calls in a single aggregate3 call and returns their results in order.
+// Every call must succeed
+func multicall(ctx context.Context, client EthClient, calls []multicallCall) ([][]byte, error) {
+
+	parsedABI, err := abi.JSON(strings.NewReader(multicall3ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	calldata, err := parsedABI.Pack("aggregate3", calls)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.CallContract(ctx, ethereum.CallMsg{
+		To:   &Multicall3Address,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// calling an address without code succeeds with no data
+	if len(result) == 0 {
+		return nil, errMulticallUnavailable
+	}
+
+	values, err := parsedABI.Unpack("aggregate3", result)
+	if err != nil {
+		return nil, err
+	}
+
+	results := *abi.ConvertType(values[0], new([]multicallResult)).(*[]multicallResult)
+	if len(results) != len(calls) {
+		return nil, fmt.Errorf("multicall returned %d results for %d calls", len(results), len(calls))
+	}
+
+	data := make([][]byte, len(results))
+	for i, r := range results {
+		if !r.Success {
+			return nil, fmt.Errorf("multicall: call %d to %s failed", i, calls[i].Target)
+		}
+
+		data[i] = r.ReturnData
+	}
+
+	return data, nil
+}
@@ -0,0 +1,50 @@
+package pkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompound_GetSupportedAssets_Multicall(t *testing.T) {
+
+	assets := []common.Address{
+		common.HexToAddress("0x2260FAC5E5542a773Aa44fBCfeDf7C193bc2C599"),
+		common.HexToAddress("0x514910771AF9Ca656af840dff83E8264EcF986CA"),
+		common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"),
+	}
+
+	pool := common.HexToAddress(CompoundV3USDCPool)
+
+	batched := newCompoundMarket(t, assets...)
+	batched.multicall = true
+
+	sequential := newCompoundMarket(t, assets...)
+
+	batchedAssets, err := getSupportedAssets(context.Background(), batched.abi, batched.client(), pool)
+	require.NoError(t, err)
+
+	sequentialAssets, err := getSupportedAssets(context.Background(), sequential.abi, sequential.client(), pool)
+	require.NoError(t, err)
+
+	require.Equal(t, assets, batchedAssets)
+	require.Equal(t, sequentialAssets, batchedAssets)
+
+	// numAssets and a single aggregate3
+	require.Equal(t, 2, batched.calls)
+	// numAssets, the failed aggregate3 and one getAssetInfo per asset
+	require.Equal(t, 2+len(assets), sequential.calls)
+}
+
+func TestMulticall_FailedCall(t *testing.T) {
+
+	market := newCompoundMarket(t)
+	market.multicall = true
+
+	_, err := multicall(context.Background(), market.client(), []multicallCall{
+		{Target: common.HexToAddress(CompoundV3USDCPool), CallData: []byte{0xde, 0xad, 0xbe, 0xef}},
+	})
+	require.Error(t, err)
+}
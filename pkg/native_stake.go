@@ -0,0 +1,460 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// nativeStakeExtraDataWithdrawalIndex is the ExtraData key of the withdrawal
+// request NativeUnStake claims instead of requesting a new one
+const nativeStakeExtraDataWithdrawalIndex = "withdrawal_index"
+
+// NativeStakeConfig describes a liquid staking contract minting its token for
+// the native token sent to a payable stake method, e.g submit(address) of Lido
+// or deposit() of Lista
+type NativeStakeConfig struct {
+	Name     ProtocolName
+	Version  string
+	ChainID  *big.Int
+	Contract common.Address
+	// Method is the name of the payable stake method
+	Method string
+	// TakesReferral is set when Method takes a referral address as only argument.
+	// The beneficiary of the stake is passed unless Referral is set
+	TakesReferral bool
+	Referral      *common.Address
+	// Token is the liquid staking token GetBalance reports
+	Token common.Address
+	// UnstakeMethod is the name of the method requesting the withdrawal of an
+	// amount of Token. NativeUnStake is not supported when empty
+	UnstakeMethod string
+	// ClaimMethod is the name of the method claiming a withdrawal request once
+	// it is processed. NativeUnStake calls it with ExtraData["withdrawal_index"]
+	ClaimMethod string
+	// Validation is the validation mode of NativeStake applied when ExtraData
+	// does not set one, strict when empty. NativeUnStake is strict by default
+	Validation ValidationMode
+}
+
+// NativeStakeOperation implements the Protocol interface for the liquid staking
+// contracts described by a NativeStakeConfig, so supporting one takes a config
+// rather than a new operation
+type NativeStakeOperation struct {
+	config    NativeStakeConfig
+	parsedABI abi.ABI
+	client    EthClient
+	observer  Observer
+
+	// verified on first use, see networkCheck
+	network networkCheck
+
+	// disabled through the registry, see SetProtocolEnabled
+	deprecation
+}
+
+var _ Protocol = (*NativeStakeOperation)(nil)
+
+func NewNativeStakeOperation(client EthClient, config NativeStakeConfig, opts ...NetworkOption) (*NativeStakeOperation, error) {
+
+	if config.ChainID == nil {
+		return nil, ErrChainUnsupported
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(erc20BalanceOfABI))
+	if err != nil {
+		return nil, err
+	}
+
+	var inputs abi.Arguments
+	if config.TakesReferral {
+		addressType, err := abi.NewType("address", "", nil)
+		if err != nil {
+			return nil, err
+		}
+
+		inputs = abi.Arguments{{Name: "_referral", Type: addressType}}
+	}
+
+	parsedABI.Methods[config.Method] = abi.NewMethod(config.Method, config.Method, abi.Function,
+		"payable", false, true, inputs, nil)
+
+	uint256Type, err := abi.NewType("uint256", "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for method, input := range map[string]string{config.UnstakeMethod: "amount", config.ClaimMethod: "index"} {
+		if method == "" {
+			continue
+		}
+
+		parsedABI.Methods[method] = abi.NewMethod(method, method, abi.Function,
+			"nonpayable", false, false, abi.Arguments{{Name: input, Type: uint256Type}}, nil)
+	}
+
+	n := &NativeStakeOperation{
+		config:    config,
+		parsedABI: parsedABI,
+		client:    client,
+	}
+
+	for _, opt := range opts {
+		opt(&n.network)
+	}
+
+	return n, nil
+}
+
+// GenerateCalldata creates the necessary blockchain transaction data
+func (n *NativeStakeOperation) GenerateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+	if err := n.deprecation.check(n.GetUniqueKey()); err != nil {
+		return "", err
+	}
+
+	if err := params.checkAmountRange(); err != nil {
+		return "", err
+	}
+
+	return observeCalldata(n.observer, n.GetName(), action, func() (string, error) {
+		return n.generateCalldata(ctx, chainID, action, params)
+	})
+}
+
+func (n *NativeStakeOperation) generateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+
+	if !n.isSupportedChain(chainID) {
+		return "", ErrChainUnsupported
+	}
+
+	if err := n.network.ensure(ctx, n.client, n.config.ChainID); err != nil {
+		return "", err
+	}
+
+	if !n.IsSupportedAction(action) {
+		return "", ErrUnsupportedAction
+	}
+
+	if action == NativeUnStake {
+		return n.generateUnstakeCalldata(params)
+	}
+
+	var args []interface{}
+	if n.config.TakesReferral {
+		referral := params.GetBeneficiaryOwner()
+		if n.config.Referral != nil {
+			referral = *n.config.Referral
+		}
+
+		args = append(args, referral)
+	}
+
+	calldata, err := n.parsedABI.Pack(n.config.Method, args...)
+	if err != nil {
+		return "", err
+	}
+
+	return HexPrefix + hex.EncodeToString(calldata), nil
+}
+
+// EstimateGas estimates the gas needed to execute the generated calldata.
+// Staking sends params.Amount along with the call
+func (n *NativeStakeOperation) EstimateGas(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (uint64, error) {
+
+	tx, err := n.BuildTransaction(ctx, chainID, action, params)
+	if err != nil {
+		return 0, err
+	}
+
+	return estimateGas(ctx, n.client, params.Sender, tx)
+}
+
+// BuildTransaction generates the calldata for the action together with
+// the contract to call and the value to send
+func (n *NativeStakeOperation) BuildTransaction(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (*Transaction, error) {
+
+	calldata, err := n.GenerateCalldata(ctx, chainID, action, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{
+		To:    n.GetContractAddress(chainID),
+		Data:  calldata,
+		Value: n.CallValue(action, params),
+	}, nil
+}
+
+// Validate checks if the provided parameters are valid for the specified action.
+// The native balance of the sender is checked unless the validation is lenient
+func (n *NativeStakeOperation) Validate(ctx context.Context,
+	chainID *big.Int, action ContractAction, params TransactionParams) error {
+
+	if err := n.deprecation.check(n.GetUniqueKey()); err != nil {
+		return err
+	}
+
+	if !n.isSupportedChain(chainID) {
+		return ErrChainUnsupported
+	}
+
+	if err := n.network.ensure(ctx, n.client, n.config.ChainID); err != nil {
+		return err
+	}
+
+	if !n.IsSupportedAsset(ctx, chainID, params.Asset) {
+		return fmt.Errorf("%w %s", ErrAssetNotSupported, params.Asset)
+	}
+
+	if !n.IsSupportedAction(action) {
+		return ErrUnsupportedAction
+	}
+
+	if action == NativeUnStake {
+		return n.validateUnstake(ctx, params)
+	}
+
+	if err := params.ValidateAmount(); err != nil {
+		return err
+	}
+
+	if params.lenientValidation(n.config.Validation) {
+		return nil
+	}
+
+	balance, err := n.client.BalanceAt(ctx, params.Sender, nil)
+	if err != nil {
+		return err
+	}
+
+	if balance.Cmp(params.Amount) < 0 {
+		return ErrInsufficientBalance
+	}
+
+	return nil
+}
+
+// withdrawalIndex returns the withdrawal request set in ExtraData to be claimed, nil when not set
+func (n *NativeStakeOperation) withdrawalIndex(params TransactionParams) (*big.Int, error) {
+
+	v, ok := params.ExtraData[nativeStakeExtraDataWithdrawalIndex]
+	if !ok {
+		return nil, nil
+	}
+
+	if n.config.ClaimMethod == "" {
+		return nil, fmt.Errorf("%s does not support claiming withdrawals", n.config.Name)
+	}
+
+	index, err := toBigInt(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid withdrawal index: %w", err)
+	}
+
+	return index, nil
+}
+
+// generateUnstakeCalldata requests the withdrawal of params.Amount of the liquid
+// staking token, or claims the request set in ExtraData["withdrawal_index"]
+func (n *NativeStakeOperation) generateUnstakeCalldata(params TransactionParams) (string, error) {
+
+	index, err := n.withdrawalIndex(params)
+	if err != nil {
+		return "", err
+	}
+
+	var calldata []byte
+	if index != nil {
+		calldata, err = n.parsedABI.Pack(n.config.ClaimMethod, index)
+	} else {
+		if params.Amount == nil {
+			return "", ErrAmountRequired
+		}
+
+		calldata, err = n.parsedABI.Pack(n.config.UnstakeMethod, params.Amount)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return HexPrefix + hex.EncodeToString(calldata), nil
+}
+
+// validateUnstake checks the sender holds the liquid staking token to withdraw
+// unless the validation is lenient. Claims take no amount
+func (n *NativeStakeOperation) validateUnstake(ctx context.Context, params TransactionParams) error {
+
+	index, err := n.withdrawalIndex(params)
+	if err != nil {
+		return err
+	}
+
+	if index != nil {
+		return nil
+	}
+
+	if err := params.ValidateAmount(); err != nil {
+		return err
+	}
+
+	if params.lenientValidation(ValidationStrict) {
+		return nil
+	}
+
+	balance, err := n.balanceOf(ctx, n.config.Token, params.Sender)
+	if err != nil {
+		return err
+	}
+
+	if balance.Cmp(params.Amount) < 0 {
+		return ErrInsufficientBalance
+	}
+
+	return nil
+}
+
+func (n *NativeStakeOperation) balanceOf(ctx context.Context, token, account common.Address) (*big.Int, error) {
+
+	calldata, err := n.parsedABI.Pack("balanceOf", account)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := n.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &token,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := new(big.Int)
+	err = n.parsedABI.UnpackIntoInterface(&balance, "balanceOf", result)
+	return balance, err
+}
+
+// GetBalance retrieves the balance of account in the liquid staking token along with its address
+func (n *NativeStakeOperation) GetBalance(ctx context.Context,
+	chainID *big.Int, account, _ common.Address) (common.Address, *big.Int, error) {
+
+	if !n.isSupportedChain(chainID) {
+		return common.Address{}, nil, ErrChainUnsupported
+	}
+
+	balance, err := n.balanceOf(ctx, n.config.Token, account)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	return n.config.Token, balance, nil
+}
+
+// GetSupportedAssets returns the native token staked and the liquid staking
+// token, e.g stETH or slisBNB, unstaked and returned by GetBalance
+func (n *NativeStakeOperation) GetSupportedAssets(ctx context.Context, chainID *big.Int) ([]common.Address, error) {
+	if !n.isSupportedChain(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	return []common.Address{
+		common.HexToAddress(nativeDenomAddress),
+		n.config.Token,
+	}, nil
+}
+
+// IsSupportedAsset checks if the specified asset is the native token or the
+// liquid staking token on the given chain
+func (n *NativeStakeOperation) IsSupportedAsset(ctx context.Context, chainID *big.Int, asset common.Address) bool {
+	if !n.isSupportedChain(chainID) {
+		return false
+	}
+
+	return IsNativeToken(asset) || asset == n.config.Token
+}
+
+func (n *NativeStakeOperation) isSupportedChain(chain *big.Int) bool {
+	return n.config.ChainID.Cmp(chain) == 0
+}
+
+// GetProtocolConfig returns the protocol config for a specific chain
+func (n *NativeStakeOperation) GetProtocolConfig(chainID *big.Int) ProtocolConfig {
+	return ProtocolConfig{
+		ChainID:  n.config.ChainID,
+		Contract: n.config.Contract,
+		ABI:      n.parsedABI,
+		Type:     TypeStake,
+	}
+}
+
+// GetABI returns the ABI of the protocol's contract
+func (n *NativeStakeOperation) GetABI(chainID *big.Int) abi.ABI { return n.parsedABI }
+
+// GetType returns the protocol type
+func (n *NativeStakeOperation) GetType() ProtocolType { return TypeStake }
+
+// GetContractAddress returns the contract address for a specific chain
+func (n *NativeStakeOperation) GetContractAddress(chainID *big.Int) common.Address {
+	return n.config.Contract
+}
+
+// GetContractAddressForAction returns the contract the calldata of action is sent to,
+// the same for every action
+func (n *NativeStakeOperation) GetContractAddressForAction(chainID *big.Int, action ContractAction) (common.Address, error) {
+	return contractAddressForAction(n, chainID, action)
+}
+
+// Name returns the human readable name for the protocol
+func (n *NativeStakeOperation) GetName() string { return n.config.Name }
+
+// GetVersion returns the version of the protocol
+func (n *NativeStakeOperation) GetVersion() string { return n.config.Version }
+
+// GetUniqueKey returns the key identifying the protocol across chains
+func (n *NativeStakeOperation) GetUniqueKey() string {
+	return protocolKey(n.GetName(), n.config.ChainID)
+}
+
+// CallValue returns the native amount to send along with the calldata.
+// The stake method is payable and takes the staked amount as msg.value
+func (n *NativeStakeOperation) CallValue(action ContractAction, params TransactionParams) *big.Int {
+	if action == NativeStake {
+		return params.nativeCallValue()
+	}
+
+	return big.NewInt(0)
+}
+
+// Capabilities describes the actions, chains and extra data supported by the protocol
+func (n *NativeStakeOperation) Capabilities() ProtocolCapabilities {
+	actions := []ContractAction{NativeStake}
+	if n.config.UnstakeMethod != "" {
+		actions = append(actions, NativeUnStake)
+	}
+
+	return ProtocolCapabilities{
+		SupportedActions:  actions,
+		SupportedChains:   []*big.Int{n.config.ChainID},
+		RequiresExtraData: []string{},
+	}
+}
+
+// IsSupportedAction reports whether calldata can be generated for action
+func (n *NativeStakeOperation) IsSupportedAction(action ContractAction) bool {
+	return n.Capabilities().Supports(action)
+}
+
+// SetObserver implements Observable
+func (n *NativeStakeOperation) SetObserver(observer Observer) {
+	n.observer = observer
+	n.client = observeClient(n.client, n.GetName(), observer)
+}
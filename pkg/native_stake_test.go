@@ -0,0 +1,131 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNativeStake_SameCalldata_Unit(t *testing.T) {
+
+	lido, err := NewLidoOperation(pkgtest.NewClient(EthChainID), EthChainID)
+	require.NoError(t, err)
+
+	lidoPolygon, err := NewLidoOperation(pkgtest.NewClient(PolygonChainID), PolygonChainID)
+	require.NoError(t, err)
+
+	lista, err := NewListaStakingOperation(pkgtest.NewClient(BscChainID), BscChainID)
+	require.NoError(t, err)
+
+	params := TransactionParams{
+		Sender: testAccount,
+		Asset:  common.HexToAddress(nativeDenomAddress),
+		Amount: big.NewInt(1e18),
+	}
+
+	// the calldata of the operations before they were built on NativeStakeOperation
+	tt := []struct {
+		name     string
+		protocol Protocol
+		chainID  *big.Int
+		calldata string
+	}{
+		{
+			name: "lido", protocol: lido, chainID: EthChainID,
+			// cast calldata "submit(address)" 0x6a22640F02F8c8b576a3193674c4aE97e0f8d007
+			calldata: "0xa1903eab0000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d007",
+		},
+		{
+			name: "lido polygon", protocol: lidoPolygon, chainID: PolygonChainID,
+			calldata: "0x7c91a3f4",
+		},
+		{
+			name: "lista", protocol: lista, chainID: BscChainID,
+			calldata: "0xd0e30db0",
+		},
+	}
+
+	for _, v := range tt {
+		t.Run(v.name, func(t *testing.T) {
+			tx, err := v.protocol.BuildTransaction(context.Background(), v.chainID, NativeStake, params)
+			require.NoError(t, err)
+			require.Equal(t, v.calldata, tx.Data)
+			require.Equal(t, params.Amount, tx.Value)
+		})
+	}
+}
+
+func TestNativeStake_Config_Unit(t *testing.T) {
+
+	contract := common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+	token := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	referral := common.HexToAddress("0x000000000000000000000000000000000000cAfE")
+
+	client := pkgtest.NewClient(EthChainID)
+	client.SetBalance(testAccount, big.NewInt(1e18))
+
+	stake, err := NewNativeStakeOperation(client, NativeStakeConfig{
+		Name:          "lst",
+		Version:       "2",
+		ChainID:       EthChainID,
+		Contract:      contract,
+		Method:        "submit",
+		TakesReferral: true,
+		Referral:      &referral,
+		Token:         token,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "lst", stake.GetName())
+	require.Equal(t, "lst:1", stake.GetUniqueKey())
+	require.Equal(t, contract, stake.GetContractAddress(EthChainID))
+
+	params := TransactionParams{
+		Sender: testAccount,
+		Asset:  common.HexToAddress(nativeDenomAddress),
+		Amount: big.NewInt(1e18),
+	}
+
+	t.Run("calldata", func(t *testing.T) {
+		calldata, err := stake.GenerateCalldata(context.Background(), EthChainID, NativeStake, params)
+		require.NoError(t, err)
+
+		// cast calldata "submit(address)" 0x000000000000000000000000000000000000cAfE
+		require.Equal(t, "0xa1903eab000000000000000000000000000000000000000000000000000000000000cafe", calldata)
+
+		_, err = stake.GenerateCalldata(context.Background(), EthChainID, NativeUnStake, params)
+		require.ErrorIs(t, err, ErrUnsupportedAction)
+
+		_, err = stake.GenerateCalldata(context.Background(), BscChainID, NativeStake, params)
+		require.ErrorIs(t, err, ErrChainUnsupported)
+	})
+
+	t.Run("strict by default", func(t *testing.T) {
+		require.NoError(t, stake.Validate(context.Background(), EthChainID, NativeStake, params))
+
+		more := params
+		more.Amount = big.NewInt(2e18)
+		require.ErrorIs(t, stake.Validate(context.Background(), EthChainID, NativeStake, more), ErrInsufficientBalance)
+
+		more.ExtraData = map[string]interface{}{"validation_mode": "lenient"}
+		require.NoError(t, stake.Validate(context.Background(), EthChainID, NativeStake, more))
+
+		usdc := params
+		usdc.Asset = testUSDC
+		require.ErrorIs(t, stake.Validate(context.Background(), EthChainID, NativeStake, usdc), ErrAssetNotSupported)
+	})
+
+	t.Run("balance in the staking token", func(t *testing.T) {
+		method := stake.parsedABI.Methods["balanceOf"]
+		client.HandleContract(token, method.ID, pkgtest.Returns(method, big.NewInt(5e17)))
+
+		address, balance, err := stake.GetBalance(context.Background(), EthChainID, testAccount, params.Asset)
+		require.NoError(t, err)
+		require.Equal(t, token, address)
+		require.Zero(t, balance.Cmp(big.NewInt(5e17)))
+	})
+}
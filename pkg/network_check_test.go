@@ -0,0 +1,213 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkCheck_DeadRPC(t *testing.T) {
+
+	// nothing listens there
+	client, err := ethclient.Dial("http://127.0.0.1:1")
+	require.NoError(t, err)
+
+	aave, err := NewAaveOperation(client, BscChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	lista, err := NewListaStakingOperation(client, BscChainID)
+	require.NoError(t, err)
+
+	params := TransactionParams{
+		Amount: big.NewInt(1e18),
+		Sender: testAccount,
+		Asset:  testUSDC,
+		ExtraData: map[string]interface{}{
+			"referral_code": 0,
+		},
+	}
+
+	_, err = aave.GenerateCalldata(context.Background(), BscChainID, LoanSupply, params)
+	require.ErrorContains(t, err, "could not fetch network id")
+
+	require.ErrorContains(t, aave.Validate(context.Background(), BscChainID, LoanSupply, params),
+		"could not fetch network id")
+
+	params.Asset = common.HexToAddress(nativeDenomAddress)
+
+	_, err = lista.GenerateCalldata(context.Background(), BscChainID, NativeStake, params)
+	require.ErrorContains(t, err, "could not fetch network id")
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: BscChainID, RPCURL: "http://127.0.0.1:1"},
+	})
+	require.NoError(t, err)
+
+	protocol, err := registry.GetProtocol(BscChainID, ListaDaoContractAddress)
+	require.NoError(t, err)
+
+	_, err = protocol.GenerateCalldata(context.Background(), BscChainID, NativeStake, params)
+	require.ErrorContains(t, err, "could not fetch network id")
+}
+
+func TestNetworkCheck_Mismatch(t *testing.T) {
+
+	client := pkgtest.NewClient(EthChainID)
+
+	venus, err := NewVenusOperation(client, BscChainID)
+	require.NoError(t, err)
+
+	params := TransactionParams{
+		Amount: big.NewInt(1e18),
+		Sender: testAccount,
+		Asset:  common.HexToAddress(nativeDenomAddress),
+	}
+
+	_, err = venus.GenerateCalldata(context.Background(), BscChainID, LoanSupply, params)
+	require.ErrorIs(t, err, ErrNetworkMismatch)
+
+	// failures are not cached
+	client.SetNetworkID(BscChainID)
+
+	_, err = venus.GenerateCalldata(context.Background(), BscChainID, LoanSupply, params)
+	require.NoError(t, err)
+
+	// and the network is only verified once
+	client.SetNetworkID(EthChainID)
+
+	_, err = venus.GenerateCalldata(context.Background(), BscChainID, LoanSupply, params)
+	require.NoError(t, err)
+}
+
+func TestNetworkCheck_Override(t *testing.T) {
+
+	// a fork of Ethereum whose node reports another network id
+	fork := pkgtest.NewClient(big.NewInt(31337))
+
+	params := TransactionParams{
+		Amount: big.NewInt(1e6),
+		Sender: testAccount,
+		Asset:  testUSDC,
+	}
+
+	aave, err := NewAaveOperation(fork, EthChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	_, err = aave.GenerateCalldata(context.Background(), EthChainID, LoanSupply, params)
+	require.ErrorIs(t, err, ErrNetworkMismatch)
+
+	t.Run("skipped check", func(t *testing.T) {
+		aave, err := NewAaveOperation(fork, EthChainID, AaveProtocolDeploymentEthereum, WithSkipNetworkCheck())
+		require.NoError(t, err)
+
+		_, err = aave.GenerateCalldata(context.Background(), EthChainID, LoanSupply, params)
+		require.NoError(t, err)
+	})
+
+	t.Run("overridden network id", func(t *testing.T) {
+		aave, err := NewAaveOperation(OverrideNetworkID(fork, EthChainID), EthChainID, AaveProtocolDeploymentEthereum)
+		require.NoError(t, err)
+
+		_, err = aave.GenerateCalldata(context.Background(), EthChainID, LoanSupply, params)
+		require.NoError(t, err)
+
+		// one node serves several chain ids
+		venus, err := NewVenusOperation(OverrideNetworkID(fork, BscChainID), BscChainID)
+		require.NoError(t, err)
+
+		params.Asset = common.HexToAddress(nativeDenomAddress)
+
+		_, err = venus.GenerateCalldata(context.Background(), BscChainID, LoanSupply, params)
+		require.NoError(t, err)
+
+		require.Equal(t, EthClient(fork), unwrapClient(OverrideNetworkID(fork, BscChainID)))
+	})
+}
+
+func TestNetworkCheck_SkipEveryOperation(t *testing.T) {
+
+	// a fork whose node reports another network id
+	fork := pkgtest.NewClient(big.NewInt(31337))
+
+	constructors := map[string]func(opts ...NetworkOption) (Protocol, error){
+		"aave": func(opts ...NetworkOption) (Protocol, error) {
+			aaveOpts := make([]AaveOption, 0, len(opts))
+			for _, opt := range opts {
+				aaveOpts = append(aaveOpts, opt)
+			}
+			return NewAaveOperation(fork, EthChainID, AaveProtocolDeploymentEthereum, aaveOpts...)
+		},
+		"lido": func(opts ...NetworkOption) (Protocol, error) {
+			lidoOpts := make([]LidoOption, 0, len(opts))
+			for _, opt := range opts {
+				lidoOpts = append(lidoOpts, opt)
+			}
+			return NewLidoOperation(fork, EthChainID, lidoOpts...)
+		},
+		"erc4626": func(opts ...NetworkOption) (Protocol, error) {
+			vaultOpts := make([]ERC4626Option, 0, len(opts))
+			for _, opt := range opts {
+				vaultOpts = append(vaultOpts, opt)
+			}
+			return NewERC4626Operation(fork, EthChainID, YearnV3USDCVaultAddress, vaultOpts...)
+		},
+		"lista": func(opts ...NetworkOption) (Protocol, error) {
+			return NewListaStakingOperation(fork, BscChainID, opts...)
+		},
+		"lista lending": func(opts ...NetworkOption) (Protocol, error) {
+			return NewListaLendingOperation(fork, BscChainID, opts...)
+		},
+		"venus": func(opts ...NetworkOption) (Protocol, error) {
+			return NewVenusOperation(fork, BscChainID, opts...)
+		},
+		"benqi": func(opts ...NetworkOption) (Protocol, error) {
+			return NewBenqiOperation(fork, AvalancheChainID, opts...)
+		},
+		"eigenlayer": func(opts ...NetworkOption) (Protocol, error) {
+			return NewEigenLayerOperation(fork, EthChainID, opts...)
+		},
+		"pendle": func(opts ...NetworkOption) (Protocol, error) {
+			return NewPendleOperation(fork, EthChainID, []common.Address{testPendleSY}, opts...)
+		},
+		"stargate": func(opts ...NetworkOption) (Protocol, error) {
+			return NewStargateOperation(fork, EthChainID, opts...)
+		},
+		"sdai": func(opts ...NetworkOption) (Protocol, error) {
+			return NewSavingsDAIOperation(fork, EthChainID, opts...)
+		},
+		"spark savings": func(opts ...NetworkOption) (Protocol, error) {
+			return NewSparkSavingsOperation(fork, EthChainID, SavingsUSDSContractAddress, opts...)
+		},
+		"yearn": func(opts ...NetworkOption) (Protocol, error) {
+			return NewYearnOperation(fork, EthChainID, YearnV3USDCVaultAddress, opts...)
+		},
+	}
+
+	for name, newProtocol := range constructors {
+		newProtocol := newProtocol
+
+		t.Run(name, func(t *testing.T) {
+			generate := func(opts ...NetworkOption) error {
+				protocol, err := newProtocol(opts...)
+				require.NoError(t, err)
+
+				chainID := protocol.GetProtocolConfig(nil).ChainID
+				action := protocol.Capabilities().SupportedActions[0]
+
+				_, err = protocol.GenerateCalldata(context.Background(), chainID, action, TransactionParams{
+					Amount: big.NewInt(1e6),
+					Sender: testAccount,
+				})
+				return err
+			}
+
+			require.ErrorIs(t, generate(), ErrNetworkMismatch)
+			require.NotErrorIs(t, generate(WithSkipNetworkCheck()), ErrNetworkMismatch)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Observer is notified of the activity of the protocol operations, e.g to log it
+// or export metrics. It is called synchronously so it must return quickly and be
+// safe for concurrent use
+type Observer interface {
+	// OnCalldataGenerated is called every time GenerateCalldata succeeds
+	OnCalldataGenerated(protocol string, action ContractAction, duration time.Duration)
+	// OnRPCError is called when a node call of the protocol fails, method being
+	// the EthClient method, e.g CallContract
+	OnRPCError(protocol string, method string, err error)
+}
+
+// NopObserver ignores every event. Operations without an observer behave as if
+// they had this one
+type NopObserver struct{}
+
+var _ Observer = NopObserver{}
+
+func (NopObserver) OnCalldataGenerated(string, ContractAction, time.Duration) {}
+
+func (NopObserver) OnRPCError(string, string, error) {}
+
+func (NopObserver) OnWarning(string, error) {}
+
+// WarningObserver is an Observer also notified of the problems the registry
+// finds in its configuration, e.g a protocol registered at the implementation
+// of a proxy with WithProxyCheck. Observers not implementing it are not notified
+type WarningObserver interface {
+	Observer
+	// OnWarning is called with the problem found with protocol
+	OnWarning(protocol string, warning error)
+}
+
+var _ WarningObserver = NopObserver{}
+
+// Observable is implemented by the operations reporting to an Observer.
+// SetObserver must be called before the operation is used
+type Observable interface {
+	SetObserver(observer Observer)
+}
+
+// observeCalldata runs generate and reports the calldata it generates to observer
+func observeCalldata(observer Observer, protocol string, action ContractAction,
+	generate func() (string, error)) (string, error) {
+
+	start := time.Now()
+
+	calldata, err := generate()
+	if err == nil && observer != nil {
+		observer.OnCalldataGenerated(protocol, action, time.Since(start))
+	}
+
+	return calldata, err
+}
+
+// observedClient reports the failed calls of an EthClient to an Observer
+type observedClient struct {
+	EthClient
+
+	protocol string
+	observer Observer
+}
+
+// observeClient wraps client so its failed calls are reported to observer on
+// behalf of protocol. A client observed already is observed by observer instead
+func observeClient(client EthClient, protocol string, observer Observer) EthClient {
+
+	if c, ok := client.(*observedClient); ok {
+		client = c.EthClient
+	}
+
+	if observer == nil || client == nil {
+		return client
+	}
+
+	return &observedClient{
+		EthClient: client,
+		protocol:  protocol,
+		observer:  observer,
+	}
+}
+
+// Unwrap returns the wrapped client
+func (c *observedClient) Unwrap() EthClient { return c.EthClient }
+
+func (c *observedClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	result, err := c.EthClient.CallContract(ctx, msg, blockNumber)
+	c.report("CallContract", err)
+	return result, err
+}
+
+func (c *observedClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	balance, err := c.EthClient.BalanceAt(ctx, account, blockNumber)
+	c.report("BalanceAt", err)
+	return balance, err
+}
+
+func (c *observedClient) NetworkID(ctx context.Context) (*big.Int, error) {
+	networkID, err := c.EthClient.NetworkID(ctx)
+	c.report("NetworkID", err)
+	return networkID, err
+}
+
+func (c *observedClient) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	gas, err := c.EthClient.EstimateGas(ctx, msg)
+	c.report("EstimateGas", err)
+	return gas, err
+}
+
+func (c *observedClient) report(method string, err error) {
+	if err != nil {
+		c.observer.OnRPCError(c.protocol, method, err)
+	}
+}
@@ -0,0 +1,118 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+type calldataEvent struct {
+	protocol string
+	action   ContractAction
+	duration time.Duration
+}
+
+type rpcErrorEvent struct {
+	protocol string
+	method   string
+	err      error
+}
+
+// recordingObserver keeps the events it is notified of
+type recordingObserver struct {
+	mu        sync.Mutex
+	calldata  []calldataEvent
+	rpcErrors []rpcErrorEvent
+	warnings  []error
+}
+
+func (o *recordingObserver) OnCalldataGenerated(protocol string, action ContractAction, duration time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.calldata = append(o.calldata, calldataEvent{protocol: protocol, action: action, duration: duration})
+}
+
+func (o *recordingObserver) OnRPCError(protocol string, method string, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.rpcErrors = append(o.rpcErrors, rpcErrorEvent{protocol: protocol, method: method, err: err})
+}
+
+func (o *recordingObserver) OnWarning(_ string, warning error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.warnings = append(o.warnings, warning)
+}
+
+func TestObserver_LidoStake(t *testing.T) {
+
+	observer := &recordingObserver{}
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+		WithDisabledProtocols(RocketPool, Compound, SparkLend, EigenLayer, Stargate, Yearn, SavingsDAI, SparkSavings),
+		WithObserver(observer))
+	require.NoError(t, err)
+
+	lido, err := registry.GetProtocol(EthChainID, LidoContractAddress)
+	require.NoError(t, err)
+
+	params := TransactionParams{
+		Amount: big.NewInt(1e18),
+		Sender: testAccount,
+		Asset:  common.HexToAddress(nativeDenomAddress),
+	}
+
+	_, err = lido.BuildTransaction(context.Background(), EthChainID, NativeStake, params)
+	require.NoError(t, err)
+
+	// failed generations are not reported
+	_, err = lido.GenerateCalldata(context.Background(), EthChainID, LoanSupply, params)
+	require.ErrorIs(t, err, ErrUnsupportedAction)
+
+	require.Len(t, observer.calldata, 1)
+	require.Equal(t, Lido, observer.calldata[0].protocol)
+	require.Equal(t, NativeStake, observer.calldata[0].action)
+	require.GreaterOrEqual(t, observer.calldata[0].duration, time.Duration(0))
+	require.Empty(t, observer.rpcErrors)
+
+	// nothing answers balanceOf on the mock client
+	_, _, err = lido.GetBalance(context.Background(), EthChainID, testAccount, common.Address{})
+	require.Error(t, err)
+
+	require.Len(t, observer.rpcErrors, 1)
+	require.Equal(t, Lido, observer.rpcErrors[0].protocol)
+	require.Equal(t, "CallContract", observer.rpcErrors[0].method)
+	require.Equal(t, err, observer.rpcErrors[0].err)
+}
+
+func TestObserver_SetTwice(t *testing.T) {
+
+	first, second := &recordingObserver{}, &recordingObserver{}
+
+	venus, err := NewVenusOperation(NewOfflineClient(BscChainID), BscChainID)
+	require.NoError(t, err)
+
+	venus.SetObserver(first)
+	venus.SetObserver(second)
+
+	_, _, err = venus.GetBalance(context.Background(), BscChainID, testAccount, common.Address{})
+	require.ErrorIs(t, err, ErrOffline)
+
+	require.Empty(t, first.rpcErrors)
+	require.Len(t, second.rpcErrors, 1)
+
+	// unwrapping still reaches the client the operation was created with
+	require.Equal(t, NewOfflineClient(BscChainID), unwrapClient(venus.client))
+}
@@ -0,0 +1,84 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// buildCase is a transaction an operation is expected to build from params,
+// or to refuse when fails is set
+type buildCase struct {
+	name    string
+	chainID *big.Int // chain of the test when nil
+	action  ContractAction
+	params  TransactionParams
+
+	to    common.Address // not checked when zero
+	data  string
+	value *big.Int // zero when nil
+
+	fails bool
+	err   error // checked with ErrorIs when set
+}
+
+// runBuildCases builds the transaction of every case on chainID
+func runBuildCases(t *testing.T, op Protocol, chainID *big.Int, cases []buildCase) {
+	t.Helper()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			chain := chainID
+			if tc.chainID != nil {
+				chain = tc.chainID
+			}
+
+			tx, err := op.BuildTransaction(context.Background(), chain, tc.action, tc.params)
+			if tc.fails || tc.err != nil {
+				require.Error(t, err)
+				if tc.err != nil {
+					require.ErrorIs(t, err, tc.err)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tc.to != (common.Address{}) {
+				require.Equal(t, tc.to, tx.To)
+			}
+
+			value := tc.value
+			if value == nil {
+				value = big.NewInt(0)
+			}
+
+			require.Equal(t, tc.data, tx.Data)
+			require.Zero(t, value.Cmp(tx.Value), "value %s", tx.Value)
+		})
+	}
+}
+
+// validateCase is a call to Validate expected to pass when valid is set
+type validateCase struct {
+	action ContractAction
+	params TransactionParams
+	valid  bool
+}
+
+// runValidateCases validates the params of every case on chainID
+func runValidateCases(t *testing.T, op Protocol, chainID *big.Int, cases []validateCase) {
+	t.Helper()
+
+	for i, tc := range cases {
+		err := op.Validate(context.Background(), chainID, tc.action, tc.params)
+		if tc.valid {
+			require.NoError(t, err, "case %d", i)
+		} else {
+			require.Error(t, err, "case %d", i)
+		}
+	}
+}
@@ -0,0 +1,547 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const pendleSYABI = `
+[
+  {
+    "inputs": [
+      { "internalType": "address", "name": "receiver", "type": "address" },
+      { "internalType": "address", "name": "tokenIn", "type": "address" },
+      { "internalType": "uint256", "name": "amountTokenToDeposit", "type": "uint256" },
+      { "internalType": "uint256", "name": "minSharesOut", "type": "uint256" }
+    ],
+    "name": "deposit",
+    "outputs": [{ "internalType": "uint256", "name": "amountSharesOut", "type": "uint256" }],
+    "stateMutability": "payable",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "address", "name": "tokenIn", "type": "address" },
+      { "internalType": "uint256", "name": "amountTokenToDeposit", "type": "uint256" }
+    ],
+    "name": "previewDeposit",
+    "outputs": [{ "internalType": "uint256", "name": "amountSharesOut", "type": "uint256" }],
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "inputs": [],
+    "name": "getTokensIn",
+    "outputs": [{ "internalType": "address[]", "name": "res", "type": "address[]" }],
+    "stateMutability": "view",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "address", "name": "account", "type": "address" }
+    ],
+    "name": "balanceOf",
+    "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }],
+    "stateMutability": "view",
+    "type": "function"
+  }
+]`
+
+const (
+	pendleExtraDataSY        = "sy"
+	pendleExtraDataMinShares = "min_shares"
+)
+
+// PendleOperation wraps base assets into Pendle Standardized Yield (SY) tokens,
+// the entrypoint to the PT/YT markets. The SY token to deposit into is picked
+// with ExtraData["sy"] and must be one of the configured SY tokens.
+// ExtraData["min_out"], or its alias ExtraData["min_shares"], optionally sets the
+// minimum SY shares to receive. Otherwise ExtraData["slippage_bps"] derives it
+// from the shares the SY token previews for the deposit
+// https://pendle.finance
+type PendleOperation struct {
+	contract  common.Address
+	parsedABI abi.ABI
+	chainID   *big.Int
+	client    EthClient
+	observer  Observer
+	metadata  AssetMetadata
+	syTokens  []common.Address
+
+	// verified on first use, see networkCheck
+	network networkCheck
+
+	// disabled through the registry, see SetProtocolEnabled
+	deprecation
+}
+
+var _ Protocol = (*PendleOperation)(nil)
+
+// NewPendleOperation creates a PendleOperation allowed to deposit into syTokens
+func NewPendleOperation(client EthClient, chainID *big.Int,
+	syTokens []common.Address, opts ...NetworkOption) (*PendleOperation, error) {
+
+	if len(syTokens) == 0 {
+		return nil, errors.New("at least one SY token must be configured")
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(pendleSYABI))
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]common.Address, len(syTokens))
+	copy(tokens, syTokens)
+
+	p := &PendleOperation{
+		parsedABI: parsedABI,
+		chainID:   chainID,
+		client:    client,
+		contract:  PendleRouterContractAddress,
+		syTokens:  tokens,
+	}
+
+	for _, opt := range opts {
+		opt(&p.network)
+	}
+
+	return p, nil
+}
+
+// GenerateCalldata creates the necessary blockchain transaction data
+func (p *PendleOperation) GenerateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+	if err := p.deprecation.check(p.GetUniqueKey()); err != nil {
+		return "", err
+	}
+
+	if err := params.checkAmountRange(); err != nil {
+		return "", err
+	}
+
+	return observeCalldata(p.observer, p.GetName(), action, func() (string, error) {
+		return p.generateCalldata(ctx, chainID, action, params)
+	})
+}
+
+func (p *PendleOperation) generateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+
+	if !p.isSupportedChain(chainID) {
+		return "", ErrChainUnsupported
+	}
+
+	if err := p.network.ensure(ctx, p.client, p.chainID); err != nil {
+		return "", err
+	}
+
+	if action != ERC20Stake {
+		return "", ErrUnsupportedAction
+	}
+
+	if params.Amount == nil {
+		return "", ErrAmountRequired
+	}
+
+	sy, err := p.syToken(params)
+	if err != nil {
+		return "", err
+	}
+
+	minShares, err := p.minShares(ctx, sy, params)
+	if err != nil {
+		return "", err
+	}
+
+	calldata, err := p.parsedABI.Pack("deposit",
+		params.GetBeneficiaryOwner(), pendleTokenIn(params.Asset), params.Amount, minShares)
+	if err != nil {
+		return "", err
+	}
+
+	return HexPrefix + hex.EncodeToString(calldata), nil
+}
+
+// EstimateGas estimates the gas needed to execute the generated calldata
+func (p *PendleOperation) EstimateGas(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (uint64, error) {
+
+	tx, err := p.BuildTransaction(ctx, chainID, action, params)
+	if err != nil {
+		return 0, err
+	}
+
+	return estimateGas(ctx, p.client, params.Sender, tx)
+}
+
+// BuildTransaction generates the calldata for the action together with
+// the contract to call and the value to send. The deposit is sent to the SY token
+func (p *PendleOperation) BuildTransaction(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (*Transaction, error) {
+
+	calldata, err := p.GenerateCalldata(ctx, chainID, action, params)
+	if err != nil {
+		return nil, err
+	}
+
+	sy, err := p.syToken(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{
+		To:    sy,
+		Data:  calldata,
+		Value: p.CallValue(action, params),
+	}, nil
+}
+
+// Validate checks if the provided parameters are valid for the specified action
+func (p *PendleOperation) Validate(ctx context.Context,
+	chainID *big.Int, action ContractAction, params TransactionParams) error {
+
+	if err := p.deprecation.check(p.GetUniqueKey()); err != nil {
+		return err
+	}
+
+	if !p.isSupportedChain(chainID) {
+		return ErrChainUnsupported
+	}
+
+	if err := p.network.ensure(ctx, p.client, p.chainID); err != nil {
+		return err
+	}
+
+	if action != ERC20Stake {
+		return ErrUnsupportedAction
+	}
+
+	sy, err := p.syToken(params)
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.minShares(ctx, sy, params); err != nil {
+		return err
+	}
+
+	if err := params.ValidateAmount(); err != nil {
+		return err
+	}
+
+	if err := checkAmountPrecision(ctx, p.client, p.metadata, p.chainID, params, params.Asset); err != nil {
+		return err
+	}
+
+	tokensIn, err := p.tokensIn(ctx, sy)
+	if err != nil {
+		return err
+	}
+
+	if !containsAddress(tokensIn, params.Asset) {
+		return fmt.Errorf("asset %s can not be deposited into %s", params.Asset, sy)
+	}
+
+	// the SY token pulls the ERC20 from the sender
+	if !IsNativeToken(params.Asset) {
+		if err := checkAllowance(ctx, p.client, params, params.Asset, sy, params.Amount); err != nil {
+			return err
+		}
+	}
+
+	if params.lenientValidation(ValidationStrict) {
+		return nil
+	}
+
+	var balance *big.Int
+	if IsNativeToken(params.Asset) {
+		balance, err = p.client.BalanceAt(ctx, params.Sender, nil)
+	} else {
+		balance, err = p.balanceOf(ctx, params.Asset, params.Sender)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if balance.Cmp(params.Amount) < 0 {
+		return ErrInsufficientBalance
+	}
+
+	return nil
+}
+
+// GetBalance retrieves the balance of account in the SY token asset
+func (p *PendleOperation) GetBalance(ctx context.Context, chainID *big.Int,
+	account, asset common.Address) (common.Address, *big.Int, error) {
+
+	if !p.isSupportedChain(chainID) {
+		return common.Address{}, nil, ErrChainUnsupported
+	}
+
+	if !containsAddress(p.syTokens, asset) {
+		return common.Address{}, nil, fmt.Errorf("SY token not allowed %s", asset)
+	}
+
+	balance, err := p.balanceOf(ctx, asset, account)
+	return asset, balance, err
+}
+
+// GetSupportedAssets returns the tokens accepted by any of the configured SY tokens
+func (p *PendleOperation) GetSupportedAssets(ctx context.Context, chainID *big.Int) ([]common.Address, error) {
+	if !p.isSupportedChain(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	var assets []common.Address
+
+	for _, sy := range p.syTokens {
+		tokensIn, err := p.tokensIn(ctx, sy)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, token := range tokensIn {
+			if !containsAddress(assets, token) {
+				assets = append(assets, token)
+			}
+		}
+	}
+
+	return assets, nil
+}
+
+func (p *PendleOperation) isSupportedChain(chain *big.Int) bool {
+	return p.chainID.Cmp(chain) == 0
+}
+
+// IsSupportedAsset checks if the specified asset is supported on the given chain
+func (p *PendleOperation) IsSupportedAsset(ctx context.Context, chainID *big.Int, asset common.Address) bool {
+	assets, err := p.GetSupportedAssets(ctx, chainID)
+	if err != nil {
+		return false
+	}
+
+	return containsAddress(assets, asset)
+}
+
+// syToken returns the SY token selected by params
+func (p *PendleOperation) syToken(params TransactionParams) (common.Address, error) {
+
+	value, ok := params.ExtraData[pendleExtraDataSY]
+	if !ok {
+		return common.Address{}, errors.New("sy must be provided in extra data")
+	}
+
+	sy, err := toAddress(value)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid sy: %w", err)
+	}
+
+	if !containsAddress(p.syTokens, sy) {
+		return common.Address{}, fmt.Errorf("SY token not allowed %s", sy)
+	}
+
+	return sy, nil
+}
+
+// minShares returns the minimum SY shares to receive, 0 if not provided
+func (p *PendleOperation) minShares(ctx context.Context, sy common.Address,
+	params TransactionParams) (*big.Int, error) {
+
+	_, hasMinOut := params.ExtraData[extraDataMinOut]
+
+	if value, ok := params.ExtraData[pendleExtraDataMinShares]; ok && !hasMinOut {
+		minShares, err := toBigInt(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min_shares: %w", err)
+		}
+
+		return minShares, nil
+	}
+
+	return minOutput(params, func() (*big.Int, error) {
+		return p.previewDeposit(ctx, sy, params)
+	})
+}
+
+// previewDeposit returns the SY shares sy mints for the deposit of params
+func (p *PendleOperation) previewDeposit(ctx context.Context, sy common.Address,
+	params TransactionParams) (*big.Int, error) {
+
+	if params.Amount == nil {
+		return nil, ErrAmountRequired
+	}
+
+	calldata, err := p.parsedABI.Pack("previewDeposit", pendleTokenIn(params.Asset), params.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := p.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &sy,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := new(big.Int)
+	err = p.parsedABI.UnpackIntoInterface(&shares, "previewDeposit", result)
+	return shares, err
+}
+
+// tokensIn returns the tokens sy accepts. The native token is reported
+// with the native denom address
+func (p *PendleOperation) tokensIn(ctx context.Context, sy common.Address) ([]common.Address, error) {
+
+	calldata, err := p.parsedABI.Pack("getTokensIn")
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := p.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &sy,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []common.Address
+	if err := p.parsedABI.UnpackIntoInterface(&tokens, "getTokensIn", result); err != nil {
+		return nil, err
+	}
+
+	for i, token := range tokens {
+		if token == (common.Address{}) {
+			tokens[i] = common.HexToAddress(nativeDenomAddress)
+		}
+	}
+
+	return tokens, nil
+}
+
+func (p *PendleOperation) balanceOf(ctx context.Context, token, account common.Address) (*big.Int, error) {
+
+	calldata, err := p.parsedABI.Pack("balanceOf", account)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := p.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &token,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := new(big.Int)
+	err = p.parsedABI.UnpackIntoInterface(&balance, "balanceOf", result)
+	return balance, err
+}
+
+// pendleTokenIn maps the native denom to the zero address SY tokens use for it
+func pendleTokenIn(asset common.Address) common.Address {
+	if IsNativeToken(asset) {
+		return common.Address{}
+	}
+
+	return asset
+}
+
+func containsAddress(addresses []common.Address, address common.Address) bool {
+	for _, v := range addresses {
+		if v == address {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetProtocolConfig returns the protocol config for a specific chain.
+// The ABI is the one of the SY tokens, Contract is left empty as no single
+// contract receives the deposits
+func (p *PendleOperation) GetProtocolConfig(chainID *big.Int) ProtocolConfig {
+	return ProtocolConfig{
+		ChainID: p.chainID,
+		ABI:     p.parsedABI,
+		Type:    TypeVault,
+	}
+}
+
+// GetABI returns the ABI of the protocol's contract
+func (p *PendleOperation) GetABI(chainID *big.Int) abi.ABI { return p.parsedABI }
+
+// GetType returns the protocol type
+func (p *PendleOperation) GetType() ProtocolType { return TypeVault }
+
+// GetContractAddress returns the address identifying Pendle in the registry.
+// Deposits are sent to the SY token selected in ExtraData
+func (p *PendleOperation) GetContractAddress(chainID *big.Int) common.Address {
+	return p.contract
+}
+
+// GetContractAddressForAction always fails for the supported actions, deposits
+// are sent to the SY token selected in ExtraData which only BuildTransaction resolves
+func (p *PendleOperation) GetContractAddressForAction(chainID *big.Int, action ContractAction) (common.Address, error) {
+	if !p.IsSupportedAction(action) {
+		return common.Address{}, ErrUnsupportedAction
+	}
+
+	return common.Address{}, errors.New("the SY token receiving the deposit is selected in extra data, use BuildTransaction")
+}
+
+// Name returns the human readable name for the protocol
+func (p *PendleOperation) GetName() string { return Pendle }
+
+// GetVersion returns the version of the protocol
+func (p *PendleOperation) GetVersion() string { return "1" }
+
+// GetUniqueKey returns the key identifying the protocol across chains
+func (p *PendleOperation) GetUniqueKey() string { return protocolKey(p.GetName(), p.chainID) }
+
+// CallValue returns the native amount to send along with the calldata.
+// Depositing the native token sends it as msg.value
+func (p *PendleOperation) CallValue(action ContractAction, params TransactionParams) *big.Int {
+	if action == ERC20Stake && IsNativeToken(params.Asset) {
+		return params.nativeCallValue()
+	}
+
+	return big.NewInt(0)
+}
+
+// Capabilities describes the actions, chains and extra data supported by the protocol
+func (p *PendleOperation) Capabilities() ProtocolCapabilities {
+	return ProtocolCapabilities{
+		SupportedActions:  []ContractAction{ERC20Stake},
+		SupportedChains:   []*big.Int{p.chainID},
+		RequiresExtraData: []string{pendleExtraDataSY},
+	}
+}
+
+// IsSupportedAction reports whether calldata can be generated for action
+func (p *PendleOperation) IsSupportedAction(action ContractAction) bool {
+	return p.Capabilities().Supports(action)
+}
+
+// SetObserver implements Observable
+func (p *PendleOperation) SetObserver(observer Observer) {
+	p.observer = observer
+	p.client = observeClient(p.client, p.GetName(), observer)
+}
+
+// SetAssetMetadata implements AssetMetadataUser
+func (p *PendleOperation) SetAssetMetadata(metadata AssetMetadata) {
+	p.metadata = metadata
+}
@@ -0,0 +1,182 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testPendleSY     = common.HexToAddress("0xcbC72d92b2dc8187414F6734718563898740C0BC")
+	testPendleWstETH = common.HexToAddress("0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0")
+)
+
+func newTestPendleOperation(t *testing.T, eth, wstETH, shares *big.Int) *PendleOperation {
+	t.Helper()
+
+	client := pkgtest.NewClient(EthChainID)
+	client.SetBalance(testAccount, eth)
+
+	pendle, err := NewPendleOperation(client, EthChainID, []common.Address{testPendleSY})
+	require.NoError(t, err)
+
+	client.HandleReturns(testPendleSY, pendle.parsedABI.Methods["getTokensIn"], []common.Address{{}, testPendleWstETH})
+	client.HandleReturns(testPendleSY, pendle.parsedABI.Methods["previewDeposit"], big.NewInt(2e18))
+
+	balanceOf := pendle.parsedABI.Methods["balanceOf"]
+	client.HandleReturns(testPendleWstETH, balanceOf, wstETH)
+	client.HandleReturns(testPendleSY, balanceOf, shares)
+
+	return pendle
+}
+
+func TestPendle_BuildTransaction(t *testing.T) {
+
+	deposit := func(asset common.Address, extraData map[string]interface{}) TransactionParams {
+		return TransactionParams{
+			Amount:    big.NewInt(1e18),
+			Sender:    testAccount,
+			Asset:     asset,
+			ExtraData: extraData,
+		}
+	}
+
+	runBuildCases(t, newTestPendleOperation(t, big.NewInt(0), big.NewInt(0), big.NewInt(0)), EthChainID, []buildCase{
+		{
+			name:   "erc20 deposit into the SY token",
+			action: ERC20Stake,
+			params: deposit(testPendleWstETH, map[string]interface{}{
+				"sy":         testPendleSY.Hex(),
+				"min_shares": "990000000000000000",
+			}),
+			to: testPendleSY,
+			data: "0x20e8c565" +
+				"0000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d007" + // receiver
+				"0000000000000000000000007f39c581f595b53c5cb19bd0b3f8da6c935e2ca0" + // tokenIn
+				"0000000000000000000000000000000000000000000000000de0b6b3a7640000" + // amountTokenToDeposit
+				"0000000000000000000000000000000000000000000000000dbd2fc137a30000", // minSharesOut
+		},
+		{
+			// previewDeposit answers 2e18 shares
+			name:   "min shares derived from slippage",
+			action: ERC20Stake,
+			params: deposit(testPendleWstETH, map[string]interface{}{
+				"sy":           testPendleSY.Hex(),
+				"slippage_bps": 50,
+			}),
+			to: testPendleSY,
+			data: "0x20e8c565" +
+				"0000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d007" + // receiver
+				"0000000000000000000000007f39c581f595b53c5cb19bd0b3f8da6c935e2ca0" + // tokenIn
+				"0000000000000000000000000000000000000000000000000de0b6b3a7640000" + // amountTokenToDeposit
+				"0000000000000000000000000000000000000000000000001b9de674df070000", // minSharesOut
+		},
+		{
+			name:   "native deposit uses the zero address",
+			action: ERC20Stake,
+			params: deposit(common.HexToAddress(nativeDenomAddress), map[string]interface{}{"sy": testPendleSY}),
+			to:     testPendleSY,
+			data: "0x20e8c565" +
+				"0000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d007" + // receiver
+				"0000000000000000000000000000000000000000000000000000000000000000" + // tokenIn
+				"0000000000000000000000000000000000000000000000000de0b6b3a7640000" + // amountTokenToDeposit
+				"0000000000000000000000000000000000000000000000000000000000000000", // minSharesOut
+			value: big.NewInt(1e18),
+		},
+		{
+			name:   "SY not allowed",
+			action: ERC20Stake,
+			params: deposit(testPendleWstETH, map[string]interface{}{"sy": testAccount.Hex()}),
+			fails:  true,
+		},
+		{
+			name:   "missing SY",
+			action: ERC20Stake,
+			params: deposit(testPendleWstETH, nil),
+			fails:  true,
+		},
+		{
+			name:   "unsupported action",
+			action: ERC20UnStake,
+			params: deposit(testPendleWstETH, map[string]interface{}{"sy": testPendleSY}),
+			fails:  true,
+		},
+	})
+}
+
+func TestPendle_ContractAddress(t *testing.T) {
+
+	pendle := newTestPendleOperation(t, big.NewInt(0), big.NewInt(0), big.NewInt(0))
+
+	// the router never receives the SY deposit calldata
+	_, err := pendle.GetContractAddressForAction(EthChainID, ERC20Stake)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrUnsupportedAction)
+
+	_, err = pendle.GetContractAddressForAction(EthChainID, ERC20UnStake)
+	require.ErrorIs(t, err, ErrUnsupportedAction)
+
+	config := pendle.GetProtocolConfig(EthChainID)
+	require.Zero(t, config.Contract)
+	require.Contains(t, config.ABI.Methods, "deposit")
+}
+
+func TestPendle_Validate(t *testing.T) {
+
+	params := func(asset common.Address, amount int64) TransactionParams {
+		return TransactionParams{
+			Amount:    big.NewInt(amount),
+			Sender:    testAccount,
+			Asset:     asset,
+			ExtraData: map[string]interface{}{"sy": testPendleSY},
+		}
+	}
+
+	native := common.HexToAddress(nativeDenomAddress)
+	pendle := newTestPendleOperation(t, big.NewInt(100), big.NewInt(50), big.NewInt(10))
+
+	runValidateCases(t, pendle, EthChainID, []validateCase{
+		{action: ERC20Stake, params: params(testPendleWstETH, 50), valid: true},
+		{action: ERC20Stake, params: params(testPendleWstETH, 51)},
+		{action: ERC20Stake, params: params(native, 100), valid: true},
+		{action: ERC20Stake, params: params(native, 101)},
+		// not accepted by the SY token
+		{action: ERC20Stake, params: params(testUSDC, 1)},
+	})
+
+	t.Run("allowance of the SY token", func(t *testing.T) {
+		handleAllowance(t, pendle.client.(*pkgtest.Client), testPendleWstETH, big.NewInt(20))
+
+		allowance := func(asset common.Address, amount int64) TransactionParams {
+			p := params(asset, amount)
+			p.ExtraData["check_allowance"] = true
+			return p
+		}
+
+		require.NoError(t, pendle.Validate(context.Background(), EthChainID, ERC20Stake, allowance(testPendleWstETH, 20)))
+
+		err := pendle.Validate(context.Background(), EthChainID, ERC20Stake, allowance(testPendleWstETH, 21))
+		require.ErrorIs(t, err, ErrInsufficientAllowance)
+
+		var allowanceErr *AllowanceError
+		require.True(t, errors.As(err, &allowanceErr))
+		require.Equal(t, testPendleSY, allowanceErr.Spender)
+
+		// native deposits are sent as value
+		require.NoError(t, pendle.Validate(context.Background(), EthChainID, ERC20Stake, allowance(native, 100)))
+	})
+
+	assets, err := pendle.GetSupportedAssets(context.Background(), EthChainID)
+	require.NoError(t, err)
+	require.Equal(t, []common.Address{native, testPendleWstETH}, assets)
+
+	token, balance, err := pendle.GetBalance(context.Background(), EthChainID, testAccount, testPendleSY)
+	require.NoError(t, err)
+	require.Equal(t, testPendleSY, token)
+	require.Equal(t, big.NewInt(10), balance)
+}
@@ -0,0 +1,231 @@
+// Package pkgtest provides test doubles for the protocol registry so operations
+// can be exercised without a live RPC endpoint
+package pkgtest
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CallHandler answers a contract call with the ABI encoded result
+type CallHandler func(msg ethereum.CallMsg) ([]byte, error)
+
+// Returns builds a CallHandler that always answers with the packed outputs of method
+func Returns(method abi.Method, values ...interface{}) CallHandler {
+	return func(ethereum.CallMsg) ([]byte, error) {
+		return method.Outputs.Pack(values...)
+	}
+}
+
+// Client is an in-memory implementation of pkg.EthClient.
+// Contract calls are routed by the target contract and the 4 byte selector
+// of the calldata, and every call is recorded so tests can assert on RPC traffic
+type Client struct {
+	mu sync.Mutex
+
+	networkID *big.Int
+	balances  map[common.Address]*big.Int
+	storage   map[common.Address]map[common.Hash]common.Hash
+
+	handlers         map[string]CallHandler
+	selectorHandlers map[string]CallHandler
+	fallback         CallHandler
+	gasHandler       func(msg ethereum.CallMsg) (uint64, error)
+
+	calls        []ethereum.CallMsg
+	estimateMsgs []ethereum.CallMsg
+}
+
+// NewClient creates a mock client reporting networkID from NetworkID
+func NewClient(networkID *big.Int) *Client {
+	return &Client{
+		networkID:        networkID,
+		balances:         make(map[common.Address]*big.Int),
+		storage:          make(map[common.Address]map[common.Hash]common.Hash),
+		handlers:         make(map[string]CallHandler),
+		selectorHandlers: make(map[string]CallHandler),
+	}
+}
+
+// SetNetworkID changes the network id reported by the client
+func (c *Client) SetNetworkID(networkID *big.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.networkID = networkID
+}
+
+// SetBalance sets the native balance returned by BalanceAt for account
+func (c *Client) SetBalance(account common.Address, balance *big.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.balances[account] = balance
+}
+
+// SetStorage sets the value StorageAt returns for key of account
+func (c *Client) SetStorage(account common.Address, key, value common.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.storage[account] == nil {
+		c.storage[account] = make(map[common.Hash]common.Hash)
+	}
+
+	c.storage[account][key] = value
+}
+
+// HandleContract routes calls to the contract whose calldata starts with selector to h
+func (c *Client) HandleContract(to common.Address, selector []byte, h CallHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.handlers[handlerKey(to, selector)] = h
+}
+
+// HandleReturns answers the calls of method to the contract with the packed values
+func (c *Client) HandleReturns(to common.Address, method abi.Method, values ...interface{}) {
+	c.HandleContract(to, method.ID, Returns(method, values...))
+}
+
+// Handle routes calls whose calldata starts with selector to h, whatever the target contract
+func (c *Client) Handle(selector []byte, h CallHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.selectorHandlers[hex.EncodeToString(selector)] = h
+}
+
+// HandleFunc routes every call that has no more specific handler to h
+func (c *Client) HandleFunc(h CallHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fallback = h
+}
+
+// HandleEstimateGas makes EstimateGas answer with h
+func (c *Client) HandleEstimateGas(h func(msg ethereum.CallMsg) (uint64, error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gasHandler = h
+}
+
+// EstimateGasCalls returns every message passed to EstimateGas so far
+func (c *Client) EstimateGasCalls() []ethereum.CallMsg {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]ethereum.CallMsg(nil), c.estimateMsgs...)
+}
+
+// Calls returns every contract call received so far
+func (c *Client) Calls() []ethereum.CallMsg {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]ethereum.CallMsg(nil), c.calls...)
+}
+
+// CallCount returns how many contract calls were received so far
+func (c *Client) CallCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.calls)
+}
+
+// CallContract implements pkg.EthClient
+func (c *Client) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	c.mu.Lock()
+	c.calls = append(c.calls, msg)
+	h := c.handlerFor(msg)
+	c.mu.Unlock()
+
+	if h == nil {
+		return nil, fmt.Errorf("pkgtest: no handler for call to %s with data %x", toHex(msg.To), msg.Data)
+	}
+
+	return h(msg)
+}
+
+// BalanceAt implements pkg.EthClient
+func (c *Client) BalanceAt(_ context.Context, account common.Address, _ *big.Int) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if balance, ok := c.balances[account]; ok {
+		return new(big.Int).Set(balance), nil
+	}
+
+	return big.NewInt(0), nil
+}
+
+// StorageAt implements pkg.StorageReader, unset slots are zero
+func (c *Client) StorageAt(_ context.Context, account common.Address, key common.Hash, _ *big.Int) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value := c.storage[account][key]
+	return value.Bytes(), nil
+}
+
+// NetworkID implements pkg.EthClient
+func (c *Client) NetworkID(_ context.Context) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return new(big.Int).Set(c.networkID), nil
+}
+
+// EstimateGas implements pkg.EthClient
+func (c *Client) EstimateGas(_ context.Context, msg ethereum.CallMsg) (uint64, error) {
+	c.mu.Lock()
+	c.estimateMsgs = append(c.estimateMsgs, msg)
+	h := c.gasHandler
+	c.mu.Unlock()
+
+	if h == nil {
+		return 0, fmt.Errorf("pkgtest: no gas handler for call to %s", toHex(msg.To))
+	}
+
+	return h(msg)
+}
+
+func (c *Client) handlerFor(msg ethereum.CallMsg) CallHandler {
+	if len(msg.Data) >= 4 {
+		selector := msg.Data[:4]
+
+		if msg.To != nil {
+			if h, ok := c.handlers[handlerKey(*msg.To, selector)]; ok {
+				return h
+			}
+		}
+
+		if h, ok := c.selectorHandlers[hex.EncodeToString(selector)]; ok {
+			return h
+		}
+	}
+
+	return c.fallback
+}
+
+func handlerKey(to common.Address, selector []byte) string {
+	return to.Hex() + ":" + hex.EncodeToString(selector)
+}
+
+func toHex(addr *common.Address) string {
+	if addr == nil {
+		return "<nil>"
+	}
+
+	return addr.Hex()
+}
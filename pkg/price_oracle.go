@@ -0,0 +1,170 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// USDValuer is implemented by lending protocols that can value the balance of
+// an account in USD. Values have 8 decimals, like the Aave oracle prices
+type USDValuer interface {
+	// GetBalanceUSD returns the USD value of the balance account holds in asset
+	GetBalanceUSD(ctx context.Context, chainID *big.Int, account, asset common.Address) (*big.Int, error)
+}
+
+var (
+	_ USDValuer = (*AaveOperation)(nil)
+	_ USDValuer = (*CompoundOperation)(nil)
+)
+
+// PriceOracle reads USD prices with 8 decimals from an Aave oracle.
+// The Aave V3 oracle aggregates the Chainlink feeds of every reserve
+type PriceOracle struct {
+	oracle    common.Address
+	oracleABI abi.ABI
+	erc20ABI  abi.ABI
+	client    EthClient
+}
+
+func NewPriceOracle(client EthClient, oracle common.Address) (*PriceOracle, error) {
+
+	oracleABI, err := abi.JSON(strings.NewReader(aaveOracleABI))
+	if err != nil {
+		return nil, err
+	}
+
+	erc20ABI, err := abi.JSON(strings.NewReader(erc20MetadataABI))
+	if err != nil {
+		return nil, err
+	}
+
+	return &PriceOracle{
+		oracle:    oracle,
+		oracleABI: oracleABI,
+		erc20ABI:  erc20ABI,
+		client:    client,
+	}, nil
+}
+
+// GetAssetPrice returns the USD price of one unit of asset with 8 decimals
+func (p *PriceOracle) GetAssetPrice(ctx context.Context, asset common.Address) (*big.Int, error) {
+
+	calldata, err := p.oracleABI.Pack("getAssetPrice", asset)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := p.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &p.oracle,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	price := new(big.Int)
+	err = p.oracleABI.UnpackIntoInterface(&price, "getAssetPrice", result)
+	return price, err
+}
+
+// GetValueUSD returns the USD value of amount of asset with 8 decimals
+func (p *PriceOracle) GetValueUSD(ctx context.Context, asset common.Address, amount *big.Int) (*big.Int, error) {
+
+	price, err := p.GetAssetPrice(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	decimals, err := p.decimals(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	return usdValue(amount, price, big.NewInt(int64(decimals))), nil
+}
+
+func (p *PriceOracle) decimals(ctx context.Context, token common.Address) (uint8, error) {
+
+	calldata, err := p.erc20ABI.Pack("decimals")
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := p.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &token,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("could not fetch the decimals of %s: %w", token, err)
+	}
+
+	var decimals uint8
+	err = p.erc20ABI.UnpackIntoInterface(&decimals, "decimals", result)
+	return decimals, err
+}
+
+// usdValue values amount of a token with decimals at price
+func usdValue(amount, price, decimals *big.Int) *big.Int {
+	value := new(big.Int).Mul(amount, price)
+	return value.Quo(value, new(big.Int).Exp(big.NewInt(10), decimals, nil))
+}
+
+// GetBalanceUSD returns the USD value of the balance account supplied in asset,
+// priced by the oracle registered in the addresses provider of the deployment
+func (l *AaveOperation) GetBalanceUSD(ctx context.Context, chainID *big.Int,
+	account, asset common.Address) (*big.Int, error) {
+
+	_, balance, err := l.GetBalance(ctx, chainID, account, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	reserve := l.reserveAsset(asset)
+
+	config, err := l.getReserveConfiguration(ctx, reserve)
+	if err != nil {
+		return nil, err
+	}
+
+	price, err := l.getAssetPrice(ctx, reserve)
+	if err != nil {
+		return nil, err
+	}
+
+	return usdValue(balance, price, config.Decimals), nil
+}
+
+// GetBalanceUSD returns the USD value of the collateral account supplied in asset,
+// priced by the feed the market uses for the asset. Only the markets with USD
+// denominated feeds can be valued
+func (l *CompoundOperation) GetBalanceUSD(ctx context.Context, chainID *big.Int,
+	account, asset common.Address) (*big.Int, error) {
+
+	if !compoundUSDMarkets[l.contract] {
+		return nil, fmt.Errorf("the prices of the %s market are not denominated in USD", l.contract)
+	}
+
+	_, balance, err := l.GetBalance(ctx, chainID, account, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	assetInfo, err := l.getAssetInfo(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	price, err := l.getPrice(ctx, assetInfo.PriceFeed)
+	if err != nil {
+		return nil, err
+	}
+
+	value := new(big.Int).Mul(balance, price)
+	return value.Quo(value, new(big.Int).SetUint64(assetInfo.Scale)), nil
+}
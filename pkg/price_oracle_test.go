@@ -0,0 +1,71 @@
+//go:build integration
+// +build integration
+
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceOracle_GetAssetPrice(t *testing.T) {
+
+	oracle, err := NewPriceOracle(getTestClient(t, ChainETH), AaveOracleEthereumAddress)
+	require.NoError(t, err)
+
+	t.Run("USDC", func(t *testing.T) {
+		usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+
+		price, err := oracle.GetAssetPrice(context.Background(), usdc)
+		require.NoError(t, err)
+
+		// pegged to 1 USD with 8 decimals
+		require.True(t, price.Cmp(big.NewInt(95_000_000)) > 0)
+		require.True(t, price.Cmp(big.NewInt(105_000_000)) < 0)
+
+		value, err := oracle.GetValueUSD(context.Background(), usdc, big.NewInt(1e6))
+		require.NoError(t, err)
+		require.Equal(t, price, value)
+	})
+
+	t.Run("WBTC", func(t *testing.T) {
+		wbtc := common.HexToAddress("0x2260FAC5E5542a773Aa44fBCfeDf7C193bc2C599")
+
+		price, err := oracle.GetAssetPrice(context.Background(), wbtc)
+		require.NoError(t, err)
+		require.True(t, price.Cmp(new(big.Int).Mul(big.NewInt(10_000), big.NewInt(1e8))) > 0)
+
+		// half a bitcoin
+		value, err := oracle.GetValueUSD(context.Background(), wbtc, big.NewInt(5e7))
+		require.NoError(t, err)
+		require.Equal(t, new(big.Int).Quo(price, big.NewInt(2)), value)
+	})
+}
+
+func TestAave_GetBalanceUSD(t *testing.T) {
+
+	aave, err := NewAaveOperation(getTestClient(t, ChainETH), EthChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+
+	value, err := aave.GetBalanceUSD(context.Background(), EthChainID, common.HexToAddress("0x000000000000000000000000000000000000dEaD"), usdc)
+	require.NoError(t, err)
+	require.NotNil(t, value)
+}
+
+func TestCompound_GetBalanceUSD(t *testing.T) {
+
+	compound, err := NewCompoundOperation(getTestClient(t, ChainETH), EthChainID, common.HexToAddress(CompoundV3USDCPool))
+	require.NoError(t, err)
+
+	wbtc := common.HexToAddress("0x2260FAC5E5542a773Aa44fBCfeDf7C193bc2C599")
+
+	value, err := compound.GetBalanceUSD(context.Background(), EthChainID, common.HexToAddress("0x000000000000000000000000000000000000dEaD"), wbtc)
+	require.NoError(t, err)
+	require.NotNil(t, value)
+}
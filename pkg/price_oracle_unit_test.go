@@ -0,0 +1,84 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceOracle_GetValueUSD_Unit(t *testing.T) {
+
+	wbtc := common.HexToAddress("0x2260FAC5E5542a773Aa44fBCfeDf7C193bc2C599")
+
+	client := pkgtest.NewClient(EthChainID)
+
+	oracle, err := NewPriceOracle(client, AaveOracleEthereumAddress)
+	require.NoError(t, err)
+
+	method := oracle.oracleABI.Methods["getAssetPrice"]
+	client.HandleContract(AaveOracleEthereumAddress, method.ID,
+		pkgtest.Returns(method, new(big.Int).Mul(big.NewInt(60_000), big.NewInt(1e8))))
+
+	method = oracle.erc20ABI.Methods["decimals"]
+	client.HandleContract(wbtc, method.ID, pkgtest.Returns(method, uint8(8)))
+
+	// half a bitcoin
+	value, err := oracle.GetValueUSD(context.Background(), wbtc, big.NewInt(5e7))
+	require.NoError(t, err)
+	require.Zero(t, value.Cmp(new(big.Int).Mul(big.NewInt(30_000), big.NewInt(1e8))))
+
+	_, err = oracle.GetValueUSD(context.Background(), testUSDC, big.NewInt(1e6))
+	require.Error(t, err)
+}
+
+func TestAave_GetBalanceUSD_Unit(t *testing.T) {
+
+	aave := newLeveragedAaveOperation(t, big.NewInt(0))
+
+	// 1000 USDC priced at 1 USD
+	value, err := aave.GetBalanceUSD(context.Background(), EthChainID, testAccount, testUSDC)
+	require.NoError(t, err)
+	require.Zero(t, value.Cmp(new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e8))))
+}
+
+func TestCompound_GetBalanceUSD_Unit(t *testing.T) {
+
+	wbtc := common.HexToAddress("0x2260FAC5E5542a773Aa44fBCfeDf7C193bc2C599")
+
+	client := newCompoundMarket(t, wbtc).client()
+
+	compound, err := NewCompoundOperation(client, EthChainID, common.HexToAddress(CompoundV3USDCPool))
+	require.NoError(t, err)
+
+	market := common.HexToAddress(CompoundV3USDCPool)
+
+	method := compound.parsedABI.Methods["userCollateral"]
+	client.HandleContract(market, method.ID, pkgtest.Returns(method, big.NewInt(5e7), big.NewInt(0)))
+
+	// the fake market has wbtc priced by the zero feed with a scale of 1e8
+	method = compound.parsedABI.Methods["getPrice"]
+	client.HandleContract(market, method.ID, func(msg ethereum.CallMsg) ([]byte, error) {
+		args, err := method.Inputs.Unpack(msg.Data[4:])
+		require.NoError(t, err)
+		require.Equal(t, common.Address{}, args[0])
+
+		return method.Outputs.Pack(new(big.Int).Mul(big.NewInt(60_000), big.NewInt(1e8)))
+	})
+
+	// half a bitcoin
+	value, err := compound.GetBalanceUSD(context.Background(), EthChainID, testAccount, wbtc)
+	require.NoError(t, err)
+	require.Zero(t, value.Cmp(new(big.Int).Mul(big.NewInt(30_000), big.NewInt(1e8))))
+
+	// the feeds of the WETH market are priced in ETH
+	weth, err := NewCompoundOperation(client, EthChainID, common.HexToAddress(CompoundV3ETHPool))
+	require.NoError(t, err)
+
+	_, err = weth.GetBalanceUSD(context.Background(), EthChainID, testAccount, wbtc)
+	require.Error(t, err)
+}
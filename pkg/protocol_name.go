@@ -0,0 +1,119 @@
+package pkg
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// protocolNames are the protocol names keyed by their compact form, see compactProtocolName
+var protocolNames = map[string]ProtocolName{}
+
+// protocolNameAliases are the names other registries use which do not compact
+// to a protocol name, e.g the pools of the tokens JSON files
+var protocolNameAliases = map[string]ProtocolName{
+	"compoundethpool":  Compound,
+	"compoundusdcpool": Compound,
+	"compoundv3":       Compound,
+	"yearn":            Yearn,
+	"sdai":             SavingsDAI,
+	"susds":            SparkSavings,
+	"spark":            SparkLend,
+	"aave":             AaveV3,
+	"lista":            ListaDao,
+}
+
+func init() {
+	for _, name := range []ProtocolName{
+		AaveV3, SparkLend, Lido, RocketPool, Ankr, Renzo, Compound, ListaDao,
+		ListaDaoLending, AvalonFinance, Venus, Benqi, Pendle, EigenLayer, Stargate,
+		Yearn, ERC4626, SavingsDAI, SparkSavings, Radiant,
+	} {
+		protocolNames[compactProtocolName(name)] = name
+	}
+}
+
+// compactProtocolName lowercases name and drops everything but letters and
+// digits, so AaveV3, aave_v3 and Aave V3 compact the same
+func compactProtocolName(name string) string {
+	var b strings.Builder
+
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// NormalizeProtocolName maps the name a protocol goes by elsewhere, e.g AaveV3
+// or SparkLend in the tokens package, to the ProtocolName of the registry.
+// Unknown names are converted to snake case, FooBar becomes foo_bar
+func NormalizeProtocolName(name string) ProtocolName {
+
+	compact := compactProtocolName(name)
+
+	if protocol, ok := protocolNames[compact]; ok {
+		return protocol
+	}
+
+	if protocol, ok := protocolNameAliases[compact]; ok {
+		return protocol
+	}
+
+	return toSnakeCase(strings.TrimSpace(name))
+}
+
+// toSnakeCase lowercases name, separating its words with underscores
+func toSnakeCase(name string) string {
+	var b strings.Builder
+
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == ' ' || r == '-' || r == '_':
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+		case unicode.IsUpper(r):
+			if i > 0 && b.Len() > 0 && !strings.HasSuffix(b.String(), "_") &&
+				(unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteRune('_')
+			}
+
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// GetProtocolByRegistryName retrieves the protocol registered on chainID under
+// the name the tokens registry lists it with, see NormalizeProtocolName. Names
+// registered at several addresses, e.g Compound pools, are looked up by address
+func (r *ProtocolRegistryImpl) GetProtocolByRegistryName(chainID *big.Int,
+	tokenRegistryName string) (Protocol, error) {
+
+	name := NormalizeProtocolName(tokenRegistryName)
+
+	var found []Protocol
+	for _, protocol := range r.ListProtocols(chainID) {
+		if protocol.GetName() == name {
+			found = append(found, protocol)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("protocol %s not found for chainID %s", name, chainID.String())
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("%d %s protocols registered for chainID %s, get them by address",
+			len(found), name, chainID.String())
+	}
+}
@@ -0,0 +1,77 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeProtocolName(t *testing.T) {
+
+	tt := []struct {
+		name     string
+		expected ProtocolName
+	}{
+		// names used by the tokens JSON files
+		{name: "AaveV3", expected: AaveV3},
+		{name: "SparkLend", expected: SparkLend},
+		{name: "AvalonFinance", expected: AvalonFinance},
+		{name: "ListaDAO", expected: ListaDao},
+		{name: "Ankr", expected: Ankr},
+		{name: "Lido", expected: Lido},
+		{name: "Rocketpool", expected: RocketPool},
+		{name: "Benqi", expected: Benqi},
+		{name: "Compound ETH Pool", expected: Compound},
+		{name: "Compound USDC Pool", expected: Compound},
+		// the registry names and other spellings
+		{name: "aave_v3", expected: AaveV3},
+		{name: "Aave V3", expected: AaveV3},
+		{name: " rocket-pool ", expected: RocketPool},
+		{name: "ListaDaoLending", expected: ListaDaoLending},
+		{name: "EigenLayer", expected: EigenLayer},
+		{name: "YearnV3", expected: Yearn},
+		{name: "sDAI", expected: SavingsDAI},
+		// unknown names are snake cased
+		{name: "MorphoBlue", expected: "morpho_blue"},
+		{name: "Curve Finance", expected: "curve_finance"},
+		{name: "", expected: ""},
+	}
+
+	for _, v := range tt {
+		require.Equal(t, v.expected, NormalizeProtocolName(v.name), v.name)
+	}
+}
+
+func TestProtocolRegistry_GetProtocolByRegistryName(t *testing.T) {
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+		{ChainID: BscChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+		WithClient(BscChainID, pkgtest.NewClient(BscChainID)),
+		WithDisabledProtocols(RocketPool, Compound))
+	require.NoError(t, err)
+
+	protocol, err := registry.GetProtocolByRegistryName(EthChainID, "SparkLend")
+	require.NoError(t, err)
+	require.Equal(t, SparkLendContractAddress, protocol.GetContractAddress(EthChainID))
+
+	protocol, err = registry.GetProtocolByRegistryName(BscChainID, "ListaDAO")
+	require.NoError(t, err)
+	require.Equal(t, ListaDao, protocol.GetName())
+
+	t.Run("not registered", func(t *testing.T) {
+		_, err := registry.GetProtocolByRegistryName(EthChainID, "Rocketpool")
+		require.Error(t, err)
+
+		_, err = registry.GetProtocolByRegistryName(BscChainID, "Lido")
+		require.Error(t, err)
+	})
+
+	t.Run("registered at several addresses", func(t *testing.T) {
+		_, err := registry.GetProtocolByRegistryName(EthChainID, "YearnV3")
+		require.Error(t, err)
+	})
+}
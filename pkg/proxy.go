@@ -0,0 +1,103 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// eip1967ImplementationSlot is the storage slot EIP-1967 proxies keep the address
+// of their implementation at, keccak256("eip1967.proxy.implementation") - 1
+var eip1967ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+
+// proxiableUUIDSelector is the selector of proxiableUUID() of EIP-1822 and UUPS
+// implementations. It answers the implementation slot only when the
+// implementation is called directly and reverts through its proxy
+var proxiableUUIDSelector = crypto.Keccak256([]byte("proxiableUUID()"))[:4]
+
+// proxyCheckTimeout bounds the node calls RegisterProtocol makes with WithProxyCheck
+const proxyCheckTimeout = 10 * time.Second
+
+// ErrProxyImplementation is the warning reported when a protocol is registered
+// at the implementation of a proxy rather than the proxy
+var ErrProxyImplementation = errors.New("registered at a proxy implementation, register its proxy instead")
+
+// StorageReader is implemented by the clients able to read the storage of a
+// contract, e.g *ethclient.Client
+type StorageReader interface {
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+}
+
+// verifyContractIsProxy reads the EIP-1967 implementation slot of addr. ok is
+// false when the slot is empty, could not be read or the client cannot read storage
+func verifyContractIsProxy(ctx context.Context, client EthClient,
+	addr common.Address) (impl common.Address, ok bool) {
+
+	reader, isReader := unwrapClient(client).(StorageReader)
+	if !isReader {
+		return impl, false
+	}
+
+	value, err := reader.StorageAt(ctx, addr, eip1967ImplementationSlot, nil)
+	if err != nil {
+		return impl, false
+	}
+
+	impl = common.BytesToAddress(value)
+	return impl, impl != (common.Address{})
+}
+
+// isProxyImplementation reports whether addr is the implementation of an
+// upgradeable proxy rather than the proxy. Only UUPS implementations can be told
+// apart since other implementations look like any other contract
+func isProxyImplementation(ctx context.Context, client EthClient, addr common.Address) bool {
+
+	if _, ok := verifyContractIsProxy(ctx, client, addr); ok {
+		return false
+	}
+
+	result, err := client.CallContract(ctx, ethereum.CallMsg{
+		To:   &addr,
+		Data: proxiableUUIDSelector,
+	}, nil)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(result, eip1967ImplementationSlot.Bytes())
+}
+
+// warnIfProxyImplementation reports to the observer of the registry when address
+// looks like the implementation behind a proxy, calldata sent to it would skip
+// the proxy storage
+func (r *ProtocolRegistryImpl) warnIfProxyImplementation(chainID *big.Int,
+	address common.Address, protocol Protocol) {
+
+	observer, ok := r.observer.(WarningObserver)
+	if !ok {
+		return
+	}
+
+	r.mu.RLock()
+	client, ok := r.clients[chainID.String()]
+	r.mu.RUnlock()
+
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), proxyCheckTimeout)
+	defer cancel()
+
+	if isProxyImplementation(ctx, client, address) {
+		observer.OnWarning(protocol.GetName(), fmt.Errorf("%w: chainID %s, address %s",
+			ErrProxyImplementation, chainID.String(), address.Hex()))
+	}
+}
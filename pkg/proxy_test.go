@@ -0,0 +1,26 @@
+//go:build integration
+// +build integration
+
+package pkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyContractIsProxy(t *testing.T) {
+
+	client := getTestClient(t, ChainETH)
+
+	// the Aave V3 pool is an EIP-1967 proxy
+	impl, ok := verifyContractIsProxy(context.Background(), client, AaveEthereumV3ContractAddress)
+	require.True(t, ok)
+	require.NotEqual(t, AaveEthereumV3ContractAddress, impl)
+
+	_, ok = verifyContractIsProxy(context.Background(), client, impl)
+	require.False(t, ok)
+
+	require.False(t, isProxyImplementation(context.Background(), client, AaveEthereumV3ContractAddress))
+}
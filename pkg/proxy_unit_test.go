@@ -0,0 +1,86 @@
+package pkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyContractIsProxy_Unit(t *testing.T) {
+
+	implementation := common.HexToAddress("0x5faab9e1adbddad0a08734be8a52185fd6558e14")
+
+	client := pkgtest.NewClient(EthChainID)
+	client.SetStorage(AaveEthereumV3ContractAddress, eip1967ImplementationSlot, common.BytesToHash(implementation.Bytes()))
+
+	impl, ok := verifyContractIsProxy(context.Background(), client, AaveEthereumV3ContractAddress)
+	require.True(t, ok)
+	require.Equal(t, implementation, impl)
+
+	t.Run("not a proxy", func(t *testing.T) {
+		_, ok := verifyContractIsProxy(context.Background(), client, implementation)
+		require.False(t, ok)
+	})
+
+	t.Run("wrapped clients are unwrapped", func(t *testing.T) {
+		impl, ok := verifyContractIsProxy(context.Background(), NewRetryClient(client, 3, 0), AaveEthereumV3ContractAddress)
+		require.True(t, ok)
+		require.Equal(t, implementation, impl)
+	})
+
+	t.Run("clients unable to read storage", func(t *testing.T) {
+		_, ok := verifyContractIsProxy(context.Background(), struct{ EthClient }{client}, AaveEthereumV3ContractAddress)
+		require.False(t, ok)
+	})
+}
+
+func TestIsProxyImplementation_Unit(t *testing.T) {
+
+	proxy := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	implementation := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	client := pkgtest.NewClient(EthChainID)
+	client.SetStorage(proxy, eip1967ImplementationSlot, common.BytesToHash(implementation.Bytes()))
+	client.Handle(proxiableUUIDSelector, func(msg ethereum.CallMsg) ([]byte, error) {
+		return eip1967ImplementationSlot.Bytes(), nil
+	})
+
+	require.True(t, isProxyImplementation(context.Background(), client, implementation))
+	require.False(t, isProxyImplementation(context.Background(), client, proxy))
+
+	// contracts without proxiableUUID are not implementations
+	require.False(t, isProxyImplementation(context.Background(), pkgtest.NewClient(EthChainID), implementation))
+
+	t.Run("registry warns with WithProxyCheck", func(t *testing.T) {
+		lido, err := NewLidoOperation(client, EthChainID)
+		require.NoError(t, err)
+
+		for address, warned := range map[common.Address]bool{implementation: true, proxy: false} {
+			observer := &recordingObserver{}
+
+			registry, err := NewProtocolRegistry([]ChainConfig{
+				{ChainID: GnosisChainID, RPCURL: "http://127.0.0.1:1"},
+			},
+				WithClient(GnosisChainID, client),
+				WithObserver(observer),
+				WithProxyCheck())
+			require.NoError(t, err)
+			observer.warnings = nil
+
+			require.NoError(t, registry.RegisterProtocol(GnosisChainID, address, lido))
+
+			if !warned {
+				require.Empty(t, observer.warnings)
+				continue
+			}
+
+			require.Len(t, observer.warnings, 1)
+			require.ErrorIs(t, observer.warnings[0], ErrProxyImplementation)
+			require.ErrorContains(t, observer.warnings[0], address.Hex())
+		}
+	})
+}
@@ -1,34 +1,68 @@
 package pkg
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"math/big"
+	"net/http"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // ChainConfig chain configuration
 type ChainConfig struct {
 	ChainID *big.Int
 	RPCURL  string
+	// HTTPClient dials RPCURL instead of a client built from WithHTTPTimeout,
+	// e.g to share a transport or route through a proxy
+	HTTPClient *http.Client
+	// Headers are sent with every request to RPCURL, e.g the API key of
+	// authenticated endpoints
+	Headers map[string]string
 }
 
 // ProtocolRegistryImpl is an implementation of the ProtocolRegistryImpl interface.
 type ProtocolRegistryImpl struct {
 	mu             sync.RWMutex
-	protocols      map[string]map[string]Protocol
+	protocols      map[string]map[string]*protocolEntry
 	protocolByType map[string]map[ProtocolType][]Protocol
-	chainConfigs   map[string]ChainConfig
+	// protocols keyed by their GetUniqueKey
+	protocolByKey map[string]Protocol
+	chainConfigs  map[string]ChainConfig
+
+	// pre-dialed clients keyed by chain id
+	clients           map[string]EthClient
+	httpTimeout       time.Duration
+	disabledProtocols map[ProtocolName]struct{}
+	referral          *referral
+	referralCode      uint16
+	retryAttempts     int
+	retryBaseDelay    time.Duration
+	assetMetadata     AssetMetadata
+	observer          Observer
+	proxyCheck        bool
+}
+
+type referral struct {
+	address common.Address
 }
 
 // NewProtocolRegistryImpl creates a new instance of ProtocolRegistryImpl.
-func NewProtocolRegistry(chainConfigs []ChainConfig) (*ProtocolRegistryImpl, error) {
+func NewProtocolRegistry(chainConfigs []ChainConfig, opts ...RegistryOption) (*ProtocolRegistryImpl, error) {
 	r := &ProtocolRegistryImpl{
-		protocols:      make(map[string]map[string]Protocol),
-		protocolByType: make(map[string]map[ProtocolType][]Protocol),
-		chainConfigs:   make(map[string]ChainConfig),
+		protocols:         make(map[string]map[string]*protocolEntry),
+		protocolByType:    make(map[string]map[ProtocolType][]Protocol),
+		protocolByKey:     make(map[string]Protocol),
+		chainConfigs:      make(map[string]ChainConfig),
+		clients:           make(map[string]EthClient),
+		disabledProtocols: make(map[ProtocolName]struct{}),
 	}
 
 	// Add chain configurations
@@ -37,6 +71,10 @@ func NewProtocolRegistry(chainConfigs []ChainConfig) (*ProtocolRegistryImpl, err
 		r.chainConfigs[chainIDStr] = config
 	}
 
+	for _, opt := range opts {
+		opt(r)
+	}
+
 	// Setup protocol operations
 	err := r.setupProtocolOperations()
 	if err != nil {
@@ -57,8 +95,31 @@ func (r *ProtocolRegistryImpl) GetChainConfig(chainID *big.Int) (ChainConfig, er
 	return ChainConfig{}, fmt.Errorf("chain config not found for chainID: %s", chainIDStr)
 }
 
-// RegisterProtocol adds a new protocol to the registry by its contract address.
-func (r *ProtocolRegistryImpl) RegisterProtocol(chainID *big.Int, address common.Address, protocol Protocol) error {
+// ListChains returns the ids of the configured chains in ascending order
+func (r *ProtocolRegistryImpl) ListChains() []*big.Int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chains := make([]*big.Int, 0, len(r.chainConfigs))
+	for _, config := range r.chainConfigs {
+		chains = append(chains, new(big.Int).Set(config.ChainID))
+	}
+
+	sort.Slice(chains, func(i, j int) bool { return chains[i].Cmp(chains[j]) < 0 })
+
+	return chains
+}
+
+// RegisterProtocol adds a new protocol to the registry by its contract address,
+// enabled unless registered WithEnabled(false).
+// With WithProxyCheck it reports addresses that are the implementation of a proxy
+// to the WarningObserver set with WithObserver
+func (r *ProtocolRegistryImpl) RegisterProtocol(chainID *big.Int, address common.Address,
+	protocol Protocol, opts ...RegisterOption) error {
+	if r.proxyCheck {
+		r.warnIfProxyImplementation(chainID, address, protocol)
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -72,17 +133,53 @@ func (r *ProtocolRegistryImpl) RegisterProtocol(chainID *big.Int, address common
 	}
 
 	if _, exists := r.protocols[chainIDStr]; !exists {
-		r.protocols[chainIDStr] = make(map[string]Protocol)
+		r.protocols[chainIDStr] = make(map[string]*protocolEntry)
 	}
 
 	if _, exists := r.protocols[chainIDStr][address.Hex()]; exists {
-		return fmt.Errorf("protocol already registered for chainID %s and address %s", chainIDStr, address.Hex())
+		return fmt.Errorf("%w for chainID %s and address %s", ErrProtocolAlreadyRegistered, chainIDStr, address.Hex())
+	}
+
+	key := protocol.GetUniqueKey()
+	if _, exists := r.protocolByKey[key]; exists {
+		return fmt.Errorf("%w with key %s", ErrProtocolAlreadyRegistered, key)
+	}
+
+	if observable, ok := protocol.(Observable); ok && r.observer != nil {
+		observable.SetObserver(r.observer)
+	}
+
+	if user, ok := protocol.(AssetMetadataUser); ok && r.assetMetadata != nil {
+		user.SetAssetMetadata(r.assetMetadata)
+	}
+
+	entry := &protocolEntry{protocol: protocol, enabled: true}
+	for _, opt := range opts {
+		opt(entry)
 	}
 
-	r.protocols[chainIDStr][address.Hex()] = protocol
+	entry.apply()
+
+	r.protocols[chainIDStr][address.Hex()] = entry
+	r.protocolByKey[key] = protocol
+
+	protocolType := protocol.GetType()
+	r.protocolByType[chainIDStr][protocolType] = append(r.protocolByType[chainIDStr][protocolType], protocol)
 	return nil
 }
 
+// GetProtocolByKey retrieves a protocol by its unique key, e.g aave_v3:56
+func (r *ProtocolRegistryImpl) GetProtocolByKey(key string) (Protocol, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if protocol, exists := r.protocolByKey[key]; exists {
+		return protocol, nil
+	}
+
+	return nil, fmt.Errorf("protocol not found for key %s", key)
+}
+
 // GetProtocol retrieves a protocol by its contract address.
 func (r *ProtocolRegistryImpl) GetProtocol(chainID *big.Int, address common.Address) (Protocol, error) {
 	r.mu.RLock()
@@ -90,8 +187,8 @@ func (r *ProtocolRegistryImpl) GetProtocol(chainID *big.Int, address common.Addr
 
 	chainIDStr := chainID.String()
 	if chainProtocols, exists := r.protocols[chainIDStr]; exists {
-		if protocol, exists := chainProtocols[address.Hex()]; exists {
-			return protocol, nil
+		if entry, exists := chainProtocols[address.Hex()]; exists {
+			return entry.protocol, nil
 		}
 	}
 
@@ -106,31 +203,82 @@ func (r *ProtocolRegistryImpl) ListProtocols(chainID *big.Int) []Protocol {
 	chainIDStr := chainID.String()
 	var protocols []Protocol
 	if chainProtocols, exists := r.protocols[chainIDStr]; exists {
-		for _, protocol := range chainProtocols {
-			protocols = append(protocols, protocol)
+		for _, entry := range chainProtocols {
+			protocols = append(protocols, entry.protocol)
 		}
 	}
 	return protocols
 }
 
+// ListAllProtocols returns a snapshot of the protocols of every chain keyed by chain id.
+// Every configured chain has an entry, even when no protocol is registered on it
+func (r *ProtocolRegistryImpl) ListAllProtocols() map[string][]Protocol {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	all := make(map[string][]Protocol, len(r.chainConfigs))
+	for chainIDStr := range r.chainConfigs {
+		all[chainIDStr] = []Protocol{}
+	}
+
+	for chainIDStr, chainProtocols := range r.protocols {
+		protocols := make([]Protocol, 0, len(chainProtocols))
+		for _, entry := range chainProtocols {
+			protocols = append(protocols, entry.protocol)
+		}
+
+		all[chainIDStr] = protocols
+	}
+
+	return all
+}
+
 // ListProtocolsByType lists all protocols of a specific type.
 func (r *ProtocolRegistryImpl) ListProtocolsByType(chainID *big.Int, protocolType ProtocolType) []Protocol {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	chainIDStr := chainID.String()
-	if protocols, exists := r.protocolByType[chainIDStr]; exists {
-		return protocols[protocolType]
+	protocols := r.protocolByType[chainIDStr][protocolType]
+
+	// callers must not be able to append to the index
+	return append([]Protocol{}, protocols...)
+}
+
+// ListProtocolsByAction lists the protocols registered on the chain supporting action
+func (r *ProtocolRegistryImpl) ListProtocolsByAction(chainID *big.Int, action ContractAction) []Protocol {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	protocols := []Protocol{}
+	for _, entry := range r.protocols[chainID.String()] {
+		if entry.protocol.IsSupportedAction(action) {
+			protocols = append(protocols, entry.protocol)
+		}
+	}
+
+	return protocols
+}
+
+// Capabilities returns the capabilities of every protocol registered on the chain
+// keyed by the address the protocol was registered with
+func (r *ProtocolRegistryImpl) Capabilities(chainID *big.Int) map[string]ProtocolCapabilities {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	capabilities := make(map[string]ProtocolCapabilities)
+	for address, entry := range r.protocols[chainID.String()] {
+		capabilities[address] = entry.protocol.Capabilities()
 	}
 
-	return []Protocol{}
+	return capabilities
 }
 
 // setupProtocolOperations initializes and registers various DeFi protocols for both ETH and BNB.
 func (r *ProtocolRegistryImpl) setupProtocolOperations() error {
 	val, ok := r.chainConfigs[EthChainStr]
 	if ok {
-		client, err := ethclient.Dial(val.RPCURL)
+		client, err := r.dial(val)
 		if err != nil {
 			return err
 		}
@@ -144,7 +292,7 @@ func (r *ProtocolRegistryImpl) setupProtocolOperations() error {
 	bscConfig, ok := r.chainConfigs[BscChainStr]
 	if ok {
 
-		bscClient, err := ethclient.Dial(bscConfig.RPCURL)
+		bscClient, err := r.dial(bscConfig)
 		if err != nil {
 			return err
 		}
@@ -156,110 +304,275 @@ func (r *ProtocolRegistryImpl) setupProtocolOperations() error {
 	}
 
 	polygonConfig, ok := r.chainConfigs[PolygonChainStr]
+	if ok {
+
+		polygonClient, err := r.dial(polygonConfig)
+		if err != nil {
+			return err
+		}
+
+		err = r.setupPolygonProtocols(polygonClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	gnosisConfig, ok := r.chainConfigs[GnosisChainStr]
+	if ok {
+
+		gnosisClient, err := r.dial(gnosisConfig)
+		if err != nil {
+			return err
+		}
+
+		err = r.setupGnosisProtocols(gnosisClient)
+		if err != nil {
+			return err
+		}
+	}
+
+	avalancheConfig, ok := r.chainConfigs[AvalancheChainStr]
 	if !ok {
 		return nil
 	}
 
-	polygonClient, err := ethclient.Dial(polygonConfig.RPCURL)
+	avalancheClient, err := r.dial(avalancheConfig)
 	if err != nil {
 		return err
 	}
 
-	return r.setupPolygonProtocols(polygonClient)
+	return r.setupAvalancheProtocols(avalancheClient)
 }
 
-// setupPolygonProtocols initializes and registers various DeFi protocols on the Polygon chain.
-func (r *ProtocolRegistryImpl) setupPolygonProtocols(client *ethclient.Client) error {
+// dial returns the client injected or already dialed for the chain, or dials its RPCURL
+func (r *ProtocolRegistryImpl) dial(config ChainConfig) (EthClient, error) {
+	chainIDStr := config.ChainID.String()
 
-	registerProtocol := func(address common.Address, chainID *big.Int,
-		createFunc func(ChainConfig) (Protocol, error)) error {
+	r.mu.RLock()
+	client, ok := r.clients[chainIDStr]
+	r.mu.RUnlock()
 
-		chainIDStr := chainID.String()
-		config, exists := r.chainConfigs[chainIDStr]
+	if ok {
+		return NewRetryClient(client, r.retryAttempts, r.retryBaseDelay), nil
+	}
 
-		if !exists {
-			return fmt.Errorf("chain configuration not found for chainID: %s", chainIDStr)
-		}
+	rpcClient, err := rpc.DialOptions(context.Background(), config.RPCURL, r.dialOptions(config)...)
+	if err != nil {
+		return nil, err
+	}
 
-		protocol, err := createFunc(config)
-		if err != nil {
-			return fmt.Errorf("failed to create protocol at address %s: %v", address.Hex(), err)
-		}
+	client = ethclient.NewClient(rpcClient)
 
-		err = r.RegisterProtocol(chainID, address, protocol)
-		if err != nil {
-			return fmt.Errorf("failed to register protocol at address %s: %v", address.Hex(), err)
-		}
+	r.mu.Lock()
+	r.clients[chainIDStr] = client
+	r.mu.Unlock()
+
+	return NewRetryClient(client, r.retryAttempts, r.retryBaseDelay), nil
+}
+
+// dialOptions returns the HTTP client and headers the RPCURL of config is dialed with
+func (r *ProtocolRegistryImpl) dialOptions(config ChainConfig) []rpc.ClientOption {
+	var opts []rpc.ClientOption
+
+	switch {
+	case config.HTTPClient != nil:
+		opts = append(opts, rpc.WithHTTPClient(config.HTTPClient))
+	case r.httpTimeout > 0:
+		opts = append(opts, rpc.WithHTTPClient(&http.Client{Timeout: r.httpTimeout}))
+	}
+
+	for key, value := range config.Headers {
+		opts = append(opts, rpc.WithHeader(key, value))
+	}
+
+	return opts
+}
+
+// aaveOptions returns the options applied to every Aave deployment
+func (r *ProtocolRegistryImpl) aaveOptions() []AaveOption {
+	return []AaveOption{WithAaveReferralCode(r.referralCode)}
+}
+
+// lidoOptions returns the options applied to Lido
+func (r *ProtocolRegistryImpl) lidoOptions() []LidoOption {
+	if r.referral == nil {
+		return nil
+	}
+
+	return []LidoOption{WithLidoReferral(r.referral.address)}
+}
+
+// isProtocolDisabled reports whether the protocol was disabled with WithDisabledProtocols
+func (r *ProtocolRegistryImpl) isProtocolDisabled(name ProtocolName) bool {
+	_, disabled := r.disabledProtocols[name]
+	return disabled
+}
+
+// registerProtocol creates the protocol with createFunc and registers it at address
+// unless it is disabled. Setup registering an address twice keeps the first protocol
+func (r *ProtocolRegistryImpl) registerProtocol(name ProtocolName, address common.Address, chainID *big.Int,
+	createFunc func(ChainConfig) (Protocol, error)) error {
+
+	if r.isProtocolDisabled(name) {
+		return nil
+	}
+
+	chainIDStr := chainID.String()
+	config, exists := r.chainConfigs[chainIDStr]
+
+	if !exists {
+		return fmt.Errorf("chain configuration not found for chainID: %s", chainIDStr)
+	}
 
+	if _, err := r.GetProtocol(chainID, address); err == nil {
+		log.Printf("protocol_registry: %s already registered for chainID %s and address %s, skipping",
+			name, chainIDStr, address.Hex())
 		return nil
 	}
 
+	protocol, err := createFunc(config)
+	if err != nil {
+		return fmt.Errorf("failed to create protocol at address %s: %v", address.Hex(), err)
+	}
+
+	err = r.RegisterProtocol(chainID, address, protocol)
+	if errors.Is(err, ErrProtocolAlreadyRegistered) {
+		log.Printf("protocol_registry: skipping %s at address %s: %v", name, address.Hex(), err)
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to register protocol at address %s: %v", address.Hex(), err)
+	}
+
+	return nil
+}
+
+// setupPolygonProtocols initializes and registers various DeFi protocols on the Polygon chain.
+func (r *ProtocolRegistryImpl) setupPolygonProtocols(client EthClient) error {
+
 	// Register Aave protocol on Polygon
-	err := registerProtocol(
+	err := r.registerProtocol(
+		AaveV3,
 		AavePolygonV3ContractAddress,
 		PolygonChainID,
 		func(config ChainConfig) (Protocol, error) {
-			return NewAaveOperation(client, PolygonChainID, AaveProtocolDeploymentPolygon)
+			return NewAaveOperation(client, PolygonChainID, AaveProtocolDeploymentPolygon, r.aaveOptions()...)
 		})
 	if err != nil {
 		return err
 	}
 
+	// Register Ankr protocol on Polygon
+	err = r.registerProtocol(Ankr, AnkrPolygonContractAddress, PolygonChainID, func(config ChainConfig) (Protocol, error) {
+		return NewAnkrOperation(client, PolygonChainID)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Register Lido protocol on Polygon
+	err = r.registerProtocol(Lido, LidoPolygonContractAddress, PolygonChainID, func(config ChainConfig) (Protocol, error) {
+		return NewLidoOperation(client, PolygonChainID, r.lidoOptions()...)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Register the Stargate USDC bridge on Polygon
+	err = r.registerProtocol(Stargate, StargateUSDCPolygonContractAddress, PolygonChainID, func(config ChainConfig) (Protocol, error) {
+		return NewStargateOperation(client, PolygonChainID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.isProtocolDisabled(Compound) {
+		return nil
+	}
+
 	return registerCompoundRegistry(r, client, PolygonChainID.Int64())
 }
 
-// setupEthProtocols initializes and registers various DeFi protocols on the Ethereum chain.
-func (r *ProtocolRegistryImpl) setupEthProtocols(client *ethclient.Client) error {
-
-	registerProtocol := func(address common.Address, chainID *big.Int,
-		createFunc func(ChainConfig) (Protocol, error)) error {
+// setupGnosisProtocols initializes and registers various DeFi protocols on the Gnosis chain.
+func (r *ProtocolRegistryImpl) setupGnosisProtocols(client EthClient) error {
 
-		chainIDStr := chainID.String()
-		config, exists := r.chainConfigs[chainIDStr]
+	// Register Aave protocol on Gnosis
+	err := r.registerProtocol(
+		AaveV3,
+		AaveGnosisV3ContractAddress,
+		GnosisChainID,
+		func(config ChainConfig) (Protocol, error) {
+			return NewAaveOperation(client, GnosisChainID, AaveProtocolDeploymentEthereum, r.aaveOptions()...)
+		})
+	if err != nil {
+		return err
+	}
 
-		if !exists {
-			return fmt.Errorf("chain configuration not found for chainID: %s", chainIDStr)
-		}
+	// Register sDAI on Gnosis
+	return r.registerProtocol(SavingsDAI, SavingsDAIGnosisContractAddress, GnosisChainID, func(config ChainConfig) (Protocol, error) {
+		return NewSavingsDAIOperation(client, GnosisChainID)
+	})
+}
 
-		protocol, err := createFunc(config)
-		if err != nil {
-			return fmt.Errorf("failed to create protocol at address %s: %v", address.Hex(), err)
-		}
+// setupAvalancheProtocols initializes and registers various DeFi protocols on the Avalanche C-Chain.
+func (r *ProtocolRegistryImpl) setupAvalancheProtocols(client EthClient) error {
 
-		err = r.RegisterProtocol(chainID, address, protocol)
-		if err != nil {
-			return fmt.Errorf("failed to register protocol at address %s: %v", address.Hex(), err)
-		}
+	// Register Aave protocol on Avalanche
+	err := r.registerProtocol(
+		AaveV3,
+		AaveAvalancheV3ContractAddress,
+		AvalancheChainID,
+		func(config ChainConfig) (Protocol, error) {
+			return NewAaveOperation(client, AvalancheChainID, AaveProtocolDeploymentEthereum, r.aaveOptions()...)
+		})
+	if err != nil {
+		return err
+	}
 
-		return nil
+	// Register Benqi liquid staking on Avalanche
+	err = r.registerProtocol(Benqi, BenqiSAVAXContractAddress, AvalancheChainID, func(config ChainConfig) (Protocol, error) {
+		return NewBenqiOperation(client, AvalancheChainID)
+	})
+	if err != nil {
+		return err
 	}
 
+	// Register the Stargate USDC bridge on Avalanche
+	return r.registerProtocol(Stargate, StargateUSDCAvalancheContractAddress, AvalancheChainID, func(config ChainConfig) (Protocol, error) {
+		return NewStargateOperation(client, AvalancheChainID)
+	})
+}
+
+// setupEthProtocols initializes and registers various DeFi protocols on the Ethereum chain.
+func (r *ProtocolRegistryImpl) setupEthProtocols(client EthClient) error {
+
 	// Register Lido protocol on Ethereum
-	err := registerProtocol(LidoContractAddress, EthChainID, func(config ChainConfig) (Protocol, error) {
-		return NewLidoOperation(client, EthChainID)
+	err := r.registerProtocol(Lido, LidoContractAddress, EthChainID, func(config ChainConfig) (Protocol, error) {
+		return NewLidoOperation(client, EthChainID, r.lidoOptions()...)
 	})
 	if err != nil {
 		return err
 	}
 
 	// Register Aave protocol on Ethereum
-	err = registerProtocol(AaveEthereumV3ContractAddress, EthChainID, func(config ChainConfig) (Protocol, error) {
-		return NewAaveOperation(client, EthChainID, AaveProtocolDeploymentEthereum)
+	err = r.registerProtocol(AaveV3, AaveEthereumV3ContractAddress, EthChainID, func(config ChainConfig) (Protocol, error) {
+		return NewAaveOperation(client, EthChainID, AaveProtocolDeploymentEthereum, r.aaveOptions()...)
 	})
 	if err != nil {
 		return err
 	}
 
 	// Register Sparklend protocol on Ethereum
-	err = registerProtocol(SparkLendContractAddress, EthChainID, func(config ChainConfig) (Protocol, error) {
-		return NewAaveOperation(client, EthChainID, AaveProtocolDeploymentSpark)
+	err = r.registerProtocol(SparkLend, SparkLendContractAddress, EthChainID, func(config ChainConfig) (Protocol, error) {
+		return NewAaveOperation(client, EthChainID, AaveProtocolDeploymentSpark, r.aaveOptions()...)
 	})
 	if err != nil {
 		return err
 	}
 
 	// Register Ankr protocol on Ethereum
-	err = registerProtocol(AnkrContractAddress, EthChainID, func(config ChainConfig) (Protocol, error) {
+	err = r.registerProtocol(Ankr, AnkrContractAddress, EthChainID, func(config ChainConfig) (Protocol, error) {
 		return NewAnkrOperation(client, EthChainID)
 	})
 	if err != nil {
@@ -267,66 +580,131 @@ func (r *ProtocolRegistryImpl) setupEthProtocols(client *ethclient.Client) error
 	}
 
 	// Register Rocketpool protocol on Ethereum
-	err = registerProtocol(RocketPoolStorageAddress, EthChainID, func(config ChainConfig) (Protocol, error) {
+	err = r.registerProtocol(RocketPool, RocketPoolStorageAddress, EthChainID, func(config ChainConfig) (Protocol, error) {
 		return NewRocketpoolOperation(client, EthChainID)
 	})
 	if err != nil {
 		return err
 	}
 
-	// Register Compound protocol on Ethereum
-	return registerCompoundRegistry(r, client, EthChainID.Int64())
-}
-
-// setupBnbProtocols initializes and registers various DeFi protocols on the Binance Smart Chain.
-func (r *ProtocolRegistryImpl) setupBnbProtocols(client *ethclient.Client) error {
-
-	registerProtocol := func(address common.Address, chainID *big.Int,
-		createFunc func(ChainConfig) (Protocol, error)) error {
-
-		chainIDStr := chainID.String()
-		config, exists := r.chainConfigs[chainIDStr]
+	// Register EigenLayer restaking on Ethereum
+	err = r.registerProtocol(EigenLayer, EigenLayerStrategyManagerAddress, EthChainID, func(config ChainConfig) (Protocol, error) {
+		return NewEigenLayerOperation(client, EthChainID)
+	})
+	if err != nil {
+		return err
+	}
 
-		if !exists {
-			return fmt.Errorf("chain configuration not found for chainID: %s", chainIDStr)
-		}
+	// Register the Stargate USDC bridge on Ethereum
+	err = r.registerProtocol(Stargate, StargateUSDCEthContractAddress, EthChainID, func(config ChainConfig) (Protocol, error) {
+		return NewStargateOperation(client, EthChainID)
+	})
+	if err != nil {
+		return err
+	}
 
-		protocol, err := createFunc(config)
+	// Register the Yearn V3 vaults on Ethereum
+	for _, vault := range yearnV3EthVaults {
+		err = r.registerProtocol(Yearn, vault, EthChainID, func(config ChainConfig) (Protocol, error) {
+			return NewYearnOperation(client, EthChainID, vault)
+		})
 		if err != nil {
-			return fmt.Errorf("failed to create protocol at address %s: %v", address.Hex(), err)
+			return err
 		}
+	}
 
-		err = r.RegisterProtocol(chainID, address, protocol)
+	// Register sDAI on Ethereum
+	err = r.registerProtocol(SavingsDAI, SavingsDAIContractAddress, EthChainID, func(config ChainConfig) (Protocol, error) {
+		return NewSavingsDAIOperation(client, EthChainID)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Register the Spark savings tokens on Ethereum
+	for _, vault := range sparkSavingsEthVaults {
+		err = r.registerProtocol(SparkSavings, vault, EthChainID, func(config ChainConfig) (Protocol, error) {
+			return NewSparkSavingsOperation(client, EthChainID, vault)
+		})
 		if err != nil {
-			return fmt.Errorf("failed to register protocol at address %s: %v", address.Hex(), err)
+			return err
 		}
+	}
 
+	if r.isProtocolDisabled(Compound) {
 		return nil
 	}
 
+	// Register Compound protocol on Ethereum
+	return registerCompoundRegistry(r, client, EthChainID.Int64())
+}
+
+// setupBnbProtocols initializes and registers various DeFi protocols on the Binance Smart Chain.
+func (r *ProtocolRegistryImpl) setupBnbProtocols(client EthClient) error {
+
 	// Register Aave protocol on BNB
-	err := registerProtocol(AaveBnbV3ContractAddress, BscChainID, func(config ChainConfig) (Protocol, error) {
-		return NewAaveOperation(client, BscChainID, AaveProtocolDeploymentEthereum)
+	err := r.registerProtocol(AaveV3, AaveBnbV3ContractAddress, BscChainID, func(config ChainConfig) (Protocol, error) {
+		return NewAaveOperation(client, BscChainID, AaveProtocolDeploymentEthereum, r.aaveOptions()...)
 	})
 	if err != nil {
 		return err
 	}
 
 	// Register Avalon Finance protocol on BNB
-	err = registerProtocol(AvalonFinanceContractAddress, BscChainID, func(config ChainConfig) (Protocol, error) {
-		return NewAaveOperation(client, BscChainID, AaveProtocolDeploymentAvalonFinance)
+	err = r.registerProtocol(AvalonFinance, AvalonFinanceContractAddress, BscChainID, func(config ChainConfig) (Protocol, error) {
+		return NewAaveOperation(client, BscChainID, AaveProtocolDeploymentAvalonFinance, r.aaveOptions()...)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Register Radiant protocol on BNB
+	err = r.registerProtocol(Radiant, RadiantBnbContractAddress, BscChainID, func(config ChainConfig) (Protocol, error) {
+		return NewAaveOperation(client, BscChainID, AaveProtocolDeploymentRadiant, r.aaveOptions()...)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Register Ankr protocol on BNB
+	err = r.registerProtocol(Ankr, AnkrBnbContractAddress, BscChainID, func(config ChainConfig) (Protocol, error) {
+		return NewAnkrOperation(client, BscChainID)
 	})
 	if err != nil {
 		return err
 	}
 
 	// Register Lista Dao protocol on BNB
-	err = registerProtocol(ListaDaoContractAddress, BscChainID, func(config ChainConfig) (Protocol, error) {
+	err = r.registerProtocol(ListaDao, ListaDaoContractAddress, BscChainID, func(config ChainConfig) (Protocol, error) {
 		return NewListaStakingOperation(client, BscChainID)
 	})
 	if err != nil {
 		return err
 	}
 
+	// Register Lista Dao lisUSD lending on BNB
+	err = r.registerProtocol(ListaDaoLending, ListaDaoInteractionContractAddress, BscChainID, func(config ChainConfig) (Protocol, error) {
+		return NewListaLendingOperation(client, BscChainID)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Register the Venus native BNB market on BNB
+	err = r.registerProtocol(Venus, VenusVBNBContractAddress, BscChainID, func(config ChainConfig) (Protocol, error) {
+		return NewVenusOperation(client, BscChainID)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Register the Stargate USDC bridge on BNB
+	err = r.registerProtocol(Stargate, StargateUSDCBnbContractAddress, BscChainID, func(config ChainConfig) (Protocol, error) {
+		return NewStargateOperation(client, BscChainID)
+	})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
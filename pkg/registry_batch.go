@@ -0,0 +1,100 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BatchStep is a single call of a route generated with GenerateBatch
+type BatchStep struct {
+	// Address is the address the protocol was registered with
+	Address common.Address
+	Action  ContractAction
+	Params  TransactionParams
+}
+
+// GenerateBatch builds the transaction of every step in order, stopping at the
+// first step that fails. ERC20Approve steps approve the contract the next step
+// of the same protocol is sent to, e.g the wrapped token gateway of an Aave
+// native withdrawal, to pull Params.Amount of Params.Asset. They are sent to Params.Asset
+func (r *ProtocolRegistryImpl) GenerateBatch(ctx context.Context, chainID *big.Int,
+	steps []BatchStep) ([]*Transaction, error) {
+
+	txs := make([]*Transaction, len(steps))
+
+	for i, step := range steps {
+		if err := r.buildStep(ctx, chainID, steps, i, txs); err != nil {
+			return nil, fmt.Errorf("step %d, %s on %s: %w", i, step.Action, step.Address.Hex(), err)
+		}
+	}
+
+	return txs, nil
+}
+
+// buildStep builds the transaction of steps[i] into txs, unless an approval
+// built it already to find its spender
+func (r *ProtocolRegistryImpl) buildStep(ctx context.Context, chainID *big.Int,
+	steps []BatchStep, i int, txs []*Transaction) error {
+
+	if txs[i] != nil {
+		return nil
+	}
+
+	step := steps[i]
+
+	protocol, err := r.enabledProtocol(chainID, step.Address)
+	if err != nil {
+		return err
+	}
+
+	if step.Action != ERC20Approve {
+		tx, err := protocol.BuildTransaction(ctx, chainID, step.Action, step.Params)
+		if err != nil {
+			return err
+		}
+
+		txs[i] = tx
+		return nil
+	}
+
+	if step.Params.Amount == nil {
+		return ErrAmountRequired
+	}
+
+	if IsNativeToken(step.Params.Asset) {
+		return errors.New("the native token is sent along rather than approved")
+	}
+
+	next := -1
+	for j := i + 1; j < len(steps); j++ {
+		if steps[j].Address == step.Address && steps[j].Action != ERC20Approve {
+			next = j
+			break
+		}
+	}
+
+	if next < 0 {
+		return errors.New("no later step of the protocol uses the allowance")
+	}
+
+	if err := r.buildStep(ctx, chainID, steps, next, txs); err != nil {
+		return fmt.Errorf("step %d using the allowance: %w", next, err)
+	}
+
+	calldata, err := ApprovalCalldata(txs[next].To, step.Params.Amount)
+	if err != nil {
+		return err
+	}
+
+	txs[i] = &Transaction{
+		To:    step.Params.Asset,
+		Data:  calldata,
+		Value: big.NewInt(0),
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocolRegistry_GenerateBatch(t *testing.T) {
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+		WithDisabledProtocols(RocketPool, Compound))
+	require.NoError(t, err)
+
+	params := TransactionParams{
+		Amount: big.NewInt(1e6),
+		Sender: testAccount,
+		Asset:  testUSDC,
+	}
+
+	aave, err := registry.GetProtocol(EthChainID, AaveEthereumV3ContractAddress)
+	require.NoError(t, err)
+
+	supply, err := aave.GenerateCalldata(context.Background(), EthChainID, LoanSupply, params)
+	require.NoError(t, err)
+
+	txs, err := registry.GenerateBatch(context.Background(), EthChainID, []BatchStep{
+		{Address: AaveEthereumV3ContractAddress, Action: ERC20Approve, Params: params},
+		{Address: AaveEthereumV3ContractAddress, Action: LoanSupply, Params: params},
+	})
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+
+	// cast calldata "approve(address,uint256)" 0x87870Bca3F3fD6335C3F4ce8392D69350B4fA4E2 1000000
+	require.Equal(t, testUSDC, txs[0].To)
+	require.Equal(t, "0x095ea7b3"+
+		"00000000000000000000000087870bca3f3fd6335c3f4ce8392d69350b4fa4e2"+
+		"00000000000000000000000000000000000000000000000000000000000f4240", txs[0].Data)
+	require.Zero(t, txs[0].Value.Sign())
+
+	require.Equal(t, AaveEthereumV3ContractAddress, txs[1].To)
+	require.Equal(t, supply, txs[1].Data)
+
+	t.Run("allowance of the gateway", func(t *testing.T) {
+		aWETH := common.HexToAddress("0x4d5F47FA6A74757f35C14fD3a6Ef8E3C9BC514E8")
+
+		withdraw := params
+		withdraw.Asset = common.HexToAddress(nativeDenomAddress)
+
+		allowance := params
+		allowance.Asset = aWETH
+
+		txs, err := registry.GenerateBatch(context.Background(), EthChainID, []BatchStep{
+			{Address: AaveEthereumV3ContractAddress, Action: ERC20Approve, Params: allowance},
+			{Address: AaveEthereumV3ContractAddress, Action: LoanWithdraw, Params: withdraw},
+		})
+		require.NoError(t, err)
+
+		gateway := aaveWrappedTokenGateways[EthChainID.Int64()].gateway
+		require.Equal(t, gateway, txs[1].To)
+
+		approval, err := ApprovalCalldata(gateway, params.Amount)
+		require.NoError(t, err)
+
+		require.Equal(t, aWETH, txs[0].To)
+		require.Equal(t, approval, txs[0].Data)
+	})
+
+	t.Run("failing step", func(t *testing.T) {
+		_, err := registry.GenerateBatch(context.Background(), EthChainID, []BatchStep{
+			{Address: AaveEthereumV3ContractAddress, Action: ERC20Approve, Params: params},
+			{Address: AaveEthereumV3ContractAddress, Action: NativeStake, Params: params},
+		})
+		require.ErrorIs(t, err, ErrUnsupportedAction)
+		require.ErrorContains(t, err, "step 1")
+
+		_, err = registry.GenerateBatch(context.Background(), EthChainID, []BatchStep{
+			{Address: common.HexToAddress("0x1"), Action: LoanSupply, Params: params},
+		})
+		require.ErrorContains(t, err, "step 0")
+
+		native := params
+		native.Asset = common.HexToAddress(nativeDenomAddress)
+
+		_, err = registry.GenerateBatch(context.Background(), EthChainID, []BatchStep{
+			{Address: AaveEthereumV3ContractAddress, Action: ERC20Approve, Params: native},
+		})
+		require.ErrorContains(t, err, "step 0")
+
+		_, err = registry.GenerateBatch(context.Background(), EthChainID, []BatchStep{
+			{Address: AaveEthereumV3ContractAddress, Action: ERC20Approve, Params: params},
+		})
+		require.ErrorContains(t, err, "no later step")
+	})
+}
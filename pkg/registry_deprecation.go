@@ -0,0 +1,133 @@
+package pkg
+
+import (
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Deprecatable is implemented by the protocols refusing to generate calldata,
+// validate or build transactions once disabled with SetProtocolEnabled. The
+// registry sets the state on the protocol itself so references retrieved
+// before it was disabled are blocked too.
+type Deprecatable interface {
+	SetDeprecated(deprecated bool)
+}
+
+// deprecation is embedded by the operations to implement Deprecatable.
+type deprecation struct {
+	deprecated atomic.Bool
+}
+
+// SetDeprecated implements Deprecatable.
+func (d *deprecation) SetDeprecated(deprecated bool) {
+	d.deprecated.Store(deprecated)
+}
+
+// check returns ErrProtocolDeprecated once the protocol identified by key was disabled.
+func (d *deprecation) check(key string) error {
+	if d.deprecated.Load() {
+		return fmt.Errorf("%w: %s", ErrProtocolDeprecated, key)
+	}
+
+	return nil
+}
+
+// protocolEntry is a protocol registered at an address.
+type protocolEntry struct {
+	protocol Protocol
+	enabled  bool
+}
+
+// apply passes the enabled flag of the entry on to the protocol.
+func (e *protocolEntry) apply() {
+	if d, ok := e.protocol.(Deprecatable); ok {
+		d.SetDeprecated(!e.enabled)
+	}
+}
+
+// check returns ErrProtocolDeprecated when the entry was disabled, for the
+// protocols that do not implement Deprecatable.
+func (e *protocolEntry) check() error {
+	if !e.enabled {
+		return fmt.Errorf("%w: %s", ErrProtocolDeprecated, e.protocol.GetUniqueKey())
+	}
+
+	return nil
+}
+
+// RegisterOption configures the registration of a protocol.
+type RegisterOption func(*protocolEntry)
+
+// WithEnabled registers the protocol enabled or, with false, already disabled
+// as with SetProtocolEnabled. Protocols are registered enabled by default.
+func WithEnabled(enabled bool) RegisterOption {
+	return func(e *protocolEntry) {
+		e.enabled = enabled
+	}
+}
+
+// SetProtocolEnabled enables or disables the protocol registered at address.
+// A disabled protocol stays listed but GenerateCalldata, BuildTransaction,
+// EstimateGas and Validate fail with ErrProtocolDeprecated, e.g once its
+// contract migrated. Protocols not implementing Deprecatable are only blocked
+// in the registry, e.g by GenerateBatch.
+func (r *ProtocolRegistryImpl) SetProtocolEnabled(chainID *big.Int, address common.Address, enabled bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	chainIDStr := chainID.String()
+
+	entry, exists := r.protocols[chainIDStr][address.Hex()]
+	if !exists {
+		return fmt.Errorf("protocol not found for chainID %s and address %s", chainIDStr, address.Hex())
+	}
+
+	entry.enabled = enabled
+	entry.apply()
+
+	return nil
+}
+
+// IsProtocolEnabled reports whether the protocol registered at address was not
+// disabled with SetProtocolEnabled or WithEnabled.
+func (r *ProtocolRegistryImpl) IsProtocolEnabled(chainID *big.Int, address common.Address) (bool, error) {
+	entry, err := r.protocolEntry(chainID, address)
+	if err != nil {
+		return false, err
+	}
+
+	return entry.enabled, nil
+}
+
+// enabledProtocol retrieves the protocol registered at address, failing with
+// ErrProtocolDeprecated when it was disabled.
+func (r *ProtocolRegistryImpl) enabledProtocol(chainID *big.Int, address common.Address) (Protocol, error) {
+	entry, err := r.protocolEntry(chainID, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := entry.check(); err != nil {
+		return nil, err
+	}
+
+	return entry.protocol, nil
+}
+
+// protocolEntry retrieves a copy of the entry registered at address.
+func (r *ProtocolRegistryImpl) protocolEntry(chainID *big.Int, address common.Address) (protocolEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chainIDStr := chainID.String()
+
+	entry, exists := r.protocols[chainIDStr][address.Hex()]
+	if !exists {
+		return protocolEntry{}, fmt.Errorf("protocol not found for chainID %s and address %s", chainIDStr, address.Hex())
+	}
+
+	return *entry, nil
+}
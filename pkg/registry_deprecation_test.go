@@ -0,0 +1,149 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// undeprecatable hides the Deprecatable implementation of the protocol it wraps
+type undeprecatable struct {
+	Protocol
+}
+
+func (undeprecatable) GetUniqueKey() string { return "undeprecatable" }
+
+func TestProtocolRegistry_SetProtocolEnabled(t *testing.T) {
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+		WithDisabledProtocols(RocketPool, Compound))
+	require.NoError(t, err)
+
+	params := TransactionParams{
+		Sender: testAccount,
+		Asset:  common.HexToAddress(nativeDenomAddress),
+		Amount: big.NewInt(1e18),
+	}
+
+	protocol, err := registry.GetProtocol(EthChainID, LidoContractAddress)
+	require.NoError(t, err)
+	key := protocol.GetUniqueKey()
+	protocols := len(registry.ListProtocols(EthChainID))
+
+	_, err = protocol.GenerateCalldata(context.Background(), EthChainID, NativeStake, params)
+	require.NoError(t, err)
+
+	require.NoError(t, registry.SetProtocolEnabled(EthChainID, LidoContractAddress, false))
+	// disabling twice is a no-op
+	require.NoError(t, registry.SetProtocolEnabled(EthChainID, LidoContractAddress, false))
+
+	enabled, err := registry.IsProtocolEnabled(EthChainID, LidoContractAddress)
+	require.NoError(t, err)
+	require.False(t, enabled)
+
+	deprecated, err := registry.GetProtocol(EthChainID, LidoContractAddress)
+	require.NoError(t, err)
+	// the registry hands out the protocol itself rather than a wrapper
+	require.Same(t, protocol, deprecated)
+	require.IsType(t, &LidoOperation{}, deprecated)
+	require.Implements(t, (*StakePreviewer)(nil), deprecated)
+
+	// the reference retrieved before it was disabled is blocked too
+	_, err = protocol.GenerateCalldata(context.Background(), EthChainID, NativeStake, params)
+	require.ErrorIs(t, err, ErrProtocolDeprecated)
+
+	err = protocol.Validate(context.Background(), EthChainID, NativeStake, params)
+	require.ErrorIs(t, err, ErrProtocolDeprecated)
+
+	_, err = protocol.BuildTransaction(context.Background(), EthChainID, NativeStake, params)
+	require.ErrorIs(t, err, ErrProtocolDeprecated)
+
+	estimator, ok := protocol.(GasEstimator)
+	require.True(t, ok)
+
+	_, err = estimator.EstimateGas(context.Background(), EthChainID, NativeStake, params)
+	require.ErrorIs(t, err, ErrProtocolDeprecated)
+
+	_, err = registry.GenerateBatch(context.Background(), EthChainID, []BatchStep{
+		{Address: LidoContractAddress, Action: NativeStake, Params: params},
+	})
+	require.ErrorIs(t, err, ErrProtocolDeprecated)
+
+	// it is still listed and readable
+	require.Equal(t, Lido, deprecated.GetName())
+	require.Len(t, registry.ListProtocols(EthChainID), protocols)
+	require.Contains(t, registry.ListProtocolsByType(EthChainID, TypeStake), protocol)
+
+	byKey, err := registry.GetProtocolByKey(key)
+	require.NoError(t, err)
+	require.Same(t, protocol, byKey)
+
+	require.NoError(t, registry.SetProtocolEnabled(EthChainID, LidoContractAddress, true))
+
+	enabled, err = registry.IsProtocolEnabled(EthChainID, LidoContractAddress)
+	require.NoError(t, err)
+	require.True(t, enabled)
+
+	_, err = protocol.GenerateCalldata(context.Background(), EthChainID, NativeStake, params)
+	require.NoError(t, err)
+
+	t.Run("registered disabled", func(t *testing.T) {
+		registry, err := NewProtocolRegistry([]ChainConfig{
+			{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+		},
+			WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+			WithDisabledProtocols(Lido, RocketPool, Compound))
+		require.NoError(t, err)
+
+		lido, err := NewLidoOperation(pkgtest.NewClient(EthChainID), EthChainID)
+		require.NoError(t, err)
+
+		err = registry.RegisterProtocol(EthChainID, LidoContractAddress, lido, WithEnabled(false))
+		require.NoError(t, err)
+
+		enabled, err := registry.IsProtocolEnabled(EthChainID, LidoContractAddress)
+		require.NoError(t, err)
+		require.False(t, enabled)
+
+		_, err = lido.GenerateCalldata(context.Background(), EthChainID, NativeStake, params)
+		require.ErrorIs(t, err, ErrProtocolDeprecated)
+	})
+
+	t.Run("not deprecatable", func(t *testing.T) {
+		wrapped := undeprecatable{protocol}
+
+		err := registry.RegisterProtocol(EthChainID, testAccount, wrapped, WithEnabled(false))
+		require.NoError(t, err)
+
+		// only the registry can refuse it
+		_, err = registry.GenerateBatch(context.Background(), EthChainID, []BatchStep{
+			{Address: testAccount, Action: NativeStake, Params: params},
+		})
+		require.ErrorIs(t, err, ErrProtocolDeprecated)
+
+		require.NoError(t, registry.SetProtocolEnabled(EthChainID, testAccount, true))
+
+		_, err = registry.GenerateBatch(context.Background(), EthChainID, []BatchStep{
+			{Address: testAccount, Action: NativeStake, Params: params},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("unknown protocol", func(t *testing.T) {
+		err := registry.SetProtocolEnabled(EthChainID, common.HexToAddress("0xdead"), false)
+		require.Error(t, err)
+
+		err = registry.SetProtocolEnabled(BscChainID, LidoContractAddress, false)
+		require.Error(t, err)
+
+		_, err = registry.IsProtocolEnabled(BscChainID, LidoContractAddress)
+		require.Error(t, err)
+	})
+}
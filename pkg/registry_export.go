@@ -0,0 +1,81 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ProtocolExport describes a registered protocol in the output of Export
+type ProtocolExport struct {
+	Name             string           `json:"name"`
+	Version          string           `json:"version"`
+	Type             ProtocolType     `json:"type"`
+	ChainID          *big.Int         `json:"chain_id"`
+	Address          common.Address   `json:"address"`
+	Key              string           `json:"key"`
+	SupportedActions []ContractAction `json:"supported_actions"`
+	SupportedAssets  []common.Address `json:"supported_assets"`
+
+	protocol Protocol
+}
+
+// Export serializes every registered protocol to a JSON array sorted by chain
+// and address. It is read only and the clients are not part of the output
+func (r *ProtocolRegistryImpl) Export() ([]byte, error) {
+
+	protocols := r.exportSnapshot()
+
+	ctx := context.Background()
+
+	for i, v := range protocols {
+		assets, err := v.protocol.GetSupportedAssets(ctx, v.ChainID)
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch the supported assets of %s: %w", v.Key, err)
+		}
+
+		protocols[i].SupportedAssets = assets
+	}
+
+	return json.Marshal(protocols)
+}
+
+// exportSnapshot describes the registered protocols without their supported
+// assets, which can take calls to the node and are not read under the lock
+func (r *ProtocolRegistryImpl) exportSnapshot() []ProtocolExport {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	protocols := []ProtocolExport{}
+	for chainIDStr, chainProtocols := range r.protocols {
+		chainID, _ := new(big.Int).SetString(chainIDStr, 10)
+
+		for address, entry := range chainProtocols {
+			protocol := entry.protocol
+			protocols = append(protocols, ProtocolExport{
+				Name:             protocol.GetName(),
+				Version:          protocol.GetVersion(),
+				Type:             protocol.GetType(),
+				ChainID:          chainID,
+				Address:          common.HexToAddress(address),
+				Key:              protocol.GetUniqueKey(),
+				SupportedActions: protocol.Capabilities().SupportedActions,
+				protocol:         protocol,
+			})
+		}
+	}
+
+	sort.Slice(protocols, func(i, j int) bool {
+		if c := protocols[i].ChainID.Cmp(protocols[j].ChainID); c != 0 {
+			return c < 0
+		}
+
+		return protocols[i].Address.Hex() < protocols[j].Address.Hex()
+	})
+
+	return protocols
+}
@@ -0,0 +1,53 @@
+package pkg
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocolRegistry_Export(t *testing.T) {
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+		WithDisabledProtocols(RocketPool, Compound, SparkLend, EigenLayer, Stargate, Yearn, SavingsDAI, SparkSavings))
+	require.NoError(t, err)
+
+	b, err := registry.Export()
+	require.NoError(t, err)
+
+	var protocols []ProtocolExport
+	require.NoError(t, json.Unmarshal(b, &protocols))
+
+	byName := make(map[string]ProtocolExport)
+	for _, protocol := range protocols {
+		require.Zero(t, protocol.ChainID.Cmp(EthChainID))
+		byName[protocol.Name] = protocol
+	}
+
+	require.Len(t, byName, 3)
+
+	aave, ok := byName[AaveV3]
+	require.True(t, ok)
+	require.Equal(t, AaveEthereumV3ContractAddress, aave.Address)
+	require.Equal(t, TypeLoan, aave.Type)
+	require.Equal(t, "aave_v3:1", aave.Key)
+	require.Contains(t, aave.SupportedActions, LoanSupply)
+	require.Contains(t, aave.SupportedAssets, testUSDC)
+
+	lido, ok := byName[Lido]
+	require.True(t, ok)
+	require.Equal(t, LidoContractAddress, lido.Address)
+	require.Equal(t, TypeStake, lido.Type)
+	require.Equal(t, []ContractAction{NativeStake}, lido.SupportedActions)
+	require.NotEmpty(t, lido.SupportedAssets)
+
+	// the output is stable
+	again, err := registry.Export()
+	require.NoError(t, err)
+	require.Equal(t, string(b), string(again))
+}
@@ -0,0 +1,89 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// chainPinger is implemented by clients able to report the chain they are
+// connected to and its head, like *ethclient.Client
+type chainPinger interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// HealthCheck pings the client of every configured chain and reports its
+// connectivity keyed by chain id. A nil error means the chain is reachable
+// and the client is connected to the expected chain
+func (r *ProtocolRegistryImpl) HealthCheck(ctx context.Context) map[string]error {
+
+	r.mu.RLock()
+	configs := make([]ChainConfig, 0, len(r.chainConfigs))
+	for _, config := range r.chainConfigs {
+		configs = append(configs, config)
+	}
+	r.mu.RUnlock()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	results := make(map[string]error, len(configs))
+
+	for _, config := range configs {
+		wg.Add(1)
+
+		go func(config ChainConfig) {
+			defer wg.Done()
+
+			err := r.checkChain(ctx, config)
+
+			mu.Lock()
+			results[config.ChainID.String()] = err
+			mu.Unlock()
+		}(config)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+func (r *ProtocolRegistryImpl) checkChain(ctx context.Context, config ChainConfig) error {
+
+	client, err := r.dial(config)
+	if err != nil {
+		return err
+	}
+
+	pinger, ok := unwrapClient(client).(chainPinger)
+	if !ok {
+		networkID, err := client.NetworkID(ctx)
+		if err != nil {
+			return err
+		}
+
+		return checkChainID(config.ChainID, networkID)
+	}
+
+	chainID, err := pinger.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := checkChainID(config.ChainID, chainID); err != nil {
+		return err
+	}
+
+	_, err = pinger.BlockNumber(ctx)
+	return err
+}
+
+func checkChainID(expected, actual *big.Int) error {
+	if expected.Cmp(actual) != 0 {
+		return fmt.Errorf("client is connected to chain %s instead of %s", actual, expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocolRegistry_HealthCheck(t *testing.T) {
+
+	optimism := big.NewInt(10)
+	fantom := big.NewInt(250)
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+		// nothing listens there
+		{ChainID: fantom, RPCURL: "http://127.0.0.1:1"},
+		{ChainID: optimism, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+		// connected to the wrong chain
+		WithClient(optimism, pkgtest.NewClient(EthChainID)),
+		WithDisabledProtocols(RocketPool, Compound))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	health := registry.HealthCheck(ctx)
+	require.Len(t, health, 3)
+
+	require.NoError(t, health[EthChainStr])
+	require.Error(t, health[fantom.String()])
+	require.ErrorContains(t, health[optimism.String()], "instead of 10")
+}
+
+// countingTransport counts the requests sent through it
+type countingTransport struct {
+	requests atomic.Int32
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestProtocolRegistry_DialOptions(t *testing.T) {
+
+	var mu sync.Mutex
+	var apiKeys []string
+
+	// a Gnosis node requiring an API key
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		apiKeys = append(apiKeys, req.Header.Get("X-Api-Key"))
+		mu.Unlock()
+
+		var msg struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		result := "0x64"
+		if msg.Method == "eth_blockNumber" {
+			result = "0x10"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      msg.ID,
+			"result":  result,
+		})
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{
+			ChainID:    GnosisChainID,
+			RPCURL:     server.URL,
+			HTTPClient: &http.Client{Transport: transport, Timeout: 5 * time.Second},
+			Headers:    map[string]string{"X-Api-Key": "secret"},
+		},
+	})
+	require.NoError(t, err)
+
+	health := registry.HealthCheck(context.Background())
+	require.NoError(t, health[GnosisChainID.String()])
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.NotEmpty(t, apiKeys)
+	for _, apiKey := range apiKeys {
+		require.Equal(t, "secret", apiKey)
+	}
+
+	require.EqualValues(t, len(apiKeys), transport.requests.Load())
+}
@@ -0,0 +1,92 @@
+package pkg
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RegistryOption configures optional behaviour of the ProtocolRegistryImpl
+type RegistryOption func(*ProtocolRegistryImpl)
+
+// WithClient makes the registry use an already dialed client for the chain
+// instead of dialing the RPCURL from its ChainConfig
+func WithClient(chainID *big.Int, client EthClient) RegistryOption {
+	return func(r *ProtocolRegistryImpl) {
+		r.clients[chainID.String()] = client
+	}
+}
+
+// WithHTTPTimeout sets the timeout of the HTTP client used when dialing
+// the RPCURL of each chain
+func WithHTTPTimeout(timeout time.Duration) RegistryOption {
+	return func(r *ProtocolRegistryImpl) {
+		r.httpTimeout = timeout
+	}
+}
+
+// WithDisabledProtocols skips the setup of the named protocols on every chain
+func WithDisabledProtocols(names ...ProtocolName) RegistryOption {
+	return func(r *ProtocolRegistryImpl) {
+		for _, name := range names {
+			r.disabledProtocols[name] = struct{}{}
+		}
+	}
+}
+
+// WithReferral sets the referral used by the protocols that support one.
+// address is passed to Lido's submit and code to Aave's supply
+func WithReferral(address common.Address, code uint16) RegistryOption {
+	return func(r *ProtocolRegistryImpl) {
+		r.referral = &referral{address: address}
+		r.referralCode = code
+	}
+}
+
+// WithDefaultReferralCode sets the referral code of the Aave supplies whose
+// ExtraData has no referral_code. It is 0 unless set here or with WithReferral
+func WithDefaultReferralCode(code uint16) RegistryOption {
+	return func(r *ProtocolRegistryImpl) {
+		r.referralCode = code
+	}
+}
+
+// WithRetry makes every operation retry the RPC calls failing with transient
+// errors like rate limits, up to attempts times with an exponential backoff
+// starting at baseDelay
+func WithRetry(attempts int, baseDelay time.Duration) RegistryOption {
+	return func(r *ProtocolRegistryImpl) {
+		r.retryAttempts = attempts
+		r.retryBaseDelay = baseDelay
+	}
+}
+
+// WithAssetMetadata resolves the metadata of known assets in
+// GetSupportedAssetsDetailed and the strict_decimals checks of Validate
+// without reading them from the chain
+func WithAssetMetadata(metadata AssetMetadata) RegistryOption {
+	return func(r *ProtocolRegistryImpl) {
+		r.assetMetadata = metadata
+	}
+}
+
+// WithObserver reports the activity of the registered protocols to observer,
+// the calldata they generate and their failed node calls. It is set on every
+// protocol implementing Observable, including the ones passed to RegisterProtocol
+func WithObserver(observer Observer) RegistryOption {
+	return func(r *ProtocolRegistryImpl) {
+		r.observer = observer
+	}
+}
+
+// WithProxyCheck makes RegisterProtocol report the protocols registered at the
+// implementation of an upgradeable proxy instead of the proxy with
+// ErrProxyImplementation, to the observer of WithObserver when it implements
+// WarningObserver. It costs a few node calls per protocol, so is meant for
+// maintainers adding new protocols
+func WithProxyCheck() RegistryOption {
+	return func(r *ProtocolRegistryImpl) {
+		r.proxyCheck = true
+	}
+}
@@ -0,0 +1,341 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocolRegistry_Options(t *testing.T) {
+
+	t.Run("no options keeps the default behaviour", func(t *testing.T) {
+		registry, err := NewProtocolRegistry([]ChainConfig{})
+		require.NoError(t, err)
+		require.Empty(t, registry.clients)
+		require.Empty(t, registry.disabledProtocols)
+		require.Zero(t, registry.httpTimeout)
+	})
+
+	t.Run("http timeout", func(t *testing.T) {
+		registry, err := NewProtocolRegistry([]ChainConfig{}, WithHTTPTimeout(5*time.Second))
+		require.NoError(t, err)
+		require.Equal(t, 5*time.Second, registry.httpTimeout)
+	})
+
+	t.Run("injected client is used and disabled protocols are skipped", func(t *testing.T) {
+		registry, err := NewProtocolRegistry([]ChainConfig{
+			{
+				ChainID: EthChainID,
+				// never dialed since a client is injected
+				RPCURL: "http://127.0.0.1:1",
+			},
+		},
+			WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+			WithDisabledProtocols(RocketPool, Compound))
+		require.NoError(t, err)
+
+		_, err = registry.GetProtocol(EthChainID, AaveEthereumV3ContractAddress)
+		require.NoError(t, err)
+
+		_, err = registry.GetProtocol(EthChainID, LidoContractAddress)
+		require.NoError(t, err)
+
+		_, err = registry.GetProtocol(EthChainID, RocketPoolStorageAddress)
+		require.Error(t, err)
+	})
+	t.Run("referral is passed to lido and aave", func(t *testing.T) {
+		referral := common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+
+		registry, err := NewProtocolRegistry([]ChainConfig{
+			{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+		},
+			WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+			WithDisabledProtocols(RocketPool, Compound),
+			WithReferral(referral, 42))
+		require.NoError(t, err)
+
+		lido, err := registry.GetProtocol(EthChainID, LidoContractAddress)
+		require.NoError(t, err)
+
+		calldata, err := lido.GenerateCalldata(context.Background(), EthChainID, NativeStake, TransactionParams{
+			Amount: big.NewInt(1e18),
+			Sender: testAccount,
+			Asset:  common.HexToAddress(nativeDenomAddress),
+		})
+		require.NoError(t, err)
+		require.True(t, strings.HasSuffix(calldata, strings.ToLower(referral.Hex()[2:])))
+
+		aave, err := registry.GetProtocol(EthChainID, AaveEthereumV3ContractAddress)
+		require.NoError(t, err)
+
+		calldata, err = aave.GenerateCalldata(context.Background(), EthChainID, LoanSupply, TransactionParams{
+			Amount: big.NewInt(1e6),
+			Sender: testAccount,
+			Asset:  testUSDC,
+		})
+		require.NoError(t, err)
+		// the referral code is the last packed argument of supply
+		require.True(t, strings.HasSuffix(calldata, "000000000000000000000000000000000000000000000000000000000000002a"))
+	})
+	t.Run("default referral code", func(t *testing.T) {
+		supply := func(extraData map[string]interface{}, opts ...RegistryOption) string {
+			registry, err := NewProtocolRegistry([]ChainConfig{
+				{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+			}, append([]RegistryOption{
+				WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+				WithDisabledProtocols(RocketPool, Compound),
+			}, opts...)...)
+			require.NoError(t, err)
+
+			aave, err := registry.GetProtocol(EthChainID, AaveEthereumV3ContractAddress)
+			require.NoError(t, err)
+
+			calldata, err := aave.GenerateCalldata(context.Background(), EthChainID, LoanSupply, TransactionParams{
+				Amount:    big.NewInt(1e6),
+				Sender:    testAccount,
+				Asset:     testUSDC,
+				ExtraData: extraData,
+			})
+			require.NoError(t, err)
+
+			// the referral code is the last packed argument of supply
+			return calldata[len(calldata)-64:]
+		}
+
+		require.Equal(t, "0000000000000000000000000000000000000000000000000000000000000000", supply(nil))
+		require.Equal(t, "0000000000000000000000000000000000000000000000000000000000000007",
+			supply(nil, WithDefaultReferralCode(7)))
+		require.Equal(t, "000000000000000000000000000000000000000000000000000000000000002a",
+			supply(map[string]interface{}{"referral_code": 42}, WithDefaultReferralCode(7)))
+	})
+}
+
+func TestProtocolRegistry_ListAllProtocols(t *testing.T) {
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+		{ChainID: BscChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+		WithClient(BscChainID, pkgtest.NewClient(BscChainID)),
+		WithDisabledProtocols(RocketPool, Compound))
+	require.NoError(t, err)
+
+	all := registry.ListAllProtocols()
+	require.Len(t, all, 2)
+	require.ElementsMatch(t, registry.ListProtocols(EthChainID), all[EthChainStr])
+	require.ElementsMatch(t, registry.ListProtocols(BscChainID), all[BscChainStr])
+
+	// mutating the snapshot does not leak into the registry
+	all[EthChainStr][0] = nil
+	all[BscChainStr] = nil
+	require.NotContains(t, registry.ListProtocols(EthChainID), nil)
+	require.NotEmpty(t, registry.ListAllProtocols()[BscChainStr])
+}
+
+func TestProtocolRegistry_ListChains(t *testing.T) {
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: PolygonChainID, RPCURL: "http://127.0.0.1:1"},
+		{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+		{ChainID: BscChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+		WithClient(BscChainID, pkgtest.NewClient(BscChainID)),
+		WithClient(PolygonChainID, pkgtest.NewClient(PolygonChainID)),
+		WithDisabledProtocols(RocketPool, Compound))
+	require.NoError(t, err)
+
+	chains := registry.ListChains()
+	require.Equal(t, []*big.Int{EthChainID, BscChainID, PolygonChainID}, chains)
+
+	// the ids are copies
+	chains[0].SetInt64(10)
+	require.Zero(t, registry.ListChains()[0].Cmp(EthChainID))
+	require.Zero(t, EthChainID.Cmp(big.NewInt(1)))
+}
+
+func TestProtocolRegistry_DuplicateSetup(t *testing.T) {
+
+	client := pkgtest.NewClient(EthChainID)
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(EthChainID, client),
+		WithDisabledProtocols(RocketPool, Compound))
+	require.NoError(t, err)
+
+	aave, err := registry.GetProtocol(EthChainID, AaveEthereumV3ContractAddress)
+	require.NoError(t, err)
+
+	protocols := len(registry.ListProtocols(EthChainID))
+
+	// the same Aave address again is skipped without creating the protocol
+	err = registry.registerProtocol(AaveV3, AaveEthereumV3ContractAddress, EthChainID, func(ChainConfig) (Protocol, error) {
+		t.Fatal("the protocol must not be created")
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	// so is a whole setup running twice
+	require.NoError(t, registry.setupEthProtocols(client))
+
+	registered, err := registry.GetProtocol(EthChainID, AaveEthereumV3ContractAddress)
+	require.NoError(t, err)
+	require.Same(t, aave, registered)
+	require.Len(t, registry.ListProtocols(EthChainID), protocols)
+
+	// registering explicitly still fails
+	err = registry.RegisterProtocol(EthChainID, AaveEthereumV3ContractAddress, aave)
+	require.ErrorIs(t, err, ErrProtocolAlreadyRegistered)
+}
+
+func TestProtocolRegistry_Gnosis(t *testing.T) {
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: GnosisChainID, RPCURL: "http://127.0.0.1:1"},
+	}, WithClient(GnosisChainID, pkgtest.NewClient(GnosisChainID)))
+	require.NoError(t, err)
+
+	protocol, err := registry.GetProtocol(GnosisChainID, AaveGnosisV3ContractAddress)
+	require.NoError(t, err)
+	require.Equal(t, AaveV3, protocol.GetName())
+
+	protocol, err = registry.GetProtocol(GnosisChainID, SavingsDAIGnosisContractAddress)
+	require.NoError(t, err)
+	require.Equal(t, SavingsDAI, protocol.GetName())
+
+	// sDAI holds WXDAI on Gnosis
+	require.True(t, protocol.IsSupportedAsset(context.Background(), GnosisChainID, savingsDAIGnosisAsset))
+	require.False(t, protocol.IsSupportedAsset(context.Background(), GnosisChainID, savingsDAIAsset))
+
+	require.Len(t, registry.ListProtocols(GnosisChainID), 2)
+}
+
+func TestProtocolRegistry_Polygon(t *testing.T) {
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: PolygonChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(PolygonChainID, pkgtest.NewClient(PolygonChainID)),
+		WithDisabledProtocols(Compound))
+	require.NoError(t, err)
+
+	ankr, err := registry.GetProtocol(PolygonChainID, AnkrPolygonContractAddress)
+	require.NoError(t, err)
+	require.Equal(t, Ankr, ankr.GetName())
+
+	lido, err := registry.GetProtocol(PolygonChainID, LidoPolygonContractAddress)
+	require.NoError(t, err)
+	require.Equal(t, Lido, lido.GetName())
+
+	stargate, err := registry.GetProtocol(PolygonChainID, StargateUSDCPolygonContractAddress)
+	require.NoError(t, err)
+	require.Equal(t, TypeBridge, stargate.GetType())
+
+	require.Len(t, registry.ListProtocols(PolygonChainID), 4)
+}
+
+func TestProtocolRegistry_ListProtocolsByType(t *testing.T) {
+
+	client := pkgtest.NewClient(PolygonChainID)
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: PolygonChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(PolygonChainID, client),
+		WithDisabledProtocols(Compound))
+	require.NoError(t, err)
+
+	pendle, err := NewPendleOperation(client, PolygonChainID, []common.Address{testPendleSY})
+	require.NoError(t, err)
+	require.NoError(t, registry.RegisterProtocol(PolygonChainID, testPendleSY, pendle))
+
+	names := func(protocolType ProtocolType) []string {
+		var names []string
+		for _, protocol := range registry.ListProtocolsByType(PolygonChainID, protocolType) {
+			require.Equal(t, protocolType, protocol.GetType())
+			names = append(names, protocol.GetName())
+		}
+		return names
+	}
+
+	require.ElementsMatch(t, []string{AaveV3}, names(TypeLoan))
+	require.ElementsMatch(t, []string{Ankr, Lido}, names(TypeStake))
+	require.ElementsMatch(t, []string{Stargate}, names(TypeBridge))
+	require.ElementsMatch(t, []string{Pendle}, names(TypeVault))
+	require.Empty(t, names(TypeSwap))
+
+	require.Empty(t, registry.ListProtocolsByType(BscChainID, TypeLoan))
+}
+
+func TestProtocolRegistry_Avalanche(t *testing.T) {
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: AvalancheChainID, RPCURL: "http://127.0.0.1:1"},
+	}, WithClient(AvalancheChainID, pkgtest.NewClient(AvalancheChainID)))
+	require.NoError(t, err)
+
+	aave, err := registry.GetProtocol(AvalancheChainID, AaveAvalancheV3ContractAddress)
+	require.NoError(t, err)
+	require.Equal(t, AaveV3, aave.GetName())
+	require.Equal(t, AaveAvalancheV3ContractAddress, aave.GetContractAddress(AvalancheChainID))
+
+	benqi, err := registry.GetProtocol(AvalancheChainID, BenqiSAVAXContractAddress)
+	require.NoError(t, err)
+	require.Equal(t, Benqi, benqi.GetName())
+
+	stargate, err := registry.GetProtocol(AvalancheChainID, StargateUSDCAvalancheContractAddress)
+	require.NoError(t, err)
+	require.Equal(t, Stargate, stargate.GetName())
+
+	require.Len(t, registry.ListProtocols(AvalancheChainID), 3)
+}
+
+func TestProtocolRegistry_GetProtocolByKey(t *testing.T) {
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+		{ChainID: BscChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+		WithClient(BscChainID, pkgtest.NewClient(BscChainID)),
+		WithDisabledProtocols(RocketPool, Compound))
+	require.NoError(t, err)
+
+	ethAave, err := registry.GetProtocol(EthChainID, AaveEthereumV3ContractAddress)
+	require.NoError(t, err)
+
+	bscAave, err := registry.GetProtocol(BscChainID, AaveBnbV3ContractAddress)
+	require.NoError(t, err)
+
+	require.Equal(t, ethAave.GetName(), bscAave.GetName())
+	require.Equal(t, "aave_v3:1", ethAave.GetUniqueKey())
+	require.Equal(t, "aave_v3:56", bscAave.GetUniqueKey())
+
+	protocol, err := registry.GetProtocolByKey("aave_v3:1")
+	require.NoError(t, err)
+	require.Same(t, ethAave, protocol)
+
+	protocol, err = registry.GetProtocolByKey("aave_v3:56")
+	require.NoError(t, err)
+	require.Same(t, bscAave, protocol)
+
+	_, err = registry.GetProtocolByKey("aave_v3:137")
+	require.Error(t, err)
+
+	t.Run("duplicate key is rejected", func(t *testing.T) {
+		aave, err := NewAaveOperation(pkgtest.NewClient(EthChainID), EthChainID, AaveProtocolDeploymentEthereum)
+		require.NoError(t, err)
+
+		err = registry.RegisterProtocol(EthChainID, common.HexToAddress("0x000000000000000000000000000000000000bEEF"), aave)
+		require.Error(t, err)
+	})
+}
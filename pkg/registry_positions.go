@@ -0,0 +1,115 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// maxPositionWorkers bounds the balance lookups GetPositions runs concurrently
+const maxPositionWorkers = 8
+
+// Position is the balance an account holds in a protocol
+type Position struct {
+	Protocol string
+	Token    common.Address
+	Balance  *big.Int
+}
+
+// positionLookup is a single GetBalance call made by GetPositions
+type positionLookup struct {
+	protocol Protocol
+	asset    common.Address
+}
+
+// GetPositions returns the non zero balances account holds across every protocol
+// registered on chainID. Positions that could be read are returned along with
+// the errors of the protocols that could not be queried
+func (r *ProtocolRegistryImpl) GetPositions(ctx context.Context, chainID *big.Int,
+	account common.Address) ([]Position, error) {
+
+	var lookups []positionLookup
+	var errs []error
+
+	for _, protocol := range r.ListProtocols(chainID) {
+		assets, err := protocol.GetSupportedAssets(ctx, chainID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", protocol.GetName(), err))
+			continue
+		}
+
+		for _, asset := range assets {
+			lookups = append(lookups, positionLookup{protocol: protocol, asset: asset})
+		}
+	}
+
+	positions := make([]*Position, len(lookups))
+	lookupErrs := make([]error, len(lookups))
+
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < maxPositionWorkers && i < len(lookups); i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				lookup := lookups[i]
+
+				token, balance, err := lookup.protocol.GetBalance(ctx, chainID, account, lookup.asset)
+				if err != nil {
+					lookupErrs[i] = fmt.Errorf("%s %s: %w", lookup.protocol.GetName(), lookup.asset, err)
+					continue
+				}
+
+				if balance == nil || balance.Sign() == 0 {
+					continue
+				}
+
+				positions[i] = &Position{
+					Protocol: lookup.protocol.GetName(),
+					Token:    token,
+					Balance:  balance,
+				}
+			}
+		}()
+	}
+
+	for i := range lookups {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	// staking protocols report the same token for every asset they accept
+	seen := make(map[string]bool)
+
+	result := []Position{}
+	for i, position := range positions {
+		if lookupErrs[i] != nil {
+			errs = append(errs, lookupErrs[i])
+			continue
+		}
+
+		if position == nil {
+			continue
+		}
+
+		key := position.Protocol + position.Token.Hex()
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		result = append(result, *position)
+	}
+
+	return result, errors.Join(errs...)
+}
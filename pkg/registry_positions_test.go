@@ -0,0 +1,60 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocolRegistry_GetPositions_Unit(t *testing.T) {
+
+	erc20ABI, err := abi.JSON(strings.NewReader(erc20BalanceOfABI))
+	require.NoError(t, err)
+
+	balanceOf := erc20ABI.Methods["balanceOf"]
+
+	client := pkgtest.NewClient(EthChainID)
+	client.HandleContract(LidoContractAddress, balanceOf.ID, pkgtest.Returns(balanceOf, big.NewInt(5e18)))
+	client.HandleContract(ankrEthER20Account, balanceOf.ID, pkgtest.Returns(balanceOf, big.NewInt(0)))
+
+	newRegistry := func(t *testing.T, disabled ...ProtocolName) *ProtocolRegistryImpl {
+		registry, err := NewProtocolRegistry([]ChainConfig{
+			{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+		},
+			WithClient(EthChainID, client),
+			WithDisabledProtocols(append([]ProtocolName{RocketPool, Compound, SparkLend, EigenLayer, Stargate, Yearn, SavingsDAI, SparkSavings}, disabled...)...))
+		require.NoError(t, err)
+
+		return registry
+	}
+
+	t.Run("zero balances are skipped", func(t *testing.T) {
+		positions, err := newRegistry(t, AaveV3).GetPositions(context.Background(), EthChainID, testAccount)
+		require.NoError(t, err)
+
+		require.Equal(t, []Position{
+			{Protocol: Lido, Token: LidoContractAddress, Balance: big.NewInt(5e18)},
+		}, positions)
+	})
+
+	t.Run("failing protocols do not hide the others", func(t *testing.T) {
+		// Aave reserves are not mocked
+		positions, err := newRegistry(t).GetPositions(context.Background(), EthChainID, testAccount)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), AaveV3)
+
+		require.Len(t, positions, 1)
+		require.Equal(t, Lido, positions[0].Protocol)
+	})
+
+	t.Run("unknown chain", func(t *testing.T) {
+		positions, err := newRegistry(t).GetPositions(context.Background(), BscChainID, testAccount)
+		require.NoError(t, err)
+		require.Empty(t, positions)
+	})
+}
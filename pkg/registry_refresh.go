@@ -0,0 +1,59 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// AssetRefresher is implemented by protocols pulling their supported assets from
+// the chain, e.g the collaterals of a Compound market or the reserves of an Aave pool
+type AssetRefresher interface {
+	RefreshSupportedAssets(ctx context.Context) error
+}
+
+var (
+	_ AssetRefresher = (*CompoundOperation)(nil)
+	_ AssetRefresher = (*AaveOperation)(nil)
+)
+
+// RefreshAll refreshes the supported assets of every registered protocol
+// implementing AssetRefresher in parallel, so newly listed assets are picked up
+// without recreating the registry. The errors of the protocols that could not
+// be refreshed are joined, the others are refreshed regardless
+func (r *ProtocolRegistryImpl) RefreshAll(ctx context.Context) error {
+
+	var refreshers []Protocol
+	for _, protocols := range r.ListAllProtocols() {
+		for _, protocol := range protocols {
+			if _, ok := protocol.(AssetRefresher); ok {
+				refreshers = append(refreshers, protocol)
+			}
+		}
+	}
+
+	errs := make([]error, len(refreshers))
+
+	var wg sync.WaitGroup
+	for i, protocol := range refreshers {
+		wg.Add(1)
+
+		go func(i int, protocol Protocol) {
+			defer wg.Done()
+
+			if err := ctx.Err(); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", protocol.GetUniqueKey(), err)
+				return
+			}
+
+			if err := protocol.(AssetRefresher).RefreshSupportedAssets(ctx); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", protocol.GetUniqueKey(), err)
+			}
+		}(i, protocol)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
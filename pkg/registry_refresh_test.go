@@ -0,0 +1,91 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocolRegistry_RefreshAll(t *testing.T) {
+
+	wbtc := common.HexToAddress("0x2260FAC5E5542a773Aa44fBCfeDf7C193bc2C599")
+	link := common.HexToAddress("0x514910771AF9Ca656af840dff83E8264EcF986CA")
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+		WithDisabledProtocols(AaveV3, Ankr, RocketPool, Compound, SparkLend, EigenLayer, Stargate, Yearn, SavingsDAI, SparkSavings))
+	require.NoError(t, err)
+
+	market := newCompoundMarket(t, wbtc)
+
+	compound, err := NewCompoundOperation(market.client(), EthChainID, common.HexToAddress(CompoundV3USDCPool))
+	require.NoError(t, err)
+	require.NoError(t, registry.RegisterProtocol(EthChainID, common.HexToAddress(CompoundV3USDCPool), compound))
+
+	reserves := []common.Address{testUSDC}
+
+	aaveClient := pkgtest.NewClient(EthChainID)
+
+	aave, err := NewAaveOperation(aaveClient, EthChainID, AaveProtocolDeploymentEthereum, WithDynamicAssets(true))
+	require.NoError(t, err)
+	require.NoError(t, registry.RegisterProtocol(EthChainID, AaveEthereumV3ContractAddress, aave))
+
+	method := aave.parsedABI.Methods["getReservesList"]
+	aaveClient.HandleContract(AaveEthereumV3ContractAddress, method.ID, func(ethereum.CallMsg) ([]byte, error) {
+		return method.Outputs.Pack(reserves)
+	})
+
+	require.NoError(t, registry.RefreshAll(context.Background()))
+
+	require.False(t, compound.IsSupportedAsset(context.Background(), EthChainID, link))
+	require.False(t, aave.IsSupportedAsset(context.Background(), EthChainID, testDAI))
+
+	// new assets are listed
+	market.setAssets(wbtc, link)
+	reserves = []common.Address{testUSDC, testDAI}
+
+	require.NoError(t, registry.RefreshAll(context.Background()))
+
+	require.True(t, compound.IsSupportedAsset(context.Background(), EthChainID, link))
+	require.True(t, aave.IsSupportedAsset(context.Background(), EthChainID, testDAI))
+
+	t.Run("errors are aggregated", func(t *testing.T) {
+		unreachable := errors.New("node unreachable")
+		aaveClient.HandleContract(AaveEthereumV3ContractAddress, method.ID, func(ethereum.CallMsg) ([]byte, error) {
+			return nil, unreachable
+		})
+
+		market.setAssets(wbtc)
+
+		err := registry.RefreshAll(context.Background())
+		require.ErrorIs(t, err, unreachable)
+		require.ErrorContains(t, err, aave.GetUniqueKey())
+
+		// the other protocols are still refreshed and the previous reserves kept
+		require.False(t, compound.IsSupportedAsset(context.Background(), EthChainID, link))
+		require.True(t, aave.IsSupportedAsset(context.Background(), EthChainID, testDAI))
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := registry.RefreshAll(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("static assets are left untouched", func(t *testing.T) {
+		static, err := NewAaveOperation(pkgtest.NewClient(big.NewInt(100)), big.NewInt(100), AaveProtocolDeploymentEthereum)
+		require.NoError(t, err)
+
+		require.NoError(t, static.RefreshSupportedAssets(context.Background()))
+	})
+}
@@ -301,3 +301,31 @@ func TestProtocolOperation_OnceChainConfig(t *testing.T) {
 	})
 	require.NoError(t, err)
 }
+
+func TestProtocolRegistry_GetPositions(t *testing.T) {
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{
+			ChainID: big.NewInt(1),
+			RPCURL:  getTestRPCURL(t, ChainETH),
+		},
+	}, WithDisabledProtocols(RocketPool, Compound, EigenLayer))
+	require.NoError(t, err)
+
+	// the wstETH contract holds the stETH it wraps
+	wstETH := common.HexToAddress("0x7f39C581F595B53c5cb19bD0b3f8dA6c935E2Ca0")
+
+	positions, err := registry.GetPositions(context.Background(), big.NewInt(1), wstETH)
+	require.NoError(t, err)
+
+	var found bool
+	for _, position := range positions {
+		require.Positive(t, position.Balance.Sign())
+
+		if position.Protocol == Lido {
+			found = true
+		}
+	}
+
+	require.True(t, found, "expected a Lido position")
+}
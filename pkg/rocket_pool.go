@@ -8,10 +8,10 @@ import (
 	"math/big"
 	"strings"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/rocket-pool/rocketpool-go/rocketpool"
 	"github.com/rocket-pool/rocketpool-go/tokens"
 )
@@ -27,37 +27,30 @@ const RocketPoolABI = `
        },
        {
          "inputs": [
-           {
-             "internalType": "address",
-             "name": "recipient",
-             "type": "address"
-           },
            {
              "internalType": "uint256",
-             "name": "amount",
+             "name": "_rethAmount",
              "type": "uint256"
            }
          ],
-         "name": "transfer",
-         "outputs": [
-           {
-             "internalType": "bool",
-             "name": "",
-             "type": "bool"
-           }
-         ],
+         "name": "burn",
+         "outputs": [],
          "stateMutability": "nonpayable",
          "type": "function"
        }
      ]`
 
+// rocketpoolRETHAddress is the rETH token minted for staked ETH and burnt when unstaking
+var rocketpoolRETHAddress = common.HexToAddress("0xae78736Cd615f374D3085123A210448E74Fc6393")
+
 // RocketpoolOperation implements the Protocol interface for Ankr
 type RocketpoolOperation struct {
 	parsedABI abi.ABI
 	chainID   *big.Int
 	version   string
 
-	client *ethclient.Client
+	client   EthClient
+	observer Observer
 
 	// main deposit pool. this contract takes in the ETH
 	contract *rocketpool.Contract
@@ -69,10 +62,99 @@ type RocketpoolOperation struct {
 	depositSettingsContract *rocketpool.Contract
 
 	rp *rocketpool.RocketPool
+
+	// disabled through the registry, see SetProtocolEnabled
+	deprecation
+}
+
+var (
+	_ Protocol       = (*RocketpoolOperation)(nil)
+	_ DepositLimiter = (*RocketpoolOperation)(nil)
+)
+
+// retryExecutionClient retries the reads the rocketpool bindings make the same
+// way retryClient does for the other operations
+type retryExecutionClient struct {
+	rocketpool.ExecutionClient
+
+	retry *retryClient
+}
+
+func (c *retryExecutionClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var result []byte
+
+	err := c.retry.retry(ctx, func() error {
+		var err error
+		result, err = c.ExecutionClient.CallContract(ctx, msg, blockNumber)
+		return err
+	})
+
+	return result, err
 }
 
-func NewRocketpoolOperation(client *ethclient.Client, chainID *big.Int) (*RocketpoolOperation, error) {
-	rp, err := rocketpool.NewRocketPool(client, RocketPoolStorageAddress)
+func (c *retryExecutionClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	var code []byte
+
+	err := c.retry.retry(ctx, func() error {
+		var err error
+		code, err = c.ExecutionClient.CodeAt(ctx, contract, blockNumber)
+		return err
+	})
+
+	return code, err
+}
+
+func (c *retryExecutionClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	var balance *big.Int
+
+	err := c.retry.retry(ctx, func() error {
+		var err error
+		balance, err = c.ExecutionClient.BalanceAt(ctx, account, blockNumber)
+		return err
+	})
+
+	return balance, err
+}
+
+// rocketpoolExecutionClient returns the rocketpool.ExecutionClient client wraps.
+// The other wrappers are dropped but the retries set up with WithRetry are kept
+func rocketpoolExecutionClient(client EthClient) (rocketpool.ExecutionClient, bool) {
+
+	var retry *retryClient
+	for c := client; ; {
+		if r, ok := c.(*retryClient); ok {
+			retry = r
+			break
+		}
+
+		wrapper, ok := c.(interface{ Unwrap() EthClient })
+		if !ok {
+			break
+		}
+
+		c = wrapper.Unwrap()
+	}
+
+	executionClient, ok := unwrapClient(client).(rocketpool.ExecutionClient)
+	if !ok {
+		return nil, false
+	}
+
+	if retry == nil {
+		return executionClient, true
+	}
+
+	return &retryExecutionClient{ExecutionClient: executionClient, retry: retry}, true
+}
+
+func NewRocketpoolOperation(client EthClient, chainID *big.Int) (*RocketpoolOperation, error) {
+	// the rocketpool bindings need more than the calls the other operations rely on
+	executionClient, ok := rocketpoolExecutionClient(client)
+	if !ok {
+		return nil, errors.New("rocketpool requires a client implementing rocketpool.ExecutionClient")
+	}
+
+	rp, err := rocketpool.NewRocketPool(executionClient, RocketPoolStorageAddress)
 	if err != nil {
 		return nil, err
 	}
@@ -135,7 +217,22 @@ func NewRocketpoolOperation(client *ethclient.Client, chainID *big.Int) (*Rocket
 // GenerateCalldata creates the necessary blockchain transaction data
 func (a *RocketpoolOperation) GenerateCalldata(ctx context.Context, chainID *big.Int,
 	action ContractAction, params TransactionParams) (string, error) {
-	if chainID.Int64() != 1 {
+	if err := a.deprecation.check(a.GetUniqueKey()); err != nil {
+		return "", err
+	}
+
+	if err := params.checkAmountRange(); err != nil {
+		return "", err
+	}
+
+	return observeCalldata(a.observer, a.GetName(), action, func() (string, error) {
+		return a.generateCalldata(ctx, chainID, action, params)
+	})
+}
+
+func (a *RocketpoolOperation) generateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+	if !IsEth(chainID) {
 		return "", ErrChainUnsupported
 	}
 
@@ -143,16 +240,22 @@ func (a *RocketpoolOperation) GenerateCalldata(ctx context.Context, chainID *big
 	case NativeStake:
 		return a.deposit(params)
 	case NativeUnStake:
+		if params.Amount == nil {
+			return "", ErrAmountRequired
+		}
+
 		return a.withdraw(params)
 
 	default:
-		return "", errors.New("unsupported action")
+		return "", ErrUnsupportedAction
 	}
 }
 
+// withdraw burns rETH for the ETH it is worth. The ETH is sent to the sender
+// and is taken from the collateral held by the rETH contract and the deposit pool
 func (r *RocketpoolOperation) withdraw(opts TransactionParams) (string, error) {
 
-	calldata, err := r.parsedABI.Pack("transfer", opts.GetBeneficiaryOwner(), opts.Amount)
+	calldata, err := r.parsedABI.Pack("burn", opts.Amount)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate calldata for %s: %w", "withdraw", err)
 	}
@@ -170,16 +273,59 @@ func (r *RocketpoolOperation) deposit(_ TransactionParams) (string, error) {
 	return HexPrefix + hex.EncodeToString(calldata), nil
 }
 
+// EstimateGas estimates the gas needed to execute the generated calldata.
+// Deposits send params.Amount to the deposit pool while unstaking burns rETH
+func (r *RocketpoolOperation) EstimateGas(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (uint64, error) {
+
+	tx, err := r.BuildTransaction(ctx, chainID, action, params)
+	if err != nil {
+		return 0, err
+	}
+
+	return estimateGas(ctx, r.client, params.Sender, tx)
+}
+
+// BuildTransaction generates the calldata for the action together with
+// the contract to call and the value to send
+func (r *RocketpoolOperation) BuildTransaction(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (*Transaction, error) {
+
+	calldata, err := r.GenerateCalldata(ctx, chainID, action, params)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := r.GetContractAddressForAction(chainID, action)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{
+		To:    to,
+		Data:  calldata,
+		Value: r.CallValue(action, params),
+	}, nil
+}
+
 // Validate checks if the provided parameters are valid for the specified action
 func (l *RocketpoolOperation) Validate(ctx context.Context,
 	chainID *big.Int, action ContractAction, params TransactionParams) error {
 
-	if chainID.Int64() != 1 {
+	if err := l.deprecation.check(l.GetUniqueKey()); err != nil {
+		return err
+	}
+
+	if !IsEth(chainID) {
 		return ErrChainUnsupported
 	}
 
 	if !l.IsSupportedAsset(ctx, l.chainID, params.Asset) {
-		return fmt.Errorf("asset not supported %s", params.Asset)
+		return fmt.Errorf("%w %s", ErrAssetNotSupported, params.Asset)
+	}
+
+	if err := params.ValidateAmount(); err != nil {
+		return err
 	}
 
 	var balance = new(big.Int)
@@ -188,58 +334,96 @@ func (l *RocketpoolOperation) Validate(ctx context.Context,
 	switch action {
 	case NativeStake:
 
-		amount := big.NewInt(0)
-
-		if err := l.contract.Call(&bind.CallOpts{}, &amount, "getMaximumDepositAmount"); err != nil {
+		min, max, err := l.GetDepositLimits(ctx, chainID)
+		if err != nil {
 			return err
 		}
 
-		if val := amount.Cmp(params.Amount); val == -1 {
+		if val := max.Cmp(params.Amount); val == -1 {
 			return errors.New("rocketpool not accepting this much eth deposit at this time")
 		}
 
-		amount = big.NewInt(0)
-
-		if err := l.depositSettingsContract.Call(&bind.CallOpts{}, &amount, "getMinimumDeposit"); err != nil {
-			return err
-		}
-
-		if val := amount.Cmp(params.Amount); val == 1 {
-			return errors.New("eth value too low to deposit to Rocketpool at this time")
+		if val := min.Cmp(params.Amount); val == 1 {
+			return fmt.Errorf("%w to deposit to Rocketpool at this time", ErrAmountTooLow)
 		}
 
 		return nil
 	case NativeUnStake:
 
-		// validate amount only during unstaking
-		if params.Amount.Cmp(big.NewInt(0)) <= 0 {
-			return errors.New("amount must be greater than zero")
-		}
+		if !params.lenientValidation(ValidationStrict) {
+			_, balance, err = l.GetBalance(ctx, l.chainID, params.Sender, params.Asset)
+			if err != nil {
+				return err
+			}
 
-		_, balance, err = l.GetBalance(ctx, l.chainID, params.Sender, params.Asset)
-		if err != nil {
-			return err
+			if balance.Cmp(params.Amount) == -1 {
+				return ErrInsufficientBalance
+			}
 		}
 
-		if balance.Cmp(params.Amount) == -1 {
-			return errors.New("balance not enough")
+		if err := l.validateBurnCollateral(ctx, params.Amount); err != nil {
+			return err
 		}
 
 	default:
 
-		return errors.New("action not supported")
+		return ErrUnsupportedAction
 	}
 
 	return nil
 }
 
+// validateBurnCollateral checks the rETH contract and the deposit pool hold
+// enough ETH to pay for burning amount of rETH
+func (l *RocketpoolOperation) validateBurnCollateral(ctx context.Context, amount *big.Int) error {
+
+	ethValue, err := tokens.GetETHValueOfRETH(l.rp, amount, &bind.CallOpts{Context: ctx})
+	if err != nil {
+		return err
+	}
+
+	collateral, err := tokens.GetRETHTotalCollateral(l.rp, &bind.CallOpts{Context: ctx})
+	if err != nil {
+		return err
+	}
+
+	if collateral.Cmp(ethValue) == -1 {
+		return errors.New("rocketpool does not have enough ETH collateral to burn this much rETH at this time")
+	}
+
+	return nil
+}
+
+// GetDepositLimits returns the minimum deposit from the protocol settings and
+// the maximum the deposit pool accepts at this time
+func (l *RocketpoolOperation) GetDepositLimits(ctx context.Context, chainID *big.Int) (*big.Int, *big.Int, error) {
+
+	if !IsEth(chainID) {
+		return nil, nil, ErrChainUnsupported
+	}
+
+	max := big.NewInt(0)
+
+	if err := l.contract.Call(&bind.CallOpts{Context: ctx}, &max, "getMaximumDepositAmount"); err != nil {
+		return nil, nil, err
+	}
+
+	min := big.NewInt(0)
+
+	if err := l.depositSettingsContract.Call(&bind.CallOpts{Context: ctx}, &min, "getMinimumDeposit"); err != nil {
+		return nil, nil, err
+	}
+
+	return min, max, nil
+}
+
 // GetBalance retrieves the balance for a specified account and asset
 func (l *RocketpoolOperation) GetBalance(ctx context.Context,
 	chainID *big.Int, account, _ common.Address) (common.Address, *big.Int, error) {
 
 	var address common.Address
 
-	if chainID.Int64() != 1 {
+	if !IsEth(chainID) {
 		return address, nil, ErrChainUnsupported
 	}
 
@@ -247,21 +431,26 @@ func (l *RocketpoolOperation) GetBalance(ctx context.Context,
 	return *l.rethContract.Address, bal, err
 }
 
-// GetSupportedAssets returns a list of assets supported by the protocol on the specified chain
+// GetSupportedAssets returns the native token staked and rETH, unstaked
+// and returned by GetBalance
 func (l *RocketpoolOperation) GetSupportedAssets(ctx context.Context, chainID *big.Int) ([]common.Address, error) {
+	if !IsEth(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
 	return []common.Address{
 		common.HexToAddress(nativeDenomAddress),
+		rocketpoolRETHAddress,
 	}, nil
 }
 
 // IsSupportedAsset checks if the specified asset is supported on the given chain
 func (l *RocketpoolOperation) IsSupportedAsset(ctx context.Context, chainID *big.Int, asset common.Address) bool {
-	if chainID.Int64() != 1 {
+	if !IsEth(chainID) {
 		return false
 	}
 
-	// native token or Reth
-	return IsNativeToken(asset) || asset.Hex() == common.HexToAddress("0xae78736cd615f374d3085123a210448e74fc6393").Hex()
+	return IsNativeToken(asset) || asset == rocketpoolRETHAddress
 }
 
 // GetProtocolConfig returns the protocol config for a specific chain
@@ -285,8 +474,56 @@ func (l *RocketpoolOperation) GetContractAddress(chainID *big.Int) common.Addres
 	return *l.contract.Address
 }
 
+// GetContractAddressForAction returns the contract the calldata of action is sent to.
+// Stakes go to the deposit pool while unstaking burns rETH on the token itself
+func (l *RocketpoolOperation) GetContractAddressForAction(chainID *big.Int,
+	action ContractAction) (common.Address, error) {
+
+	switch action {
+	case NativeStake:
+		return l.GetContractAddress(chainID), nil
+	case NativeUnStake:
+		return *l.rethContract.Address, nil
+	default:
+		return common.Address{}, ErrUnsupportedAction
+	}
+}
+
 // Name returns the human readable name for the protocol
 func (l *RocketpoolOperation) GetName() string { return RocketPool }
 
 // GetVersion returns the version of the protocol
 func (l *RocketpoolOperation) GetVersion() string { return l.version }
+
+// GetUniqueKey returns the key identifying the protocol across chains
+func (l *RocketpoolOperation) GetUniqueKey() string { return protocolKey(l.GetName(), l.chainID) }
+
+// CallValue returns the native amount to send along with the calldata.
+// The deposit call is payable and takes the staked ETH as msg.value
+func (l *RocketpoolOperation) CallValue(action ContractAction, params TransactionParams) *big.Int {
+	if action == NativeStake {
+		return params.nativeCallValue()
+	}
+
+	return big.NewInt(0)
+}
+
+// Capabilities describes the actions, chains and extra data supported by the protocol
+func (l *RocketpoolOperation) Capabilities() ProtocolCapabilities {
+	return ProtocolCapabilities{
+		SupportedActions:  []ContractAction{NativeStake, NativeUnStake},
+		SupportedChains:   []*big.Int{EthChainID},
+		RequiresExtraData: []string{},
+	}
+}
+
+// IsSupportedAction reports whether calldata can be generated for action
+func (l *RocketpoolOperation) IsSupportedAction(action ContractAction) bool {
+	return l.Capabilities().Supports(action)
+}
+
+// SetObserver implements Observable
+func (a *RocketpoolOperation) SetObserver(observer Observer) {
+	a.observer = observer
+	a.client = observeClient(a.client, a.GetName(), observer)
+}
@@ -90,6 +90,55 @@ func TestRocketPoolOperation_Validate(t *testing.T) {
 		})
 		require.Error(t, err)
 	})
+
+	t.Run("missing amount", func(t *testing.T) {
+		for _, action := range []ContractAction{NativeStake, NativeUnStake} {
+			err := rp.Validate(context.Background(), big.NewInt(1), action, TransactionParams{
+				Asset: common.HexToAddress(nativeDenomAddress),
+			})
+			require.ErrorIs(t, err, ErrAmountRequired)
+		}
+
+		_, err := rp.GenerateCalldata(context.Background(), big.NewInt(1), NativeUnStake, TransactionParams{})
+		require.ErrorIs(t, err, ErrAmountRequired)
+	})
+}
+
+func TestRocketPoolOperation_Validate_Unstake(t *testing.T) {
+
+	rp, err := NewRocketpoolOperation(getTestClient(t, ChainETH), big.NewInt(1))
+	require.NoError(t, err)
+
+	params := func(amount *big.Int) TransactionParams {
+		return TransactionParams{
+			Amount:    amount,
+			Asset:     common.HexToAddress(nativeDenomAddress),
+			Sender:    common.HexToAddress("0xFc21d6d146E6086B8359705C8b28512a983db0cb"),
+			ExtraData: map[string]interface{}{"validation_mode": ValidationLenient},
+		}
+	}
+
+	t.Run("collateral covers a small burn", func(t *testing.T) {
+		err := rp.Validate(context.Background(), big.NewInt(1), NativeUnStake, params(big.NewInt(1e9)))
+		require.NoError(t, err)
+	})
+
+	t.Run("collateral does not cover the whole supply", func(t *testing.T) {
+		// 100M rETH
+		amount, ok := new(big.Int).SetString("100000000000000000000000000", 10)
+		require.True(t, ok)
+
+		err := rp.Validate(context.Background(), big.NewInt(1), NativeUnStake, params(amount))
+		require.ErrorContains(t, err, "collateral")
+	})
+
+	t.Run("rETH balance is checked", func(t *testing.T) {
+		p := params(big.NewInt(1e9))
+		p.ExtraData = nil
+
+		err := rp.Validate(context.Background(), big.NewInt(1), NativeUnStake, p)
+		require.ErrorIs(t, err, ErrInsufficientBalance)
+	})
 }
 
 func TestRocketPoolOperation_IsSupportedAsset(t *testing.T) {
@@ -194,3 +243,19 @@ func TestRocketPoolOperation_GenerateCallData(t *testing.T) {
 		})
 	}
 }
+
+func TestRocketPoolOperation_GetDepositLimits(t *testing.T) {
+
+	rp, err := NewRocketpoolOperation(getTestClient(t, ChainETH), big.NewInt(1))
+	require.NoError(t, err)
+
+	min, max, err := rp.GetDepositLimits(context.Background(), big.NewInt(1))
+	require.NoError(t, err)
+
+	require.Equal(t, 1, min.Sign())
+	require.NotNil(t, max)
+	require.GreaterOrEqual(t, max.Sign(), 0)
+
+	_, _, err = rp.GetDepositLimits(context.Background(), BscChainID)
+	require.ErrorIs(t, err, ErrChainUnsupported)
+}
@@ -0,0 +1,133 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rocket-pool/rocketpool-go/rocketpool"
+	"github.com/stretchr/testify/require"
+)
+
+// executionClientStub is an ExecutionClient only answering the calls of the EthClient
+type executionClientStub struct {
+	rocketpool.ExecutionClient
+	client *pkgtest.Client
+}
+
+func (c *executionClientStub) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return c.client.CallContract(ctx, msg, blockNumber)
+}
+
+func (c *executionClientStub) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return c.client.BalanceAt(ctx, account, blockNumber)
+}
+
+func (c *executionClientStub) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return c.client.EstimateGas(ctx, msg)
+}
+
+func (c *executionClientStub) NetworkID(ctx context.Context) (*big.Int, error) {
+	return c.client.NetworkID(ctx)
+}
+
+func TestRocketpoolExecutionClient_Unit(t *testing.T) {
+
+	rateLimited := rpc.HTTPError{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"}
+	result := common.LeftPadBytes(big.NewInt(42).Bytes(), 32)
+
+	stub := func() (*executionClientStub, *int) {
+		client := pkgtest.NewClient(EthChainID)
+		handler, calls := failingHandler(2, rateLimited, result)
+		client.HandleFunc(handler)
+
+		return &executionClientStub{client: client}, calls
+	}
+
+	t.Run("retries set up with WithRetry are kept", func(t *testing.T) {
+		inner, calls := stub()
+
+		client := OverrideNetworkID(NewRetryClient(inner, 3, time.Millisecond), EthChainID)
+
+		executionClient, ok := rocketpoolExecutionClient(client)
+		require.True(t, ok)
+
+		got, err := executionClient.CallContract(context.Background(), ethereum.CallMsg{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, result, got)
+		require.Equal(t, 3, *calls)
+	})
+
+	t.Run("without retries the client is used as is", func(t *testing.T) {
+		inner, calls := stub()
+
+		executionClient, ok := rocketpoolExecutionClient(inner)
+		require.True(t, ok)
+		require.Same(t, inner, executionClient)
+
+		_, err := executionClient.CallContract(context.Background(), ethereum.CallMsg{}, nil)
+		require.Error(t, err)
+		require.Equal(t, 1, *calls)
+	})
+
+	t.Run("clients without the rocketpool calls", func(t *testing.T) {
+		_, ok := rocketpoolExecutionClient(NewRetryClient(pkgtest.NewClient(EthChainID), 3, time.Millisecond))
+		require.False(t, ok)
+	})
+}
+
+func TestRocketPoolOperation_GenerateCalldata_Burn_Unit(t *testing.T) {
+
+	// the rocketpool contracts are fetched from the node when created,
+	// packing the calldata only needs the ABI
+	parsedABI, err := abi.JSON(strings.NewReader(RocketPoolABI))
+	require.NoError(t, err)
+
+	rp := &RocketpoolOperation{parsedABI: parsedABI, chainID: EthChainID}
+
+	calldata, err := rp.GenerateCalldata(context.Background(), EthChainID, NativeUnStake, TransactionParams{
+		Amount: big.NewInt(1e18),
+		Sender: testAccount,
+		Asset:  common.HexToAddress(nativeDenomAddress),
+	})
+	require.NoError(t, err)
+
+	// cast calldata "burn(uint256)" 1000000000000000000
+	require.Equal(t, "0x42966c68"+
+		"0000000000000000000000000000000000000000000000000de0b6b3a7640000", calldata)
+}
+
+func TestRocketPoolOperation_GetContractAddressForAction_Unit(t *testing.T) {
+
+	depositPool := common.HexToAddress("0xDD3f50F8A6CafbE9b31a427582963f465E745AF8")
+	reth := common.HexToAddress("0xae78736Cd615f374D3085123A210448E74Fc6393")
+
+	rp := &RocketpoolOperation{
+		chainID:      EthChainID,
+		contract:     &rocketpool.Contract{Address: &depositPool},
+		rethContract: &rocketpool.Contract{Address: &reth},
+	}
+
+	to, err := rp.GetContractAddressForAction(EthChainID, NativeStake)
+	require.NoError(t, err)
+	require.Equal(t, depositPool, to)
+
+	// rETH is burnt on the token itself
+	to, err = rp.GetContractAddressForAction(EthChainID, NativeUnStake)
+	require.NoError(t, err)
+	require.Equal(t, reth, to)
+
+	// the primary contract stays the deposit pool
+	require.Equal(t, depositPool, rp.GetContractAddress(EthChainID))
+
+	_, err = rp.GetContractAddressForAction(EthChainID, LoanSupply)
+	require.ErrorIs(t, err, ErrUnsupportedAction)
+}
@@ -0,0 +1,54 @@
+package pkg
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// savingsDAIAsset is the DAI token deposited into sDAI
+var savingsDAIAsset = common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+
+// savingsDAIGnosisAsset is the WXDAI token deposited into sDAI on Gnosis
+var savingsDAIGnosisAsset = common.HexToAddress("0xe91D153E0b41518A2Ce8Dd3D7944Fa863463a97d")
+
+// savingsDAIDeployments maps the chains sDAI is deployed on to its vault and the asset it holds
+var savingsDAIDeployments = map[int64]struct{ vault, asset common.Address }{
+	EthChainID.Int64():    {SavingsDAIContractAddress, savingsDAIAsset},
+	GnosisChainID.Int64(): {SavingsDAIGnosisContractAddress, savingsDAIGnosisAsset},
+}
+
+// SavingsDAIOperation deposits DAI into sDAI to earn the DAI savings rate and
+// redeems it back. ERC20Stake deposits params.Amount of DAI and ERC20UnStake
+// withdraws it, ExtraData["shares"] switching to mint and redeem of sDAI shares.
+// GetBalance reports the DAI the sDAI of the account is worth.
+// On Gnosis sDAI holds WXDAI instead of DAI
+// https://docs.spark.fi/user-guides/earning-savings/sdai
+type SavingsDAIOperation struct {
+	*ERC4626Operation
+}
+
+var _ Protocol = (*SavingsDAIOperation)(nil)
+
+func NewSavingsDAIOperation(client EthClient, chainID *big.Int, opts ...NetworkOption) (*SavingsDAIOperation, error) {
+
+	deployment, ok := savingsDAIDeployments[chainID.Int64()]
+	if !ok {
+		return nil, ErrChainUnsupported
+	}
+
+	vault, err := NewERC4626Operation(client, chainID, deployment.vault,
+		WithERC4626Protocol(SavingsDAI, "1"))
+	if err != nil {
+		return nil, err
+	}
+
+	// the asset of sDAI is fixed so it does not need to be fetched
+	vault.asset = deployment.asset
+
+	for _, opt := range opts {
+		opt(&vault.network)
+	}
+
+	return &SavingsDAIOperation{ERC4626Operation: vault}, nil
+}
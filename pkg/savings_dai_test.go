@@ -0,0 +1,113 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavingsDAI_Unit(t *testing.T) {
+
+	funded := common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+
+	client := pkgtest.NewClient(EthChainID)
+
+	sdai, err := NewSavingsDAIOperation(client, EthChainID)
+	require.NoError(t, err)
+
+	// 5 DAI and 2 sDAI worth 3 DAI, asset() is never called
+	method := sdai.parsedABI.Methods["balanceOf"]
+	client.HandleContract(testDAI, method.ID, func(msg ethereum.CallMsg) ([]byte, error) {
+		if common.BytesToAddress(msg.Data[4:]) == funded {
+			return method.Outputs.Pack(big.NewInt(5e18))
+		}
+
+		return method.Outputs.Pack(big.NewInt(0))
+	})
+	client.HandleContract(SavingsDAIContractAddress, method.ID, pkgtest.Returns(method, big.NewInt(2e18)))
+
+	method = sdai.parsedABI.Methods["convertToAssets"]
+	client.HandleContract(SavingsDAIContractAddress, method.ID, pkgtest.Returns(method, big.NewInt(3e18)))
+
+	params := func(sender common.Address, amount *big.Int) TransactionParams {
+		return TransactionParams{
+			Sender: sender,
+			Asset:  testDAI,
+			Amount: amount,
+		}
+	}
+
+	t.Run("deposit", func(t *testing.T) {
+		calldata, err := sdai.GenerateCalldata(context.Background(), EthChainID, ERC20Stake, params(funded, big.NewInt(1e18)))
+		require.NoError(t, err)
+
+		// cast calldata "deposit(uint256,address)" 1000000000000000000 0x000000000000000000000000000000000000bEEF
+		require.Equal(t, "0x6e553f65"+
+			"0000000000000000000000000000000000000000000000000de0b6b3a7640000"+
+			"000000000000000000000000000000000000000000000000000000000000beef", calldata)
+	})
+
+	t.Run("redeem", func(t *testing.T) {
+		redeem := params(funded, big.NewInt(1e18))
+		redeem.ExtraData = map[string]interface{}{"shares": true}
+
+		calldata, err := sdai.GenerateCalldata(context.Background(), EthChainID, ERC20UnStake, redeem)
+		require.NoError(t, err)
+
+		// cast calldata "redeem(uint256,address,address)" 1000000000000000000 0x000000000000000000000000000000000000bEEF 0x000000000000000000000000000000000000bEEF
+		require.Equal(t, "0xba087652"+
+			"0000000000000000000000000000000000000000000000000de0b6b3a7640000"+
+			"000000000000000000000000000000000000000000000000000000000000beef"+
+			"000000000000000000000000000000000000000000000000000000000000beef", calldata)
+	})
+
+	t.Run("balance in DAI", func(t *testing.T) {
+		token, balance, err := sdai.GetBalance(context.Background(), EthChainID, funded, testDAI)
+		require.NoError(t, err)
+		require.Equal(t, testDAI, token)
+		require.Zero(t, balance.Cmp(big.NewInt(3e18)))
+	})
+
+	t.Run("validate the DAI balance", func(t *testing.T) {
+		err := sdai.Validate(context.Background(), EthChainID, ERC20Stake, params(funded, big.NewInt(5e18)))
+		require.NoError(t, err)
+
+		err = sdai.Validate(context.Background(), EthChainID, ERC20Stake, params(funded, big.NewInt(6e18)))
+		require.ErrorIs(t, err, ErrInsufficientBalance)
+
+		err = sdai.Validate(context.Background(), EthChainID, ERC20Stake, params(testAccount, big.NewInt(1e18)))
+		require.ErrorIs(t, err, ErrInsufficientBalance)
+
+		usdc := params(funded, big.NewInt(1e6))
+		usdc.Asset = testUSDC
+
+		err = sdai.Validate(context.Background(), EthChainID, ERC20Stake, usdc)
+		require.ErrorIs(t, err, ErrAssetNotSupported)
+	})
+
+	t.Run("unsupported chain", func(t *testing.T) {
+		_, err := NewSavingsDAIOperation(pkgtest.NewClient(PolygonChainID), PolygonChainID)
+		require.ErrorIs(t, err, ErrChainUnsupported)
+	})
+}
+
+func TestProtocolRegistry_SavingsDAI(t *testing.T) {
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+		WithDisabledProtocols(RocketPool, Compound))
+	require.NoError(t, err)
+
+	protocol, err := registry.GetProtocol(EthChainID, SavingsDAIContractAddress)
+	require.NoError(t, err)
+	require.Equal(t, SavingsDAI, protocol.GetName())
+	require.Equal(t, TypeVault, protocol.GetType())
+	require.True(t, protocol.IsSupportedAsset(context.Background(), EthChainID, testDAI))
+}
@@ -0,0 +1,78 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// savingsUSDSAsset is the USDS token deposited into sUSDS
+var savingsUSDSAsset = common.HexToAddress("0xdC035D45d973E3EC169d2276DDab16f1e407384F")
+
+// sparkSavingsVaults maps the Spark savings tokens to the stablecoin they hold
+var sparkSavingsVaults = map[common.Address]common.Address{
+	SavingsDAIContractAddress:  savingsDAIAsset,
+	SavingsUSDSContractAddress: savingsUSDSAsset,
+}
+
+// sparkSavingsEthVaults are the Spark savings tokens registered on Ethereum.
+// sDAI is registered as SavingsDAI already
+var sparkSavingsEthVaults = []common.Address{
+	SavingsUSDSContractAddress,
+}
+
+// SparkSavingsOperation deposits DAI into sDAI or USDS into sUSDS, the savings
+// tokens of Spark, and redeems them back. It is a different protocol from the
+// SparkLend money market which is an Aave V3 fork, see AaveOperation.
+// Each savings token is an ERC4626 vault, see ERC4626Operation
+// https://docs.spark.fi/user-guides/earning-savings
+type SparkSavingsOperation struct {
+	*ERC4626Operation
+}
+
+var _ Protocol = (*SparkSavingsOperation)(nil)
+
+func NewSparkSavingsOperation(client EthClient, chainID *big.Int, vault common.Address,
+	opts ...NetworkOption) (*SparkSavingsOperation, error) {
+
+	if !IsEth(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	asset, ok := sparkSavingsVaults[vault]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a Spark savings token", vault)
+	}
+
+	vaultOperation, err := NewERC4626Operation(client, chainID, vault, WithERC4626Protocol(SparkSavings, "1"))
+	if err != nil {
+		return nil, err
+	}
+
+	// the assets of the savings tokens are fixed so they do not need to be fetched
+	vaultOperation.asset = asset
+
+	for _, opt := range opts {
+		opt(&vaultOperation.network)
+	}
+
+	return &SparkSavingsOperation{ERC4626Operation: vaultOperation}, nil
+}
+
+// GetName returns spark_savings, the savings tokens rather than the
+// SparkLend money market named spark_lend
+func (s *SparkSavingsOperation) GetName() string { return SparkSavings }
+
+// GetSavingsBalance returns the savings tokens held by account, sDAI or sUSDS.
+// GetBalance reports what they are worth in the stablecoin instead
+func (s *SparkSavingsOperation) GetSavingsBalance(ctx context.Context, chainID *big.Int,
+	account common.Address) (*big.Int, error) {
+
+	if !s.isSupportedChain(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	return s.balanceOf(ctx, s.contract, account)
+}
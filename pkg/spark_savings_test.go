@@ -0,0 +1,89 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSparkSavings_Unit(t *testing.T) {
+
+	client := pkgtest.NewClient(EthChainID)
+
+	susds, err := NewSparkSavingsOperation(client, EthChainID, SavingsUSDSContractAddress)
+	require.NoError(t, err)
+	require.Equal(t, SparkSavings, susds.GetName())
+	require.NotEqual(t, SparkLend, susds.GetName())
+
+	// 2 sUSDS worth 3 USDS, asset() is never called
+	method := susds.parsedABI.Methods["balanceOf"]
+	client.HandleContract(SavingsUSDSContractAddress, method.ID, pkgtest.Returns(method, big.NewInt(2e18)))
+
+	method = susds.parsedABI.Methods["convertToAssets"]
+	client.HandleContract(SavingsUSDSContractAddress, method.ID, pkgtest.Returns(method, big.NewInt(3e18)))
+
+	require.True(t, susds.IsSupportedAsset(context.Background(), EthChainID, savingsUSDSAsset))
+	require.False(t, susds.IsSupportedAsset(context.Background(), EthChainID, testDAI))
+
+	t.Run("deposit", func(t *testing.T) {
+		tx, err := susds.BuildTransaction(context.Background(), EthChainID, ERC20Stake, TransactionParams{
+			Sender: testAccount,
+			Asset:  savingsUSDSAsset,
+			Amount: big.NewInt(1e18),
+		})
+		require.NoError(t, err)
+		require.Equal(t, SavingsUSDSContractAddress, tx.To)
+		require.Zero(t, tx.Value.Sign())
+	})
+
+	t.Run("balances", func(t *testing.T) {
+		savings, err := susds.GetSavingsBalance(context.Background(), EthChainID, testAccount)
+		require.NoError(t, err)
+		require.Zero(t, savings.Cmp(big.NewInt(2e18)))
+
+		token, balance, err := susds.GetBalance(context.Background(), EthChainID, testAccount, savingsUSDSAsset)
+		require.NoError(t, err)
+		require.Equal(t, savingsUSDSAsset, token)
+		require.Zero(t, balance.Cmp(big.NewInt(3e18)))
+
+		_, err = susds.GetSavingsBalance(context.Background(), BscChainID, testAccount)
+		require.ErrorIs(t, err, ErrChainUnsupported)
+	})
+
+	t.Run("sDAI", func(t *testing.T) {
+		sdai, err := NewSparkSavingsOperation(client, EthChainID, SavingsDAIContractAddress)
+		require.NoError(t, err)
+		require.True(t, sdai.IsSupportedAsset(context.Background(), EthChainID, testDAI))
+	})
+
+	t.Run("unknown vault", func(t *testing.T) {
+		_, err := NewSparkSavingsOperation(client, EthChainID, YearnV3DAIVaultAddress)
+		require.Error(t, err)
+
+		_, err = NewSparkSavingsOperation(pkgtest.NewClient(GnosisChainID), GnosisChainID, SavingsUSDSContractAddress)
+		require.ErrorIs(t, err, ErrChainUnsupported)
+	})
+}
+
+func TestProtocolRegistry_SparkSavings(t *testing.T) {
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+		WithDisabledProtocols(RocketPool, Compound))
+	require.NoError(t, err)
+
+	protocol, err := registry.GetProtocol(EthChainID, SavingsUSDSContractAddress)
+	require.NoError(t, err)
+	require.Equal(t, SparkSavings, protocol.GetName())
+	require.Equal(t, TypeVault, protocol.GetType())
+
+	// the SparkLend market is registered on its own
+	sparkLend, err := registry.GetProtocol(EthChainID, SparkLendContractAddress)
+	require.NoError(t, err)
+	require.Equal(t, SparkLend, sparkLend.GetName())
+}
@@ -0,0 +1,267 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// StakePreviewer is implemented by staking protocols able to tell how much of
+// their liquid staking token a stake mints, and how much native token an
+// unstake yields, before they are sent
+type StakePreviewer interface {
+	// PreviewStake returns the liquid staking token minted for staking amount
+	// of the native token at the current exchange rate
+	PreviewStake(ctx context.Context, chainID *big.Int, amount *big.Int) (*big.Int, error)
+
+	// PreviewUnstake returns the native token lstAmount of the liquid staking
+	// token is worth at the current exchange rate. It fails with
+	// ErrUnsupportedAction when the protocol does not unstake
+	PreviewUnstake(ctx context.Context, chainID *big.Int, lstAmount *big.Int) (*big.Int, error)
+}
+
+var (
+	_ StakePreviewer = (*LidoOperation)(nil)
+	_ StakePreviewer = (*RocketpoolOperation)(nil)
+	_ StakePreviewer = (*AnkrOperation)(nil)
+	_ StakePreviewer = (*ListaStakingOperation)(nil)
+)
+
+// stakePreviewABI holds the views converting between the native token and the
+// liquid staking tokens. ratio is the amount of ankr token a native token is
+// worth, scaled by 1e18
+const stakePreviewABI = `
+ [
+   {
+     "name": "getSharesByPooledEth",
+     "type": "function",
+     "stateMutability": "view",
+     "inputs": [{ "internalType": "uint256", "name": "_ethAmount", "type": "uint256" }],
+     "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }]
+   },
+   {
+     "name": "getPooledEthByShares",
+     "type": "function",
+     "stateMutability": "view",
+     "inputs": [{ "internalType": "uint256", "name": "_sharesAmount", "type": "uint256" }],
+     "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }]
+   },
+   {
+     "name": "ratio",
+     "type": "function",
+     "stateMutability": "view",
+     "inputs": [],
+     "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }]
+   },
+   {
+     "name": "convertBnbToSnBnb",
+     "type": "function",
+     "stateMutability": "view",
+     "inputs": [{ "internalType": "uint256", "name": "_amount", "type": "uint256" }],
+     "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }]
+   },
+   {
+     "name": "convertSnBnbToBnb",
+     "type": "function",
+     "stateMutability": "view",
+     "inputs": [{ "internalType": "uint256", "name": "_amountInSlisBnb", "type": "uint256" }],
+     "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }]
+   }
+ ]`
+
+// callPreview calls a view of stakePreviewABI returning a single uint256
+func callPreview(ctx context.Context, client EthClient, contract common.Address,
+	method string, args ...interface{}) (*big.Int, error) {
+
+	parsedABI, err := abi.JSON(strings.NewReader(stakePreviewABI))
+	if err != nil {
+		return nil, err
+	}
+
+	calldata, err := parsedABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.CallContract(ctx, ethereum.CallMsg{
+		To:   &contract,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not call %s on %s: %w", method, contract, err)
+	}
+
+	value := new(big.Int)
+	if err := parsedABI.UnpackIntoInterface(&value, method, result); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// PreviewStake returns the stETH minted for amount of ETH. stETH balances are
+// derived from shares so the amount is rounded down to a whole number of shares.
+// Stakes into stMATIC on Polygon are not previewed
+func (l *LidoOperation) PreviewStake(ctx context.Context, chainID *big.Int, amount *big.Int) (*big.Int, error) {
+
+	if !l.isSupportedChain(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	if amount == nil {
+		return nil, ErrAmountRequired
+	}
+
+	if !IsEth(chainID) {
+		return nil, fmt.Errorf("%s does not preview stakes on chain %s", l.GetName(), chainID.String())
+	}
+
+	shares, err := callPreview(ctx, l.client, l.config.Contract, "getSharesByPooledEth", amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return callPreview(ctx, l.client, l.config.Contract, "getPooledEthByShares", shares)
+}
+
+// PreviewUnstake fails since stETH is withdrawn through the Lido withdrawal
+// queue, which this operation does not support
+func (l *LidoOperation) PreviewUnstake(ctx context.Context, chainID *big.Int, lstAmount *big.Int) (*big.Int, error) {
+
+	if !l.isSupportedChain(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	return nil, fmt.Errorf("%w: %s withdrawals go through its queue", ErrUnsupportedAction, l.GetName())
+}
+
+// PreviewStake returns the slisBNB minted for amount of BNB
+func (l *ListaStakingOperation) PreviewStake(ctx context.Context, chainID *big.Int, amount *big.Int) (*big.Int, error) {
+
+	if !l.isSupportedChain(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	if amount == nil {
+		return nil, ErrAmountRequired
+	}
+
+	return callPreview(ctx, l.client, l.config.Contract, "convertBnbToSnBnb", amount)
+}
+
+// PreviewUnstake returns the BNB lstAmount of slisBNB is worth. The BNB is
+// claimed once the withdrawal request is processed
+func (l *ListaStakingOperation) PreviewUnstake(ctx context.Context, chainID *big.Int, lstAmount *big.Int) (*big.Int, error) {
+
+	if !l.isSupportedChain(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	if lstAmount == nil {
+		return nil, ErrAmountRequired
+	}
+
+	return callPreview(ctx, l.client, l.config.Contract, "convertSnBnbToBnb", lstAmount)
+}
+
+// PreviewStake returns the ankrETH, ankrMATIC or ankrBNB minted for amount of
+// the native token using the ratio of the liquid staking token
+func (a *AnkrOperation) PreviewStake(ctx context.Context, chainID *big.Int, amount *big.Int) (*big.Int, error) {
+
+	if !a.isSupportedChain(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	if amount == nil {
+		return nil, ErrAmountRequired
+	}
+
+	ratio, err := callPreview(ctx, a.client, a.certToken, "ratio")
+	if err != nil {
+		return nil, err
+	}
+
+	shares := new(big.Int).Mul(amount, ratio)
+	return shares.Quo(shares, big.NewInt(1e18)), nil
+}
+
+// PreviewUnstake returns the native token lstAmount of ankrETH, ankrMATIC or
+// ankrBNB is worth using the ratio of the liquid staking token
+func (a *AnkrOperation) PreviewUnstake(ctx context.Context, chainID *big.Int, lstAmount *big.Int) (*big.Int, error) {
+
+	if !a.isSupportedChain(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	if lstAmount == nil {
+		return nil, ErrAmountRequired
+	}
+
+	ratio, err := callPreview(ctx, a.client, a.certToken, "ratio")
+	if err != nil {
+		return nil, err
+	}
+
+	if ratio.Sign() == 0 {
+		return nil, fmt.Errorf("%s ratio of %s is zero", a.GetName(), a.certToken)
+	}
+
+	amount := new(big.Int).Mul(lstAmount, big.NewInt(1e18))
+	return amount.Quo(amount, ratio), nil
+}
+
+// PreviewStake returns the rETH minted for amount of ETH once the deposit fee
+// of the protocol is taken
+func (l *RocketpoolOperation) PreviewStake(ctx context.Context, chainID *big.Int, amount *big.Int) (*big.Int, error) {
+
+	if !IsEth(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	if amount == nil {
+		return nil, ErrAmountRequired
+	}
+
+	fee := big.NewInt(0)
+	if err := l.depositSettingsContract.Call(&bind.CallOpts{Context: ctx}, &fee, "getDepositFee"); err != nil {
+		return nil, err
+	}
+
+	// the fee is a fraction of the deposit scaled by 1e18
+	net := new(big.Int).Mul(amount, fee)
+	net.Sub(amount, net.Quo(net, big.NewInt(1e18)))
+
+	reth := big.NewInt(0)
+	if err := l.rethContract.Call(&bind.CallOpts{Context: ctx}, &reth, "getRethValue", net); err != nil {
+		return nil, err
+	}
+
+	return reth, nil
+}
+
+// PreviewUnstake returns the ETH burning lstAmount of rETH yields. Burns are
+// paid from the collateral of the rETH contract and the deposit pool, see
+// validateBurnCollateral
+func (l *RocketpoolOperation) PreviewUnstake(ctx context.Context, chainID *big.Int, lstAmount *big.Int) (*big.Int, error) {
+
+	if !IsEth(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	if lstAmount == nil {
+		return nil, ErrAmountRequired
+	}
+
+	eth := big.NewInt(0)
+	if err := l.rethContract.Call(&bind.CallOpts{Context: ctx}, &eth, "getEthValue", lstAmount); err != nil {
+		return nil, err
+	}
+
+	return eth, nil
+}
@@ -0,0 +1,68 @@
+//go:build integration
+// +build integration
+
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLido_PreviewStake(t *testing.T) {
+
+	lido, err := NewLidoOperation(getTestClient(t, ChainETH), EthChainID)
+	require.NoError(t, err)
+
+	amount := big.NewInt(1e18)
+
+	stETH, err := lido.PreviewStake(context.Background(), EthChainID, amount)
+	require.NoError(t, err)
+
+	// stETH is minted 1:1, less the wei lost rounding to whole shares
+	require.LessOrEqual(t, stETH.Cmp(amount), 0)
+	require.Less(t, new(big.Int).Sub(amount, stETH).Int64(), int64(10))
+}
+
+func TestAnkr_PreviewStake(t *testing.T) {
+
+	ankr, err := NewAnkrOperation(getTestClient(t, ChainETH), EthChainID)
+	require.NoError(t, err)
+
+	ankrETH, err := ankr.PreviewStake(context.Background(), EthChainID, big.NewInt(1e18))
+	require.NoError(t, err)
+
+	// ankrETH accrues rewards so is worth more than ETH
+	require.Equal(t, -1, ankrETH.Cmp(big.NewInt(1e18)))
+	require.Equal(t, 1, ankrETH.Cmp(big.NewInt(5e17)))
+}
+
+func TestRocketPool_PreviewStake(t *testing.T) {
+
+	rp, err := NewRocketpoolOperation(getTestClient(t, ChainETH), EthChainID)
+	require.NoError(t, err)
+
+	reth, err := rp.PreviewStake(context.Background(), EthChainID, big.NewInt(1e18))
+	require.NoError(t, err)
+
+	require.Equal(t, -1, reth.Cmp(big.NewInt(1e18)))
+	require.Equal(t, 1, reth.Cmp(big.NewInt(5e17)))
+}
+
+func TestRocketPool_PreviewUnstake(t *testing.T) {
+
+	rp, err := NewRocketpoolOperation(getTestClient(t, ChainETH), EthChainID)
+	require.NoError(t, err)
+
+	eth, err := rp.PreviewUnstake(context.Background(), EthChainID, big.NewInt(1e18))
+	require.NoError(t, err)
+
+	// rETH accrues rewards so is worth more than ETH
+	require.Equal(t, 1, eth.Cmp(big.NewInt(1e18)))
+	require.Equal(t, -1, eth.Cmp(big.NewInt(2e18)))
+
+	_, err = rp.PreviewUnstake(context.Background(), BscChainID, big.NewInt(1e18))
+	require.ErrorIs(t, err, ErrChainUnsupported)
+}
@@ -0,0 +1,129 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewStake_Unit(t *testing.T) {
+
+	previewABI, err := abi.JSON(strings.NewReader(stakePreviewABI))
+	require.NoError(t, err)
+
+	t.Run("lido rounds to whole shares", func(t *testing.T) {
+		client := pkgtest.NewClient(EthChainID)
+
+		lido, err := NewLidoOperation(client, EthChainID)
+		require.NoError(t, err)
+
+		method := previewABI.Methods["getSharesByPooledEth"]
+		client.HandleContract(LidoContractAddress, method.ID, pkgtest.Returns(method, big.NewInt(850)))
+
+		method = previewABI.Methods["getPooledEthByShares"]
+		client.HandleContract(LidoContractAddress, method.ID, pkgtest.Returns(method, big.NewInt(999)))
+
+		stETH, err := lido.PreviewStake(context.Background(), EthChainID, big.NewInt(1000))
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(999), stETH)
+
+		_, err = lido.PreviewStake(context.Background(), BscChainID, big.NewInt(1000))
+		require.ErrorIs(t, err, ErrChainUnsupported)
+
+		_, err = lido.PreviewStake(context.Background(), EthChainID, nil)
+		require.ErrorIs(t, err, ErrAmountRequired)
+	})
+
+	t.Run("ankr applies the ratio", func(t *testing.T) {
+		client := pkgtest.NewClient(BscChainID)
+
+		ankr, err := NewAnkrOperation(client, BscChainID)
+		require.NoError(t, err)
+
+		// 1 BNB is worth 0.9 ankrBNB
+		method := previewABI.Methods["ratio"]
+		client.HandleContract(ankrBnbER20Account, method.ID, pkgtest.Returns(method, big.NewInt(9e17)))
+
+		ankrBNB, err := ankr.PreviewStake(context.Background(), BscChainID, big.NewInt(2e18))
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(18e17), ankrBNB)
+
+		_, err = ankr.PreviewStake(context.Background(), EthChainID, big.NewInt(2e18))
+		require.ErrorIs(t, err, ErrChainUnsupported)
+	})
+
+	t.Run("lista", func(t *testing.T) {
+		client := pkgtest.NewClient(BscChainID)
+
+		lista, err := NewListaStakingOperation(client, BscChainID)
+		require.NoError(t, err)
+
+		method := previewABI.Methods["convertBnbToSnBnb"]
+		client.HandleContract(ListaDaoContractAddress, method.ID, pkgtest.Returns(method, big.NewInt(97e16)))
+
+		slisBNB, err := lista.PreviewStake(context.Background(), BscChainID, big.NewInt(1e18))
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(97e16), slisBNB)
+	})
+}
+
+func TestPreviewUnstake_Unit(t *testing.T) {
+
+	previewABI, err := abi.JSON(strings.NewReader(stakePreviewABI))
+	require.NoError(t, err)
+
+	t.Run("lido withdrawals are queued", func(t *testing.T) {
+		lido, err := NewLidoOperation(pkgtest.NewClient(EthChainID), EthChainID)
+		require.NoError(t, err)
+
+		_, err = lido.PreviewUnstake(context.Background(), EthChainID, big.NewInt(1e18))
+		require.ErrorIs(t, err, ErrUnsupportedAction)
+
+		_, err = lido.PreviewUnstake(context.Background(), BscChainID, big.NewInt(1e18))
+		require.ErrorIs(t, err, ErrChainUnsupported)
+	})
+
+	t.Run("ankr applies the ratio", func(t *testing.T) {
+		client := pkgtest.NewClient(BscChainID)
+
+		ankr, err := NewAnkrOperation(client, BscChainID)
+		require.NoError(t, err)
+
+		method := previewABI.Methods["ratio"]
+		client.HandleContract(ankrBnbER20Account, method.ID, pkgtest.Returns(method, big.NewInt(9e17)))
+
+		bnb, err := ankr.PreviewUnstake(context.Background(), BscChainID, big.NewInt(18e17))
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(2e18), bnb)
+
+		// staking then unstaking gives the amount back
+		ankrBNB, err := ankr.PreviewStake(context.Background(), BscChainID, bnb)
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(18e17), ankrBNB)
+
+		_, err = ankr.PreviewUnstake(context.Background(), EthChainID, big.NewInt(1))
+		require.ErrorIs(t, err, ErrChainUnsupported)
+
+		_, err = ankr.PreviewUnstake(context.Background(), BscChainID, nil)
+		require.ErrorIs(t, err, ErrAmountRequired)
+	})
+
+	t.Run("lista", func(t *testing.T) {
+		client := pkgtest.NewClient(BscChainID)
+
+		lista, err := NewListaStakingOperation(client, BscChainID)
+		require.NoError(t, err)
+
+		method := previewABI.Methods["convertSnBnbToBnb"]
+		client.HandleContract(ListaDaoContractAddress, method.ID, pkgtest.Returns(method, big.NewInt(103e16)))
+
+		bnb, err := lista.PreviewUnstake(context.Background(), BscChainID, big.NewInt(1e18))
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(103e16), bnb)
+	})
+}
@@ -0,0 +1,581 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// stargatePoolABI is the subset of the Stargate V2 pool used to bridge tokens
+const stargatePoolABI = `
+[
+  {
+    "inputs": [
+      {
+        "components": [
+          { "internalType": "uint32", "name": "dstEid", "type": "uint32" },
+          { "internalType": "bytes32", "name": "to", "type": "bytes32" },
+          { "internalType": "uint256", "name": "amountLD", "type": "uint256" },
+          { "internalType": "uint256", "name": "minAmountLD", "type": "uint256" },
+          { "internalType": "bytes", "name": "extraOptions", "type": "bytes" },
+          { "internalType": "bytes", "name": "composeMsg", "type": "bytes" },
+          { "internalType": "bytes", "name": "oftCmd", "type": "bytes" }
+        ],
+        "internalType": "struct SendParam",
+        "name": "_sendParam",
+        "type": "tuple"
+      },
+      {
+        "components": [
+          { "internalType": "uint256", "name": "nativeFee", "type": "uint256" },
+          { "internalType": "uint256", "name": "lzTokenFee", "type": "uint256" }
+        ],
+        "internalType": "struct MessagingFee",
+        "name": "_fee",
+        "type": "tuple"
+      },
+      { "internalType": "address", "name": "_refundAddress", "type": "address" }
+    ],
+    "name": "sendToken",
+    "outputs": [],
+    "stateMutability": "payable",
+    "type": "function"
+  }
+]`
+
+const (
+	stargateExtraDataDestinationChain = "destination_chain_id"
+	stargateExtraDataRecipient        = "recipient"
+	stargateExtraDataNativeFee        = "native_fee"
+)
+
+const (
+	// stargateSharedDecimals is the precision amounts are bridged with, the
+	// pools drop the dust below it from the amount sent
+	stargateSharedDecimals = 6
+
+	// stargateDefaultSlippageBps lowers the minimum amount delivered when
+	// neither min_out nor slippage_bps is provided
+	stargateDefaultSlippageBps = 50
+)
+
+// stargateEndpointIDs maps the chains Stargate can bridge to their LayerZero V2 endpoint id
+var stargateEndpointIDs = map[int64]uint32{
+	EthChainID.Int64():       30101,
+	BscChainID.Int64():       30102,
+	AvalancheChainID.Int64(): 30106,
+	PolygonChainID.Int64():   30109,
+}
+
+// stargateUSDCPools maps the Stargate V2 USDC pool of a chain to the USDC token
+// it holds and the local decimals of the token
+var stargateUSDCPools = map[int64]struct {
+	pool     common.Address
+	token    common.Address
+	decimals uint8
+}{
+	EthChainID.Int64(): {
+		pool:     StargateUSDCEthContractAddress,
+		token:    common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+		decimals: 6,
+	},
+	BscChainID.Int64(): {
+		pool:     StargateUSDCBnbContractAddress,
+		token:    common.HexToAddress("0x8AC76a51cc950d9822D68b83fE1Ad97B32Cd580d"),
+		decimals: 18,
+	},
+	PolygonChainID.Int64(): {
+		pool:     StargateUSDCPolygonContractAddress,
+		token:    common.HexToAddress("0x3c499c542cEF5E3811e1192ce70d8cC03d5c3359"),
+		decimals: 6,
+	},
+	AvalancheChainID.Int64(): {
+		pool:     StargateUSDCAvalancheContractAddress,
+		token:    common.HexToAddress("0xB97EF9Ef8734C71904D8002F8b6Bc66Dd9c48a6E"),
+		decimals: 6,
+	},
+}
+
+// stargateSendParam mirrors the SendParam struct of the Stargate V2 pools
+type stargateSendParam struct {
+	DstEid       uint32
+	To           [32]byte
+	AmountLD     *big.Int
+	MinAmountLD  *big.Int
+	ExtraOptions []byte
+	ComposeMsg   []byte
+	OftCmd       []byte
+}
+
+// stargateMessagingFee mirrors the MessagingFee struct of the Stargate V2 pools
+type stargateMessagingFee struct {
+	NativeFee  *big.Int
+	LzTokenFee *big.Int
+}
+
+// StargateOperation bridges USDC to another chain through the Stargate V2 pool
+// of the chain. The destination is selected with ExtraData["destination_chain_id"]
+// and receives the tokens at ExtraData["recipient"], the beneficiary of the
+// transaction when omitted. The LayerZero fee quoted by the pool must be set in
+// ExtraData["native_fee"] and is sent as msg.value. The pool pulls the tokens
+// with an allowance. ExtraData["min_out"] or ExtraData["slippage_bps"] set the
+// minimum amount delivered, the amount less 0.5% and the dust the pool drops
+// by default
+// https://stargate.finance
+type StargateOperation struct {
+	contract  common.Address
+	token     common.Address
+	decimals  uint8
+	parsedABI abi.ABI
+	erc20ABI  abi.ABI
+	chainID   *big.Int
+	client    EthClient
+	observer  Observer
+	metadata  AssetMetadata
+
+	// verified on first use, see networkCheck
+	network networkCheck
+
+	// disabled through the registry, see SetProtocolEnabled
+	deprecation
+}
+
+var _ Protocol = (*StargateOperation)(nil)
+
+func NewStargateOperation(client EthClient, chainID *big.Int, opts ...NetworkOption) (*StargateOperation, error) {
+
+	pool, ok := stargateUSDCPools[chainID.Int64()]
+	if !ok {
+		return nil, ErrChainUnsupported
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(stargatePoolABI))
+	if err != nil {
+		return nil, err
+	}
+
+	erc20ABI, err := abi.JSON(strings.NewReader(erc20BalanceOfABI))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &StargateOperation{
+		contract:  pool.pool,
+		token:     pool.token,
+		decimals:  pool.decimals,
+		parsedABI: parsedABI,
+		erc20ABI:  erc20ABI,
+		chainID:   chainID,
+		client:    client,
+	}
+
+	for _, opt := range opts {
+		opt(&s.network)
+	}
+
+	return s, nil
+}
+
+// GenerateCalldata creates the necessary blockchain transaction data
+func (s *StargateOperation) GenerateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+	if err := s.deprecation.check(s.GetUniqueKey()); err != nil {
+		return "", err
+	}
+
+	if err := params.checkAmountRange(); err != nil {
+		return "", err
+	}
+
+	return observeCalldata(s.observer, s.GetName(), action, func() (string, error) {
+		return s.generateCalldata(ctx, chainID, action, params)
+	})
+}
+
+func (s *StargateOperation) generateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+
+	if !s.isSupportedChain(chainID) {
+		return "", ErrChainUnsupported
+	}
+
+	if err := s.network.ensure(ctx, s.client, s.chainID); err != nil {
+		return "", err
+	}
+
+	if action != BridgeSend {
+		return "", ErrUnsupportedAction
+	}
+
+	if params.Amount == nil {
+		return "", ErrAmountRequired
+	}
+
+	dstEid, err := s.destination(params)
+	if err != nil {
+		return "", err
+	}
+
+	recipient, err := stargateRecipient(params)
+	if err != nil {
+		return "", err
+	}
+
+	nativeFee, err := stargateNativeFee(params)
+	if err != nil {
+		return "", err
+	}
+
+	minAmount, err := s.minAmount(params)
+	if err != nil {
+		return "", err
+	}
+
+	var to [32]byte
+	copy(to[12:], recipient.Bytes())
+
+	calldata, err := s.parsedABI.Pack("sendToken",
+		stargateSendParam{
+			DstEid:       dstEid,
+			To:           to,
+			AmountLD:     params.Amount,
+			MinAmountLD:  minAmount,
+			ExtraOptions: []byte{},
+			ComposeMsg:   []byte{},
+			OftCmd:       []byte{},
+		},
+		stargateMessagingFee{NativeFee: nativeFee, LzTokenFee: big.NewInt(0)},
+		params.Sender)
+	if err != nil {
+		return "", err
+	}
+
+	return HexPrefix + hex.EncodeToString(calldata), nil
+}
+
+// EstimateGas estimates the gas needed to execute the generated calldata
+func (s *StargateOperation) EstimateGas(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (uint64, error) {
+
+	tx, err := s.BuildTransaction(ctx, chainID, action, params)
+	if err != nil {
+		return 0, err
+	}
+
+	return estimateGas(ctx, s.client, params.Sender, tx)
+}
+
+// BuildTransaction generates the calldata for the action together with
+// the contract to call and the value to send
+func (s *StargateOperation) BuildTransaction(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (*Transaction, error) {
+
+	calldata, err := s.GenerateCalldata(ctx, chainID, action, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{
+		To:    s.GetContractAddress(chainID),
+		Data:  calldata,
+		Value: s.CallValue(action, params),
+	}, nil
+}
+
+// Validate checks if the provided parameters are valid for the specified action.
+// The sender must hold the tokens to bridge and the native fee
+func (s *StargateOperation) Validate(ctx context.Context,
+	chainID *big.Int, action ContractAction, params TransactionParams) error {
+
+	if err := s.deprecation.check(s.GetUniqueKey()); err != nil {
+		return err
+	}
+
+	if !s.isSupportedChain(chainID) {
+		return ErrChainUnsupported
+	}
+
+	if err := s.network.ensure(ctx, s.client, s.chainID); err != nil {
+		return err
+	}
+
+	if !s.IsSupportedAsset(ctx, chainID, params.Asset) {
+		return fmt.Errorf("%w %s", ErrAssetNotSupported, params.Asset)
+	}
+
+	if action != BridgeSend {
+		return ErrUnsupportedAction
+	}
+
+	if err := params.ValidateAmount(); err != nil {
+		return err
+	}
+
+	if err := checkAmountPrecision(ctx, s.client, s.metadata, s.chainID, params, params.Asset); err != nil {
+		return err
+	}
+
+	if _, err := s.destination(params); err != nil {
+		return err
+	}
+
+	if _, err := stargateRecipient(params); err != nil {
+		return err
+	}
+
+	nativeFee, err := stargateNativeFee(params)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.minAmount(params); err != nil {
+		return err
+	}
+
+	if err := checkAllowance(ctx, s.client, params, s.token, s.contract, params.Amount); err != nil {
+		return err
+	}
+
+	if params.lenientValidation(ValidationStrict) {
+		return nil
+	}
+
+	_, balance, err := s.GetBalance(ctx, chainID, params.Sender, params.Asset)
+	if err != nil {
+		return err
+	}
+
+	if balance.Cmp(params.Amount) < 0 {
+		return ErrInsufficientBalance
+	}
+
+	native, err := s.client.BalanceAt(ctx, params.Sender, nil)
+	if err != nil {
+		return err
+	}
+
+	if native.Cmp(nativeFee) < 0 {
+		return fmt.Errorf("native fee %w", ErrInsufficientBalance)
+	}
+
+	return nil
+}
+
+// GetBalance retrieves the balance account holds in the bridged token
+func (s *StargateOperation) GetBalance(ctx context.Context, chainID *big.Int,
+	account, _ common.Address) (common.Address, *big.Int, error) {
+
+	if !s.isSupportedChain(chainID) {
+		return common.Address{}, nil, ErrChainUnsupported
+	}
+
+	calldata, err := s.erc20ABI.Pack("balanceOf", account)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	result, err := s.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &s.token,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	balance := new(big.Int)
+	err = s.erc20ABI.UnpackIntoInterface(&balance, "balanceOf", result)
+	return s.token, balance, err
+}
+
+// GetSupportedAssets returns a list of assets supported by the protocol on the specified chain
+func (s *StargateOperation) GetSupportedAssets(ctx context.Context, chainID *big.Int) ([]common.Address, error) {
+	if !s.isSupportedChain(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	return []common.Address{s.token}, nil
+}
+
+func (s *StargateOperation) isSupportedChain(chain *big.Int) bool {
+	return s.chainID.Cmp(chain) == 0
+}
+
+// IsSupportedAsset checks if the specified asset is supported on the given chain
+func (s *StargateOperation) IsSupportedAsset(ctx context.Context, chainID *big.Int, asset common.Address) bool {
+	if !s.isSupportedChain(chainID) {
+		return false
+	}
+
+	return asset == s.token
+}
+
+// destination returns the LayerZero endpoint id of the destination chain
+func (s *StargateOperation) destination(params TransactionParams) (uint32, error) {
+
+	v, ok := params.ExtraData[stargateExtraDataDestinationChain]
+	if !ok {
+		return 0, errors.New("destination_chain_id must be provided in extra data")
+	}
+
+	chainID, err := toBigInt(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid destination_chain_id: %w", err)
+	}
+
+	if chainID.Cmp(s.chainID) == 0 {
+		return 0, errors.New("destination_chain_id must differ from the source chain")
+	}
+
+	eid, ok := stargateEndpointIDs[chainID.Int64()]
+	if !chainID.IsInt64() || !ok {
+		return 0, fmt.Errorf("destination chain %s %w", chainID, ErrChainUnsupported)
+	}
+
+	return eid, nil
+}
+
+// stargateRecipient returns the account receiving the tokens on the destination chain
+func stargateRecipient(params TransactionParams) (common.Address, error) {
+
+	v, ok := params.ExtraData[stargateExtraDataRecipient]
+	if !ok {
+		return params.GetBeneficiaryOwner(), nil
+	}
+
+	recipient, err := toAddress(v)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid recipient: %w", err)
+	}
+
+	return recipient, nil
+}
+
+// stargateNativeFee returns the LayerZero fee paid in the native token
+func stargateNativeFee(params TransactionParams) (*big.Int, error) {
+
+	v, ok := params.ExtraData[stargateExtraDataNativeFee]
+	if !ok {
+		return nil, errors.New("native_fee must be provided in extra data")
+	}
+
+	fee, err := toBigInt(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid native_fee: %w", err)
+	}
+
+	return fee, nil
+}
+
+// minAmount returns the minimum amount delivered on the destination chain.
+// Without min_out the amount is lowered by slippage_bps, or the default
+// slippage, and the dust the pool drops when converting to shared decimals
+func (s *StargateOperation) minAmount(params TransactionParams) (*big.Int, error) {
+
+	_, hasMinOut := params.ExtraData[extraDataMinOut]
+	_, hasSlippage := params.ExtraData[extraDataSlippageBps]
+
+	if !hasMinOut && !hasSlippage {
+		return s.removeDust(applySlippage(params.Amount, stargateDefaultSlippageBps)), nil
+	}
+
+	minAmount, err := minOutput(params, func() (*big.Int, error) { return params.Amount, nil })
+	if err != nil || hasMinOut {
+		return minAmount, err
+	}
+
+	return s.removeDust(minAmount), nil
+}
+
+// removeDust drops the part of amount below the shared decimals
+func (s *StargateOperation) removeDust(amount *big.Int) *big.Int {
+
+	if s.decimals <= stargateSharedDecimals {
+		return amount
+	}
+
+	rate := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(s.decimals-stargateSharedDecimals)), nil)
+	return new(big.Int).Sub(amount, new(big.Int).Mod(amount, rate))
+}
+
+// GetProtocolConfig returns the protocol config for a specific chain
+func (s *StargateOperation) GetProtocolConfig(chainID *big.Int) ProtocolConfig {
+	return ProtocolConfig{
+		ChainID:  s.chainID,
+		ABI:      s.parsedABI,
+		Type:     TypeBridge,
+		Contract: s.contract,
+	}
+}
+
+// GetABI returns the ABI of the protocol's contract
+func (s *StargateOperation) GetABI(chainID *big.Int) abi.ABI { return s.parsedABI }
+
+// GetType returns the protocol type
+func (s *StargateOperation) GetType() ProtocolType { return TypeBridge }
+
+// GetContractAddress returns the contract address for a specific chain
+func (s *StargateOperation) GetContractAddress(chainID *big.Int) common.Address {
+	return s.contract
+}
+
+// GetContractAddressForAction returns the contract the calldata of action is sent to,
+// the same for every action
+func (s *StargateOperation) GetContractAddressForAction(chainID *big.Int, action ContractAction) (common.Address, error) {
+	return contractAddressForAction(s, chainID, action)
+}
+
+// Name returns the human readable name for the protocol
+func (s *StargateOperation) GetName() string { return Stargate }
+
+// GetVersion returns the version of the protocol
+func (s *StargateOperation) GetVersion() string { return "2" }
+
+// GetUniqueKey returns the key identifying the protocol across chains
+func (s *StargateOperation) GetUniqueKey() string { return protocolKey(s.GetName(), s.chainID) }
+
+// CallValue returns the native amount to send along with the calldata.
+// The LayerZero fee is paid as msg.value
+func (s *StargateOperation) CallValue(action ContractAction, params TransactionParams) *big.Int {
+	if action != BridgeSend {
+		return big.NewInt(0)
+	}
+
+	fee, err := stargateNativeFee(params)
+	if err != nil {
+		return big.NewInt(0)
+	}
+
+	return fee
+}
+
+// Capabilities describes the actions, chains and extra data supported by the protocol
+func (s *StargateOperation) Capabilities() ProtocolCapabilities {
+	return ProtocolCapabilities{
+		SupportedActions:  []ContractAction{BridgeSend},
+		SupportedChains:   []*big.Int{s.chainID},
+		RequiresExtraData: []string{stargateExtraDataDestinationChain, stargateExtraDataNativeFee},
+	}
+}
+
+// IsSupportedAction reports whether calldata can be generated for action
+func (s *StargateOperation) IsSupportedAction(action ContractAction) bool {
+	return s.Capabilities().Supports(action)
+}
+
+// SetObserver implements Observable
+func (s *StargateOperation) SetObserver(observer Observer) {
+	s.observer = observer
+	s.client = observeClient(s.client, s.GetName(), observer)
+}
+
+// SetAssetMetadata implements AssetMetadataUser
+func (s *StargateOperation) SetAssetMetadata(metadata AssetMetadata) {
+	s.metadata = metadata
+}
@@ -0,0 +1,195 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStargate_BridgeSend_Unit(t *testing.T) {
+
+	funded := common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+	recipient := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	client := pkgtest.NewClient(EthChainID)
+	client.SetBalance(funded, big.NewInt(1e18))
+
+	stargate, err := NewStargateOperation(client, EthChainID)
+	require.NoError(t, err)
+
+	method := stargate.erc20ABI.Methods["balanceOf"]
+	client.HandleContract(testUSDC, method.ID, func(msg ethereum.CallMsg) ([]byte, error) {
+		if common.BytesToAddress(msg.Data[4:]) == funded {
+			return method.Outputs.Pack(big.NewInt(1000e6))
+		}
+
+		return method.Outputs.Pack(big.NewInt(0))
+	})
+
+	bridge := func(sender common.Address, extraData map[string]interface{}) TransactionParams {
+		return TransactionParams{
+			Sender:    sender,
+			Asset:     testUSDC,
+			Amount:    big.NewInt(100e6),
+			ExtraData: extraData,
+		}
+	}
+
+	t.Run("transaction", func(t *testing.T) {
+		tx, err := stargate.BuildTransaction(context.Background(), EthChainID, BridgeSend, bridge(funded, map[string]interface{}{
+			"destination_chain_id": PolygonChainID,
+			"recipient":            recipient.Hex(),
+			"native_fee":           "1000000000000000",
+			"slippage_bps":         50,
+		}))
+		require.NoError(t, err)
+
+		require.Equal(t, StargateUSDCEthContractAddress, tx.To)
+		require.EqualValues(t, 1e15, tx.Value.Int64())
+
+		args := unpackCalldata(t, stargate.parsedABI, "sendToken", tx.Data)
+
+		sendParam := abi.ConvertType(args[0], new(stargateSendParam)).(*stargateSendParam)
+		require.EqualValues(t, 30109, sendParam.DstEid)
+		require.Equal(t, recipient, common.BytesToAddress(sendParam.To[:]))
+		require.EqualValues(t, 100e6, sendParam.AmountLD.Int64())
+		require.EqualValues(t, 99_500_000, sendParam.MinAmountLD.Int64())
+
+		fee := abi.ConvertType(args[1], new(stargateMessagingFee)).(*stargateMessagingFee)
+		require.EqualValues(t, 1e15, fee.NativeFee.Int64())
+		require.Zero(t, fee.LzTokenFee.Sign())
+
+		require.Equal(t, funded, args[2])
+	})
+
+	t.Run("recipient defaults to the beneficiary", func(t *testing.T) {
+		calldata, err := stargate.GenerateCalldata(context.Background(), EthChainID, BridgeSend, bridge(funded, map[string]interface{}{
+			"destination_chain_id": BscChainID.Int64(),
+			"native_fee":           1,
+		}))
+		require.NoError(t, err)
+
+		args := unpackCalldata(t, stargate.parsedABI, "sendToken", calldata)
+
+		sendParam := abi.ConvertType(args[0], new(stargateSendParam)).(*stargateSendParam)
+		require.EqualValues(t, 30102, sendParam.DstEid)
+		require.Equal(t, funded, common.BytesToAddress(sendParam.To[:]))
+		require.EqualValues(t, 99_500_000, sendParam.MinAmountLD.Int64())
+	})
+
+	t.Run("invalid extra data", func(t *testing.T) {
+		for name, extraData := range map[string]map[string]interface{}{
+			"missing destination": {"native_fee": 1},
+			"same chain":          {"destination_chain_id": EthChainID, "native_fee": 1},
+			"unknown destination": {"destination_chain_id": GnosisChainID, "native_fee": 1},
+			"missing native fee":  {"destination_chain_id": PolygonChainID},
+			"invalid recipient":   {"destination_chain_id": PolygonChainID, "native_fee": 1, "recipient": 1},
+		} {
+			_, err := stargate.GenerateCalldata(context.Background(), EthChainID, BridgeSend, bridge(funded, extraData))
+			require.Error(t, err, name)
+		}
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		extraData := map[string]interface{}{
+			"destination_chain_id": PolygonChainID,
+			"native_fee":           1e15,
+		}
+
+		err := stargate.Validate(context.Background(), EthChainID, BridgeSend, bridge(funded, extraData))
+		require.NoError(t, err)
+
+		err = stargate.Validate(context.Background(), EthChainID, BridgeSend, bridge(testAccount, extraData))
+		require.ErrorIs(t, err, ErrInsufficientBalance)
+
+		lenient := map[string]interface{}{
+			"destination_chain_id": PolygonChainID,
+			"native_fee":           1e15,
+			"validation_mode":      ValidationLenient,
+		}
+
+		err = stargate.Validate(context.Background(), EthChainID, BridgeSend, bridge(testAccount, lenient))
+		require.NoError(t, err)
+
+		unsupported := bridge(funded, extraData)
+		unsupported.Asset = common.HexToAddress(nativeDenomAddress)
+
+		err = stargate.Validate(context.Background(), EthChainID, BridgeSend, unsupported)
+		require.ErrorIs(t, err, ErrAssetNotSupported)
+
+		err = stargate.Validate(context.Background(), EthChainID, LoanSupply, bridge(funded, extraData))
+		require.ErrorIs(t, err, ErrUnsupportedAction)
+	})
+
+	t.Run("min_out is kept as provided", func(t *testing.T) {
+		calldata, err := stargate.GenerateCalldata(context.Background(), EthChainID, BridgeSend, bridge(funded, map[string]interface{}{
+			"destination_chain_id": PolygonChainID,
+			"native_fee":           1,
+			"min_out":              100e6,
+		}))
+		require.NoError(t, err)
+
+		args := unpackCalldata(t, stargate.parsedABI, "sendToken", calldata)
+
+		sendParam := abi.ConvertType(args[0], new(stargateSendParam)).(*stargateSendParam)
+		require.EqualValues(t, 100e6, sendParam.MinAmountLD.Int64())
+	})
+
+	t.Run("unsupported chain", func(t *testing.T) {
+		_, err := NewStargateOperation(pkgtest.NewClient(GnosisChainID), GnosisChainID)
+		require.ErrorIs(t, err, ErrChainUnsupported)
+	})
+}
+
+func TestStargate_BridgeSendBsc_Unit(t *testing.T) {
+
+	stargate, err := NewStargateOperation(pkgtest.NewClient(BscChainID), BscChainID)
+	require.NoError(t, err)
+
+	// BSC USDC has 18 decimals, the pool drops everything below the 6 shared decimals
+	amount, ok := new(big.Int).SetString("100000000000000123456", 10)
+	require.True(t, ok)
+
+	for name, tt := range map[string]struct {
+		extraData map[string]interface{}
+		expected  string
+	}{
+		"default slippage": {
+			extraData: map[string]interface{}{},
+			expected:  "99500000000000000000",
+		},
+		"slippage_bps": {
+			extraData: map[string]interface{}{"slippage_bps": 0},
+			expected:  "100000000000000000000",
+		},
+		"min_out": {
+			extraData: map[string]interface{}{"min_out": "100000000000000123456"},
+			expected:  "100000000000000123456",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tt.extraData["destination_chain_id"] = EthChainID
+			tt.extraData["native_fee"] = 1
+
+			calldata, err := stargate.GenerateCalldata(context.Background(), BscChainID, BridgeSend, TransactionParams{
+				Sender:    testAccount,
+				Asset:     stargate.token,
+				Amount:    amount,
+				ExtraData: tt.extraData,
+			})
+			require.NoError(t, err)
+
+			args := unpackCalldata(t, stargate.parsedABI, "sendToken", calldata)
+
+			sendParam := abi.ConvertType(args[0], new(stargateSendParam)).(*stargateSendParam)
+			require.Equal(t, amount, sendParam.AmountLD)
+			require.Equal(t, tt.expected, sendParam.MinAmountLD.String())
+		})
+	}
+}
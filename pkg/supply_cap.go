@@ -0,0 +1,29 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrSupplyCapExceeded is returned when a supply would take the total supply of
+// an asset over its cap. Use errors.As with *SupplyCapError to get the capacity left
+var ErrSupplyCapExceeded = errors.New("supply cap exceeded")
+
+// SupplyCapError is the detail of an ErrSupplyCapExceeded
+type SupplyCapError struct {
+	Asset       common.Address
+	SupplyCap   *big.Int
+	TotalSupply *big.Int
+	// Remaining is the most that can still be supplied
+	Remaining *big.Int
+}
+
+func (e *SupplyCapError) Error() string {
+	return fmt.Sprintf("%s: %s of %s supplied out of %s, %s can still be supplied",
+		ErrSupplyCapExceeded, e.TotalSupply, e.Asset, e.SupplyCap, e.Remaining)
+}
+
+func (e *SupplyCapError) Unwrap() error { return ErrSupplyCapExceeded }
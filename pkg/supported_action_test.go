@@ -0,0 +1,97 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocol_IsSupportedAction(t *testing.T) {
+
+	aave, err := NewAaveOperation(pkgtest.NewClient(EthChainID), EthChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	lido, err := NewLidoOperation(pkgtest.NewClient(EthChainID), EthChainID)
+	require.NoError(t, err)
+
+	ankr, err := NewAnkrOperation(pkgtest.NewClient(EthChainID), EthChainID)
+	require.NoError(t, err)
+
+	compound, err := NewCompoundOperation(newCompoundMarket(t).client(), EthChainID,
+		common.HexToAddress(CompoundV3USDCPool))
+	require.NoError(t, err)
+
+	eigenLayer, err := NewEigenLayerOperation(pkgtest.NewClient(EthChainID), EthChainID)
+	require.NoError(t, err)
+
+	pendle, err := NewPendleOperation(pkgtest.NewClient(EthChainID), EthChainID, []common.Address{testPendleSY})
+	require.NoError(t, err)
+
+	listaStaking, err := NewListaStakingOperation(pkgtest.NewClient(BscChainID), BscChainID)
+	require.NoError(t, err)
+
+	listaLending, err := NewListaLendingOperation(pkgtest.NewClient(BscChainID), BscChainID)
+	require.NoError(t, err)
+
+	venus, err := NewVenusOperation(pkgtest.NewClient(BscChainID), BscChainID)
+	require.NoError(t, err)
+
+	benqi, err := NewBenqiOperation(pkgtest.NewClient(AvalancheChainID), AvalancheChainID)
+	require.NoError(t, err)
+
+	allActions := []ContractAction{
+		LoanSupply, LoanWithdraw, NativeStake, NativeUnStake,
+		ERC20Stake, ERC20UnStake, LoanBorrow, LoanRepay,
+		LoanSetEMode, LoanSetCollateral, LoanFlashLoan, BridgeSend,
+		LoanDelegateCredit, LoanAllowManager, ERC20Approve,
+	}
+
+	tt := []struct {
+		name      string
+		protocol  Protocol
+		supported []ContractAction
+	}{
+		{name: "aave", protocol: aave, supported: []ContractAction{LoanSupply, LoanWithdraw, LoanBorrow, LoanRepay, LoanSetEMode, LoanSetCollateral, LoanFlashLoan, LoanDelegateCredit}},
+		{name: "lido", protocol: lido, supported: []ContractAction{NativeStake}},
+		{name: "ankr", protocol: ankr, supported: []ContractAction{NativeStake, NativeUnStake}},
+		{name: "compound", protocol: compound, supported: []ContractAction{LoanSupply, LoanWithdraw, LoanAllowManager}},
+		{name: "eigenlayer", protocol: eigenLayer, supported: []ContractAction{ERC20Stake}},
+		{name: "pendle", protocol: pendle, supported: []ContractAction{ERC20Stake}},
+		{name: "lista staking", protocol: listaStaking, supported: []ContractAction{NativeStake, NativeUnStake}},
+		{name: "lista lending", protocol: listaLending, supported: []ContractAction{LoanSupply, LoanWithdraw, LoanBorrow, LoanRepay}},
+		{name: "venus", protocol: venus, supported: []ContractAction{LoanSupply, LoanWithdraw}},
+		{name: "benqi", protocol: benqi, supported: []ContractAction{NativeStake, NativeUnStake}},
+	}
+
+	for _, v := range tt {
+		t.Run(v.name, func(t *testing.T) {
+			for _, action := range allActions {
+				expected := false
+				for _, supported := range v.supported {
+					expected = expected || supported == action
+				}
+
+				require.Equal(t, expected, v.protocol.IsSupportedAction(action), action.String())
+			}
+		})
+	}
+}
+
+func TestProtocolRegistry_ListProtocolsByAction(t *testing.T) {
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+		WithDisabledProtocols(RocketPool, Compound))
+	require.NoError(t, err)
+
+	unstake := registry.ListProtocolsByAction(EthChainID, NativeUnStake)
+	require.Len(t, unstake, 1)
+	require.Equal(t, Ankr, unstake[0].GetName())
+
+	require.Len(t, registry.ListProtocolsByAction(EthChainID, NativeStake), 2)
+	require.Empty(t, registry.ListProtocolsByAction(BscChainID, NativeStake))
+}
@@ -0,0 +1,57 @@
+package pkg
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProtocolRegistry_SupportedAssetsInvariant checks every registered protocol
+// accepts in IsSupportedAsset each asset listed by its GetSupportedAssets.
+// Compound and Rocket Pool need a node to be created, see TestStaking_GetSupportedAssets
+func TestProtocolRegistry_SupportedAssetsInvariant(t *testing.T) {
+
+	chains := []ChainConfig{
+		{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+		{ChainID: BscChainID, RPCURL: "http://127.0.0.1:1"},
+		{ChainID: PolygonChainID, RPCURL: "http://127.0.0.1:1"},
+		{ChainID: GnosisChainID, RPCURL: "http://127.0.0.1:1"},
+		{ChainID: AvalancheChainID, RPCURL: "http://127.0.0.1:1"},
+	}
+
+	// the Yearn vaults read their asset from the chain
+	vaultABI, err := abi.JSON(strings.NewReader(erc4626ABI))
+	require.NoError(t, err)
+	method := vaultABI.Methods["asset"]
+
+	opts := []RegistryOption{WithDisabledProtocols(RocketPool, Compound)}
+	for _, chain := range chains {
+		client := pkgtest.NewClient(chain.ChainID)
+		client.Handle(method.ID, pkgtest.Returns(method, testDAI))
+
+		opts = append(opts, WithClient(chain.ChainID, client))
+	}
+
+	registry, err := NewProtocolRegistry(chains, opts...)
+	require.NoError(t, err)
+
+	for _, chainID := range registry.ListChains() {
+		protocols := registry.ListProtocols(chainID)
+		require.NotEmpty(t, protocols)
+
+		for _, protocol := range protocols {
+			assets, err := protocol.GetSupportedAssets(context.Background(), chainID)
+			require.NoError(t, err, protocol.GetUniqueKey())
+			require.NotEmpty(t, assets, protocol.GetUniqueKey())
+
+			for _, asset := range assets {
+				require.True(t, protocol.IsSupportedAsset(context.Background(), chainID, asset),
+					"%s lists %s but does not support it", protocol.GetUniqueKey(), asset.Hex())
+			}
+		}
+	}
+}
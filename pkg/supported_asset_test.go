@@ -0,0 +1,92 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaking_GetSupportedAssets(t *testing.T) {
+
+	newAnkr := func(chainID *big.Int) Protocol {
+		ankr, err := NewAnkrOperation(pkgtest.NewClient(chainID), chainID)
+		require.NoError(t, err)
+		return ankr
+	}
+
+	lido, err := NewLidoOperation(pkgtest.NewClient(EthChainID), EthChainID)
+	require.NoError(t, err)
+
+	lista, err := NewListaStakingOperation(pkgtest.NewClient(BscChainID), BscChainID)
+	require.NoError(t, err)
+
+	tt := []struct {
+		name     string
+		protocol Protocol
+		chainID  *big.Int
+		lst      common.Address
+	}{
+		{name: "ankrETH", protocol: newAnkr(EthChainID), chainID: EthChainID, lst: ankrEthER20Account},
+		{name: "ankrMATIC", protocol: newAnkr(PolygonChainID), chainID: PolygonChainID, lst: ankrMaticER20Account},
+		{name: "ankrBNB", protocol: newAnkr(BscChainID), chainID: BscChainID, lst: ankrBnbER20Account},
+		{name: "rETH", protocol: &RocketpoolOperation{chainID: EthChainID}, chainID: EthChainID, lst: rocketpoolRETHAddress},
+		{name: "stETH", protocol: lido, chainID: EthChainID, lst: LidoContractAddress},
+		{name: "slisBNB", protocol: lista, chainID: BscChainID, lst: slisBNBTokenAddress},
+	}
+
+	for _, v := range tt {
+		t.Run(v.name, func(t *testing.T) {
+			assets, err := v.protocol.GetSupportedAssets(context.Background(), v.chainID)
+			require.NoError(t, err)
+			require.Equal(t, []common.Address{common.HexToAddress(nativeDenomAddress), v.lst}, assets)
+
+			for _, asset := range assets {
+				require.True(t, v.protocol.IsSupportedAsset(context.Background(), v.chainID, asset), asset.Hex())
+			}
+
+			require.False(t, v.protocol.IsSupportedAsset(context.Background(), v.chainID, testUSDC))
+
+			// other chains list nothing as they support nothing
+			_, err = v.protocol.GetSupportedAssets(context.Background(), GnosisChainID)
+			require.ErrorIs(t, err, ErrChainUnsupported)
+		})
+	}
+}
+
+func TestIsSupportedAsset_Case(t *testing.T) {
+
+	wbtc := "0x2260FAC5E5542a773Aa44fBCfeDf7C193bc2C599"
+
+	compound, err := NewCompoundOperation(newCompoundMarket(t, common.HexToAddress(wbtc)).client(),
+		EthChainID, common.HexToAddress(CompoundV3USDCPool))
+	require.NoError(t, err)
+
+	aave, err := NewAaveOperationOffline(EthChainID, AaveProtocolDeploymentEthereum)
+	require.NoError(t, err)
+
+	tt := []struct {
+		name     string
+		protocol Protocol
+		asset    string
+	}{
+		{name: "compound", protocol: compound, asset: wbtc},
+		{name: "aave", protocol: aave, asset: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"},
+		{name: "aave native", protocol: aave, asset: "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE"},
+	}
+
+	for _, v := range tt {
+		t.Run(v.name, func(t *testing.T) {
+			for _, asset := range []string{v.asset, strings.ToLower(v.asset), "0x" + strings.ToUpper(v.asset[2:])} {
+				require.True(t, v.protocol.IsSupportedAsset(context.Background(), EthChainID, common.HexToAddress(asset)), asset)
+			}
+		})
+	}
+
+	require.True(t, IsNativeToken(common.HexToAddress("0xEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEE")))
+	require.False(t, compound.IsSupportedAsset(context.Background(), EthChainID, testUSDC))
+}
@@ -0,0 +1,112 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenListProtocol is a protocol of the tokens/*.json files, read without the
+// tokens package which imports this one
+type tokenListProtocol struct {
+	Address string   `json:"address"`
+	Name    string   `json:"name"`
+	Tokens  []string `json:"tokens"`
+}
+
+// tokenListDiff is the outcome of reconciling the tokens of a protocol listed
+// in tokenSupportedMap with the ones of the tokens JSON files
+type tokenListDiff struct {
+	// Union holds the tokens of both lists, the list either should become
+	Union []common.Address
+	// OnlyStatic are the tokens missing from the JSON files
+	OnlyStatic []common.Address
+	// OnlyJSON are the tokens missing from tokenSupportedMap
+	OnlyJSON []common.Address
+}
+
+func (d tokenListDiff) consistent() bool { return len(d.OnlyStatic) == 0 && len(d.OnlyJSON) == 0 }
+
+// reconcile compares two token lists regardless of the case and order of the
+// addresses. The addresses of the returned lists are sorted
+func reconcile(static, listed []string) tokenListDiff {
+
+	toSet := func(tokens []string) map[common.Address]struct{} {
+		set := make(map[common.Address]struct{}, len(tokens))
+		for _, token := range tokens {
+			set[common.HexToAddress(token)] = struct{}{}
+		}
+		return set
+	}
+
+	staticSet, listedSet := toSet(static), toSet(listed)
+
+	var diff tokenListDiff
+	for token := range staticSet {
+		diff.Union = append(diff.Union, token)
+		if _, ok := listedSet[token]; !ok {
+			diff.OnlyStatic = append(diff.OnlyStatic, token)
+		}
+	}
+
+	for token := range listedSet {
+		if _, ok := staticSet[token]; !ok {
+			diff.Union = append(diff.Union, token)
+			diff.OnlyJSON = append(diff.OnlyJSON, token)
+		}
+	}
+
+	for _, addresses := range [][]common.Address{diff.Union, diff.OnlyStatic, diff.OnlyJSON} {
+		sort.Slice(addresses, func(i, j int) bool { return addresses[i].Hex() < addresses[j].Hex() })
+	}
+
+	return diff
+}
+
+func TestReconcile(t *testing.T) {
+
+	usdc, dai := testUSDC.Hex(), testDAI.Hex()
+	wbtc := "0x2260fac5e5542a773aa44fbcfedf7c193bc2c599"
+
+	diff := reconcile([]string{usdc, wbtc}, []string{dai, common.HexToAddress(wbtc).Hex()})
+	require.False(t, diff.consistent())
+	require.Equal(t, []common.Address{testUSDC}, diff.OnlyStatic)
+	require.Equal(t, []common.Address{testDAI}, diff.OnlyJSON)
+	require.Len(t, diff.Union, 3)
+
+	require.True(t, reconcile([]string{wbtc}, []string{common.HexToAddress(wbtc).Hex()}).consistent())
+}
+
+// TestTokenLists_Consistency checks the protocols listed both in tokenSupportedMap
+// and in the tokens JSON files support the same tokens on every chain
+func TestTokenLists_Consistency(t *testing.T) {
+
+	for chainID, protocols := range tokenSupportedMap {
+		b, err := os.ReadFile(filepath.Join("..", "tokens", fmt.Sprintf("%d.json", chainID)))
+		require.NoError(t, err)
+
+		var data struct {
+			Protocols []tokenListProtocol `json:"protocols"`
+		}
+		require.NoError(t, json.Unmarshal(b, &data))
+
+		for _, listed := range data.Protocols {
+			static, ok := protocols[NormalizeProtocolName(listed.Name)]
+			if !ok || len(static) == 0 {
+				continue
+			}
+
+			diff := reconcile(static, listed.Tokens)
+			if !diff.consistent() {
+				t.Errorf("%s on chain %d: only in validate.go %v, only in tokens/%d.json %v, union %v",
+					listed.Name, chainID, diff.OnlyStatic, chainID, diff.OnlyJSON, diff.Union)
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTransaction(t *testing.T) {
+
+	t.Run("native stake sends the amount", func(t *testing.T) {
+		lido, err := NewLidoOperation(pkgtest.NewClient(EthChainID), EthChainID)
+		require.NoError(t, err)
+
+		params := TransactionParams{
+			Amount: big.NewInt(1e18),
+			Sender: testAccount,
+			Asset:  common.HexToAddress(nativeDenomAddress),
+		}
+
+		tx, err := lido.BuildTransaction(context.Background(), EthChainID, NativeStake, params)
+		require.NoError(t, err)
+
+		calldata, err := lido.GenerateCalldata(context.Background(), EthChainID, NativeStake, params)
+		require.NoError(t, err)
+
+		require.Equal(t, LidoContractAddress, tx.To)
+		require.Equal(t, calldata, tx.Data)
+		require.Equal(t, big.NewInt(1e18), tx.Value)
+	})
+
+	t.Run("unstake sends nothing", func(t *testing.T) {
+		ankr := newTestAnkrOperation(t, big.NewInt(1e18))
+
+		tx, err := ankr.BuildTransaction(context.Background(), EthChainID, NativeUnStake, TransactionParams{
+			Amount: big.NewInt(1e18),
+			Sender: testAccount,
+			Asset:  common.HexToAddress(nativeDenomAddress),
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, ankr.GetContractAddress(EthChainID), tx.To)
+		require.Zero(t, tx.Value.Sign())
+	})
+
+	t.Run("calldata errors are returned", func(t *testing.T) {
+		lido, err := NewLidoOperation(pkgtest.NewClient(EthChainID), EthChainID)
+		require.NoError(t, err)
+
+		_, err = lido.BuildTransaction(context.Background(), EthChainID, LoanSupply, TransactionParams{
+			Amount: big.NewInt(1e18),
+			Sender: testAccount,
+			Asset:  common.HexToAddress(nativeDenomAddress),
+		})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,65 @@
+package pkg
+
+import (
+	"fmt"
+	"math/big"
+)
+
+const (
+	// extraDataMinOut is the ExtraData key setting the minimum output of a conversion
+	extraDataMinOut = "min_out"
+	// extraDataSlippageBps is the ExtraData key setting the slippage, in basis
+	// points, the minimum output is derived with when min_out is not provided
+	extraDataSlippageBps = "slippage_bps"
+)
+
+// maxSlippageBps is 100% expressed in basis points
+const maxSlippageBps = 10_000
+
+// applySlippage returns the minimum output accepted for amount once bps basis
+// points of slippage are allowed. bps of 10000 or more accept any output
+func applySlippage(amount *big.Int, bps uint16) *big.Int {
+
+	if bps >= maxSlippageBps {
+		return big.NewInt(0)
+	}
+
+	out := new(big.Int).Mul(amount, big.NewInt(int64(maxSlippageBps-bps)))
+	return out.Quo(out, big.NewInt(maxSlippageBps))
+}
+
+// minOutput returns the minimum output of a conversion. ExtraData["min_out"]
+// is used when provided, otherwise ExtraData["slippage_bps"] is applied to the
+// output returned by expected. Without either 0 is returned and expected is not called
+func minOutput(params TransactionParams, expected func() (*big.Int, error)) (*big.Int, error) {
+
+	if value, ok := params.ExtraData[extraDataMinOut]; ok {
+		minOut, err := toBigInt(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min_out: %w", err)
+		}
+
+		return minOut, nil
+	}
+
+	value, ok := params.ExtraData[extraDataSlippageBps]
+	if !ok {
+		return big.NewInt(0), nil
+	}
+
+	bps, err := toUint16(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid slippage_bps: %w", err)
+	}
+
+	if bps > maxSlippageBps {
+		return nil, fmt.Errorf("slippage_bps %d exceeds %d", bps, maxSlippageBps)
+	}
+
+	amount, err := expected()
+	if err != nil {
+		return nil, err
+	}
+
+	return applySlippage(amount, bps), nil
+}
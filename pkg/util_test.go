@@ -0,0 +1,90 @@
+package pkg
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplySlippage(t *testing.T) {
+
+	tt := []struct {
+		name     string
+		amount   *big.Int
+		bps      uint16
+		expected *big.Int
+	}{
+		{name: "50 bps", amount: big.NewInt(1e18), bps: 50, expected: big.NewInt(995e15)},
+		{name: "100 bps", amount: big.NewInt(1e18), bps: 100, expected: big.NewInt(99e16)},
+		{name: "0 bps keeps the amount", amount: big.NewInt(1e18), bps: 0, expected: big.NewInt(1e18)},
+		{name: "10000 bps accepts anything", amount: big.NewInt(1e18), bps: 10_000, expected: big.NewInt(0)},
+		{name: "above 10000 bps accepts anything", amount: big.NewInt(1e18), bps: 20_000, expected: big.NewInt(0)},
+		{name: "rounds down", amount: big.NewInt(199), bps: 50, expected: big.NewInt(198)},
+		{name: "zero amount", amount: big.NewInt(0), bps: 50, expected: big.NewInt(0)},
+	}
+
+	for _, v := range tt {
+		t.Run(v.name, func(t *testing.T) {
+			amount := new(big.Int).Set(v.amount)
+
+			require.Equal(t, v.expected, applySlippage(amount, v.bps))
+			require.Equal(t, v.amount, amount, "amount must not be modified")
+		})
+	}
+}
+
+func TestMinOutput(t *testing.T) {
+
+	expected := func() (*big.Int, error) { return big.NewInt(1e18), nil }
+
+	t.Run("nothing set", func(t *testing.T) {
+		minOut, err := minOutput(TransactionParams{}, func() (*big.Int, error) {
+			t.Fatal("expected output must not be fetched")
+			return nil, nil
+		})
+		require.NoError(t, err)
+		require.Zero(t, minOut.Sign())
+	})
+
+	t.Run("explicit min_out wins over slippage", func(t *testing.T) {
+		minOut, err := minOutput(TransactionParams{ExtraData: map[string]interface{}{
+			"min_out":      "5",
+			"slippage_bps": 50,
+		}}, expected)
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(5), minOut)
+	})
+
+	t.Run("derived from slippage", func(t *testing.T) {
+		minOut, err := minOutput(TransactionParams{ExtraData: map[string]interface{}{
+			"slippage_bps": float64(100),
+		}}, expected)
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(99e16), minOut)
+	})
+
+	t.Run("slippage above 10000 bps", func(t *testing.T) {
+		_, err := minOutput(TransactionParams{ExtraData: map[string]interface{}{
+			"slippage_bps": 10_001,
+		}}, expected)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid min_out", func(t *testing.T) {
+		_, err := minOutput(TransactionParams{ExtraData: map[string]interface{}{
+			"min_out": "-1",
+		}}, expected)
+		require.Error(t, err)
+	})
+
+	t.Run("expected output error", func(t *testing.T) {
+		quoteErr := errors.New("quote failed")
+
+		_, err := minOutput(TransactionParams{ExtraData: map[string]interface{}{
+			"slippage_bps": 50,
+		}}, func() (*big.Int, error) { return nil, quoteErr })
+		require.ErrorIs(t, err, quoteErr)
+	})
+}
@@ -1,8 +1,6 @@
 package pkg
 
 import (
-	"strings"
-
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -60,6 +58,13 @@ var tokenSupportedMap = map[int64]map[ProtocolName][]string{
 			"0x4aae823a6a0b376De6A78e74eCC5b079d38cBCf7", // SolvBTC
 			"0x1346b618dC92810EC74163e4c27004c921D446a5", // SolvBTC.BBN
 		},
+		Radiant: {
+			"0x55d398326f99059fF775485246999027B3197955", // USDT
+			"0x8AC76a51cc950d9822D68b83fE1Ad97B32Cd580d", // USDC
+			"0x7130d2A12B9BCbFAe4f2634d864A1Ee1Ce3Ead9c", // BTCB
+			"0x2170ed0880ac9a755fd29b2688956bd959f933f8", // ETH
+			"0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c", // WBNB
+		},
 	},
 	PolygonChainID.Int64(): {
 		AaveV3: {
@@ -78,9 +83,33 @@ var tokenSupportedMap = map[int64]map[ProtocolName][]string{
 			"0x3c499c542cEF5E3811e1192ce70d8cC03d5c3359",
 		},
 	},
+	GnosisChainID.Int64(): {
+		AaveV3: {
+			"0x6A023CCd1ff6F2045C3309768eAd9E68F978f6e1", // WETH
+			"0x6C76971f98945AE98dD7d4DFcA8711ebea946eA6", // wstETH
+			"0x9C58BAcC331c9aa871AFD802DB6379a98e80CEdb", // GNO
+			"0xDDAfbb505ad214D7b80b1f830fcCc89B60fb7A83", // USDC
+			"0xe91D153E0b41518A2Ce8Dd3D7944Fa863463a97d", // WXDAI
+			"0xcB444e90D8198415266c6a2724b7900fb12FC56E", // EURe
+			"0xaf204776c7245bF4147c2612BF6e5972Ee483701", // savingsXDAI ( sDAI )
+		},
+	},
+	AvalancheChainID.Int64(): {
+		AaveV3: {
+			"0xB31f66AA3C1e785363F0875A1B74E27b85FD66c7", // WAVAX
+			"0x2b2C81e08f1Af8835a78Bb2A90AE924ACE0eA4bE", // sAVAX
+			"0xB97EF9Ef8734C71904D8002F8b6Bc66Dd9c48a6E", // USDC
+			"0x9702230A8Ea53601f5cD2dc00fDBc13d4dF4A8c7", // USDt
+			"0x49D5c2BdFfac6CE2BFdB6640F4F80f226bc10bAB", // WETH.e
+			"0x152b9d0FdC40C096757F570A51E494bd4b943E50", // BTC.b
+			"0x50b7545627a5162F82A992c33b87aDc75187B218", // WBTC.e
+			"0xd586E7F844cEa2F87f50152665BCbc2C279D8d70", // DAI.e
+			"0x5947BB275c521040051D82396192181b413227A3", // LINK.e
+		},
+	},
 }
 
 // IsNativeToken checks if the token is ETH
 func IsNativeToken(asset common.Address) bool {
-	return strings.ToLower(asset.Hex()) == nativeDenomAddress
+	return asset == common.HexToAddress(nativeDenomAddress)
 }
@@ -0,0 +1,336 @@
+package pkg
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const venusVBNBABI = `
+[
+  {
+    "inputs": [],
+    "name": "mint",
+    "outputs": [],
+    "stateMutability": "payable",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "uint256", "name": "redeemAmount", "type": "uint256" }
+    ],
+    "name": "redeemUnderlying",
+    "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }],
+    "stateMutability": "nonpayable",
+    "type": "function"
+  },
+  {
+    "inputs": [
+      { "internalType": "address", "name": "owner", "type": "address" }
+    ],
+    "name": "balanceOfUnderlying",
+    "outputs": [{ "internalType": "uint256", "name": "", "type": "uint256" }],
+    "stateMutability": "nonpayable",
+    "type": "function"
+  }
+]`
+
+// VenusOperation implements the native BNB market (vBNB) of Venus.
+// Supplying mints vBNB in exchange for BNB and withdrawing redeems it
+// https://venus.io
+type VenusOperation struct {
+	contract  common.Address
+	parsedABI abi.ABI
+	chainID   *big.Int
+	client    EthClient
+	observer  Observer
+
+	// verified on first use, see networkCheck
+	network networkCheck
+
+	// disabled through the registry, see SetProtocolEnabled
+	deprecation
+}
+
+var _ Protocol = (*VenusOperation)(nil)
+
+func NewVenusOperation(client EthClient, chainID *big.Int, opts ...NetworkOption) (*VenusOperation, error) {
+
+	if chainID.Cmp(BscChainID) != 0 {
+		return nil, ErrChainUnsupported
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(venusVBNBABI))
+	if err != nil {
+		return nil, err
+	}
+
+	v := &VenusOperation{
+		parsedABI: parsedABI,
+		chainID:   chainID,
+		client:    client,
+		contract:  VenusVBNBContractAddress,
+	}
+
+	for _, opt := range opts {
+		opt(&v.network)
+	}
+
+	return v, nil
+}
+
+// GenerateCalldata creates the necessary blockchain transaction data
+func (v *VenusOperation) GenerateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+	if err := v.deprecation.check(v.GetUniqueKey()); err != nil {
+		return "", err
+	}
+
+	if err := params.checkAmountRange(); err != nil {
+		return "", err
+	}
+
+	return observeCalldata(v.observer, v.GetName(), action, func() (string, error) {
+		return v.generateCalldata(ctx, chainID, action, params)
+	})
+}
+
+func (v *VenusOperation) generateCalldata(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (string, error) {
+
+	if !v.isSupportedChain(chainID) {
+		return "", ErrChainUnsupported
+	}
+
+	if err := v.network.ensure(ctx, v.client, v.chainID); err != nil {
+		return "", err
+	}
+
+	var calldata []byte
+	var err error
+
+	switch action {
+	case LoanSupply:
+		calldata, err = v.parsedABI.Pack("mint")
+	case LoanWithdraw:
+		if params.Amount == nil {
+			return "", ErrAmountRequired
+		}
+
+		calldata, err = v.parsedABI.Pack("redeemUnderlying", params.Amount)
+	default:
+		return "", ErrUnsupportedAction
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return HexPrefix + hex.EncodeToString(calldata), nil
+}
+
+// EstimateGas estimates the gas needed to execute the generated calldata
+func (v *VenusOperation) EstimateGas(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (uint64, error) {
+
+	tx, err := v.BuildTransaction(ctx, chainID, action, params)
+	if err != nil {
+		return 0, err
+	}
+
+	return estimateGas(ctx, v.client, params.Sender, tx)
+}
+
+// BuildTransaction generates the calldata for the action together with
+// the contract to call and the value to send
+func (v *VenusOperation) BuildTransaction(ctx context.Context, chainID *big.Int,
+	action ContractAction, params TransactionParams) (*Transaction, error) {
+
+	calldata, err := v.GenerateCalldata(ctx, chainID, action, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{
+		To:    v.GetContractAddress(chainID),
+		Data:  calldata,
+		Value: v.CallValue(action, params),
+	}, nil
+}
+
+// Validate checks if the provided parameters are valid for the specified action
+func (v *VenusOperation) Validate(ctx context.Context,
+	chainID *big.Int, action ContractAction, params TransactionParams) error {
+
+	if err := v.deprecation.check(v.GetUniqueKey()); err != nil {
+		return err
+	}
+
+	if !v.isSupportedChain(chainID) {
+		return ErrChainUnsupported
+	}
+
+	if err := v.network.ensure(ctx, v.client, v.chainID); err != nil {
+		return err
+	}
+
+	if !v.IsSupportedAsset(ctx, chainID, params.Asset) {
+		return fmt.Errorf("%w %s", ErrAssetNotSupported, params.Asset)
+	}
+
+	if err := params.ValidateAmount(); err != nil {
+		return err
+	}
+
+	if !v.IsSupportedAction(action) {
+		return ErrUnsupportedAction
+	}
+
+	if params.lenientValidation(ValidationStrict) {
+		return nil
+	}
+
+	var balance *big.Int
+	var err error
+
+	switch action {
+	case LoanSupply:
+		balance, err = v.client.BalanceAt(ctx, params.Sender, nil)
+	case LoanWithdraw:
+		_, balance, err = v.GetBalance(ctx, chainID, params.Sender, params.Asset)
+	default:
+		return ErrUnsupportedAction
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if balance.Cmp(params.Amount) < 0 {
+		return ErrInsufficientBalance
+	}
+
+	return nil
+}
+
+// GetBalance retrieves the BNB supplied by account, interest included
+func (v *VenusOperation) GetBalance(ctx context.Context, chainID *big.Int,
+	account, _ common.Address) (common.Address, *big.Int, error) {
+
+	if !v.isSupportedChain(chainID) {
+		return common.Address{}, nil, ErrChainUnsupported
+	}
+
+	calldata, err := v.parsedABI.Pack("balanceOfUnderlying", account)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	result, err := v.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &v.contract,
+		Data: calldata,
+	}, nil)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	balance := new(big.Int)
+	err = v.parsedABI.UnpackIntoInterface(&balance, "balanceOfUnderlying", result)
+	return v.contract, balance, err
+}
+
+// GetSupportedAssets returns a list of assets supported by the protocol on the specified chain
+func (v *VenusOperation) GetSupportedAssets(ctx context.Context, chainID *big.Int) ([]common.Address, error) {
+	if !v.isSupportedChain(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	return []common.Address{
+		common.HexToAddress(nativeDenomAddress),
+	}, nil
+}
+
+func (v *VenusOperation) isSupportedChain(chain *big.Int) bool {
+	return v.chainID.Cmp(chain) == 0
+}
+
+// IsSupportedAsset checks if the specified asset is supported on the given chain
+func (v *VenusOperation) IsSupportedAsset(ctx context.Context, chainID *big.Int, asset common.Address) bool {
+	if !v.isSupportedChain(chainID) {
+		return false
+	}
+
+	return IsNativeToken(asset)
+}
+
+// GetProtocolConfig returns the protocol config for a specific chain
+func (v *VenusOperation) GetProtocolConfig(chainID *big.Int) ProtocolConfig {
+	return ProtocolConfig{
+		ChainID:  v.chainID,
+		ABI:      v.parsedABI,
+		Type:     TypeLoan,
+		Contract: v.contract,
+	}
+}
+
+// GetABI returns the ABI of the protocol's contract
+func (v *VenusOperation) GetABI(chainID *big.Int) abi.ABI { return v.parsedABI }
+
+// GetType returns the protocol type
+func (v *VenusOperation) GetType() ProtocolType { return TypeLoan }
+
+// GetContractAddress returns the contract address for a specific chain
+func (v *VenusOperation) GetContractAddress(chainID *big.Int) common.Address {
+	return v.contract
+}
+
+// GetContractAddressForAction returns the contract the calldata of action is sent to,
+// the same for every action
+func (v *VenusOperation) GetContractAddressForAction(chainID *big.Int, action ContractAction) (common.Address, error) {
+	return contractAddressForAction(v, chainID, action)
+}
+
+// Name returns the human readable name for the protocol
+func (v *VenusOperation) GetName() string { return Venus }
+
+// GetVersion returns the version of the protocol
+func (v *VenusOperation) GetVersion() string { return "1" }
+
+// GetUniqueKey returns the key identifying the protocol across chains
+func (v *VenusOperation) GetUniqueKey() string { return protocolKey(v.GetName(), v.chainID) }
+
+// CallValue returns the native amount to send along with the calldata.
+// The mint call is payable and takes the supplied BNB as msg.value
+func (v *VenusOperation) CallValue(action ContractAction, params TransactionParams) *big.Int {
+	if action == LoanSupply {
+		return params.nativeCallValue()
+	}
+
+	return big.NewInt(0)
+}
+
+// Capabilities describes the actions, chains and extra data supported by the protocol
+func (v *VenusOperation) Capabilities() ProtocolCapabilities {
+	return ProtocolCapabilities{
+		SupportedActions:  []ContractAction{LoanSupply, LoanWithdraw},
+		SupportedChains:   []*big.Int{v.chainID},
+		RequiresExtraData: []string{},
+	}
+}
+
+// IsSupportedAction reports whether calldata can be generated for action
+func (v *VenusOperation) IsSupportedAction(action ContractAction) bool {
+	return v.Capabilities().Supports(action)
+}
+
+// SetObserver implements Observable
+func (v *VenusOperation) SetObserver(observer Observer) {
+	v.observer = observer
+	v.client = observeClient(v.client, v.GetName(), observer)
+}
@@ -0,0 +1,73 @@
+package pkg
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestVenusOperation(t *testing.T, bnb, supplied *big.Int) *VenusOperation {
+	t.Helper()
+
+	client := pkgtest.NewClient(BscChainID)
+	client.SetBalance(testAccount, bnb)
+
+	venus, err := NewVenusOperation(client, BscChainID)
+	require.NoError(t, err)
+
+	client.HandleReturns(VenusVBNBContractAddress, venus.parsedABI.Methods["balanceOfUnderlying"], supplied)
+
+	return venus
+}
+
+func TestVenus_BuildTransaction(t *testing.T) {
+
+	params := TransactionParams{
+		Amount: big.NewInt(1e18),
+		Sender: testAccount,
+		Asset:  common.HexToAddress(nativeDenomAddress),
+	}
+
+	runBuildCases(t, newTestVenusOperation(t, big.NewInt(0), big.NewInt(0)), BscChainID, []buildCase{
+		{
+			name:   "supply mints with the BNB as value",
+			action: LoanSupply,
+			params: params,
+			to:     VenusVBNBContractAddress,
+			data:   "0x1249c58b",
+			value:  big.NewInt(1e18),
+		},
+		{
+			name:   "withdraw redeems the underlying",
+			action: LoanWithdraw,
+			params: params,
+			to:     VenusVBNBContractAddress,
+			data: "0x852a12e3" +
+				"0000000000000000000000000000000000000000000000000de0b6b3a7640000", // redeemAmount
+		},
+	})
+}
+
+func TestVenus_Validate(t *testing.T) {
+
+	params := func(asset common.Address, amount int64) TransactionParams {
+		return TransactionParams{
+			Amount: big.NewInt(amount),
+			Sender: testAccount,
+			Asset:  asset,
+		}
+	}
+
+	native := common.HexToAddress(nativeDenomAddress)
+
+	runValidateCases(t, newTestVenusOperation(t, big.NewInt(100), big.NewInt(50)), BscChainID, []validateCase{
+		{action: LoanSupply, params: params(native, 100), valid: true},
+		{action: LoanSupply, params: params(native, 101)},
+		{action: LoanWithdraw, params: params(native, 50), valid: true},
+		{action: LoanWithdraw, params: params(native, 51)},
+		{action: LoanSupply, params: params(slisBNBTokenAddress, 1)},
+	})
+}
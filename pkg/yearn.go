@@ -0,0 +1,41 @@
+package pkg
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// yearnV3EthVaults are the Yearn V3 vaults registered on Ethereum
+var yearnV3EthVaults = []common.Address{
+	YearnV3USDCVaultAddress,
+	YearnV3DAIVaultAddress,
+}
+
+// YearnOperation deposits into and withdraws from a single Yearn V3 vault.
+// Yearn V3 vaults are plain ERC4626 vaults, see ERC4626Operation
+// https://yearn.fi
+type YearnOperation struct {
+	*ERC4626Operation
+}
+
+var _ Protocol = (*YearnOperation)(nil)
+
+func NewYearnOperation(client EthClient, chainID *big.Int, vault common.Address,
+	opts ...NetworkOption) (*YearnOperation, error) {
+
+	if !IsEth(chainID) {
+		return nil, ErrChainUnsupported
+	}
+
+	vaultOperation, err := NewERC4626Operation(client, chainID, vault, WithERC4626Protocol(Yearn, "3"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, opt := range opts {
+		opt(&vaultOperation.network)
+	}
+
+	return &YearnOperation{ERC4626Operation: vaultOperation}, nil
+}
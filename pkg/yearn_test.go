@@ -0,0 +1,148 @@
+package pkg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYearn_Unit(t *testing.T) {
+
+	funded := common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+
+	client := pkgtest.NewClient(EthChainID)
+
+	yearn, err := NewYearnOperation(client, EthChainID, YearnV3USDCVaultAddress)
+	require.NoError(t, err)
+
+	method := yearn.parsedABI.Methods["asset"]
+	client.HandleContract(YearnV3USDCVaultAddress, method.ID, pkgtest.Returns(method, testUSDC))
+
+	// 1 share is worth 1.1 USDC
+	method = yearn.parsedABI.Methods["convertToAssets"]
+	client.HandleContract(YearnV3USDCVaultAddress, method.ID, func(msg ethereum.CallMsg) ([]byte, error) {
+		shares := new(big.Int).SetBytes(msg.Data[4:])
+		return method.Outputs.Pack(new(big.Int).Div(new(big.Int).Mul(shares, big.NewInt(11)), big.NewInt(10)))
+	})
+
+	method = yearn.parsedABI.Methods["balanceOf"]
+	balances := func(msg ethereum.CallMsg) ([]byte, error) {
+		if common.BytesToAddress(msg.Data[4:]) == funded {
+			return method.Outputs.Pack(big.NewInt(1000e6))
+		}
+
+		return method.Outputs.Pack(big.NewInt(0))
+	}
+	client.HandleContract(YearnV3USDCVaultAddress, method.ID, balances)
+	client.HandleContract(testUSDC, method.ID, balances)
+
+	params := func(sender common.Address) TransactionParams {
+		return TransactionParams{
+			Sender: sender,
+			Asset:  testUSDC,
+			Amount: big.NewInt(1050e6),
+		}
+	}
+
+	t.Run("deposit", func(t *testing.T) {
+		tx, err := yearn.BuildTransaction(context.Background(), EthChainID, ERC20Stake, params(funded))
+		require.NoError(t, err)
+
+		// cast calldata "deposit(uint256,address)" 1050000000 0x000000000000000000000000000000000000bEEF
+		require.Equal(t, "0x6e553f65"+
+			"000000000000000000000000000000000000000000000000000000003e95ba80"+
+			"000000000000000000000000000000000000000000000000000000000000beef", tx.Data)
+		require.Equal(t, YearnV3USDCVaultAddress, tx.To)
+		require.Zero(t, tx.Value.Sign())
+	})
+
+	t.Run("withdraw", func(t *testing.T) {
+		calldata, err := yearn.GenerateCalldata(context.Background(), EthChainID, ERC20UnStake, params(funded))
+		require.NoError(t, err)
+
+		args := unpackCalldata(t, yearn.parsedABI, "withdraw", calldata)
+		require.EqualValues(t, 1050e6, args[0].(*big.Int).Int64())
+		require.Equal(t, funded, args[1])
+		require.Equal(t, funded, args[2])
+	})
+
+	t.Run("balance is converted to assets", func(t *testing.T) {
+		token, balance, err := yearn.GetBalance(context.Background(), EthChainID, funded, testUSDC)
+		require.NoError(t, err)
+		require.Equal(t, testUSDC, token)
+		require.EqualValues(t, 1100e6, balance.Int64())
+
+		assets, err := yearn.GetSupportedAssets(context.Background(), EthChainID)
+		require.NoError(t, err)
+		require.Equal(t, []common.Address{testUSDC}, assets)
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		// 1000 USDC held against 1050 deposited
+		err := yearn.Validate(context.Background(), EthChainID, ERC20Stake, params(funded))
+		require.ErrorIs(t, err, ErrInsufficientBalance)
+
+		// the shares are worth 1100 USDC
+		err = yearn.Validate(context.Background(), EthChainID, ERC20UnStake, params(funded))
+		require.NoError(t, err)
+
+		err = yearn.Validate(context.Background(), EthChainID, ERC20UnStake, params(testAccount))
+		require.ErrorIs(t, err, ErrInsufficientBalance)
+
+		lenient := params(testAccount)
+		lenient.ExtraData = map[string]interface{}{"validation_mode": ValidationLenient}
+
+		err = yearn.Validate(context.Background(), EthChainID, ERC20Stake, lenient)
+		require.NoError(t, err)
+
+		wrongAsset := params(funded)
+		wrongAsset.Asset = common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+
+		err = yearn.Validate(context.Background(), EthChainID, ERC20Stake, wrongAsset)
+		require.ErrorIs(t, err, ErrAssetNotSupported)
+
+		err = yearn.Validate(context.Background(), EthChainID, NativeStake, params(funded))
+		require.ErrorIs(t, err, ErrUnsupportedAction)
+	})
+
+	t.Run("asset is fetched once", func(t *testing.T) {
+		calls := client.CallCount()
+
+		require.True(t, yearn.IsSupportedAsset(context.Background(), EthChainID, testUSDC))
+		require.Equal(t, calls, client.CallCount())
+	})
+
+	t.Run("unsupported chain", func(t *testing.T) {
+		_, err := NewYearnOperation(pkgtest.NewClient(BscChainID), BscChainID, YearnV3USDCVaultAddress)
+		require.ErrorIs(t, err, ErrChainUnsupported)
+	})
+}
+
+func TestProtocolRegistry_YearnVaults(t *testing.T) {
+
+	registry, err := NewProtocolRegistry([]ChainConfig{
+		{ChainID: EthChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		WithClient(EthChainID, pkgtest.NewClient(EthChainID)),
+		WithDisabledProtocols(RocketPool, Compound))
+	require.NoError(t, err)
+
+	for _, vault := range []common.Address{YearnV3USDCVaultAddress, YearnV3DAIVaultAddress} {
+		protocol, err := registry.GetProtocolByKey("yearn_v3:1:" + vault.Hex())
+		require.NoError(t, err)
+		require.Equal(t, vault, protocol.GetContractAddress(EthChainID))
+	}
+
+	var vaults int
+	for _, protocol := range registry.ListProtocolsByType(EthChainID, TypeVault) {
+		if protocol.GetName() == Yearn {
+			vaults++
+		}
+	}
+	require.Equal(t, 2, vaults)
+}
@@ -0,0 +1,247 @@
+// Package server exposes a ProtocolRegistry over HTTP so clients can discover
+// the supported protocols and generate calldata without importing the registry
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+
+	"github.com/blndgs/protocol_registry/pkg"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Handler serves the registry endpoints:
+//
+//	GET  /chains/{id}/protocols
+//	GET  /chains/{id}/protocols/{address}
+//	POST /chains/{id}/protocols/{address}/calldata
+//	GET  /export
+type Handler struct {
+	registry pkg.ProtocolRegistry
+	mux      *http.ServeMux
+}
+
+// New creates a Handler backed by registry
+func New(registry pkg.ProtocolRegistry) *Handler {
+	h := &Handler{
+		registry: registry,
+		mux:      http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("GET /chains/{id}/protocols", h.listProtocols)
+	h.mux.HandleFunc("GET /chains/{id}/protocols/{address}", h.getProtocol)
+	h.mux.HandleFunc("POST /chains/{id}/protocols/{address}/calldata", h.generateCalldata)
+	h.mux.HandleFunc("GET /export", h.export)
+
+	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// ProtocolResponse describes a registered protocol
+type ProtocolResponse struct {
+	Name            string                   `json:"name"`
+	Version         string                   `json:"version"`
+	Type            pkg.ProtocolType         `json:"type"`
+	Address         common.Address           `json:"address"`
+	SupportedAssets []common.Address         `json:"supported_assets,omitempty"`
+	Capabilities    pkg.ProtocolCapabilities `json:"capabilities"`
+}
+
+// CalldataRequest is the body of the calldata endpoint. Amount is a base 10
+// integer, omitted for the actions sending no amount
+type CalldataRequest struct {
+	Action    string                 `json:"action"`
+	Amount    string                 `json:"amount"`
+	Sender    common.Address         `json:"sender"`
+	Recipient common.Address         `json:"recipient"`
+	Asset     common.Address         `json:"asset"`
+	ExtraData map[string]interface{} `json:"extra_data"`
+}
+
+// CalldataResponse is the transaction to send to execute the requested action
+type CalldataResponse struct {
+	To       common.Address `json:"to"`
+	Calldata string         `json:"calldata"`
+	Value    string         `json:"value"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// exporter is implemented by registries that can serialize all their protocols
+type exporter interface {
+	Export() ([]byte, error)
+}
+
+func (h *Handler) listProtocols(w http.ResponseWriter, r *http.Request) {
+
+	chainID, err := parseChainID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	protocols := h.registry.ListProtocols(chainID)
+
+	resp := make([]ProtocolResponse, 0, len(protocols))
+	for _, protocol := range protocols {
+		resp = append(resp, newProtocolResponse(chainID, protocol))
+	}
+
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].Address.Hex() < resp[j].Address.Hex()
+	})
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) getProtocol(w http.ResponseWriter, r *http.Request) {
+
+	chainID, protocol, ok := h.lookupProtocol(w, r)
+	if !ok {
+		return
+	}
+
+	assets, err := protocol.GetSupportedAssets(r.Context(), chainID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := newProtocolResponse(chainID, protocol)
+	resp.SupportedAssets = assets
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) generateCalldata(w http.ResponseWriter, r *http.Request) {
+
+	chainID, protocol, ok := h.lookupProtocol(w, r)
+	if !ok {
+		return
+	}
+
+	var req CalldataRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid body: %w", err))
+		return
+	}
+
+	action, err := pkg.ParseContractAction(req.Action)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	params := pkg.TransactionParams{
+		Sender:    req.Sender,
+		Recipient: req.Recipient,
+		Asset:     req.Asset,
+		ExtraData: req.ExtraData,
+	}
+
+	// the amount rules depend on the action and are left to the protocol
+	if req.Amount != "" {
+		amount, ok := new(big.Int).SetString(req.Amount, 10)
+		if !ok {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid amount %q", req.Amount))
+			return
+		}
+
+		params.Amount = amount
+	}
+
+	tx, err := protocol.BuildTransaction(r.Context(), chainID, action, params)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CalldataResponse{
+		To:       tx.To,
+		Calldata: tx.Data,
+		Value:    tx.Value.String(),
+	})
+}
+
+func (h *Handler) export(w http.ResponseWriter, r *http.Request) {
+
+	registry, ok := h.registry.(exporter)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, errors.New("the registry can not be exported"))
+		return
+	}
+
+	b, err := registry.Export()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b)
+}
+
+// lookupProtocol resolves the protocol addressed by the request path and
+// writes the error response when it cannot
+func (h *Handler) lookupProtocol(w http.ResponseWriter, r *http.Request) (*big.Int, pkg.Protocol, bool) {
+
+	chainID, err := parseChainID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return nil, nil, false
+	}
+
+	address := r.PathValue("address")
+	if !common.IsHexAddress(address) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid address %q", address))
+		return nil, nil, false
+	}
+
+	protocol, err := h.registry.GetProtocol(chainID, common.HexToAddress(address))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return nil, nil, false
+	}
+
+	return chainID, protocol, true
+}
+
+func parseChainID(r *http.Request) (*big.Int, error) {
+	chainID, ok := new(big.Int).SetString(r.PathValue("id"), 10)
+	if !ok || chainID.Sign() <= 0 {
+		return nil, errors.New("invalid chain id")
+	}
+
+	return chainID, nil
+}
+
+func newProtocolResponse(chainID *big.Int, protocol pkg.Protocol) ProtocolResponse {
+	return ProtocolResponse{
+		Name:         protocol.GetName(),
+		Version:      protocol.GetVersion(),
+		Type:         protocol.GetType(),
+		Address:      protocol.GetContractAddress(chainID),
+		Capabilities: protocol.Capabilities(),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,219 @@
+package server
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/blndgs/protocol_registry/pkg"
+	"github.com/blndgs/protocol_registry/pkg/pkgtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	// only lido is set up on the mocked ethereum client
+	registry, err := pkg.NewProtocolRegistry([]pkg.ChainConfig{
+		{ChainID: pkg.EthChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		pkg.WithClient(pkg.EthChainID, pkgtest.NewClient(pkg.EthChainID)),
+		pkg.WithDisabledProtocols(pkg.AaveV3, pkg.SparkLend, pkg.Ankr, pkg.RocketPool, pkg.Compound, pkg.EigenLayer, pkg.Stargate, pkg.Yearn, pkg.SavingsDAI, pkg.SparkSavings))
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(New(registry))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestHandler_ListProtocols(t *testing.T) {
+
+	srv := newTestServer(t)
+
+	t.Run("registered protocols", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/chains/1/protocols")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var protocols []ProtocolResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&protocols))
+		require.Len(t, protocols, 1)
+		require.Equal(t, pkg.Lido, protocols[0].Name)
+		require.Equal(t, pkg.TypeStake, protocols[0].Type)
+		require.Equal(t, pkg.LidoContractAddress, protocols[0].Address)
+		require.Equal(t, []pkg.ContractAction{pkg.NativeStake}, protocols[0].Capabilities.SupportedActions)
+	})
+
+	t.Run("chain without protocols", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/chains/56/protocols")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var protocols []ProtocolResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&protocols))
+		require.Empty(t, protocols)
+	})
+
+	t.Run("invalid chain id", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/chains/eth/protocols")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestHandler_GetProtocol(t *testing.T) {
+
+	srv := newTestServer(t)
+
+	t.Run("registered protocol", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/chains/1/protocols/" + pkg.LidoContractAddress.Hex())
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var protocol ProtocolResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&protocol))
+		require.Equal(t, pkg.Lido, protocol.Name)
+		// ETH and stETH
+		require.Equal(t, []common.Address{common.HexToAddress("0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"), pkg.LidoContractAddress},
+			protocol.SupportedAssets)
+	})
+
+	t.Run("unknown protocol", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/chains/1/protocols/0x000000000000000000000000000000000000dEaD")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/chains/1/protocols/lido")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestHandler_GenerateCalldata(t *testing.T) {
+
+	srv := newTestServer(t)
+	url := srv.URL + "/chains/1/protocols/" + pkg.LidoContractAddress.Hex() + "/calldata"
+
+	post := func(t *testing.T, body string) *http.Response {
+		resp, err := http.Post(url, "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("native stake", func(t *testing.T) {
+		resp := post(t, `{
+			"action": "native_stake",
+			"amount": "1000000000000000000",
+			"sender": "0x6a22640F02F8c8b576a3193674c4aE97e0f8d007",
+			"asset": "0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
+		}`)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var tx CalldataResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&tx))
+
+		require.Equal(t, pkg.LidoContractAddress, tx.To)
+		require.Equal(t, big.NewInt(1e18).String(), tx.Value)
+		require.Equal(t,
+			"0xa1903eab0000000000000000000000006a22640f02f8c8b576a3193674c4ae97e0f8d007",
+			tx.Calldata)
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		resp := post(t, `{"action": "fly", "amount": "1"}`)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid amount", func(t *testing.T) {
+		resp := post(t, `{"action": "native_stake", "amount": "one"}`)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("amount overflowing uint256", func(t *testing.T) {
+		// 2^256
+		resp := post(t, `{"action": "native_stake", "amount": "115792089237316195423570985008687907853269984665640564039457584007913129639936"}`)
+		require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	})
+
+	t.Run("negative amount", func(t *testing.T) {
+		resp := post(t, `{"action": "native_stake", "amount": "-1"}`)
+		require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	})
+
+	t.Run("unsupported action for the protocol", func(t *testing.T) {
+		resp := post(t, `{"action": "loan_supply", "amount": "1"}`)
+		require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+		var body errorResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		require.NotEmpty(t, body.Error)
+	})
+}
+
+func TestHandler_GenerateCalldata_WithoutAmount(t *testing.T) {
+
+	registry, err := pkg.NewProtocolRegistry([]pkg.ChainConfig{
+		{ChainID: pkg.EthChainID, RPCURL: "http://127.0.0.1:1"},
+	},
+		pkg.WithClient(pkg.EthChainID, pkgtest.NewClient(pkg.EthChainID)),
+		pkg.WithDisabledProtocols(pkg.Lido, pkg.SparkLend, pkg.Ankr, pkg.RocketPool, pkg.Compound, pkg.EigenLayer, pkg.Stargate, pkg.Yearn, pkg.SavingsDAI, pkg.SparkSavings))
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(New(registry))
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Post(srv.URL+"/chains/1/protocols/"+pkg.AaveEthereumV3ContractAddress.Hex()+"/calldata",
+		"application/json", strings.NewReader(`{
+			"action": "loan_set_emode",
+			"sender": "0x6a22640F02F8c8b576a3193674c4aE97e0f8d007",
+			"extra_data": {"emode_category": 1}
+		}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var tx CalldataResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&tx))
+
+	require.Equal(t, pkg.AaveEthereumV3ContractAddress, tx.To)
+	require.Equal(t, "0", tx.Value)
+	require.Equal(t, "0x28530a470000000000000000000000000000000000000000000000000000000000000001", tx.Calldata)
+}
+
+func TestHandler_Export(t *testing.T) {
+
+	srv := newTestServer(t)
+
+	resp, err := http.Get(srv.URL + "/export")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var protocols []pkg.ProtocolExport
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&protocols))
+	require.Len(t, protocols, 1)
+	require.Equal(t, pkg.Lido, protocols[0].Name)
+	require.Equal(t, pkg.LidoContractAddress, protocols[0].Address)
+	require.Zero(t, protocols[0].ChainID.Cmp(pkg.EthChainID))
+}
@@ -48,4 +48,9 @@ type TokenRegistry interface {
 type JSONTokenRegistry struct {
 	data     map[string]*Data
 	dataLock sync.RWMutex
+
+	// dir and chainIDs are the token lists Watch reloads, only set for
+	// registries created with NewJSONTokenRegistryFromDir
+	dir      string
+	chainIDs []*big.Int
 }
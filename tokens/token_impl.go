@@ -5,21 +5,31 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/blndgs/protocol_registry/pkg"
+	"github.com/ethereum/go-ethereum/common"
 )
 
+var _ pkg.AssetMetadata = (*JSONTokenRegistry)(nil)
+
 //go:embed *.json
 var jsonFiles embed.FS
 
+// supportedChainIDs are the chains a token list is embedded for
+func supportedChainIDs() []*big.Int {
+	return []*big.Int{pkg.EthChainID, pkg.BscChainID, pkg.PolygonChainID, pkg.GnosisChainID, pkg.AvalancheChainID}
+}
+
 // NewJSONTokenRegistry creates a new JSONTokenRegistry.
 func NewJSONTokenRegistry() (*JSONTokenRegistry, error) {
 	registry := &JSONTokenRegistry{
 		data: make(map[string]*Data),
 	}
 	// supported chain ids.
-	chainIDs := []*big.Int{pkg.EthChainID, pkg.BscChainID, pkg.PolygonChainID}
-	for _, chainID := range chainIDs {
+	for _, chainID := range supportedChainIDs() {
 		fileName := fmt.Sprintf("%d.json", chainID)
 		data, err := loadJSONFile(fileName)
 		if err != nil {
@@ -31,14 +41,81 @@ func NewJSONTokenRegistry() (*JSONTokenRegistry, error) {
 	return registry, nil
 }
 
+// NewJSONTokenRegistryFromDir creates a JSONTokenRegistry from the <chain_id>.json
+// files of dir rather than the embedded ones, e.g a mounted config directory.
+// The embedded chains are loaded when chainIDs is empty
+func NewJSONTokenRegistryFromDir(dir string, chainIDs []*big.Int) (*JSONTokenRegistry, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("token registry directory %s: %w", dir, err)
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("token registry directory %s is not a directory", dir)
+	}
+
+	if len(chainIDs) == 0 {
+		chainIDs = supportedChainIDs()
+	}
+
+	var missing []string
+	for _, chainID := range chainIDs {
+		fileName := fmt.Sprintf("%d.json", chainID)
+		if _, err := os.Stat(filepath.Join(dir, fileName)); err != nil {
+			missing = append(missing, fileName)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("token registry directory %s is missing %s", dir, strings.Join(missing, ", "))
+	}
+
+	data, err := loadDir(dir, chainIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONTokenRegistry{
+		data:     data,
+		dir:      dir,
+		chainIDs: chainIDs,
+	}, nil
+}
+
+// loadDir reads the token lists of chainIDs from dir
+func loadDir(dir string, chainIDs []*big.Int) (map[string]*Data, error) {
+	loaded := make(map[string]*Data, len(chainIDs))
+
+	for _, chainID := range chainIDs {
+		path := filepath.Join(dir, fmt.Sprintf("%d.json", chainID))
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading file %s: %w", path, err)
+		}
+
+		data, err := parseJSONFile(path, content)
+		if err != nil {
+			return nil, fmt.Errorf("error loading data for chain ID %d: %w", chainID, err)
+		}
+		loaded[chainID.String()] = data
+	}
+
+	return loaded, nil
+}
+
 func loadJSONFile(fileName string) (*Data, error) {
 	content, err := jsonFiles.ReadFile(fileName)
 	if err != nil {
 		return nil, fmt.Errorf("error reading file %s: %w", fileName, err)
 	}
 
+	return parseJSONFile(fileName, content)
+}
+
+func parseJSONFile(fileName string, content []byte) (*Data, error) {
 	var data Data
-	err = json.Unmarshal(content, &data)
+	err := json.Unmarshal(content, &data)
 	if err != nil {
 		return nil, fmt.Errorf("error unmarshaling JSON from %s: %w", fileName, err)
 	}
@@ -105,3 +182,82 @@ func (r *JSONTokenRegistry) GetProtocolByAddress(chainID *big.Int, address strin
 	}
 	return nil, fmt.Errorf("protocol not found with address: %s for chain ID %d", address, chainID)
 }
+
+// AssetInfo returns the symbol and decimals of asset when the token is listed
+// for the chain, so the registry does not have to read them from the chain
+func (r *JSONTokenRegistry) AssetInfo(chainID *big.Int, asset common.Address) (pkg.AssetInfo, bool) {
+	r.dataLock.RLock()
+	defer r.dataLock.RUnlock()
+
+	data, ok := r.data[chainID.String()]
+	if !ok {
+		return pkg.AssetInfo{}, false
+	}
+
+	for _, token := range data.Tokens {
+		if strings.EqualFold(token.TokenAddress, asset.Hex()) {
+			return pkg.AssetInfo{
+				Address:  asset,
+				Symbol:   token.Symbol,
+				Decimals: uint8(token.Decimals),
+			}, true
+		}
+	}
+
+	return pkg.AssetInfo{}, false
+}
+
+// NormalizeAmount rescales amount of token, looked up by its symbol, from its
+// decimals on fromChain to its decimals on toChain, e.g 1 USDC is 1e6 on Ethereum
+// but 1e18 on BSC. Scaling down truncates the digits the destination cannot hold
+func (r *JSONTokenRegistry) NormalizeAmount(fromChain, toChain *big.Int, token string, amount *big.Int) (*big.Int, error) {
+	if amount == nil {
+		return nil, fmt.Errorf("amount is required")
+	}
+
+	from, err := r.getTokenBySymbol(fromChain, token)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := r.getTokenBySymbol(toChain, token)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := to.Decimals - from.Decimals
+	if diff == 0 {
+		return new(big.Int).Set(amount), nil
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(diff))), nil)
+	if diff > 0 {
+		return new(big.Int).Mul(amount, scale), nil
+	}
+
+	return new(big.Int).Quo(amount, scale), nil
+}
+
+func (r *JSONTokenRegistry) getTokenBySymbol(chainID *big.Int, symbol string) (*Token, error) {
+	r.dataLock.RLock()
+	defer r.dataLock.RUnlock()
+
+	data, ok := r.data[chainID.String()]
+	if !ok {
+		return nil, fmt.Errorf("no data available for chain ID %d", chainID)
+	}
+
+	for _, token := range data.Tokens {
+		if strings.EqualFold(token.Symbol, symbol) {
+			return &token, nil
+		}
+	}
+	return nil, fmt.Errorf("token not found with symbol: %s for chain ID %d", symbol, chainID)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
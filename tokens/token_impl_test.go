@@ -10,20 +10,12 @@ import (
 	"testing"
 
 	"github.com/blndgs/protocol_registry/pkg"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 // Sample data for testing
-const sampleEthData = `{
-	"tokens": [
-		{"token_address": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", "name": "USD Coin", "symbol": "USDC", "decimals": 6}
-	],
-	"protocols": [
-		{"address": "0x87870bca3f3fd6335c3f4ce8392d69350b4fa4e2", "name": "AaveV3", "source": true, "destination": true, "tokens": ["0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"]}
-	]
-}`
-
 const sampleBscData = `{
 	"tokens": [
 		{"token_address": "0x8AC76a51cc950d9822D68b83fE1Ad97B32Cd580d", "name": "USD Coin", "symbol": "USDC", "decimals": 18}
@@ -59,7 +51,54 @@ func TestNewJSONTokenRegistry(t *testing.T) {
 	registry, err := NewJSONTokenRegistry()
 	require.NoError(t, err)
 	assert.NotNil(t, registry)
-	assert.Len(t, registry.data, 3)
+	assert.Len(t, registry.data, 5)
+}
+
+func TestNewJSONTokenRegistryFromDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTempJSONFile(t, tmpDir, "1.json", sampleEthData)
+	createTempJSONFile(t, tmpDir, "56.json", sampleBscData)
+	createTempJSONFile(t, tmpDir, "137.json", samplePolygonData)
+
+	t.Run("loads the chains from the directory", func(t *testing.T) {
+		registry, err := NewJSONTokenRegistryFromDir(tmpDir, []*big.Int{pkg.EthChainID, pkg.BscChainID, pkg.PolygonChainID})
+		require.NoError(t, err)
+		assert.Len(t, registry.data, 3)
+
+		tokens, err := registry.GetTokens(pkg.BscChainID)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		assert.Equal(t, "0x8AC76a51cc950d9822D68b83fE1Ad97B32Cd580d", tokens[0].TokenAddress)
+
+		_, err = registry.GetTokens(pkg.GnosisChainID)
+		assert.Error(t, err)
+	})
+
+	t.Run("lists every missing file", func(t *testing.T) {
+		_, err := NewJSONTokenRegistryFromDir(tmpDir, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "100.json, 43114.json")
+	})
+
+	t.Run("directory does not exist", func(t *testing.T) {
+		_, err := NewJSONTokenRegistryFromDir(filepath.Join(tmpDir, "missing"), nil)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("path is a file", func(t *testing.T) {
+		_, err := NewJSONTokenRegistryFromDir(filepath.Join(tmpDir, "1.json"), nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a directory")
+	})
+
+	t.Run("malformed file", func(t *testing.T) {
+		dir := t.TempDir()
+		createTempJSONFile(t, dir, "1.json", "{")
+
+		_, err := NewJSONTokenRegistryFromDir(dir, []*big.Int{pkg.EthChainID})
+		require.Error(t, err)
+	})
 }
 
 func TestGetTokens(t *testing.T) {
@@ -72,8 +111,10 @@ func TestGetTokens(t *testing.T) {
 		wantErr bool
 	}{
 		{"Ethereum chain", pkg.EthChainID, 17, false},
-		{"BSC chain", pkg.BscChainID, 9, false},
-		{"Polyhon chain", pkg.PolygonChainID, 12, false},
+		{"BSC chain", pkg.BscChainID, 13, false},
+		{"Polyhon chain", pkg.PolygonChainID, 16, false},
+		{"Gnosis chain", pkg.GnosisChainID, 8, false},
+		{"Avalanche chain", pkg.AvalancheChainID, 10, false},
 		{"Unknown chain", big.NewInt(999), 0, true},
 	}
 
@@ -102,7 +143,9 @@ func TestGetProtocols(t *testing.T) {
 	}{
 		{"Ethereum chain", pkg.EthChainID, 7, false},
 		{"BSC chain", pkg.BscChainID, 3, false},
-		{"Polygon chain", pkg.PolygonChainID, 1, false},
+		{"Polygon chain", pkg.PolygonChainID, 3, false},
+		{"Gnosis chain", pkg.GnosisChainID, 1, false},
+		{"Avalanche chain", pkg.AvalancheChainID, 2, false},
 		{"Unknown chain", big.NewInt(999), 0, true},
 	}
 
@@ -150,6 +193,23 @@ func TestGetTokenByAddress(t *testing.T) {
 	}
 }
 
+func TestAssetInfo(t *testing.T) {
+	registry, err := NewJSONTokenRegistry()
+	require.NoError(t, err)
+
+	usdc := common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+
+	info, ok := registry.AssetInfo(pkg.EthChainID, usdc)
+	require.True(t, ok)
+	assert.Equal(t, pkg.AssetInfo{Address: usdc, Symbol: "USDC", Decimals: 6}, info)
+
+	_, ok = registry.AssetInfo(pkg.EthChainID, common.HexToAddress("0x1234567890123456789012345678901234567890"))
+	assert.False(t, ok)
+
+	_, ok = registry.AssetInfo(big.NewInt(999), usdc)
+	assert.False(t, ok)
+}
+
 func TestGetProtocolByAddress(t *testing.T) {
 	registry, err := NewJSONTokenRegistry()
 	require.NoError(t, err)
@@ -181,8 +241,40 @@ func TestGetProtocolByAddress(t *testing.T) {
 	}
 }
 
-func createTempJSONFile(t *testing.T, dir, filename, content string) {
-	path := filepath.Join(dir, filename)
-	err := os.WriteFile(path, []byte(content), 0644)
+func TestNormalizeAmount(t *testing.T) {
+	registry, err := NewJSONTokenRegistry()
 	require.NoError(t, err)
+
+	oneUSDCOnEth := big.NewInt(1_000_000)
+	oneUSDCOnBsc, _ := new(big.Int).SetString("1000000000000000000", 10)
+
+	tests := []struct {
+		name      string
+		fromChain *big.Int
+		toChain   *big.Int
+		token     string
+		amount    *big.Int
+		want      *big.Int
+		wantErr   bool
+	}{
+		{"USDC ETH to BSC", pkg.EthChainID, pkg.BscChainID, "USDC", oneUSDCOnEth, oneUSDCOnBsc, false},
+		{"USDC BSC to ETH", pkg.BscChainID, pkg.EthChainID, "usdc", oneUSDCOnBsc, oneUSDCOnEth, false},
+		{"USDC BSC to ETH truncates", pkg.BscChainID, pkg.EthChainID, "USDC", big.NewInt(1_999_999_999_999), big.NewInt(1), false},
+		{"USDC same decimals", pkg.EthChainID, pkg.PolygonChainID, "USDC", oneUSDCOnEth, oneUSDCOnEth, false},
+		{"Unknown token", pkg.EthChainID, pkg.BscChainID, "NOPE", oneUSDCOnEth, nil, true},
+		{"Unknown chain", pkg.EthChainID, big.NewInt(999), "USDC", oneUSDCOnEth, nil, true},
+		{"Missing amount", pkg.EthChainID, pkg.BscChainID, "USDC", nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, err := registry.NormalizeAmount(tt.fromChain, tt.toChain, tt.token, tt.amount)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want.String(), amount.String())
+			}
+		})
+	}
 }
@@ -0,0 +1,81 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watch reloads the token lists whenever the directory the registry was
+// created from changes, until ctx is done. Every list is reloaded and swapped
+// in at once. An update failing to load, e.g a malformed or half written file,
+// keeps the previous lists and is passed to onError with the watcher errors,
+// onError may be nil to ignore them.
+// Registries created with NewJSONTokenRegistry have nothing to watch
+func (r *JSONTokenRegistry) Watch(ctx context.Context, onError func(error)) error {
+	if r.dir == "" {
+		return errors.New("token registry was not loaded from a directory")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("could not create token registry watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	// the directory is watched rather than the files, and any change in it
+	// reloads the lists, so lists replaced through a rename, as editors do, or
+	// through the ..data symlink swap of a mounted config map are picked up
+	if err := watcher.Add(r.dir); err != nil {
+		return fmt.Errorf("could not watch token registry directory %s: %w", r.dir, err)
+	}
+
+	report := func(err error) {
+		if onError != nil {
+			onError(err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			if err := r.reload(); err != nil {
+				report(fmt.Errorf("keeping the previous token lists: %w", err))
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			report(fmt.Errorf("watching token registry directory %s: %w", r.dir, err))
+		}
+	}
+}
+
+// reload swaps in the token lists of the directory if they all load
+func (r *JSONTokenRegistry) reload() error {
+	data, err := loadDir(r.dir, r.chainIDs)
+	if err != nil {
+		return err
+	}
+
+	r.dataLock.Lock()
+	r.data = data
+	r.dataLock.Unlock()
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package tokens
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blndgs/protocol_registry/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Sample data for testing
+const sampleEthData = `{
+	"tokens": [
+		{"token_address": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", "name": "USD Coin", "symbol": "USDC", "decimals": 6}
+	],
+	"protocols": [
+		{"address": "0x87870bca3f3fd6335c3f4ce8392d69350b4fa4e2", "name": "AaveV3", "source": true, "destination": true, "tokens": ["0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"]}
+	]
+}`
+
+func TestJSONTokenRegistry_Watch(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTempJSONFile(t, tmpDir, "1.json", sampleEthData)
+
+	registry, err := NewJSONTokenRegistryFromDir(tmpDir, []*big.Int{pkg.EthChainID})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errs := make(chan error, 16)
+	onError := func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- registry.Watch(ctx, onError) }()
+
+	symbols := func() []string {
+		tokens, err := registry.GetTokens(pkg.EthChainID)
+		if err != nil {
+			return nil
+		}
+
+		var symbols []string
+		for _, token := range tokens {
+			symbols = append(symbols, token.Symbol)
+		}
+		return symbols
+	}
+
+	updated := strings.Replace(sampleEthData, `"symbol": "USDC"`, `"symbol": "USDC.e"`, 1)
+
+	// the file is written again on every check as the watcher may not have
+	// started on the first one
+	require.Eventually(t, func() bool {
+		createTempJSONFile(t, tmpDir, "1.json", updated)
+		return assert.ObjectsAreEqual([]string{"USDC.e"}, symbols())
+	}, 5*time.Second, 50*time.Millisecond)
+
+	t.Run("malformed update keeps the previous data", func(t *testing.T) {
+		createTempJSONFile(t, tmpDir, "1.json", `{"tokens": [`)
+
+		assert.Never(t, func() bool {
+			return !assert.ObjectsAreEqual([]string{"USDC.e"}, symbols())
+		}, 500*time.Millisecond, 50*time.Millisecond)
+
+		assert.Error(t, registry.reload())
+		assert.Equal(t, []string{"USDC.e"}, symbols())
+
+		select {
+		case err := <-errs:
+			assert.ErrorContains(t, err, "keeping the previous token lists")
+		case <-time.After(5 * time.Second):
+			t.Fatal("the failed reload was not reported")
+		}
+	})
+
+	cancel()
+	require.NoError(t, <-done)
+
+	t.Run("embedded registry", func(t *testing.T) {
+		embedded, err := NewJSONTokenRegistry()
+		require.NoError(t, err)
+		assert.Error(t, embedded.Watch(context.Background(), nil))
+	})
+}
+
+// mounted config maps expose their files through symlinks into a ..data
+// directory symlink, an update swaps ..data and never touches the files
+func TestJSONTokenRegistry_WatchConfigMap(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	version := func(name, data string) {
+		require.NoError(t, os.Mkdir(filepath.Join(tmpDir, name), 0o755))
+		createTempJSONFile(t, filepath.Join(tmpDir, name), "1.json", data)
+	}
+
+	version("..v1", sampleEthData)
+	require.NoError(t, os.Symlink("..v1", filepath.Join(tmpDir, "..data")))
+	require.NoError(t, os.Symlink(filepath.Join("..data", "1.json"), filepath.Join(tmpDir, "1.json")))
+
+	registry, err := NewJSONTokenRegistryFromDir(tmpDir, []*big.Int{pkg.EthChainID})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- registry.Watch(ctx, nil) }()
+
+	symbol := func() string {
+		token, err := registry.GetTokens(pkg.EthChainID)
+		if err != nil || len(token) != 1 {
+			return ""
+		}
+		return token[0].Symbol
+	}
+
+	updated := strings.Replace(sampleEthData, `"symbol": "USDC"`, `"symbol": "USDC.e"`, 1)
+	version("..v2", updated)
+
+	// the swap is repeated on every check as the watcher may not have
+	// started on the first one
+	require.Eventually(t, func() bool {
+		require.NoError(t, os.Symlink("..v2", filepath.Join(tmpDir, "..data_tmp")))
+		require.NoError(t, os.Rename(filepath.Join(tmpDir, "..data_tmp"), filepath.Join(tmpDir, "..data")))
+		return symbol() == "USDC.e"
+	}, 5*time.Second, 50*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func createTempJSONFile(t *testing.T, dir, filename, content string) {
+	path := filepath.Join(dir, filename)
+	err := os.WriteFile(path, []byte(content), 0644)
+	require.NoError(t, err)
+}